@@ -0,0 +1,137 @@
+// cross_lang_proof/amendments.go
+//
+// Amendments — payload by reference (chain rule "amendments")
+// ============================================================
+//
+//   {"amendment": {"amendments": "fail"}}     in chain -chain-rules r.json
+//
+// An amendment record does not repeat the record it corrects; its payload
+// points at it by causal hash and carries only what changed:
+//
+//   {"amends": "<chain hash of the original>", "changes": {...}}
+//
+// With the amendments check on (it is off in the built-in rule), every
+// amendment is resolved against the corpus after pass 2 and judged:
+//
+//   malformed amendment            payload is not {amends, changes}   structure
+//   amendment unresolvable         original not in the corpus         completeness
+//   amendment of unverified record original failed verification       integrity
+//   amendment rule violated        original resolved and verified,    policy
+//                                  but: another agent_id, timestamp
+//                                  not later, or itself an amendment
+//
+// so a report tells "could not find what was amended" (UNVERIFIABLE)
+// apart from "found it, and the amendment breaks a rule". Every record
+// read in pass 1 resolves, including history before a -since window:
+// its signature was checked as it was read, its link is assumed like the
+// rest of that history. Under -shard only the shard's own agents are in
+// the corpus, which is enough for amendments that keep to the rules.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// recordTypeAmendment is the record_type that carries payload by reference.
+const recordTypeAmendment = "amendment"
+
+// amendment is what pass 1 keeps of an amendment record's payload.
+type amendment struct {
+	Amends string // normalized chain hash of the original
+	Err    string // why the payload is not an amendment, "" if it is
+}
+
+// parseAmendment reads the {amends, changes} payload of an amendment.
+func parseAmendment(payload interface{}) *amendment {
+	p, ok := payload.(map[string]interface{})
+	if !ok {
+		return &amendment{Err: "payload is not a JSON object"}
+	}
+	hash, ok := p["amends"].(string)
+	if !ok || len(normalizeHash(hash)) != 64 {
+		return &amendment{Err: fmt.Sprintf("amends %v is not a causal hash", p["amends"])}
+	}
+	if _, ok := p["changes"].(map[string]interface{}); !ok {
+		return &amendment{Err: "changes missing or not a JSON object"}
+	}
+	return &amendment{Amends: normalizeHash(hash)}
+}
+
+// hasAmendments reports whether any amendment in ordered is checked.
+func hasAmendments(ordered []chainTuple, rules *chainRules) bool {
+	for _, t := range ordered {
+		if t.Amendment != nil && rules.forType(t.RecordType).Amendments != severityOff {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAmendments judges every amendment among ordered against the
+// records read in pass 1. verified is keyed by chain hash, as in pass 2.
+func checkAmendments(run *chainRun, rules *chainRules, ordered, corpus []chainTuple, verified map[string]bool) {
+	defer func() { run.agent = "" }()
+	byHash := make(map[string]chainTuple, len(corpus))
+	for _, t := range corpus {
+		if t.ChainHash != "" {
+			byHash[t.ChainHash] = t
+		}
+	}
+	for _, t := range ordered {
+		rule := rules.forType(t.RecordType)
+		if t.Amendment == nil || rule.Amendments == severityOff {
+			continue
+		}
+		run.agent = t.AgentID
+		name := filepath.Base(t.File)
+		if t.Amendment.Err != "" {
+			run.ruleCheck(rule, rule.Amendments, gefverify.CategoryStructure, "malformed amendment", fmt.Sprintf("%s payload is {amends, changes}", name), false,
+				t.Amendment.Err)
+			continue
+		}
+		orig, found := byHash[t.Amendment.Amends]
+		run.ruleCheck(rule, rule.Amendments, gefverify.CategoryCompleteness, "amendment unresolvable", fmt.Sprintf("%s amended record in corpus", name), found,
+			fmt.Sprintf("amends=%s", t.Amendment.Amends))
+		if !found {
+			continue
+		}
+		origVerified, inWindow := verified[orig.ChainHash]
+		if !inWindow {
+			origVerified = orig.Verified
+		}
+		if !origVerified {
+			run.ruleCheck(rule, rule.Amendments, gefverify.CategoryIntegrity, "amendment of unverified record", fmt.Sprintf("%s amends a verified record", name), false,
+				fmt.Sprintf("%s failed verification", filepath.Base(orig.File)))
+			continue
+		}
+		var broken []string
+		if orig.AgentID != t.AgentID {
+			broken = append(broken, fmt.Sprintf("agent %s amends a record of %s", t.AgentID, orig.AgentID))
+		}
+		if t.Timestamp.IsZero() || orig.Timestamp.IsZero() || !t.Timestamp.After(orig.Timestamp) {
+			broken = append(broken, fmt.Sprintf("timestamp %s not after the original's %s",
+				formatTimestamp(t), formatTimestamp(orig)))
+		}
+		if orig.RecordType == recordTypeAmendment {
+			broken = append(broken, "the original is itself an amendment")
+		}
+		details := fmt.Sprintf("amends %s", filepath.Base(orig.File))
+		if len(broken) > 0 {
+			details = strings.Join(broken, "; ")
+		}
+		run.ruleCheck(rule, rule.Amendments, gefverify.CategoryPolicy, "amendment rule violated", fmt.Sprintf("%s amendment rules hold", name), len(broken) == 0,
+			details)
+	}
+}
+
+func formatTimestamp(t chainTuple) string {
+	if t.Timestamp.IsZero() {
+		return "(none)"
+	}
+	return t.Timestamp.UTC().Format(gefverify.TimestampLayout)
+}
@@ -0,0 +1,171 @@
+// cross_lang_proof/amendments_test.go
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+const amendmentRules = `{"amendment": {"amendments": "fail"}}`
+
+var amendmentBase = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// amendmentCorpus writes linked chains for several agents, one record at
+// a time, and remembers every record's chain hash by file name.
+type amendmentCorpus struct {
+	t      *testing.T
+	dir    string
+	prev   map[string]string
+	seq    map[string]int64
+	hashes map[string]string
+}
+
+func newAmendmentCorpus(t *testing.T) *amendmentCorpus {
+	return &amendmentCorpus{t: t, dir: t.TempDir(),
+		prev: map[string]string{}, seq: map[string]int64{}, hashes: map[string]string{}}
+}
+
+// add writes the next record of agent as name.json, at base+second
+// seconds. The first record of an agent is its genesis.
+func (c *amendmentCorpus) add(name, agent, recordType string, second int, payload map[string]interface{}) {
+	c.t.Helper()
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte(agent[:1]), 32))
+	b := gefverify.NewRecord(agent, recordType).Sequence(c.seq[agent]).PreviousHash(c.prev[agent])
+	if _, ok := c.prev[agent]; !ok {
+		b = gefverify.NewRecord(agent, "genesis").Genesis()
+	}
+	if payload != nil {
+		b.Payload(payload)
+	}
+	_, bundle, err := b.At(amendmentBase.Add(time.Duration(second) * time.Second)).Finalize(key)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.prev[agent], c.seq[agent] = bundle.CausalHashOfThis, c.seq[agent]+1
+	data, _ := json.Marshal(bundle)
+	path := filepath.Join(c.dir, name+".json")
+	must(c.t, os.WriteFile(path, data, 0o644))
+	c.hashes[name] = chainHashOf(c.t, path)
+}
+
+// amend is an amendment payload pointing at the record written as name.
+func (c *amendmentCorpus) amend(name string) map[string]interface{} {
+	return map[string]interface{}{"amends": c.hashes[name], "changes": map[string]interface{}{"exit_code": 0}}
+}
+
+// amendmentFixture writes genesis and one execution for alpha and for beta.
+func amendmentFixture(t *testing.T) *amendmentCorpus {
+	c := newAmendmentCorpus(t)
+	c.add("a0", "alpha", "genesis", 0, nil)
+	c.add("b0", "beta", "genesis", 0, nil)
+	c.add("a1", "alpha", "execution", 10, map[string]interface{}{"exit_code": 1})
+	c.add("b1", "beta", "execution", 10, nil)
+	return c
+}
+
+func TestChainAmendments(t *testing.T) {
+	rulesPath := filepath.Join(t.TempDir(), "rules.json")
+	must(t, os.WriteFile(rulesPath, []byte(amendmentRules), 0o644))
+
+	tests := []struct {
+		name  string
+		build func(c *amendmentCorpus)
+		ok    bool
+		want  string
+	}{
+		{"happy path", func(c *amendmentCorpus) {
+			c.add("a2", "alpha", "amendment", 20, c.amend("a1"))
+		}, true, "✅  a2.json amendment rules hold"},
+		{"other agent", func(c *amendmentCorpus) {
+			c.add("b2", "beta", "amendment", 20, c.amend("a1"))
+		}, false, "agent beta amends a record of alpha"},
+		{"timestamp not later", func(c *amendmentCorpus) {
+			c.add("b2", "beta", "execution", 30, nil)
+			c.add("a2", "alpha", "amendment", 10, c.amend("a1"))
+		}, false, "not after the original's 2026-03-01T09:00:10.000Z"},
+		{"amendment of an amendment", func(c *amendmentCorpus) {
+			c.add("a2", "alpha", "amendment", 20, c.amend("a1"))
+			c.add("a3", "alpha", "amendment", 30, c.amend("a2"))
+		}, false, "the original is itself an amendment"},
+		{"unresolvable", func(c *amendmentCorpus) {
+			c.add("a2", "alpha", "amendment", 20, map[string]interface{}{
+				"amends": strings.Repeat("ab", 32), "changes": map[string]interface{}{}})
+		}, false, "❌  a2.json amended record in corpus"},
+		{"malformed", func(c *amendmentCorpus) {
+			c.add("a2", "alpha", "amendment", 20, map[string]interface{}{"amends": "a1"})
+		}, false, "amends a1 is not a causal hash"},
+	}
+	for _, tt := range tests {
+		c := amendmentFixture(t)
+		tt.build(c)
+		code, out, errOut := runCaptured(t, "chain", "-chain-rules", rulesPath, c.dir)
+		if (code == 0) != tt.ok {
+			t.Errorf("%s: exit %d\n%s%s", tt.name, code, out, errOut)
+		}
+		if !strings.Contains(out, tt.want) {
+			t.Errorf("%s: missing %q:\n%s", tt.name, tt.want, out)
+		}
+		if !tt.ok && !strings.Contains(out, "[rule amendment]") {
+			t.Errorf("%s: finding does not name rule amendment:\n%s", tt.name, out)
+		}
+	}
+}
+
+func TestChainAmendmentUnverifiableWhenOriginalMissing(t *testing.T) {
+	rulesPath := filepath.Join(t.TempDir(), "rules.json")
+	must(t, os.WriteFile(rulesPath, []byte(amendmentRules), 0o644))
+	c := amendmentFixture(t)
+	c.add("a2", "alpha", "amendment", 20, map[string]interface{}{
+		"amends": strings.Repeat("ab", 32), "changes": map[string]interface{}{}})
+
+	code, out, _ := runCaptured(t, "chain", "-chain-rules", rulesPath, c.dir)
+	if want := gefverify.VerdictUnverifiable.ExitCode(); code != want {
+		t.Errorf("exit %d, want %d (UNVERIFIABLE)\n%s", code, want, out)
+	}
+}
+
+func TestChainAmendmentOfUnverifiedRecord(t *testing.T) {
+	rulesPath := filepath.Join(t.TempDir(), "rules.json")
+	must(t, os.WriteFile(rulesPath, []byte(amendmentRules), 0o644))
+	c := amendmentFixture(t)
+	c.add("a2", "alpha", "amendment", 20, c.amend("a1"))
+
+	// Forge a1's signature: the record still parses, links and hashes,
+	// but no longer verifies.
+	path := filepath.Join(c.dir, "a1.json")
+	var bundle gefverify.ProofBundle
+	readJSON(t, path, &bundle)
+	forged := "A"
+	if strings.HasPrefix(bundle.SignatureB64URL, forged) {
+		forged = "B"
+	}
+	bundle.SignatureB64URL, bundle.SignatureHex = forged+bundle.SignatureB64URL[1:], ""
+	data, _ := json.Marshal(bundle)
+	must(t, os.WriteFile(path, data, 0o644))
+
+	_, out, _ := runCaptured(t, "chain", "-chain-rules", rulesPath, c.dir)
+	if !strings.Contains(out, "❌  a2.json amends a verified record") {
+		t.Errorf("amendment of a forged record not reported:\n%s", out)
+	}
+}
+
+func TestChainAmendmentsOffByDefault(t *testing.T) {
+	c := amendmentFixture(t)
+	c.add("b2", "beta", "amendment", 20, c.amend("a1"))
+	code, out, errOut := runCaptured(t, "chain", c.dir)
+	if code != 0 || strings.Contains(out, "AMENDMENTS") {
+		t.Errorf("exit %d, amendments checked without a rule:\n%s%s", code, out, errOut)
+	}
+	if _, err := parseChainRules([]byte(`{"amendment": {"amendments": "strict"}}`)); err == nil {
+		t.Error("amendments accepted an unknown severity")
+	}
+}
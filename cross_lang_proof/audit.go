@@ -0,0 +1,609 @@
+// cross_lang_proof/audit.go
+//
+// Folder audit (audit subcommand)
+// ===============================
+//
+//   verify_proof audit [-keyring keys.json] [-report-json r.json] <dir>
+//
+// For whoever is handed a folder of evidence and needs one answer: audit
+// walks the tree, sniffs what every file is, verifies each artifact the
+// way its own subcommand would with default options, links what belongs
+// together, and ends in one verdict for the folder.
+//
+//   bundle     JSON object with signing_dict       Verify, as verify_proof b.json
+//   envelope   JSON object with signature and      Verify on BundleFromEnvelope
+//              signer_public_key, no signing_dict
+//   paseto     text starting v4.public.            as verify-paseto; the key comes
+//                                                  from -keyring, else UNVERIFIABLE
+//   snapshot   binary starting GEFSNAP             checksum and structure, then its
+//                                                  heads against the chains found
+//   report     report document (schema_version)    not evidence: its claimed verdict
+//                                                  is compared with the bundle's
+//
+// Cross-links:
+//
+//   chains     the bundle and envelope records of every agent_id, across
+//              all files and directories, ordered by sequence and checked
+//              for signatures and causal linkage as chain does under the
+//              built-in rule; identical copies of a record count once
+//   snapshots  a head whose sequence is in the tree must be the chain
+//              hash of the record there (TAMPERED otherwise: the history
+//              was rewritten after the snapshot); other heads are noted
+//   reports    matched to a bundle by file base name (the report
+//              document records no digest); a claim that disagrees with
+//              the verdict found now is printed, the verdict is not
+//              affected — the bundle's own result already counts
+//
+// Everything else is listed under SKIPPED with the reason: archives
+// (zip, gzip, tar) are not unpacked, other JSON and text is not a GEF
+// artifact, and unreadable files say why. Nothing is ignored silently.
+//
+// The folder verdict is derived from the artifact and chain verdicts by
+// the usual rule order (verdict.go), so one TAMPERED file makes the
+// folder TAMPERED; a folder with nothing to verify is UNVERIFIABLE. The
+// exit code is the folder verdict's. -report-json writes the same as a
+// JSON document.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// Artifact kinds, as classifyArtifact names them.
+const (
+	kindBundle   = "bundle"
+	kindEnvelope = "envelope"
+	kindPaseto   = "paseto"
+	kindSnapshot = "snapshot"
+	kindReport   = "report"
+)
+
+// sectionArtifacts heads the per-file checks audit adds itself.
+const sectionArtifacts = "ARTIFACTS"
+
+// sniffLen is how much of a file classification looks at first.
+const sniffLen = 512
+
+// auditArtifact is one verified file.
+type auditArtifact struct {
+	Path    string            `json:"path"`
+	Kind    string            `json:"kind"`
+	Verdict gefverify.Verdict `json:"verdict"`
+	Passed  int               `json:"passed"`
+	Total   int               `json:"total"`
+	Failed  []string          `json:"failed,omitempty"` // check IDs
+	Notes   []string          `json:"notes,omitempty"`
+}
+
+// auditChain is the linkage verdict of one agent's records.
+type auditChain struct {
+	AgentID string            `json:"agent_id"`
+	Records int               `json:"records"`
+	Files   []string          `json:"files"`
+	Verdict gefverify.Verdict `json:"verdict"`
+	Failed  []string          `json:"failed,omitempty"` // check names
+	Notes   []string          `json:"notes,omitempty"`
+}
+
+// auditClaim is a report document set against the bundle it names.
+type auditClaim struct {
+	Path    string            `json:"path"`
+	Bundle  string            `json:"bundle"`
+	Claimed gefverify.Verdict `json:"claimed"`
+	Found   gefverify.Verdict `json:"found,omitempty"`
+	Status  string            `json:"status"` // matches, contradicted, unmatched, ambiguous
+}
+
+// auditSkip is a file audit did not verify.
+type auditSkip struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// auditDocument is the -report-json form of an audit.
+type auditDocument struct {
+	SchemaVersion   int               `json:"schema_version"`
+	VerifierVersion string            `json:"verifier_version"`
+	Root            string            `json:"root"`
+	Verdict         gefverify.Verdict `json:"verdict"`
+	Artifacts       []auditArtifact   `json:"artifacts"`
+	Chains          []auditChain      `json:"chains"`
+	Reports         []auditClaim      `json:"reports"`
+	Skipped         []auditSkip       `json:"skipped"`
+}
+
+// auditAdvice says what a verdict means for someone deciding whether to
+// rely on the folder.
+var auditAdvice = map[gefverify.Verdict]string{
+	gefverify.VerdictVerified:             "every artifact verified — the evidence is intact",
+	gefverify.VerdictVerifiedUntrustedKey: "intact, but signed by a key nobody vouched for — check the signer",
+	gefverify.VerdictPolicyRejected:       "intact, but a policy check failed — see the failures above",
+	gefverify.VerdictUnverifiable:         "something needed to verify is missing — do not rely on it yet",
+	gefverify.VerdictTampered:             "evidence was altered or does not link up — do not trust this folder",
+	gefverify.VerdictMalformed:            "some files are damaged or not what they claim to be — do not trust this folder",
+}
+
+// verdictCategory is the category whose failure produces each verdict.
+var verdictCategory = map[gefverify.Verdict]gefverify.Category{
+	gefverify.VerdictMalformed:            gefverify.CategoryStructure,
+	gefverify.VerdictTampered:             gefverify.CategoryIntegrity,
+	gefverify.VerdictPolicyRejected:       gefverify.CategoryPolicy,
+	gefverify.VerdictUnverifiable:         gefverify.CategoryCompleteness,
+	gefverify.VerdictVerifiedUntrustedKey: gefverify.CategoryTrust,
+}
+
+// folderVerdict derives one verdict from many by the verdict.go rules.
+func folderVerdict(verdicts []gefverify.Verdict) gefverify.Verdict {
+	if len(verdicts) == 0 {
+		return gefverify.VerdictUnverifiable
+	}
+	var checks []gefverify.CheckResult
+	for _, v := range verdicts {
+		category, failed := verdictCategory[v]
+		checks = append(checks, gefverify.CheckResult{Passed: !failed, Category: category})
+	}
+	return gefverify.DeriveVerdict(checks)
+}
+
+// classifyArtifact names the kind of a file from its first bytes, or
+// says in reason why it is skipped. Both are "" for JSON, which
+// classifyJSON decides on the whole file.
+func classifyArtifact(head []byte) (kind, reason string) {
+	switch {
+	case bytes.HasPrefix(head, []byte(snapshotMagic)):
+		return kindSnapshot, ""
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		return "", "zip archive, not unpacked — extract it and audit the contents"
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		return "", "gzip archive, not unpacked — extract it and audit the contents"
+	case len(head) >= 262 && string(head[257:262]) == "ustar":
+		return "", "tar archive, not unpacked — extract it and audit the contents"
+	}
+	trimmed := bytes.TrimSpace(head)
+	if bytes.HasPrefix(trimmed, []byte(pasetoV4Public)) {
+		return kindPaseto, ""
+	}
+	if !bytes.HasPrefix(trimmed, []byte("{")) {
+		return "", "not JSON, a PASETO token or a snapshot"
+	}
+	return "", "" // JSON: decided by classifyJSON on the whole file
+}
+
+// classifyJSON names the kind of a JSON file from its members.
+func classifyJSON(data []byte) (kind, reason string) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return "", "JSON that does not parse: " + err.Error()
+	}
+	_, hasSigningDict := members["signing_dict"]
+	_, hasSignature := members["signature"]
+	_, hasSigner := members["signer_public_key"]
+	_, hasSchema := members["schema_version"]
+	_, hasVerdict := members["verdict"]
+	switch {
+	case hasSigningDict:
+		return kindBundle, ""
+	case hasSignature && hasSigner:
+		return kindEnvelope, ""
+	case hasSchema && hasVerdict:
+		return kindReport, ""
+	}
+	return "", "JSON, but not a bundle, envelope or report document"
+}
+
+// auditRun collects one audit.
+type auditRun struct {
+	root    string
+	keyring map[string]ed25519.PublicKey
+	doc     auditDocument
+	tuples  []chainTuple
+	reports []string // report document paths, matched after the bundles
+	snaps   []string
+}
+
+// rel is path as shown: relative to the root, with forward slashes.
+func (a *auditRun) rel(path string) string {
+	if r, err := filepath.Rel(a.root, path); err == nil {
+		return filepath.ToSlash(r)
+	}
+	return path
+}
+
+func (a *auditRun) skip(path, reason string) {
+	a.doc.Skipped = append(a.doc.Skipped, auditSkip{a.rel(path), reason})
+}
+
+func (a *auditRun) add(path, kind string, report gefverify.Report) {
+	art := auditArtifact{
+		Path: a.rel(path), Kind: kind, Verdict: report.Verdict,
+		Passed: report.Passed(), Total: report.Total(), Notes: report.Notes,
+	}
+	for _, c := range report.Failed() {
+		art.Failed = append(art.Failed, c.ID)
+	}
+	a.doc.Artifacts = append(a.doc.Artifacts, art)
+}
+
+// file classifies and verifies one file.
+func (a *auditRun) file(path string) {
+	head, err := readHead(path)
+	if err != nil {
+		a.skip(path, "unreadable: "+err.Error())
+		return
+	}
+	kind, reason := classifyArtifact(head)
+	if reason != "" {
+		a.skip(path, reason)
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		a.skip(path, "unreadable: "+err.Error())
+		return
+	}
+	if kind == "" {
+		if kind, reason = classifyJSON(data); reason != "" {
+			a.skip(path, reason)
+			return
+		}
+	}
+
+	switch kind {
+	case kindBundle, kindEnvelope:
+		parse := gefverify.ParseBundle
+		if kind == kindEnvelope {
+			parse = gefverify.BundleFromEnvelope
+		}
+		bundle, err := parse(data)
+		if err != nil {
+			a.add(path, kind, malformedReport(err))
+			return
+		}
+		report, err := gefverify.Verify(bundle, gefverify.VerifyOptions{})
+		if err != nil {
+			report = malformedReport(err)
+		}
+		a.add(path, kind, report)
+		if t, err := bundleTuple(chainTuple{File: path}, bundle, report, nil); err == nil && t.AgentID != "" {
+			a.tuples = append(a.tuples, t)
+		}
+	case kindPaseto:
+		report, err := verifyPaseto(string(bytes.TrimSpace(data)), pasetoRequest{Keyring: a.keyring})
+		if err != nil {
+			report = malformedReport(err)
+		}
+		if a.keyring == nil && !report.OK() {
+			report.Notes = append(report.Notes, "no -keyring given: tokens cannot be verified without a key")
+		}
+		a.add(path, kind, report)
+	case kindSnapshot:
+		a.snaps = append(a.snaps, path)
+	case kindReport:
+		a.reports = append(a.reports, path)
+	}
+}
+
+// readHead returns the first sniffLen bytes of path.
+func readHead(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		err = nil
+	}
+	return head[:n], err
+}
+
+// malformedReport is the report of an artifact that could not be read as
+// its kind at all.
+func malformedReport(err error) gefverify.Report {
+	checks := []gefverify.CheckResult{{
+		ID: "A.readable", Section: sectionArtifacts, Name: "artifact readable", Details: err.Error(), Category: gefverify.CategoryStructure,
+	}}
+	return gefverify.Report{Checks: checks, Verdict: gefverify.VerdictMalformed}
+}
+
+// chains links the records of every agent across the tree, under the
+// built-in chain rule: signatures, genesis and causal links. A chain
+// that starts after genesis is noted, as chain does.
+func (a *auditRun) chains() map[string][]chainTuple {
+	byAgent := make(map[string][]chainTuple)
+	seen := make(map[string]bool)
+	for _, t := range orderBySequence(a.tuples) {
+		if t.ChainHash != "" && seen[t.ChainHash] {
+			continue
+		}
+		seen[t.ChainHash] = true
+		byAgent[t.AgentID] = append(byAgent[t.AgentID], t)
+	}
+	agents := make([]string, 0, len(byAgent))
+	for agent := range byAgent {
+		agents = append(agents, agent)
+	}
+	sort.Strings(agents)
+
+	for _, agent := range agents {
+		records := byAgent[agent]
+		c := auditChain{AgentID: agent, Records: len(records)}
+		var checks []gefverify.CheckResult
+		check := func(category gefverify.Category, name string, passed bool) {
+			checks = append(checks, gefverify.CheckResult{Name: name, Passed: passed, Category: category})
+			if !passed {
+				c.Failed = append(c.Failed, name)
+			}
+		}
+		for i, t := range records {
+			name := a.rel(t.File)
+			c.Files = append(c.Files, name)
+			check(gefverify.CategoryIntegrity, name+" verifies under every contract", t.Verified)
+			switch {
+			case i > 0:
+				prev := records[i-1]
+				check(gefverify.CategoryIntegrity, fmt.Sprintf("%s links to %s", name, a.rel(prev.File)),
+					t.CausalHash == prev.ChainHash)
+			case t.Sequence == 0:
+				check(gefverify.CategoryIntegrity, name+" links to genesis", t.CausalHash == genesisCausalHash)
+			default:
+				c.Notes = append(c.Notes, fmt.Sprintf("starts at seq %d in %s — earlier history not in the folder", t.Sequence, name))
+			}
+		}
+		c.Verdict = gefverify.DeriveVerdict(checks)
+		a.doc.Chains = append(a.doc.Chains, c)
+	}
+	return byAgent
+}
+
+// snapshots checks every snapshot file and its heads against chains.
+func (a *auditRun) snapshots(chains map[string][]chainTuple) {
+	for _, path := range a.snaps {
+		var report gefverify.Report
+		check := func(id string, category gefverify.Category, name string, passed bool, details string) {
+			report.Checks = append(report.Checks, gefverify.CheckResult{
+				ID: id, Section: sectionArtifacts, Name: name, Passed: passed, Details: details, Category: category,
+			})
+		}
+		data, err := os.ReadFile(path)
+		var snap *chainSnapshot
+		if err == nil {
+			snap, err = decodeSnapshot(data)
+		}
+		switch {
+		case errors.Is(err, errSnapshotChecksum):
+			check("S.checksum", gefverify.CategoryIntegrity, "snapshot checksum valid", false, err.Error())
+		case err != nil:
+			check("S.structure", gefverify.CategoryStructure, "snapshot decodes", false, err.Error())
+		default:
+			check("S.checksum", gefverify.CategoryIntegrity, "snapshot checksum valid", true,
+				fmt.Sprintf("%d head(s)", len(snap.Heads)))
+			for _, h := range snap.Heads {
+				want := hex.EncodeToString(h.HeadHash[:])
+				at, found := recordAt(chains[h.AgentID], h.Sequence)
+				if !found {
+					report.Notes = append(report.Notes, fmt.Sprintf(
+						"head of %s at seq %d not in the folder", h.AgentID, h.Sequence))
+					continue
+				}
+				check("S.head", gefverify.CategoryIntegrity, fmt.Sprintf("head of %s matches %s", h.AgentID, a.rel(at.File)),
+					at.ChainHash == want, fmt.Sprintf("snapshot=%s... folder=%s...", gefverify.ShortHex(want, 16), gefverify.ShortHex(at.ChainHash, 16)))
+			}
+		}
+		report.Verdict = gefverify.DeriveVerdict(report.Checks)
+		a.add(path, kindSnapshot, report)
+	}
+}
+
+// recordAt finds the record of an agent's chain at seq.
+func recordAt(records []chainTuple, seq int64) (chainTuple, bool) {
+	for _, t := range records {
+		if t.Sequence == seq {
+			return t, true
+		}
+	}
+	return chainTuple{}, false
+}
+
+// claims matches every report document to the bundle it names.
+func (a *auditRun) claims() {
+	byBase := make(map[string][]auditArtifact)
+	for _, art := range a.doc.Artifacts {
+		if art.Kind == kindBundle || art.Kind == kindEnvelope {
+			byBase[filepath.Base(art.Path)] = append(byBase[filepath.Base(art.Path)], art)
+		}
+	}
+	for _, path := range a.reports {
+		data, err := os.ReadFile(path)
+		var doc gefverify.ReportDocument
+		if err == nil {
+			doc, err = gefverify.ParseReportDocument(data)
+		}
+		if err != nil {
+			a.skip(path, "report document that does not parse: "+err.Error())
+			continue
+		}
+		c := auditClaim{Path: a.rel(path), Bundle: doc.Bundle, Claimed: doc.Verdict}
+		switch matches := byBase[filepath.Base(doc.Bundle)]; len(matches) {
+		case 0:
+			c.Status = "unmatched"
+		case 1:
+			c.Found, c.Status = matches[0].Verdict, "matches"
+			if c.Found != c.Claimed {
+				c.Status = "contradicted"
+			}
+		default:
+			c.Status = "ambiguous"
+		}
+		a.doc.Reports = append(a.doc.Reports, c)
+	}
+}
+
+// runAudit implements the audit subcommand and returns the exit code.
+func runAudit(args []string) int {
+	fs := newFlagSet("audit")
+	keyringPath := fs.String("keyring", "",
+		"JSON `file` of kid → public key for PASETO tokens in the folder")
+	reportJSON := fs.String("report-json", "",
+		"also write the audit as JSON to this `file` (- for stdout)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof audit [-keyring keys.json] [-report-json r.json] <dir>")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	a := &auditRun{root: fs.Arg(0)}
+	a.doc = auditDocument{SchemaVersion: 1, VerifierVersion: gefverify.Version, Root: a.root,
+		Artifacts: []auditArtifact{}, Chains: []auditChain{}, Reports: []auditClaim{}, Skipped: []auditSkip{}}
+	if *keyringPath != "" {
+		keys, err := loadKeyring(*keyringPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: %v\n", err)
+			return 2
+		}
+		a.keyring = keys
+	}
+
+	err := filepath.WalkDir(a.root, func(path string, d os.DirEntry, err error) error {
+		switch {
+		case err != nil && path == a.root:
+			return err
+		case err != nil:
+			a.skip(path, "unreadable: "+err.Error())
+		case d.Type().IsRegular():
+			a.file(path)
+		case !d.IsDir():
+			a.skip(path, "not a regular file")
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot walk %s: %v\n", a.root, err)
+		return 2
+	}
+	a.snapshots(a.chains())
+	a.claims()
+
+	var verdicts []gefverify.Verdict
+	for _, art := range a.doc.Artifacts {
+		verdicts = append(verdicts, art.Verdict)
+	}
+	for _, c := range a.doc.Chains {
+		verdicts = append(verdicts, c.Verdict)
+	}
+	a.doc.Verdict = folderVerdict(verdicts)
+
+	console := stdout
+	if *reportJSON == "-" {
+		console = stderr
+	}
+	printAudit(console, a.doc)
+	if *reportJSON != "" {
+		data, err := json.MarshalIndent(a.doc, "", "  ")
+		switch {
+		case err == nil && *reportJSON == "-":
+			_, err = fmt.Fprintln(stdout, string(data))
+		case err == nil:
+			err = writeFileAtomic(*reportJSON, append(data, '\n'), 0o644)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", *reportJSON, err)
+			return 2
+		}
+	}
+	return a.doc.Verdict.ExitCode()
+}
+
+// printAudit is the human report of an audit.
+func printAudit(w io.Writer, doc auditDocument) {
+	bar := console.bar
+	rule := "  " + console.rule
+	mark := func(v gefverify.Verdict) string {
+		return console.mark(v == gefverify.VerdictVerified) + " "
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, bar)
+	fmt.Fprintf(w, "  GEF Folder Audit — Go Verifier\n  %s\n", doc.Root)
+	fmt.Fprintln(w, bar)
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  ARTIFACTS — each file verified on its own")
+	fmt.Fprintln(w, rule)
+	for _, art := range doc.Artifacts {
+		fmt.Fprintf(w, "  %s %-22s %-9s %s  (%d/%d checks)\n", mark(art.Verdict), art.Verdict, art.Kind, art.Path, art.Passed, art.Total)
+		if len(art.Failed) > 0 {
+			fmt.Fprintf(w, "       failed: %s\n", strings.Join(art.Failed, ", "))
+		}
+		for _, n := range art.Notes {
+			fmt.Fprintf(w, "       note: %s\n", n)
+		}
+	}
+	if len(doc.Artifacts) == 0 {
+		fmt.Fprintln(w, "  (none)")
+	}
+
+	if len(doc.Chains) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "  CHAINS — records linked across files, per agent_id")
+		fmt.Fprintln(w, rule)
+		for _, c := range doc.Chains {
+			fmt.Fprintf(w, "  %s %-22s %s  (%d record(s))\n", mark(c.Verdict), c.Verdict, c.AgentID, c.Records)
+			for _, f := range c.Failed {
+				fmt.Fprintf(w, "       failed: %s\n", f)
+			}
+			for _, n := range c.Notes {
+				fmt.Fprintf(w, "       note: %s\n", n)
+			}
+		}
+	}
+
+	if len(doc.Reports) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "  REPORTS — earlier claims against what was found now (verdict unaffected)")
+		fmt.Fprintln(w, rule)
+		for _, c := range doc.Reports {
+			switch c.Status {
+			case "matches":
+				fmt.Fprintf(w, "  %s  %s claims %s for %s — matches\n", console.note, c.Path, c.Claimed, c.Bundle)
+			case "contradicted":
+				fmt.Fprintf(w, "  %s  %s claims %s for %s — found %s now\n", console.warn, c.Path, c.Claimed, c.Bundle, c.Found)
+			default:
+				fmt.Fprintf(w, "  %s  %s claims %s for %s — %s in the folder\n", console.note, c.Path, c.Claimed, c.Bundle, c.Status)
+			}
+		}
+	}
+
+	if len(doc.Skipped) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "  SKIPPED — not verified")
+		fmt.Fprintln(w, rule)
+		for _, s := range doc.Skipped {
+			fmt.Fprintf(w, "  %s  %s: %s\n", console.note, s.Path, s.Reason)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, bar)
+	fmt.Fprintf(w, "  %s FOLDER %s  (%d artifact(s), %d chain(s), %d skipped)\n",
+		mark(doc.Verdict), doc.Verdict, len(doc.Artifacts), len(doc.Chains), len(doc.Skipped))
+	fmt.Fprintf(w, "      %s\n", auditAdvice[doc.Verdict])
+	fmt.Fprintln(w, bar)
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,222 @@
+// cross_lang_proof/audit_test.go
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// writeAuditTree writes one of every artifact audit knows, linked up,
+// plus files it must skip:
+//
+//   chain/part1/alpha-0.json            bundle, alpha genesis
+//   chain/part2/alpha-{1,2}.json        bundles, continuing alpha across directories
+//   envelopes/beta-0.json               envelope, beta genesis
+//   token.paseto                        PASETO token, kid "build-2026"
+//   heads.gefsnap                       snapshot of alpha's head
+//   reports/alpha-2.report.json         report document claiming VERIFIED
+//   bundle.zip, README.txt, other.json  skipped
+//
+// and returns the root and the keyring file for the token.
+func writeAuditTree(t *testing.T) (root, keyring string) {
+	t.Helper()
+	root = t.TempDir()
+	write := func(rel string, data []byte) {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		must(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		must(t, os.WriteFile(path, data, 0o644))
+	}
+
+	dir := t.TempDir()
+	alpha := writeChainDir(t, dir, "alpha", 3)
+	for i, part := range []string{"chain/part1/", "chain/part2/", "chain/part2/"} {
+		data, err := os.ReadFile(alpha[i])
+		must(t, err)
+		write(part+filepath.Base(alpha[i]), data)
+	}
+
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte("b"), 32))
+	env, _, err := gefverify.NewRecord("beta", "genesis").Genesis().Finalize(key)
+	must(t, err)
+	data, _ := json.Marshal(env)
+	write("envelopes/beta-0.json", data)
+
+	tok, pub := signPaseto(t, `{"kid":"build-2026"}`, nil)
+	write("token.paseto", []byte(tok+"\n"))
+	keyring = filepath.Join(t.TempDir(), "keys.json")
+	must(t, os.WriteFile(keyring, []byte(`{"build-2026": "`+hex.EncodeToString(pub)+`"}`), 0o644))
+
+	head, err := readChainTuple(alpha[2], nil, shardSpec{})
+	must(t, err)
+	snap, err := snapshotFromHeads(map[string]chainTuple{"alpha": head}, [32]byte{})
+	must(t, err)
+	write("heads.gefsnap", encodeSnapshot(snap))
+
+	bundle, err := gefverify.ParseBundle(mustRead(t, alpha[2]))
+	must(t, err)
+	report, _ := gefverify.Verify(bundle, gefverify.VerifyOptions{})
+	data, _ = json.Marshal(gefverify.NewReportDocument(report, "alpha-2.json", "1.0"))
+	write("reports/alpha-2.report.json", data)
+
+	write("bundle.zip", []byte("PK\x03\x04\x14\x00\x00\x00"))
+	write("README.txt", []byte("evidence for incident 42\n"))
+	write("other.json", []byte(`{"ticket": 42}`))
+	return root, keyring
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	must(t, err)
+	return data
+}
+
+func auditJSON(t *testing.T, args ...string) (int, auditDocument, string) {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "audit.json")
+	code, console, errOut := runCaptured(t, append([]string{"audit", "-report-json", out}, args...)...)
+	var doc auditDocument
+	if code != 2 {
+		readJSON(t, out, &doc)
+	}
+	return code, doc, console + errOut
+}
+
+func TestAuditFolderVerified(t *testing.T) {
+	root, keyring := writeAuditTree(t)
+	code, doc, out := auditJSON(t, "-keyring", keyring, root)
+	if code != 0 || doc.Verdict != gefverify.VerdictVerified {
+		t.Fatalf("exit %d verdict %s\n%s", code, doc.Verdict, out)
+	}
+
+	kinds := map[string]string{}
+	for _, a := range doc.Artifacts {
+		kinds[a.Path] = a.Kind
+		if a.Verdict != gefverify.VerdictVerified {
+			t.Errorf("%s: %s, failed %v", a.Path, a.Verdict, a.Failed)
+		}
+	}
+	for path, kind := range map[string]string{
+		"chain/part1/alpha-0.json": kindBundle,
+		"chain/part2/alpha-2.json": kindBundle,
+		"envelopes/beta-0.json":    kindEnvelope,
+		"token.paseto":             kindPaseto,
+		"heads.gefsnap":            kindSnapshot,
+	} {
+		if kinds[path] != kind {
+			t.Errorf("%s classified %q, want %q", path, kinds[path], kind)
+		}
+	}
+
+	if len(doc.Chains) != 2 || doc.Chains[0].AgentID != "alpha" || doc.Chains[0].Records != 3 ||
+		doc.Chains[1].AgentID != "beta" {
+		t.Errorf("chains %+v", doc.Chains)
+	}
+	if len(doc.Reports) != 1 || doc.Reports[0].Status != "matches" {
+		t.Errorf("reports %+v", doc.Reports)
+	}
+	skipped := map[string]bool{}
+	for _, s := range doc.Skipped {
+		skipped[s.Path] = true
+	}
+	if len(skipped) != 3 || !skipped["bundle.zip"] || !skipped["README.txt"] || !skipped["other.json"] {
+		t.Errorf("skipped %+v", doc.Skipped)
+	}
+	if !strings.Contains(out, "zip archive, not unpacked") || !strings.Contains(out, "FOLDER VERIFIED") {
+		t.Errorf("console:\n%s", out)
+	}
+}
+
+func TestAuditFolderFindings(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(t *testing.T, root string)
+		verdict gefverify.Verdict
+		want    string // in the console report
+	}{
+		{"tampered bundle", func(t *testing.T, root string) {
+			path := filepath.Join(root, "chain", "part2", "alpha-2.json")
+			var bundle gefverify.ProofBundle
+			readJSON(t, path, &bundle)
+			bundle.SigningDict["payload"] = map[string]interface{}{"forged": true}
+			data, _ := json.Marshal(bundle)
+			must(t, os.WriteFile(path, data, 0o644))
+		}, gefverify.VerdictTampered, "found TAMPERED now"},
+		{"record removed from the middle", func(t *testing.T, root string) {
+			must(t, os.Remove(filepath.Join(root, "chain", "part2", "alpha-1.json")))
+		}, gefverify.VerdictTampered, "chain/part2/alpha-2.json links to chain/part1/alpha-0.json"},
+		{"snapshot of another history", func(t *testing.T, root string) {
+			other := writeChainDir(t, t.TempDir(), "alpha", 3)
+			head, err := readChainTuple(other[2], nil, shardSpec{})
+			must(t, err)
+			snap, err := snapshotFromHeads(map[string]chainTuple{"alpha": head}, [32]byte{})
+			must(t, err)
+			must(t, os.WriteFile(filepath.Join(root, "heads.gefsnap"), encodeSnapshot(snap), 0o644))
+		}, gefverify.VerdictTampered, "failed: S.head"},
+		{"corrupt snapshot", func(t *testing.T, root string) {
+			path := filepath.Join(root, "heads.gefsnap")
+			data := mustRead(t, path)
+			data[len(data)-1] ^= 1
+			must(t, os.WriteFile(path, data, 0o644))
+		}, gefverify.VerdictTampered, "failed: S.checksum"},
+		{"unreadable envelope", func(t *testing.T, root string) {
+			must(t, os.WriteFile(filepath.Join(root, "envelopes", "beta-0.json"),
+				[]byte(`{"signature": 7, "signer_public_key": "00"}`), 0o644))
+		}, gefverify.VerdictMalformed, "failed: A.readable"},
+	}
+	for _, tt := range tests {
+		root, keyring := writeAuditTree(t)
+		tt.edit(t, root)
+		code, doc, out := auditJSON(t, "-keyring", keyring, root)
+		if doc.Verdict != tt.verdict || code != tt.verdict.ExitCode() {
+			t.Errorf("%s: exit %d verdict %s, want %s\n%s", tt.name, code, doc.Verdict, tt.verdict, out)
+		}
+		if !strings.Contains(out, tt.want) {
+			t.Errorf("%s: missing %q:\n%s", tt.name, tt.want, out)
+		}
+	}
+}
+
+func TestAuditPasetoWithoutKeyring(t *testing.T) {
+	root, _ := writeAuditTree(t)
+	code, doc, out := auditJSON(t, root)
+	if doc.Verdict != gefverify.VerdictUnverifiable || code != gefverify.VerdictUnverifiable.ExitCode() {
+		t.Errorf("exit %d verdict %s\n%s", code, doc.Verdict, out)
+	}
+	if !strings.Contains(out, "no -keyring given") {
+		t.Errorf("missing keyring hint:\n%s", out)
+	}
+}
+
+func TestAuditNothingToVerify(t *testing.T) {
+	root := t.TempDir()
+	must(t, os.WriteFile(filepath.Join(root, "notes.txt"), []byte("hello"), 0o644))
+	code, doc, _ := auditJSON(t, root)
+	if code != gefverify.VerdictUnverifiable.ExitCode() || len(doc.Skipped) != 1 || len(doc.Artifacts) != 0 {
+		t.Errorf("exit %d doc %+v", code, doc)
+	}
+	if code, _, _ := runCaptured(t, "audit"); code != 2 {
+		t.Errorf("audit without a directory: exit %d, want 2", code)
+	}
+}
+
+func TestFolderVerdictOrder(t *testing.T) {
+	v := folderVerdict([]gefverify.Verdict{
+		gefverify.VerdictVerified, gefverify.VerdictUnverifiable, gefverify.VerdictTampered, gefverify.VerdictPolicyRejected,
+	})
+	if v != gefverify.VerdictTampered {
+		t.Errorf("folder verdict %s, want TAMPERED", v)
+	}
+	if v := folderVerdict([]gefverify.Verdict{gefverify.VerdictVerified}); v != gefverify.VerdictVerified {
+		t.Errorf("folder verdict %s, want VERIFIED", v)
+	}
+}
@@ -0,0 +1,143 @@
+// cross_lang_proof/badge.go
+//
+// Verification Badge
+// ==================
+//
+//   verify_proof -report-json report.json bundle.json
+//   verify_proof badge -from report.json -out badge.svg [-label "GEF proof"]
+//
+// Renders a shields-style flat SVG ("GEF proof | passing, 14 checks")
+// from a report document, locally — no network. Output is deterministic:
+// text widths come from a fixed per-character table, not font metrics.
+//
+// The badge is only as honest as the report it reads. Reports with a
+// schema_version this verifier does not understand are refused outright
+// instead of being rendered as "passing" by guesswork.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// badgeStyle is the message word and color for each verdict.
+var badgeStyle = map[gefverify.Verdict]struct{ word, color string }{
+	gefverify.VerdictVerified:             {"passing", "#4c1"},
+	gefverify.VerdictVerifiedUntrustedKey: {"untrusted key", "#dfb317"},
+	gefverify.VerdictPolicyRejected:       {"policy rejected", "#fe7d37"},
+	gefverify.VerdictUnverifiable:         {"unverifiable", "#9f9f9f"},
+	gefverify.VerdictTampered:             {"failing", "#e05d44"},
+	gefverify.VerdictMalformed:            {"malformed", "#e05d44"},
+}
+
+// textWidth approximates the rendered width of s in 11px Verdana.
+func textWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune("iljI.,:;!|' ", r):
+			w += 4
+		case strings.ContainsRune("frt()[]-/", r):
+			w += 5
+		case strings.ContainsRune("mwMW", r):
+			w += 10
+		case r >= 'A' && r <= 'Z':
+			w += 8
+		default:
+			w += 7
+		}
+	}
+	return w
+}
+
+func xmlEscape(s string) string {
+	return strings.NewReplacer(
+		"&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;",
+	).Replace(s)
+}
+
+// badgeMessage is the right-hand text, e.g. "passing, 14 checks · v0.7.1".
+func badgeMessage(doc gefverify.ReportDocument) (string, string) {
+	style, ok := badgeStyle[doc.Verdict]
+	if !ok {
+		style.word, style.color = "unknown", "#9f9f9f"
+	}
+	counts := fmt.Sprintf("%d checks", doc.Total)
+	if doc.Passed != doc.Total {
+		counts = fmt.Sprintf("%d/%d checks", doc.Passed, doc.Total)
+	}
+	return fmt.Sprintf("%s, %s · v%s", style.word, counts, doc.VerifierVersion), style.color
+}
+
+// renderBadge returns the SVG for doc.
+func renderBadge(doc gefverify.ReportDocument, label string) string {
+	message, color := badgeMessage(doc)
+
+	labelW := textWidth(label) + 10
+	msgW   := textWidth(message) + 10
+	totalW := labelW + msgW
+	title  := xmlEscape(label + ": " + message)
+	l, m   := xmlEscape(label), xmlEscape(message)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s">`+"\n", totalW, title)
+	fmt.Fprintf(&b, "<title>%s</title>\n", title)
+	b.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` + "\n")
+	fmt.Fprintf(&b, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+"\n", totalW)
+	fmt.Fprintf(&b, `<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`+"\n",
+		labelW, labelW, msgW, color, totalW)
+	b.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` + "\n")
+	for _, t := range []struct {
+		x    int
+		text string
+	}{{labelW / 2, l}, {labelW + msgW/2, m}} {
+		fmt.Fprintf(&b, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`+"\n",
+			t.x, t.text, t.x, t.text)
+	}
+	b.WriteString("</g>\n</svg>\n")
+	return b.String()
+}
+
+// runBadge implements the badge subcommand and returns the exit code.
+func runBadge(args []string) int {
+	fs := newFlagSet("badge")
+	from  := fs.String("from", "", "report `file` written by -report-json")
+	out   := fs.String("out", "", "SVG output `file` (default: stdout)")
+	label := fs.String("label", "GEF proof", "left-hand badge `text`")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof badge -from report.json [-out badge.svg] [-label text]")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+
+	if *from == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	data, err := os.ReadFile(*from)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", *from, err)
+		return 2
+	}
+	doc, err := gefverify.ParseReportDocument(data)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %s: %v\n", *from, err)
+		return gefverify.VerdictMalformed.ExitCode()
+	}
+
+	svg := renderBadge(doc, *label)
+	if *out == "" {
+		stdout.WriteString(svg)
+		return 0
+	}
+	if err := writeFileAtomic(*out, []byte(svg), 0o644); err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", *out, err)
+		return 2
+	}
+	return 0
+}
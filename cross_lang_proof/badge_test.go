@@ -0,0 +1,64 @@
+// cross_lang_proof/badge_test.go
+
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata/")
+
+func TestRenderBadgeGolden(t *testing.T) {
+	tests := []struct {
+		golden string
+		label  string
+		doc    gefverify.ReportDocument
+	}{
+		{"badge_passing.svg", "GEF proof", gefverify.ReportDocument{
+			SchemaVersion: 1, VerifierVersion: "0.7.1",
+			Verdict: gefverify.VerdictVerified, Passed: 14, Total: 14,
+		}},
+		{"badge_tampered.svg", "GEF proof", gefverify.ReportDocument{
+			SchemaVersion: 1, VerifierVersion: "0.7.1",
+			Verdict: gefverify.VerdictTampered, Passed: 11, Total: 14,
+		}},
+		{"badge_label.svg", "audit <prod>", gefverify.ReportDocument{
+			SchemaVersion: 1, VerifierVersion: "0.7.1",
+			Verdict: gefverify.VerdictPolicyRejected, Passed: 13, Total: 14,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			got  := renderBadge(tt.doc, tt.label)
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("badge differs from %s (run go test -update):\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestBadgeRefusesUnknownSchema(t *testing.T) {
+	for _, data := range []string{
+		`{"schema_version": 2, "verdict": "VERIFIED", "passed": 14, "total": 14}`,
+		`{"verdict": "VERIFIED", "passed": 14, "total": 14}`,
+	} {
+		if _, err := gefverify.ParseReportDocument([]byte(data)); err == nil {
+			t.Errorf("accepted %s", data)
+		}
+	}
+}
@@ -0,0 +1,457 @@
+// cross_lang_proof/batch.go
+//
+// Batch verification
+// ==================
+//
+//   verify_proof ./bundles/*.json
+//   verify_proof -dir ./bundles
+//   verify_proof -format junit -dir ./bundles > TEST-gef.xml
+//
+// More than one bundle path, a directory, a glob the shell did not
+// expand, or -dir switch verify_proof to batch mode: every bundle is
+// verified with the same options and gets one verdict line, then a
+// summary with the failing files. A directory is walked and contributes
+// every *.json file that is a proof bundle; other JSON found there
+// (report documents, manifests) is listed as skipped. audit goes further
+// and verifies envelopes, tokens and snapshots too.
+//
+// A file that cannot be read or parsed is MALFORMED on its own line and
+// the run goes on; so is a named file, or one matched by a glob, that is
+// not a bundle. The exit code is that of the worst verdict, in the
+// usual rule order (verdict.go), so any failing bundle fails the run.
+//
+// -format junit and -junit write one <testsuite> per bundle inside
+// <testsuites>. -format json and -report-json write the batch document,
+// and the summary ends with the distribution of failed checks
+// (batchreport.go).
+//
+// -merkle-root adds gefverify.MerkleRoot over every bundle of the batch,
+// in batch order (arguments as given, directories and globs sorted), to
+// the summary, or to stderr with -format junit. Failing bundles are
+// included: the root commits to the files as they are. It is not
+// computed if any bundle could not be read or parsed.
+//
+// Every bundle with a nonce is also checked for replay against the
+// bundles before it, and with -nonce-db against earlier runs
+// (B.nonce_replay, nonces.go). The bundles of each agent must then have
+// consecutive sequences: B.sequence_gap and B.duplicate_sequence, gaps
+// as warnings with -allow-gaps (sequences.go).
+//
+// -jobs N verifies N bundles at a time, on a pool of N goroutines; a
+// Verifier is safe for concurrent use. Results are collected by their
+// place in the batch, so the output, the JUnit suites and the Merkle
+// root are those of a serial run (-jobs 1, the default).
+// Options that name a single bundle's output (-format sarif, -git-rev,
+// -cross-verify, -bench) are usage errors here.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// batchFile is one file of a batch. Found is set for files found by
+// walking a directory rather than named or matched by a glob.
+type batchFile struct {
+	Path  string
+	Found bool
+}
+
+// batchEntry is the outcome for one file of a batch.
+type batchEntry struct {
+	Path    string
+	Verdict gefverify.Verdict
+	Passed  int
+	Total   int
+	Failed  []string               // IDs of the failed checks
+	Error   string                 // why the file could not be verified at all
+	Skipped string                 // why a found file is not a bundle; no verdict
+	Report  gefverify.Report       // every result, for -quiet, -verbose and JUnit
+	Timer   phaseTimer             // phase times of Report, with JUnit
+	Bundle  *gefverify.ProofBundle // nil unless the file parsed
+}
+
+// summarize sets the verdict and counts of e from its report.
+func (e *batchEntry) summarize() {
+	e.Verdict, e.Passed, e.Total = e.Report.Verdict, e.Report.Passed(), e.Report.Total()
+	e.Failed = nil
+	for _, c := range e.Report.Failed() {
+		e.Failed = append(e.Failed, c.ID)
+	}
+}
+
+// suite is the JUnit suite of e.
+func (e batchEntry) suite() junitSuite {
+	if e.Error != "" {
+		return newJUnitErrorSuite(e.Path, errors.New(e.Error))
+	}
+	return newJUnitSuite(e.Path, e.Bundle.GEFVersion, e.Report, e.Timer)
+}
+
+// batchPaths expands the -dir flag and the positional arguments into the
+// files to verify. batch reports whether they ask for batch mode at all;
+// a single plain path does not.
+func batchPaths(dir string, args []string) (files []batchFile, batch bool, err error) {
+	if dir != "" {
+		args = append([]string{dir}, args...)
+	}
+	batch = dir != "" || len(args) > 1
+	for _, arg := range args {
+		var matches []batchFile
+		switch info, statErr := os.Stat(arg); {
+		case statErr == nil && info.IsDir():
+			err = filepath.WalkDir(arg, func(path string, d os.DirEntry, err error) error {
+				if err == nil && !d.IsDir() && strings.HasSuffix(d.Name(), ".json") {
+					matches = append(matches, batchFile{path, true})
+				}
+				return err
+			})
+			batch = true
+		case statErr != nil && strings.ContainsAny(arg, "*?["):
+			var paths []string
+			paths, err = filepath.Glob(arg)
+			sort.Strings(paths)
+			for _, p := range paths {
+				matches = append(matches, batchFile{Path: p})
+			}
+			batch = true
+		default:
+			matches = []batchFile{{Path: arg}}
+		}
+		if err != nil {
+			return nil, batch, fmt.Errorf("%s: %v", arg, err)
+		}
+		if len(matches) == 0 {
+			return nil, batch, fmt.Errorf("%s: no bundle files", arg)
+		}
+		files = append(files, matches...)
+	}
+	return files, batch, nil
+}
+
+// verifyBatchFile verifies the bundle in f. junit also times its
+// phases for the JUnit suite.
+func verifyBatchFile(f batchFile, opts gefverify.VerifyOptions, junit bool) batchEntry {
+	e := batchEntry{Path: f.Path, Verdict: gefverify.VerdictMalformed}
+	data, err := os.ReadFile(f.Path)
+	if err == nil && f.Found && json.Valid(data) {
+		if kind, reason := classifyJSON(data); kind != kindBundle {
+			e.Skipped = reason
+			if kind != "" {
+				e.Skipped = kind + ", not a bundle"
+			}
+			return e
+		}
+	}
+	var bundle gefverify.ProofBundle
+	if err == nil {
+		bundle, err = gefverify.ParseBundle(data)
+	}
+	if junit {
+		e.Timer = phaseTimer{}
+		opts.Metrics = e.Timer
+	}
+	if err == nil {
+		e.Bundle = &bundle
+		e.Report, err = gefverify.Verify(bundle, opts)
+	}
+	if err != nil {
+		e.Error = err.Error()
+		return e
+	}
+	e.summarize()
+	return e
+}
+
+// checkBatchNonces adds B.nonce_replay to every verified bundle of
+// entries that carries an agent_id and nonce, in batch order.
+func checkBatchNonces(entries []batchEntry, idx *nonceIndex) {
+	for i := range entries {
+		e := &entries[i]
+		if e.Bundle == nil || e.Verdict != gefverify.VerdictVerified {
+			continue
+		}
+		agentID, _ := e.Bundle.SigningDict["agent_id"].(string)
+		nonce, _ := e.Bundle.SigningDict["nonce"].(string)
+		if agentID == "" || nonce == "" {
+			continue
+		}
+		passed, details := idx.check(agentID, nonce, e.Path, e.Bundle.CausalHashOfThis)
+		c := gefverify.CheckResult{
+			ID:       "B.nonce_replay",
+			Section:  sectionNonces,
+			Name:     "nonce not seen in an earlier bundle",
+			Passed:   passed,
+			Details:  details,
+			Category: gefverify.CategoryIntegrity,
+		}
+		e.Report.Checks = append(e.Report.Checks, c)
+		e.Report.Verdict = gefverify.DeriveVerdict(e.Report.Checks)
+		e.summarize()
+	}
+}
+
+// verifyBatch verifies files on jobs goroutines and returns their
+// entries in batch order.
+func verifyBatch(files []batchFile, opts gefverify.VerifyOptions, junit bool, jobs int) []batchEntry {
+	type result struct {
+		i int
+		e batchEntry
+	}
+	work := make(chan int)
+	results := make(chan result)
+	for w := 0; w < jobs; w++ {
+		go func() {
+			for i := range work {
+				results <- result{i, verifyBatchFile(files[i], opts, junit)}
+			}
+		}()
+	}
+	go func() {
+		for i := range files {
+			work <- i
+		}
+		close(work)
+	}()
+	entries := make([]batchEntry, len(files))
+	for range files {
+		r := <-results
+		entries[r.i] = r.e
+	}
+	return entries
+}
+
+// batchOptions are the flags of verify_proof that shape a batch run.
+type batchOptions struct {
+	Format     string // text, json or junit
+	JUnitPath  string // also write JUnit XML here
+	ReportJSON string // also write the batch document here, - for docOut
+	Merkle     bool   // add the Merkle root
+	NonceDB    string // keep the nonces across runs in this file
+	AllowGaps  bool   // sequence gaps are warnings
+	Jobs       int
+	Level      outputLevel
+	Threshold  *failThreshold // -fail-threshold, nil if not given
+	Baseline   string         // batch document of an earlier run
+}
+
+// runBatch verifies every bundle in paths, b.Jobs at a time, and returns
+// the exit code. JUnit XML goes to b.JUnitPath if set and, with -format
+// junit, to docOut in place of the human report on stdout; -format json
+// puts the batch document there. With levelQuiet only the failures and
+// the verdict line are printed, the line to docOut. Under b.Threshold the
+// exit code is that of the failures it counts (threshold.go).
+func runBatch(files []batchFile, opts gefverify.VerifyOptions, b batchOptions, docOut io.Writer) int {
+	var known map[string]bool
+	if b.Baseline != "" {
+		var err error
+		if known, err = loadBaseline(b.Baseline); err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", b.Baseline, err)
+			return 2
+		}
+	}
+	nonces, err := loadNonceIndex(b.NonceDB)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", b.NonceDB, err)
+		return 2
+	}
+	nonces.warnSkipped()
+	junit := b.Format == "junit" || b.JUnitPath != ""
+	entries := verifyBatch(files, opts, junit, b.Jobs)
+	checkBatchNonces(entries, nonces)
+	checkBatchSequences(entries, b.AllowGaps)
+	if err := nonces.save(); err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", b.NonceDB, err)
+		return 2
+	}
+	var verdicts []gefverify.Verdict
+	var suites []junitSuite
+	for _, e := range entries {
+		if e.Skipped == "" {
+			verdicts = append(verdicts, e.Verdict)
+			if junit {
+				suites = append(suites, e.suite())
+			}
+		}
+	}
+	verdict := folderVerdict(verdicts)
+	exitVerdict := verdict
+	var threshold *thresholdResult
+	if b.Threshold != nil {
+		threshold = evaluateThreshold(entries, *b.Threshold, b.Baseline, known)
+		exitVerdict = threshold.verdict
+	}
+	var root, rootHex string
+	if b.Merkle {
+		rootHex, root = batchMerkleRoot(entries)
+	}
+	if b.JUnitPath != "" {
+		if err := writeJUnitFile(b.JUnitPath, suites); err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", b.JUnitPath, err)
+			return 2
+		}
+	}
+	reportJSON := b.ReportJSON
+	if b.Format == "json" {
+		reportJSON = "-"
+	}
+	if reportJSON != "" {
+		if err := writeBatchDocument(reportJSON, newBatchDocument(entries, verdict, rootHex, threshold), docOut); err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", reportJSON, err)
+			return 2
+		}
+	}
+	switch {
+	case b.Format == "junit":
+		if err := writeJUnit(docOut, suites); err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot write JUnit XML: %v\n", err)
+			return 2
+		}
+		if root != "" {
+			fmt.Fprintln(stderr, strings.TrimSpace(root))
+		}
+	case b.Format == "json":
+	case b.Level == levelQuiet:
+		printBatchQuiet(docOut, entries, verdict, root, threshold)
+	default:
+		printBatch(entries, root, threshold, opts.TrustedKeys != nil, b.Level)
+	}
+	return exitVerdict.ExitCode()
+}
+
+// batchMerkleRoot returns the Merkle root of entries in hex, "" if it
+// could not be computed, and the summary line for it.
+func batchMerkleRoot(entries []batchEntry) (string, string) {
+	var records []gefverify.ProofBundle
+	unparsed := 0
+	for _, e := range entries {
+		switch {
+		case e.Skipped != "":
+		case e.Bundle == nil:
+			unparsed++
+		default:
+			records = append(records, *e.Bundle)
+		}
+	}
+	if unparsed > 0 {
+		return "", fmt.Sprintf("  merkle root  not computed: %d bundle(s) could not be read or parsed", unparsed)
+	}
+	root, err := gefverify.MerkleRoot(records)
+	if err != nil {
+		return "", fmt.Sprintf("  merkle root  not computed: %v", err)
+	}
+	return fmt.Sprintf("%x", root), fmt.Sprintf("  merkle root  %x  (%d record(s), batch order)", root, len(records))
+}
+
+// printBatch prints a verdict line per bundle and the summary, with the
+// threshold evaluation if there is one, warning if key trust was not
+// checked; with levelVerbose every bundle's checks follow its line.
+func printBatch(entries []batchEntry, merkleRoot string, threshold *thresholdResult, keysTrusted bool, level outputLevel) {
+	bar := console.bar
+	rule := "  " + console.rule
+	fmt.Fprintln(stdout, "  BATCH — each bundle verified on its own")
+	fmt.Fprintln(stdout, rule)
+	var failing, skipped []batchEntry
+	for _, e := range entries {
+		mark := console.pass + " "
+		switch {
+		case e.Skipped != "":
+			skipped = append(skipped, e)
+			continue
+		case e.Verdict != gefverify.VerdictVerified:
+			mark = console.fail + " "
+			failing = append(failing, e)
+		}
+		if e.Total > 0 {
+			fmt.Fprintf(stdout, "  %s %-22s %s  (%d/%d checks)\n", mark, e.Verdict, e.Path, e.Passed, e.Total)
+		} else {
+			fmt.Fprintf(stdout, "  %s %-22s %s\n", mark, e.Verdict, e.Path)
+		}
+		if len(e.Failed) > 0 {
+			fmt.Fprintf(stdout, "       failed: %s\n", strings.Join(e.Failed, ", "))
+		}
+		if e.Error != "" {
+			fmt.Fprintf(stdout, "       error: %s\n", e.Error)
+		}
+		for _, w := range e.Report.Warnings {
+			fmt.Fprintf(stdout, "       %s %s\n", console.warn, w)
+		}
+		if level == levelVerbose && len(e.Report.Checks) > 0 {
+			fmt.Fprintln(stdout)
+			printChecks(e.Report.Checks)
+			fmt.Fprintln(stdout)
+		}
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, "  SKIPPED — not a proof bundle")
+		fmt.Fprintln(stdout, rule)
+		for _, e := range skipped {
+			fmt.Fprintf(stdout, "  %s  %s: %s\n", console.note, e.Path, e.Skipped)
+		}
+	}
+
+	verified := len(entries) - len(failing) - len(skipped)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintf(stdout, "  %d passed, %d failed of %d bundle(s)", verified, len(failing), verified+len(failing))
+	if len(skipped) > 0 {
+		fmt.Fprintf(stdout, ", %d file(s) skipped", len(skipped))
+	}
+	fmt.Fprintln(stdout)
+	for _, e := range failing {
+		fmt.Fprintf(stdout, "    %-22s %s\n", e.Verdict, e.Path)
+	}
+	printFailureDistribution(stdout, batchHistogram(entries))
+	if merkleRoot != "" {
+		fmt.Fprintln(stdout, merkleRoot)
+	}
+	if threshold != nil {
+		fmt.Fprintf(stdout, "  %s  %s\n", console.mark(!threshold.Exceeded), threshold.summary())
+	}
+	if !keysTrusted {
+		fmt.Fprintf(stdout, "  %s  %s\n", console.warn, keyTrustWarning)
+	}
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+}
+
+// printBatchQuiet is the -quiet form of printBatch: the failures of
+// every bundle on stderr, and the verdict of the batch, with the Merkle
+// root and the threshold evaluation if there are any, on w.
+func printBatchQuiet(w io.Writer, entries []batchEntry, verdict gefverify.Verdict, merkleRoot string, threshold *thresholdResult) {
+	verified, failing := 0, 0
+	for _, e := range entries {
+		switch {
+		case e.Skipped != "":
+			continue
+		case e.Verdict == gefverify.VerdictVerified:
+			verified++
+			continue
+		}
+		failing++
+		if e.Error != "" {
+			fmt.Fprintf(stderr, "FAILED: %s: %s\n", e.Path, e.Error)
+		}
+		printQuietFailures(e.Path, e.Report.Failed())
+	}
+	if h := batchHistogram(entries); len(h) > 0 {
+		fmt.Fprintf(stderr, "failure distribution: %s\n", quietDistribution(h))
+	}
+	fmt.Fprintf(w, "batch: %s  (%d passed, %d failed of %d bundle(s))\n", verdict, verified, failing, verified+failing)
+	if merkleRoot != "" {
+		fmt.Fprintln(w, strings.TrimSpace(merkleRoot))
+	}
+	if threshold != nil {
+		fmt.Fprintln(w, threshold.summary())
+	}
+}
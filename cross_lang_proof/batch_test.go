@@ -0,0 +1,189 @@
+// cross_lang_proof/batch_test.go
+
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// writeBatchDir writes a valid, a tampered and a malformed bundle, and
+// in a subdirectory another valid bundle and a report document, plus a
+// file batch mode must not pick up.
+func writeBatchDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	good := string(mustRead(t, "proof_bundle.json"))
+	must(t, os.Mkdir(filepath.Join(dir, "sub"), 0o755))
+	writeChainDir(t, filepath.Join(dir, "sub"), "deep", 1)
+	must(t, os.Rename(filepath.Join(dir, "sub", "deep-0.json"), filepath.Join(dir, "sub", "deep.json")))
+	for name, data := range map[string]string{
+		"good.json":       good,
+		"tampered.json":   tamperSignature(good),
+		"broken.json":     `{"signing_dict": `,
+		"notes.txt":       "not a bundle",
+		"sub/report.json": `{"schema_version": 1, "verdict": "VERIFIED"}`,
+	} {
+		must(t, os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(data), 0o644))
+	}
+	return dir
+}
+
+func TestBatchDir(t *testing.T) {
+	dir := writeBatchDir(t)
+	want := folderVerdict([]gefverify.Verdict{gefverify.VerdictTampered, gefverify.VerdictMalformed})
+	walked := []string{
+		"VERIFIED               " + filepath.Join(dir, "sub", "deep.json"),
+		"sub/report.json: report, not a bundle",
+		"2 passed, 2 failed of 4 bundle(s), 1 file(s) skipped",
+	}
+	for _, tt := range []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"-dir", dir}, walked},
+		{[]string{dir}, walked},
+		{[]string{filepath.Join(dir, "*.json")}, []string{"1 passed, 2 failed of 3 bundle(s)\n"}},
+		{[]string{filepath.Join(dir, "good.json"), filepath.Join(dir, "tampered.json"), filepath.Join(dir, "broken.json")},
+			[]string{"1 passed, 2 failed of 3 bundle(s)\n"}},
+	} {
+		code, out, errOut := runCaptured(t, tt.args...)
+		if code != want.ExitCode() {
+			t.Errorf("%v: exit %d, want %d\n%s%s", tt.args, code, want.ExitCode(), out, errOut)
+		}
+		for _, line := range append([]string{
+			"VERIFIED               " + filepath.Join(dir, "good.json"),
+			"TAMPERED               " + filepath.Join(dir, "tampered.json"),
+			"MALFORMED              " + filepath.Join(dir, "broken.json"),
+			"failed: C3.signature_go",
+			"error: cannot parse proof bundle",
+		}, tt.want...) {
+			if !strings.Contains(out, line) {
+				t.Errorf("%v: missing %q:\n%s", tt.args, line, out)
+			}
+		}
+		if strings.Contains(out, "notes.txt") {
+			t.Errorf("%v: picked up notes.txt:\n%s", tt.args, out)
+		}
+	}
+}
+
+func TestBatchNamedNonBundle(t *testing.T) {
+	dir := writeBatchDir(t)
+	code, out, _ := runCaptured(t, filepath.Join(dir, "good.json"), filepath.Join(dir, "sub", "report.json"))
+	if code == 0 || !strings.Contains(out, "MALFORMED              "+filepath.Join(dir, "sub", "report.json")) {
+		t.Errorf("a named file that is not a bundle must fail: exit %d\n%s", code, out)
+	}
+}
+
+func TestBatchAllVerified(t *testing.T) {
+	dir := writeBatchDir(t)
+	good := filepath.Join(dir, "good.json")
+	code, out, _ := runCaptured(t, good, good)
+	if code != 0 || !strings.Contains(out, "2 passed, 0 failed of 2 bundle(s)") {
+		t.Errorf("exit %d\n%s", code, out)
+	}
+}
+
+func TestBatchJUnit(t *testing.T) {
+	code, out, _ := runCaptured(t, "-format", "junit", writeBatchDir(t))
+	var doc junitSuites
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("stdout is not JUnit XML: %v\n%s", err, out)
+	}
+	if code == 0 || len(doc.Suites) != 4 {
+		t.Fatalf("exit %d, %d suites", code, len(doc.Suites))
+	}
+	// broken, good, sub/deep, tampered: in walk order; sub/report is skipped.
+	if s := doc.Suites[0]; s.Errors != 1 || s.Cases[0].Error == nil {
+		t.Errorf("malformed bundle suite %+v", s)
+	}
+	if doc.Suites[1].Failures != 0 || doc.Suites[3].Failures != 4 {
+		t.Errorf("failures %d, %d", doc.Suites[1].Failures, doc.Suites[3].Failures)
+	}
+}
+
+func TestBatchUsageErrors(t *testing.T) {
+	dir := writeBatchDir(t)
+	for _, args := range [][]string{
+		{"-dir", t.TempDir()},
+		{"-format", "sarif", dir},
+		{filepath.Join(dir, "*.nothing")},
+	} {
+		if code, _, _ := runCaptured(t, args...); code != 2 {
+			t.Errorf("%v: exit %d, want 2", args, code)
+		}
+	}
+}
+
+func TestBatchJUnitFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "TEST-gef.xml")
+	_, out, _ := runCaptured(t, "-junit", path, writeBatchDir(t))
+	var doc junitSuites
+	if err := xml.Unmarshal(mustRead(t, path), &doc); err != nil || len(doc.Suites) != 4 {
+		t.Fatalf("%v, %d suites", err, len(doc.Suites))
+	}
+	if !strings.Contains(out, "2 passed, 2 failed of 4 bundle(s)") {
+		t.Errorf("human report missing:\n%s", out)
+	}
+}
+
+func TestBatchMerkleRoot(t *testing.T) {
+	dir := writeBatchDir(t)
+	good, tampered := filepath.Join(dir, "good.json"), filepath.Join(dir, "tampered.json")
+	var records []gefverify.ProofBundle
+	for _, p := range []string{good, tampered, good} {
+		b, err := gefverify.ParseBundle(mustRead(t, p))
+		must(t, err)
+		records = append(records, b)
+	}
+	root, err := gefverify.MerkleRoot(records)
+	must(t, err)
+	want := fmt.Sprintf("merkle root  %x  (3 record(s), batch order)", root)
+
+	_, out, _ := runCaptured(t, "-merkle-root", good, tampered, good)
+	if !strings.Contains(out, want) {
+		t.Errorf("missing %q:\n%s", want, out)
+	}
+	_, _, errOut := runCaptured(t, "-merkle-root", "-format", "junit", good, tampered, good)
+	if !strings.Contains(errOut, want) {
+		t.Errorf("-format junit: missing %q on stderr:\n%s", want, errOut)
+	}
+	if _, out, _ := runCaptured(t, "-merkle-root", dir); !strings.Contains(out, "merkle root  not computed: 1 bundle(s) could not be read or parsed") {
+		t.Errorf("root computed despite broken.json:\n%s", out)
+	}
+	if code, _, _ := runCaptured(t, "-merkle-root", good); code != 2 {
+		t.Errorf("-merkle-root on a single bundle: exit %d, want 2", code)
+	}
+}
+
+func TestBatchJobs(t *testing.T) {
+	dir := writeBatchDir(t)
+	good := string(mustRead(t, "proof_bundle.json"))
+	for i := 0; i < 12; i++ {
+		data := good
+		if i%3 == 0 {
+			data = tamperSignature(good)
+		}
+		must(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("bundle-%02d.json", i)), []byte(data), 0o644))
+	}
+	wantCode, want, _ := runCaptured(t, "-merkle-root", dir)
+	for _, jobs := range []string{"2", "8", "64"} {
+		code, out, errOut := runCaptured(t, "-jobs", jobs, "-merkle-root", dir)
+		if code != wantCode || out != want {
+			t.Errorf("-jobs %s: exit %d, want %d; output differs from serial:\n%s%s", jobs, code, wantCode, out, errOut)
+		}
+	}
+
+	for _, args := range [][]string{{"-jobs", "0", dir}, {"-jobs", "4", filepath.Join(dir, "good.json")}} {
+		if code, _, _ := runCaptured(t, args...); code != 2 {
+			t.Errorf("%v: exit %d, want 2", args, code)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// cross_lang_proof/batchreport.go
+//
+// Batch summary: failure distribution and JSON document
+// =====================================================
+//
+//   verify_proof -dir ./bundles
+//   verify_proof -format json -dir ./bundles > batch.json
+//   verify_proof -report-json batch.json -dir ./bundles
+//
+// A batch with many failures is summarized by how often each check
+// failed, most common first, so "40 × C1.canonical_bytes, 3 ×
+// C3.signature_go" reads as a diagnosis rather than a wall of files.
+// A bundle that could not be read or parsed counts once under
+// histogramUnverified. The text summary ends with the distribution;
+// -quiet prints it on stderr with the failures.
+//
+// -format json writes the batch document alone on stdout, -report-json
+// to a file as well as the text report:
+//
+//   {"schema_version": 1, "verifier_version": "…", "verdict": "TAMPERED",
+//    "passed": 10, "failed": 2,
+//    "bundles": [{"path": "…", "verdict": "…", "passed": 18, "total": 18,
+//                 "failed": ["C1.canonical_bytes"], "error": "…"}, …],
+//    "skipped": [{"path": "…", "reason": "…"}],
+//    "failure_histogram": [{"code": "C1.canonical_bytes", "count": 40}, …],
+//    "merkle_root": "…",
+//    "threshold": {"threshold": "2%", "baseline": "…", "bundles": 12,
+//                  "failed": 2, "known": 1, "counted": 1, "exceeded": false}}
+//
+// failed and error are omitted when empty, merkle_root without
+// -merkle-root, threshold without -fail-threshold or -baseline.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// histogramUnverified is the histogram code of a bundle that could not
+// be verified at all.
+const histogramUnverified = "bundle not verified"
+
+// batchBundle is one verified, or unverifiable, bundle of a batch
+// document.
+type batchBundle struct {
+	Path    string            `json:"path"`
+	Verdict gefverify.Verdict `json:"verdict"`
+	Passed  int               `json:"passed"`
+	Total   int               `json:"total"`
+	Failed  []string          `json:"failed,omitempty"` // check IDs
+	Error   string            `json:"error,omitempty"`
+}
+
+// batchDocument is the -format json and -report-json form of a batch.
+type batchDocument struct {
+	SchemaVersion    int               `json:"schema_version"`
+	VerifierVersion  string            `json:"verifier_version"`
+	Verdict          gefverify.Verdict `json:"verdict"`
+	Passed           int               `json:"passed"`
+	Failed           int               `json:"failed"`
+	Bundles          []batchBundle     `json:"bundles"`
+	Skipped          []auditSkip       `json:"skipped"`
+	FailureHistogram []histogramEntry  `json:"failure_histogram"`
+	MerkleRoot       string            `json:"merkle_root,omitempty"`
+	Threshold        *thresholdResult  `json:"threshold,omitempty"` // threshold.go
+}
+
+// batchHistogram counts the failed checks of every failing bundle.
+func batchHistogram(entries []batchEntry) failureHistogram {
+	h := failureHistogram{}
+	for _, e := range entries {
+		if e.Skipped != "" || e.Verdict == gefverify.VerdictVerified {
+			continue
+		}
+		if e.Error != "" {
+			h[histogramUnverified]++
+		}
+		for _, id := range e.Failed {
+			h[id]++
+		}
+	}
+	return h
+}
+
+// printFailureDistribution prints h, most common first, under a heading.
+func printFailureDistribution(w io.Writer, h failureHistogram) {
+	if len(h) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "  Failure distribution:")
+	for _, e := range h.sorted() {
+		fmt.Fprintf(w, "  %6d  %s\n", e.Count, e.Code)
+	}
+}
+
+// newBatchDocument converts the entries of a batch with the given
+// verdict, Merkle root (hex, "" if not computed) and threshold
+// evaluation (nil without -fail-threshold).
+func newBatchDocument(entries []batchEntry, verdict gefverify.Verdict, merkleRoot string, threshold *thresholdResult) batchDocument {
+	doc := batchDocument{
+		SchemaVersion:    gefverify.ReportSchemaVersion,
+		VerifierVersion:  gefverify.Version,
+		Verdict:          verdict,
+		Bundles:          []batchBundle{},
+		Skipped:          []auditSkip{},
+		FailureHistogram: batchHistogram(entries).sorted(),
+		MerkleRoot:       merkleRoot,
+		Threshold:        threshold,
+	}
+	for _, e := range entries {
+		switch {
+		case e.Skipped != "":
+			doc.Skipped = append(doc.Skipped, auditSkip{e.Path, e.Skipped})
+			continue
+		case e.Verdict == gefverify.VerdictVerified:
+			doc.Passed++
+		default:
+			doc.Failed++
+		}
+		doc.Bundles = append(doc.Bundles, batchBundle{e.Path, e.Verdict, e.Passed, e.Total, e.Failed, e.Error})
+	}
+	return doc
+}
+
+// writeBatchDocument writes doc to path, or to w for "-".
+func writeBatchDocument(path string, doc batchDocument, w io.Writer) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	switch {
+	case err == nil && path == "-":
+		_, err = fmt.Fprintln(w, string(data))
+	case err == nil:
+		err = writeFileAtomic(path, append(data, '\n'), 0o644)
+	}
+	return err
+}
+
+// quietDistribution is the failure distribution on one line, for -quiet.
+func quietDistribution(h failureHistogram) string {
+	var parts []string
+	for _, e := range h.sorted() {
+		parts = append(parts, fmt.Sprintf("%d %s", e.Count, e.Code))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,68 @@
+// cross_lang_proof/batchreport_test.go
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+func TestBatchFailureDistribution(t *testing.T) {
+	dir := writeBatchDir(t)
+	must(t, os.WriteFile(filepath.Join(dir, "tampered-2.json"), mustRead(t, filepath.Join(dir, "tampered.json")), 0o644))
+	want := []histogramEntry{
+		{"C3.signature_go", 2},
+		{"C3.signature_python", 2},
+		{"C6.original_intact", 2},
+		{"C8.envelope_signature", 2},
+		{histogramUnverified, 1},
+	}
+
+	code, out, _ := runCaptured(t, "-dir", dir)
+	if code != gefverify.VerdictMalformed.ExitCode() || !strings.Contains(out, "  Failure distribution:\n       2  C3.signature_go\n") ||
+		!strings.Contains(out, "       1  "+histogramUnverified+"\n") {
+		t.Errorf("text: exit %d\n%s", code, out)
+	}
+	_, _, errOut := runCaptured(t, "-quiet", "-dir", dir)
+	if !strings.Contains(errOut, "failure distribution: 2 C3.signature_go, 2 C3.signature_python, 2 C6.original_intact, 2 C8.envelope_signature, 1 "+histogramUnverified+"\n") {
+		t.Errorf("-quiet: stderr %q", errOut)
+	}
+
+	code, out, _ = runCaptured(t, "-format", "json", "-dir", dir)
+	var doc batchDocument
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("-format json: %v\n%s", err, out)
+	}
+	if code != gefverify.VerdictMalformed.ExitCode() || doc.Verdict != gefverify.VerdictMalformed || doc.Passed != 2 || doc.Failed != 3 ||
+		len(doc.Bundles) != 5 || len(doc.Skipped) != 1 {
+		t.Errorf("-format json: exit %d, %+v", code, doc)
+	}
+	if !reflect.DeepEqual(doc.FailureHistogram, want) {
+		t.Errorf("failure_histogram %v, want %v", doc.FailureHistogram, want)
+	}
+
+	// -report-json writes the same document next to the text report.
+	path := filepath.Join(t.TempDir(), "batch.json")
+	_, out, _ = runCaptured(t, "-merkle-root", "-report-json", path, "-dir", dir)
+	var written batchDocument
+	must(t, json.Unmarshal(mustRead(t, path), &written))
+	if !strings.Contains(out, "Failure distribution:") || !reflect.DeepEqual(written.FailureHistogram, want) {
+		t.Errorf("-report-json: %+v\n%s", written, out)
+	}
+	if written.MerkleRoot != "" {
+		t.Errorf("merkle_root %q with an unparsed bundle", written.MerkleRoot)
+	}
+
+	_, out, _ = runCaptured(t, "-merkle-root", "-format", "json", "proof_bundle.json", "proof_bundle.json")
+	doc = batchDocument{}
+	must(t, json.Unmarshal([]byte(out), &doc))
+	if len(doc.MerkleRoot) != 64 || doc.FailureHistogram == nil || len(doc.FailureHistogram) != 0 {
+		t.Errorf("clean batch: merkle_root %q, failure_histogram %v", doc.MerkleRoot, doc.FailureHistogram)
+	}
+}
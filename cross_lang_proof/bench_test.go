@@ -0,0 +1,40 @@
+// cross_lang_proof/bench_test.go
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+func TestBench(t *testing.T) {
+	code, out, errOut := runCaptured(t, "-bench", "20ms", "proof_bundle.json")
+	if code != 0 || !strings.Contains(out, "CROSS-LANGUAGE PROOF PASSED") {
+		t.Fatalf("exit %d\n%s%s", code, out, errOut)
+	}
+	for _, want := range []string{"BENCH — ", " records/s", "canonicalize", "chain_hash", "signature", "total", "100.0%"} {
+		if !strings.Contains(errOut, want) {
+			t.Errorf("stderr lacks %q:\n%s", want, errOut)
+		}
+	}
+	if strings.Contains(out, "BENCH") {
+		t.Errorf("bench table on stdout:\n%s", out)
+	}
+
+	// The exit code is that of the verification.
+	path := filepath.Join(t.TempDir(), "tampered.json")
+	must(t, os.WriteFile(path, []byte(tamperSignature(string(mustRead(t, "proof_bundle.json")))), 0o644))
+	if code, _, errOut := runCaptured(t, "-bench", "10ms", path); code != gefverify.VerdictTampered.ExitCode() || !strings.Contains(errOut, "BENCH") {
+		t.Errorf("tampered: exit %d\n%s", code, errOut)
+	}
+
+	for _, args := range [][]string{{"-bench", "-1s", "proof_bundle.json"}, {"-bench", "1s", "proof_bundle.json", path}} {
+		if code, _, _ := runCaptured(t, args...); code != 2 {
+			t.Errorf("%v: exit %d, want 2", args, code)
+		}
+	}
+}
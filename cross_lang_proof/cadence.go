@@ -0,0 +1,237 @@
+// cross_lang_proof/cadence.go
+//
+// Signing cadence (chain -cadence)
+// ================================
+//
+// An agent that signs every 30 s and then emits 500 records in one second,
+// or goes silent for a day mid-chain, is worth a look even if every link
+// holds. With -cadence, pass 2 feeds each agent's inter-record intervals
+// (signing_dict.timestamp deltas, in causal order) into online statistics
+// and reports unusual intervals as FINDINGS — informational, never checks,
+// so the verdict is unaffected.
+//
+// Statistics are Welford-style (count, mean, M2, min, max per agent), so
+// memory is constant per agent however long the chain. An interval is
+// flagged when:
+//
+//   burst     shorter than -cadence-burst, or z below -cadence-z
+//   silence   longer than -cadence-silence, or z above +cadence-z
+//   backwards timestamp earlier than the previous record's
+//
+// z is computed against the agent's baseline so far — the unflagged
+// intervals — and only once cadenceMinSamples intervals are in it, so the
+// first records do not flag each other and a long burst does not become
+// its own baseline. Consecutive flagged intervals of the same kind merge
+// into one finding over a sequence range.
+//
+// -cadence-json writes the raw per-agent statistics over ALL intervals
+// plus the findings, for tooling that applies its own thresholds.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// cadenceMinSamples is the baseline size required before z-scores apply.
+const cadenceMinSamples = 10
+
+// welford accumulates count, mean and variance in one pass.
+type welford struct {
+	Count int64   `json:"count"`
+	Mean  float64 `json:"mean_seconds"`
+	M2    float64 `json:"m2"`
+	Min   float64 `json:"min_seconds"`
+	Max   float64 `json:"max_seconds"`
+}
+
+func (w *welford) add(x float64) {
+	w.Count++
+	if w.Count == 1 || x < w.Min {
+		w.Min = x
+	}
+	if w.Count == 1 || x > w.Max {
+		w.Max = x
+	}
+	delta := x - w.Mean
+	w.Mean += delta / float64(w.Count)
+	w.M2   += delta * (x - w.Mean)
+}
+
+// stddev is the sample standard deviation, 0 below two samples.
+func (w welford) stddev() float64 {
+	if w.Count < 2 {
+		return 0
+	}
+	return math.Sqrt(w.M2 / float64(w.Count-1))
+}
+
+// cadenceThresholds configures what counts as unusual. Zero disables a
+// threshold.
+type cadenceThresholds struct {
+	Z       float64
+	Burst   time.Duration
+	Silence time.Duration
+}
+
+// cadenceFinding is one run of unusual intervals for an agent.
+type cadenceFinding struct {
+	AgentID   string  `json:"agent_id"`
+	Kind      string  `json:"kind"` // burst, silence or backwards
+	FromSeq   int64   `json:"from_sequence"`
+	ToSeq     int64   `json:"to_sequence"`
+	Intervals int     `json:"intervals"`
+	Extreme   float64 `json:"extreme_seconds"` // shortest burst / longest silence
+	Reason    string  `json:"reason"`
+}
+
+func (f cadenceFinding) String() string {
+	return fmt.Sprintf("%s seq %d–%d: %s of %d interval(s), extreme %s (%s)",
+		f.AgentID, f.FromSeq, f.ToSeq, f.Kind, f.Intervals, seconds(f.Extreme), f.Reason)
+}
+
+// agentCadence is the per-agent state; constant size.
+type agentCadence struct {
+	All      welford // every interval, exposed as raw stats
+	baseline welford // unflagged intervals, used for z
+	lastSeq  int64
+	lastTime time.Time
+	open     *cadenceFinding // run being extended, if any
+}
+
+// cadenceTracker consumes (agent, sequence, timestamp) in causal order.
+type cadenceTracker struct {
+	thresholds cadenceThresholds
+	agents     map[string]*agentCadence
+	findings   []cadenceFinding
+}
+
+func newCadenceTracker(th cadenceThresholds) *cadenceTracker {
+	return &cadenceTracker{thresholds: th, agents: map[string]*agentCadence{}}
+}
+
+// observe records the next record of agentID.
+func (c *cadenceTracker) observe(agentID string, seq int64, ts time.Time) {
+	a, seen := c.agents[agentID]
+	if !seen {
+		c.agents[agentID] = &agentCadence{lastSeq: seq, lastTime: ts}
+		return
+	}
+	interval := ts.Sub(a.lastTime).Seconds()
+	from := a.lastSeq
+	a.lastSeq, a.lastTime = seq, ts
+	a.All.add(interval)
+
+	kind, reason := c.classify(a, interval)
+	if kind == "" {
+		a.baseline.add(interval)
+		c.close(a)
+		return
+	}
+	if a.open != nil && a.open.Kind == kind {
+		a.open.ToSeq = seq
+		a.open.Intervals++
+		if kind == "silence" {
+			a.open.Extreme = math.Max(a.open.Extreme, interval)
+		} else {
+			a.open.Extreme = math.Min(a.open.Extreme, interval)
+		}
+		return
+	}
+	c.close(a)
+	a.open = &cadenceFinding{AgentID: agentID, Kind: kind, FromSeq: from, ToSeq: seq,
+		Intervals: 1, Extreme: interval, Reason: reason}
+}
+
+// classify names an unusual interval, or returns "" for a normal one.
+func (c *cadenceTracker) classify(a *agentCadence, interval float64) (kind, reason string) {
+	th := c.thresholds
+	switch {
+	case interval < 0:
+		return "backwards", "timestamp earlier than previous record"
+	case th.Burst > 0 && interval < th.Burst.Seconds():
+		return "burst", "below -cadence-burst " + th.Burst.String()
+	case th.Silence > 0 && interval > th.Silence.Seconds():
+		return "silence", "above -cadence-silence " + th.Silence.String()
+	}
+	if th.Z <= 0 || a.baseline.Count < cadenceMinSamples {
+		return "", ""
+	}
+	// Timestamps carry milliseconds, so spread below that is noise.
+	sd := math.Max(a.baseline.stddev(), 0.001)
+	z := (interval - a.baseline.Mean) / sd
+	switch {
+	case z < -th.Z:
+		return "burst", fmt.Sprintf("z < -%g vs mean %s", th.Z, seconds(a.baseline.Mean))
+	case z > th.Z:
+		return "silence", fmt.Sprintf("z > %g vs mean %s", th.Z, seconds(a.baseline.Mean))
+	}
+	return "", ""
+}
+
+func (c *cadenceTracker) close(a *agentCadence) {
+	if a.open != nil {
+		c.findings = append(c.findings, *a.open)
+		a.open = nil
+	}
+}
+
+// finish flushes open findings; call once after the last record.
+func (c *cadenceTracker) finish() []cadenceFinding {
+	for _, id := range c.agentIDs() {
+		c.close(c.agents[id])
+	}
+	return c.findings
+}
+
+func (c *cadenceTracker) agentIDs() []string {
+	ids := make([]string, 0, len(c.agents))
+	for id := range c.agents {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// cadenceAgentDocument is the raw statistics of one agent.
+type cadenceAgentDocument struct {
+	AgentID string  `json:"agent_id"`
+	StdDev  float64 `json:"stddev_seconds"`
+	welford
+}
+
+// cadenceDocument is what -cadence-json writes.
+type cadenceDocument struct {
+	Thresholds struct {
+		Z              float64 `json:"z"`
+		BurstSeconds   float64 `json:"burst_seconds"`
+		SilenceSeconds float64 `json:"silence_seconds"`
+		MinSamples     int     `json:"min_samples"`
+	} `json:"thresholds"`
+	Agents   []cadenceAgentDocument `json:"agents"`
+	Findings []cadenceFinding       `json:"findings"`
+}
+
+// document returns the raw statistics and findings; call after finish.
+func (c *cadenceTracker) document() cadenceDocument {
+	var doc cadenceDocument
+	doc.Thresholds.Z              = c.thresholds.Z
+	doc.Thresholds.BurstSeconds   = c.thresholds.Burst.Seconds()
+	doc.Thresholds.SilenceSeconds = c.thresholds.Silence.Seconds()
+	doc.Thresholds.MinSamples     = cadenceMinSamples
+	doc.Agents   = []cadenceAgentDocument{}
+	doc.Findings = append([]cadenceFinding{}, c.findings...)
+	for _, id := range c.agentIDs() {
+		a := c.agents[id]
+		doc.Agents = append(doc.Agents, cadenceAgentDocument{AgentID: id, StdDev: a.All.stddev(), welford: a.All})
+	}
+	return doc
+}
+
+// seconds formats a float second count as a duration.
+func seconds(s float64) string {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond).String()
+}
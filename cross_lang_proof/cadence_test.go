@@ -0,0 +1,106 @@
+// cross_lang_proof/cadence_test.go
+
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWelfordMatchesTwoPass(t *testing.T) {
+	xs := []float64{30, 29.5, 31, 30.2, 28.9, 30.4}
+	var w welford
+	var sum float64
+	for _, x := range xs {
+		w.add(x)
+		sum += x
+	}
+	mean := sum / float64(len(xs))
+	var ss float64
+	for _, x := range xs {
+		ss += (x - mean) * (x - mean)
+	}
+	sd := math.Sqrt(ss / float64(len(xs)-1))
+	if math.Abs(w.Mean-mean) > 1e-9 || math.Abs(w.stddev()-sd) > 1e-9 || w.Min != 28.9 || w.Max != 31 {
+		t.Errorf("welford = %+v stddev %g, want mean %g stddev %g", w, w.stddev(), mean, sd)
+	}
+}
+
+// feed observes n records of agent, step apart, starting after at.
+func feed(c *cadenceTracker, agent string, seq *int64, at *time.Time, n int, step time.Duration) {
+	for i := 0; i < n; i++ {
+		*at = at.Add(step)
+		c.observe(agent, *seq, *at)
+		*seq++
+	}
+}
+
+func TestCadenceBurstAndSilence(t *testing.T) {
+	c := newCadenceTracker(cadenceThresholds{Z: 4})
+	at, seq := time.Date(2026, 2, 24, 12, 0, 0, 0, time.UTC), int64(0)
+	c.observe("agent-a", seq, at)
+	seq++
+	for i := 0; i < 50; i++ {
+		// Regular 30 s with a little jitter.
+		feed(c, "agent-a", &seq, &at, 1, 30*time.Second+time.Duration(i%3)*100*time.Millisecond)
+	}
+	feed(c, "agent-a", &seq, &at, 500, 2*time.Millisecond)
+	feed(c, "agent-a", &seq, &at, 1, 30*time.Second)
+	feed(c, "agent-a", &seq, &at, 1, 24*time.Hour)
+	feed(c, "agent-a", &seq, &at, 5, 30*time.Second)
+
+	findings := c.finish()
+	if len(findings) != 2 {
+		t.Fatalf("findings = %v, want a burst and a silence", findings)
+	}
+	burst, silence := findings[0], findings[1]
+	if burst.Kind != "burst" || burst.Intervals != 500 || burst.FromSeq != 50 || burst.ToSeq != 550 {
+		t.Errorf("burst = %v", burst)
+	}
+	if silence.Kind != "silence" || silence.Intervals != 1 || silence.Extreme != (24*time.Hour).Seconds() {
+		t.Errorf("silence = %v", silence)
+	}
+}
+
+func TestCadenceAbsoluteThresholdsAndBackwards(t *testing.T) {
+	c := newCadenceTracker(cadenceThresholds{Burst: time.Second, Silence: time.Hour})
+	at, seq := time.Date(2026, 2, 24, 12, 0, 0, 0, time.UTC), int64(0)
+	c.observe("b", seq, at)
+	seq++
+	feed(c, "b", &seq, &at, 2, 500*time.Millisecond)
+	feed(c, "b", &seq, &at, 1, 2*time.Hour)
+	feed(c, "b", &seq, &at, 1, -time.Minute)
+
+	var kinds []string
+	for _, f := range c.finish() {
+		kinds = append(kinds, f.Kind)
+	}
+	if got := strings.Join(kinds, ","); got != "burst,silence,backwards" {
+		t.Errorf("kinds = %s", got)
+	}
+}
+
+func TestCadenceDocument(t *testing.T) {
+	c := newCadenceTracker(cadenceThresholds{Z: 3})
+	start := time.Date(2026, 2, 24, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		c.observe("a", int64(i), start.Add(time.Duration(i)*10*time.Second))
+	}
+	c.finish()
+	data, err := json.Marshal(c.document())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Agents []map[string]interface{} `json:"agents"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Agents) != 1 || doc.Agents[0]["count"] != 3.0 || doc.Agents[0]["mean_seconds"] != 10.0 {
+		t.Errorf("document = %s", data)
+	}
+}
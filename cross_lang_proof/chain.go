@@ -0,0 +1,832 @@
+// cross_lang_proof/chain.go
+//
+// GEF Chain Verification — causal order across bundle directories
+// ================================================================
+//
+//   verify_proof chain [-manifest order.json] <dir|file> [dir|file...]
+//
+// Inputs are directories, whose *.json bundles are read, and bundle
+// files. A file holding a JSON array of bundles is a chain on its own;
+// its records are named file#index in the report.
+//
+// Per-record proof bundles are usually named by UUID, so filename order
+// says nothing about causal order. The chain command establishes the order
+// first and only then checks linkage:
+//
+//   -manifest   JSON array of filenames or record_ids, in causal order.
+//               Entries are matched by path first, then by record_id. A
+//               path matches the file it names, or any file it is the
+//               trailing part of: "x.json" matches a/x.json unless b/x.json
+//               exists too, which needs "a/x.json". An entry matching more
+//               than one record, or a record listed twice, is an error.
+//               Records on disk but absent from the manifest (and vice
+//               versa) are reported as failures.
+//
+//   (default)   Two-pass mode. Pass 1 reads every *.json bundle and keeps
+//               only a small tuple per record (file, agent, sequence,
+//               record_id, hashes). Bundles themselves are not retained,
+//               so memory is bounded by the tuple size, not bundle size.
+//               The order is then (agent_id, sequence).
+//
+// Every record is verified under all contracts, as the verify command
+// does; pass 2 reports the checks a record fails and checks links only
+// between records that verify.
+//
+// Linkage rule (GEF-SPEC-v1.0): within one agent_id, record N's
+// signing_dict.causal_hash must equal the chain hash of record N-1,
+// H(JCS(chain_dict)) with H its hash_alg (SHA-256 when absent), as
+// CONTRACT 2 computes it. The first record of an agent at sequence 0
+// must link to the all-zero genesis hash.
+//
+// With -ref-pointer, a third pass checks cross-references between
+// records found in the payload (see refs.go). With -cadence, signing
+// intervals are summarized per agent (see cadence.go). With
+// -metrics-textfile, run metrics are written for a textfile collector
+// (see openmetrics.go). -chain-rules sets pass 2 strictness per
+// record_type (see chainrules.go) and turns on the amendment checks
+// (see amendments.go). -snapshot writes the per-agent heads
+// of a verified chain (see snapshot.go); -since, -trusted-head and
+// -from-snapshot verify only the tail of a chain (see since.go). -shard
+// splits the corpus by agent across machines (see shard.go). Records
+// are checked for nonce replay, across runs with -nonce-db (see
+// nonces.go), and each agent's sequence for gaps and duplicates, gaps
+// as warnings with -allow-gaps (see sequences.go).
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+const genesisCausalHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// chainRun collects and prints the checks of one chain verification.
+type chainRun struct {
+	results  []gefverify.CheckResult
+	failures failureHistogram
+	metrics  *chainMetrics
+	agent    string // agent_id the current checks are about, if any
+	warnings int    // rule findings at warn severity (chainrules.go)
+}
+
+func newChainRun() *chainRun {
+	return &chainRun{failures: make(failureHistogram), metrics: newChainMetrics()}
+}
+
+// passedSince reports whether every check recorded from index i on passed.
+func (c *chainRun) passedSince(i int) bool {
+	for _, r := range c.results[i:] {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// check records and prints a result, counting it under code if failed.
+func (c *chainRun) check(category gefverify.Category, code, name string, passed bool, details string) {
+	r := gefverify.CheckResult{
+		ID:       "chain." + strings.ReplaceAll(code, " ", "_"),
+		Name:     name,
+		Passed:   passed,
+		Details:  details,
+		Category: category,
+	}
+	c.results = append(c.results, r)
+	printCheck(r)
+	if !passed {
+		c.failures[code]++
+		c.metrics.CheckFailures[r.ID]++
+		if c.agent != "" {
+			c.metrics.AgentFailures[c.agent]++
+		}
+	}
+}
+
+// carry records and prints a failed check of a record's own
+// verification, counting it under its check ID.
+func (c *chainRun) carry(r gefverify.CheckResult) {
+	c.results = append(c.results, r)
+	printCheck(r)
+	c.failures[r.ID]++
+	c.metrics.CheckFailures[r.ID]++
+	if c.agent != "" {
+		c.metrics.AgentFailures[c.agent]++
+	}
+}
+
+// failureHistogram counts failing checks by failure code, so a run with
+// many failures summarizes as "40 broken link, 3 record unverified"
+// instead of a wall of per-file lines.
+type failureHistogram map[string]int
+
+type histogramEntry struct {
+	Code  string `json:"code"`
+	Count int    `json:"count"`
+}
+
+// sorted returns entries by descending count, then code.
+func (h failureHistogram) sorted() []histogramEntry {
+	entries := make([]histogramEntry, 0, len(h))
+	for code, n := range h {
+		entries = append(entries, histogramEntry{code, n})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Code < entries[j].Code
+	})
+	return entries
+}
+
+// chainTuple is everything pass 1 keeps about a record.
+type chainTuple struct {
+	File       string
+	AgentID    string
+	Sequence   int64
+	RecordID   string
+	RecordType string
+	Nonce      string
+	CausalHash string                  // link to previous record, from signing_dict
+	ChainHash  string                  // H(JCS(chain_dict)) under hash_alg, "" unless C4 passed
+	Verified   bool                    // every contract executed, every check passed
+	Failed     []gefverify.CheckResult // checks the record failed, Details prefixed with File
+	Refs       []recordRef             // cross-references at -ref-pointer paths
+	Timestamp  time.Time               // zero if signing_dict.timestamp does not parse
+	Amendment  *amendment              // set for record_type amendment (amendments.go)
+}
+
+// chainVerifier verifies every record pass 1 reads.
+var chainVerifier = gefverify.NewVerifier()
+
+// readChainTuple parses one bundle, verifies it and reduces it to a
+// chainTuple, keeping the payload references found at refPointers. A
+// record of an agent shard does not own is returned as errOtherShard
+// before any hashing or signature work.
+func readChainTuple(path string, refPointers []string, shard shardSpec) (chainTuple, error) {
+	t := chainTuple{File: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return t, err
+	}
+	bundle, err := gefverify.ParseBundle(data)
+	if err != nil {
+		return t, err
+	}
+
+	t.AgentID, _    = bundle.SigningDict["agent_id"].(string)
+	if !shard.owns(partitionKey(t.AgentID, path)) {
+		return t, errOtherShard
+	}
+	report, _ := chainVerifier.Verify(bundle)
+	return bundleTuple(t, bundle, report, refPointers)
+}
+
+// bundleTuple reduces a parsed bundle, verified into report, to t, which
+// carries its File.
+func bundleTuple(t chainTuple, bundle gefverify.ProofBundle, report gefverify.Report, refPointers []string) (chainTuple, error) {
+	t.AgentID, _    = bundle.SigningDict["agent_id"].(string)
+	t.RecordID, _   = bundle.SigningDict["record_id"].(string)
+	t.RecordType, _ = bundle.SigningDict["record_type"].(string)
+	t.Nonce, _      = bundle.SigningDict["nonce"].(string)
+	t.CausalHash, _ = bundle.SigningDict["causal_hash"].(string)
+	seq, ok := bundle.SigningDict["sequence"].(float64)
+	if !ok {
+		return t, fmt.Errorf("signing_dict.sequence missing or not a number")
+	}
+	t.Sequence = int64(seq)
+	t.Refs     = extractRefs(bundle.SigningDict["payload"], refPointers)
+	if t.RecordType == recordTypeAmendment {
+		t.Amendment = parseAmendment(bundle.SigningDict["payload"])
+	}
+	if ts, ok := bundle.SigningDict["timestamp"].(string); ok {
+		t.Timestamp, _ = time.Parse(time.RFC3339Nano, ts)
+	}
+
+	t.Verified = report.OK()
+	for _, c := range report.Failed() {
+		c.Details = fmt.Sprintf("%s: %s", filepath.Base(t.File), c.Details)
+		c.Diagnostics = nil
+		t.Failed = append(t.Failed, c)
+	}
+
+	// chain_dict is not signed: it stands for the record only where C4
+	// found it equal to signing_dict, and only then is it hashed.
+	if !passedCheck(report, "C4.dict_identity") {
+		return t, nil
+	}
+	chainBytes, err := canonicalDict(bundle.RawChainDict(), bundle.ChainDict)
+	if err != nil {
+		return t, fmt.Errorf("canonicalize chain_dict: %w", err)
+	}
+	t.ChainHash, _ = chainVerifier.ChainHashHex(bundle.HashAlg, chainBytes)
+	return t, nil
+}
+
+// passedCheck reports whether report holds the check id and it passed.
+func passedCheck(report gefverify.Report, id string) bool {
+	for _, c := range report.Checks {
+		if c.ID == id {
+			return c.Passed
+		}
+	}
+	return false
+}
+
+// canonicalDict canonicalizes a dict from the JSON it was parsed from,
+// when there is that JSON, rather than re-marshaling the map: on a large
+// chain the marshal is most of pass 1.
+func canonicalDict(raw json.RawMessage, dict map[string]interface{}) ([]byte, error) {
+	if raw != nil {
+		return gefverify.CanonicalizeRaw(raw)
+	}
+	return gefverify.Canonicalize(dict)
+}
+
+// chainRead is one record read in pass 1.
+type chainRead struct {
+	t   chainTuple
+	err error
+}
+
+// readChainFile reads the record in path, or every record of a JSON array
+// of bundles, as readChainTuple does.
+func readChainFile(path string, refPointers []string, shard shardSpec) []chainRead {
+	if !isJSONArray(path) {
+		t, err := readChainTuple(path, refPointers, shard)
+		return []chainRead{{t, err}}
+	}
+	var elems []json.RawMessage
+	data, err := os.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, &elems)
+	}
+	if err != nil {
+		return []chainRead{{chainTuple{File: path}, err}}
+	}
+	reads := make([]chainRead, 0, len(elems))
+	for i, raw := range elems {
+		t := chainTuple{File: fmt.Sprintf("%s#%d", path, i)}
+		bundle, err := gefverify.ParseBundle(raw)
+		if err == nil {
+			t.AgentID, _ = bundle.SigningDict["agent_id"].(string)
+			if !shard.owns(partitionKey(t.AgentID, t.File)) {
+				err = errOtherShard
+			}
+		}
+		if err == nil {
+			report, _ := chainVerifier.Verify(bundle)
+			t, err = bundleTuple(t, bundle, report, refPointers)
+		}
+		reads = append(reads, chainRead{t, err})
+	}
+	return reads
+}
+
+// isJSONArray reports whether the file at path starts with a JSON array.
+func isJSONArray(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, _ := f.Read(head)
+	head = bytes.TrimLeft(head[:n], " \t\r\n")
+	return len(head) > 0 && head[0] == '['
+}
+
+// listBundleFiles returns every *.json file directly inside the
+// directories among args, sorted, and the other args as given.
+func listBundleFiles(args []string) ([]string, error) {
+	var files []string
+	for _, dir := range args {
+		if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+			files = append(files, dir)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readManifest loads a JSON array of filenames or record_ids.
+func readManifest(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("manifest must be a JSON array of strings: %w", err)
+	}
+	return entries, nil
+}
+
+// orderByManifest arranges tuples in manifest order and reports both
+// directions of set difference. An entry that matches several records,
+// or a record two entries match, is an error.
+func orderByManifest(tuples []chainTuple, manifest []string) (ordered []chainTuple, notOnDisk, notInManifest []string, err error) {
+	byBase := make(map[string][]int)
+	byRecord := make(map[string][]int)
+	for i, t := range tuples {
+		base := filepath.Base(t.File)
+		byBase[base] = append(byBase[base], i)
+		if t.RecordID != "" {
+			byRecord[t.RecordID] = append(byRecord[t.RecordID], i)
+		}
+	}
+
+	listed := make(map[int]string)
+	for _, entry := range manifest {
+		want := filepath.Clean(filepath.FromSlash(entry))
+		var matches []int
+		for _, i := range byBase[filepath.Base(want)] {
+			if namesFile(want, tuples[i].File) {
+				matches = append(matches, i)
+			}
+		}
+		if len(matches) == 0 {
+			matches = byRecord[entry]
+		}
+		switch len(matches) {
+		case 0:
+			notOnDisk = append(notOnDisk, entry)
+			continue
+		case 1:
+		default:
+			var files []string
+			for _, i := range matches {
+				files = append(files, tuples[i].File)
+			}
+			return nil, nil, nil, fmt.Errorf("manifest entry %q matches %d records (%s); name one by a longer path", entry, len(matches), strings.Join(files, ", "))
+		}
+		i := matches[0]
+		if first, dup := listed[i]; dup {
+			return nil, nil, nil, fmt.Errorf("manifest lists %s twice, as %q and %q", tuples[i].File, first, entry)
+		}
+		listed[i] = entry
+		ordered = append(ordered, tuples[i])
+	}
+	for i, t := range tuples {
+		if _, ok := listed[i]; !ok {
+			notInManifest = append(notInManifest, t.File)
+		}
+	}
+	return ordered, notOnDisk, notInManifest, nil
+}
+
+// namesFile reports whether want, a cleaned manifest path, names file:
+// the file itself or a trailing part of its path.
+func namesFile(want, file string) bool {
+	file = filepath.Clean(filepath.FromSlash(file))
+	return file == want || strings.HasSuffix(file, string(filepath.Separator)+want)
+}
+
+// orderBySequence is the two-pass default: (agent_id, sequence, file).
+func orderBySequence(tuples []chainTuple) []chainTuple {
+	ordered := append([]chainTuple(nil), tuples...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.AgentID != b.AgentID {
+			return a.AgentID < b.AgentID
+		}
+		if a.Sequence != b.Sequence {
+			return a.Sequence < b.Sequence
+		}
+		return a.File < b.File
+	})
+	return ordered
+}
+
+// runChain implements the chain subcommand and returns the exit code.
+func runChain(args []string) int {
+	fs := newFlagSet("chain")
+	manifestPath := fs.String("manifest", "",
+		"JSON array of filenames or record_ids giving the causal `order`")
+	var refPointers stringList
+	fs.Var(&refPointers, "ref-pointer",
+		"JSON `pointer` into payload holding causal-hash references (repeatable)")
+	closedWorld := fs.Bool("require-closed-world", false,
+		"fail references to records outside the corpus instead of noting them")
+	cadence := fs.Bool("cadence", false,
+		"report unusual signing intervals per agent (informational)")
+	var th cadenceThresholds
+	fs.Float64Var(&th.Z, "cadence-z", 4,
+		"flag intervals more than `z` standard deviations from the agent's mean")
+	fs.DurationVar(&th.Burst, "cadence-burst", 0,
+		"flag intervals shorter than this `duration`")
+	fs.DurationVar(&th.Silence, "cadence-silence", 0,
+		"flag intervals longer than this `duration`")
+	cadenceJSON := fs.String("cadence-json", "",
+		"write raw per-agent interval statistics and findings to this `file`")
+	rulesPath := fs.String("chain-rules", "",
+		"JSON `file` of per-record_type chain rules (gaps, timestamps, intervals, linkage)")
+	metricsFile := fs.String("metrics-textfile", "",
+		"atomically write run metrics in OpenMetrics text format to this `file`")
+	maxAgents := fs.Int("metrics-max-agents", defaultMaxAgentSeries,
+		"per-agent failure series in -metrics-textfile; the rest sum into agent_id=\"_other\"")
+	snapshotPath := fs.String("snapshot", "",
+		"if the chain verifies, write its per-agent heads as a binary snapshot to this `file`")
+	sinceFlag := fs.String("since", "",
+		"verify only records from this RFC 3339 `time or sequence` on; earlier history is assumed")
+	trustedHead := fs.String("trusted-head", "",
+		"chain `hash` the first verified record must link to")
+	fromSnapshot := fs.String("from-snapshot", "",
+		"start from the per-agent heads in this snapshot `file` (from chain -snapshot)")
+	shardFlag := fs.String("shard", "",
+		"verify only the chains of shard `i/N` (by agent_id); see report merge")
+	shardReportPath := fs.String("shard-report", "",
+		"write this shard's claimed records and report to this `file`, input for report merge")
+	nonceDB := fs.String("nonce-db", "",
+		"also detect nonce replays against earlier runs, kept in this `file` (nonces.go)")
+	allowGaps := fs.Bool("allow-gaps", false,
+		"report sequence gaps as warnings; duplicates still fail (sequences.go)")
+	level := levelFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof chain [-quiet | -verbose] [-manifest order.json] [-ref-pointer /ptr ...] [-require-closed-world] [-chain-rules r.json] [-cadence ...] [-metrics-textfile f] [-snapshot f] [-since t|seq] [-trusted-head h | -from-snapshot f] [-shard i/N] [-shard-report f] [-nonce-db f] [-allow-gaps] <dir|file> [dir|file...]")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+	if fs.NArg() == 0 || *maxAgents < 0 {
+		fs.Usage()
+		return 2
+	}
+	lvl, err := level()
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %v\n", err)
+		return 2
+	}
+	verdictOut := io.Writer(stdout)
+	if lvl == levelQuiet {
+		var restore func()
+		verdictOut, restore = silenceStdout()
+		defer restore()
+	}
+	start := time.Now()
+
+	rules := &chainRules{Default: builtinChainRule}
+	if *rulesPath != "" {
+		var err error
+		if rules, err = loadChainRules(*rulesPath); err != nil {
+			fmt.Fprintf(stderr, "FATAL: %v\n", err)
+			return 2
+		}
+	}
+	since, err := parseSince(*sinceFlag)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %v\n", err)
+		return 2
+	}
+	shard, err := parseShard(*shardFlag)
+	if err == nil && shard.sharded() && (*manifestPath != "" || len(refPointers) > 0) {
+		err = fmt.Errorf("-manifest and -ref-pointer need the whole corpus; give the manifest to report merge instead")
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %v\n", err)
+		return 2
+	}
+	heads, err := loadTrustedHeads(*trustedHead, *fromSnapshot)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %v\n", err)
+		var pathErr *os.PathError
+		if *fromSnapshot != "" && *trustedHead == "" && !errors.As(err, &pathErr) {
+			return gefverify.VerdictMalformed.ExitCode()
+		}
+		return 2
+	}
+	nonces, err := loadNonceIndex(*nonceDB)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", *nonceDB, err)
+		return 2
+	}
+	nonces.warnSkipped()
+
+	bar := console.bar
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout, "  GEF Chain Verification — Go Verifier")
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+
+	files, err := listBundleFiles(fs.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot list bundles: %v\n", err)
+		return 2
+	}
+	if len(files) == 0 {
+		fmt.Fprintf(stderr, "FATAL: no *.json bundles in %s\n", strings.Join(fs.Args(), ", "))
+		return 2
+	}
+
+	// ── Pass 1: reduce every bundle to a tuple ────────────────
+	fmt.Fprintln(stdout, "  PASS 1 — Read records")
+	fmt.Fprintln(stdout, "  " + console.rule)
+
+	run := newChainRun()
+	root := corpusRoot(fs.Args())
+	var tuples []chainTuple
+	var claimed []shardRecord
+	for _, f := range files {
+		readStart := time.Now()
+		for _, r := range readChainFile(f, refPointers, shard) {
+			t, err := r.t, r.err
+			if errors.Is(err, errOtherShard) || (err != nil && !shard.owns(partitionKey(t.AgentID, t.File))) {
+				continue
+			}
+			run.metrics.RecordSeconds.observe(time.Since(readStart).Seconds())
+			readStart = time.Now()
+			claimed = append(claimed, shardRecord{File: corpusName(root, t.File), AgentID: t.AgentID, RecordID: t.RecordID, Readable: err == nil})
+			if err != nil {
+				run.check(gefverify.CategoryStructure, "unreadable bundle", fmt.Sprintf("%s readable", filepath.Base(t.File)), false, err.Error())
+				continue
+			}
+			tuples = append(tuples, t)
+		}
+	}
+	fmt.Fprintf(stdout, "  %d file(s), %d record(s) read\n", len(files), len(tuples))
+	if shard.sharded() {
+		fmt.Fprintf(stdout, "  shard %s: %d of %d file(s) claimed by agent_id\n", shard, len(claimed), len(files))
+	}
+
+	// ── Order ─────────────────────────────────────────────────
+	fmt.Fprintln(stdout)
+	var ordered []chainTuple
+	if *manifestPath != "" {
+		fmt.Fprintf(stdout, "  ORDER — manifest %s\n", *manifestPath)
+		fmt.Fprintln(stdout, "  " + console.rule)
+		manifest, err := readManifest(*manifestPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot read manifest %s: %v\n", *manifestPath, err)
+			return 2
+		}
+		var notOnDisk, notInManifest []string
+		ordered, notOnDisk, notInManifest, err = orderByManifest(tuples, manifest)
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: manifest %s: %v\n", *manifestPath, err)
+			return 2
+		}
+		run.check(gefverify.CategoryCompleteness, "manifest entry absent on disk", "every manifest entry present on disk", len(notOnDisk) == 0,
+			fmt.Sprintf("%d missing", len(notOnDisk)))
+		for _, e := range notOnDisk {
+			fmt.Fprintf(stdout, "       absent on disk   : %s\n", e)
+		}
+		run.check(gefverify.CategoryCompleteness, "record absent from manifest", "every record on disk listed in manifest", len(notInManifest) == 0,
+			fmt.Sprintf("%d unlisted", len(notInManifest)))
+		for _, f := range notInManifest {
+			fmt.Fprintf(stdout, "       absent in manifest: %s\n", f)
+		}
+	} else {
+		fmt.Fprintln(stdout, "  ORDER — two-pass (agent_id, sequence)")
+		fmt.Fprintln(stdout, "  " + console.rule)
+		ordered = orderBySequence(tuples)
+		fmt.Fprintf(stdout, "  %d record(s) ordered\n", len(ordered))
+	}
+
+	// ── Window (optional) ─────────────────────────────────────
+	sliced, leftOut := since.Set || heads != nil, 0
+	if sliced {
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, "  WINDOW — earlier history assumed, not verified")
+		fmt.Fprintln(stdout, "  " + console.rule)
+		ordered, leftOut = sliceWindow(ordered, since, heads)
+		if since.Set {
+			fmt.Fprintf(stdout, "  since %s\n", since)
+		}
+		if heads != nil {
+			fmt.Fprintf(stdout, "  trusted heads from %s\n", heads.Source)
+			if heads.ByAgent != nil && heads.Digest != rules.Digest {
+				fmt.Fprintf(stdout, "  %s  snapshot was written under different chain rules\n", console.warn)
+			}
+		}
+		fmt.Fprintf(stdout, "  %d record(s) before the window left out, %d to verify\n", leftOut, len(ordered))
+	}
+
+	// ── Pass 2: linkage in causal order ───────────────────────
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  PASS 2 — Records and causal linkage")
+	fmt.Fprintln(stdout, "  " + console.rule)
+
+	last     := make(map[string]chainTuple) // agent_id → last record
+	verifiedHead := make(map[string]chainTuple) // agent_id → last fully verified record
+	verified := make(map[string]bool)       // chain hash → signature and link OK
+	inCorpus := make(map[string]bool)
+	cadenceOn := *cadence || *cadenceJSON != ""
+	tracker   := newCadenceTracker(th)
+	headMismatch := false
+	for _, t := range ordered {
+		first := len(run.results)
+		name := filepath.Base(t.File)
+		run.agent = t.AgentID
+		rule := rules.forType(t.RecordType)
+		run.check(gefverify.CategoryIntegrity, "record unverified", fmt.Sprintf("%s verifies under every contract", name), t.Verified,
+			fmt.Sprintf("agent=%s seq=%d, %d check(s) failed", t.AgentID, t.Sequence, len(t.Failed)))
+		for _, c := range t.Failed {
+			run.carry(c)
+		}
+		if lvl == levelVerbose {
+			fmt.Fprintf(stdout, "       chain_hash=%s  causal_hash=%s\n", t.ChainHash, t.CausalHash)
+		}
+		if t.Verified && t.AgentID != "" && t.Nonce != "" {
+			fresh, details := nonces.check(t.AgentID, t.Nonce, t.File, t.ChainHash)
+			run.check(gefverify.CategoryIntegrity, "nonce replay", fmt.Sprintf("%s nonce not seen before", name), fresh, details)
+		}
+
+		prev, seen := last[t.AgentID]
+		trusted, anchored := heads.forAgent(t.AgentID)
+		switch {
+		case !t.Verified || (seen && !prev.Verified):
+			fmt.Fprintf(stdout, "       %s: link not checked, it or the record before it does not verify\n", name)
+		case seen:
+			order, details := sequenceStep(t.AgentID, prev.Sequence, t.Sequence, prev.RecordID, t.RecordID)
+			seqName := fmt.Sprintf("%s follows seq %d", name, prev.Sequence)
+			gaps := rule.gapSeverity(*allowGaps)
+			switch {
+			case order == sequenceDuplicate:
+				run.check(gefverify.CategoryIntegrity, "duplicate sequence", seqName, false, details)
+			case order == sequenceRegression:
+				run.check(gefverify.CategoryIntegrity, "sequence regression", seqName, false, details)
+			case order == sequenceGap && t.CausalHash != prev.ChainHash:
+				fmt.Fprintf(stdout, "       %s: links to a record between seq %d and %d, not in the corpus; link not checked\n", name, prev.Sequence, t.Sequence)
+				run.ruleCheck(rule, gaps, gefverify.CategoryCompleteness, "sequence gap", seqName, false, details)
+			default:
+				run.ruleCheck(rule, rule.Linkage, gefverify.CategoryIntegrity, "broken link", fmt.Sprintf("%s links to previous", name), t.CausalHash == prev.ChainHash,
+					fmt.Sprintf("causal_hash=%s expected=%s", t.CausalHash, prev.ChainHash))
+				run.ruleCheck(rule, gaps, gefverify.CategoryCompleteness, "sequence gap", seqName, order == sequenceNext, details)
+			}
+			if !t.Timestamp.IsZero() && !prev.Timestamp.IsZero() {
+				interval := t.Timestamp.Sub(prev.Timestamp)
+				run.ruleCheck(rule, rule.TimestampMonotonic, gefverify.CategoryPolicy, "timestamp regression", fmt.Sprintf("%s timestamp not before previous", name), interval >= 0,
+					fmt.Sprintf("interval=%s", interval))
+				if rule.MaxInterval > 0 {
+					run.ruleCheck(rule, rule.MaxIntervalSeverity, gefverify.CategoryPolicy, "interval exceeded", fmt.Sprintf("%s within %s of previous", name, rule.MaxInterval), interval <= rule.MaxInterval,
+						fmt.Sprintf("interval=%s", interval))
+				}
+			}
+		case anchored && t.Sequence > 0:
+			headMismatch = t.CausalHash != trusted
+			run.check(gefverify.CategoryIntegrity, "trusted head mismatch", fmt.Sprintf("%s links to trusted head", name), !headMismatch,
+				fmt.Sprintf("causal_hash=%s trusted=%s", t.CausalHash, trusted))
+		case t.Sequence == 0:
+			run.ruleCheck(rule, rule.Linkage, gefverify.CategoryIntegrity, "bad genesis link", fmt.Sprintf("%s links to genesis", name), t.CausalHash == genesisCausalHash,
+				fmt.Sprintf("causal_hash=%s", t.CausalHash))
+		default:
+			fmt.Fprintf(stdout, "       %s: first record for agent %s at seq %d — earlier history not provided\n",
+				name, t.AgentID, t.Sequence)
+		}
+		last[t.AgentID] = t
+		if t.ChainHash != "" {
+			inCorpus[t.ChainHash] = true
+			verified[t.ChainHash] = run.passedSince(first)
+			if verified[t.ChainHash] {
+				verifiedHead[t.AgentID] = t
+			}
+		}
+		if cadenceOn && !t.Timestamp.IsZero() {
+			tracker.observe(t.AgentID, t.Sequence, t.Timestamp)
+		}
+		if headMismatch {
+			fmt.Fprintf(stdout, "       stopping: the trusted head of agent %s is wrong, nothing after it can be verified\n", t.AgentID)
+			break
+		}
+	}
+	run.agent = ""
+	if err := nonces.save(); err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", *nonceDB, err)
+		return 2
+	}
+
+	// ── Pass 3: referential integrity (optional) ──────────────
+	if len(refPointers) > 0 && !headMismatch {
+		fmt.Fprintln(stdout)
+		fmt.Fprintf(stdout, "  PASS 3 — Payload references (%s)\n", refPointers.String())
+		fmt.Fprintln(stdout, "  " + console.rule)
+		checkRefs(run, ordered, verified, inCorpus, *closedWorld)
+	}
+
+	// ── Amendments (optional, chain rule "amendments") ────────
+	if hasAmendments(ordered, rules) && !headMismatch {
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, "  AMENDMENTS — Payload by reference")
+		fmt.Fprintln(stdout, "  " + console.rule)
+		checkAmendments(run, rules, ordered, tuples, verified)
+	}
+
+	// ── Cadence (optional, informational) ─────────────────────
+	if cadenceOn {
+		findings := tracker.finish()
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, "  CADENCE — Signing intervals (findings, verdict unaffected)")
+		fmt.Fprintln(stdout, "  " + console.rule)
+		for _, id := range tracker.agentIDs() {
+			s := tracker.agents[id].All
+			fmt.Fprintf(stdout, "  %s  %s: %d interval(s), mean %s, stddev %s, min %s, max %s\n", console.note,
+				id, s.Count, seconds(s.Mean), seconds(s.stddev()), seconds(s.Min), seconds(s.Max))
+		}
+		for _, f := range findings {
+			fmt.Fprintf(stdout, "  %s  %s\n", console.warn, f)
+		}
+		if *cadenceJSON != "" {
+			data, err := json.MarshalIndent(tracker.document(), "", "  ")
+			if err == nil {
+				err = writeFileAtomic(*cadenceJSON, append(data, '\n'), 0o644)
+			}
+			if err != nil {
+				fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", *cadenceJSON, err)
+				return 2
+			}
+		}
+	}
+
+	// ── Verdict ───────────────────────────────────────────────
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	report := gefverify.Report{Checks: run.results, Verdict: gefverify.DeriveVerdict(run.results)}
+	if *metricsFile != "" {
+		run.metrics.Records  = len(ordered)
+		run.metrics.Duration = time.Since(start)
+		var buf bytes.Buffer
+		err := writeOpenMetrics(&buf, run.metrics, *maxAgents)
+		if err == nil {
+			err = writeFileAtomic(*metricsFile, buf.Bytes(), 0o644)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", *metricsFile, err)
+			return 2
+		}
+	}
+	passed, total, verdict := report.Passed(), report.Total(), report.Verdict
+	if *shardReportPath != "" {
+		data, err := json.MarshalIndent(newShardReport(shard, root, files, claimed, report), "", "  ")
+		if err == nil {
+			err = writeFileAtomic(*shardReportPath, append(data, '\n'), 0o644)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", *shardReportPath, err)
+			return 2
+		}
+	}
+	if *snapshotPath != "" && passed == total {
+		heads.carryOver(verifiedHead)
+		snap, err := snapshotFromHeads(verifiedHead, rules.Digest)
+		if err == nil {
+			err = writeFileAtomic(*snapshotPath, encodeSnapshot(snap), 0o644)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", *snapshotPath, err)
+			return 2
+		}
+	}
+	if lvl == levelQuiet {
+		printQuiet(verdictOut, "chain", report.Failed(), verdict, fmt.Sprintf("%d records, %d/%d checks", len(ordered), passed, total))
+		return verdict.ExitCode()
+	}
+	if passed == total {
+		fmt.Fprintf(stdout, "  %s  CHAIN VERIFIED  (%d records, %d/%d checks)  verdict=%s\n", console.pass,
+			len(ordered), passed, total, verdict)
+		if run.warnings > 0 {
+			fmt.Fprintf(stdout, "  %s  %d rule finding(s) at warn severity — see PASS 2\n", console.warn, run.warnings)
+		}
+		if sliced {
+			fmt.Fprintf(stdout, "  %s  history before the window assumed, not verified (%d record(s)) — see WINDOW\n", console.warn, leftOut)
+		}
+		fmt.Fprintln(stdout, bar)
+		fmt.Fprintln(stdout)
+		return verdict.ExitCode()
+	}
+	fmt.Fprintf(stdout, "  %s  CHAIN VERIFICATION FAILED  (%d/%d checks passed)  verdict=%s\n\n", console.fail,
+		passed, total, verdict)
+	if run.warnings > 0 {
+		fmt.Fprintf(stdout, "  %s  %d rule finding(s) at warn severity — see PASS 2\n\n", console.warn, run.warnings)
+	}
+	if sliced {
+		fmt.Fprintf(stdout, "  %s  history before the window assumed, not verified (%d record(s)) — see WINDOW\n\n", console.warn, leftOut)
+	}
+	printFailures(report.Failed())
+	fmt.Fprintln(stdout, "  Failure distribution:")
+	for _, e := range run.failures.sorted() {
+		fmt.Fprintf(stdout, "  %6d  %s\n", e.Count, e.Code)
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+	return verdict.ExitCode()
+}
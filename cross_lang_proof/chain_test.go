@@ -0,0 +1,182 @@
+// cross_lang_proof/chain_test.go
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// writeChainArray writes the bundles in files, in that order, as one
+// JSON array file.
+func writeChainArray(t *testing.T, files ...string) string {
+	t.Helper()
+	var elems []json.RawMessage
+	for _, f := range files {
+		elems = append(elems, mustRead(t, f))
+	}
+	data, _ := json.Marshal(elems)
+	path := filepath.Join(t.TempDir(), "chain.json")
+	must(t, os.WriteFile(path, append([]byte("\n"), data...), 0o644))
+	return path
+}
+
+func TestChainFileArguments(t *testing.T) {
+	files := writeChainDir(t, t.TempDir(), "alpha", 3)
+	for _, args := range [][]string{
+		{files[2], files[0], files[1]},
+		{writeChainArray(t, files[2], files[0], files[1])},
+	} {
+		code, out, errOut := runCaptured(t, append([]string{"chain"}, args...)...)
+		if code != 0 || !strings.Contains(out, "3 record(s) read") {
+			t.Errorf("%v: exit %d\n%s%s", args, code, out, errOut)
+		}
+	}
+}
+
+func TestChainArrayBrokenLink(t *testing.T) {
+	files := writeChainDir(t, t.TempDir(), "alpha", 3)
+	other := writeChainDir(t, t.TempDir(), "alpha", 3)
+	array := writeChainArray(t, other[2], files[0], files[1])
+	code, out, _ := runCaptured(t, "chain", array)
+	if code == 0 {
+		t.Fatalf("spliced chain verified:\n%s", out)
+	}
+	want := "causal_hash=" + chainHashOf(t, other[1]) + " expected=" + chainHashOf(t, files[1])
+	if !strings.Contains(out, "chain.json#0 links to previous") || !strings.Contains(out, want) {
+		t.Errorf("broken link not reported with both hashes (%s):\n%s", want, out)
+	}
+}
+
+func TestChainArrayUnreadableElement(t *testing.T) {
+	files := writeChainDir(t, t.TempDir(), "alpha", 1)
+	path := filepath.Join(t.TempDir(), "chain.json")
+	must(t, os.WriteFile(path, []byte(`[`+string(mustRead(t, files[0]))+`, {"signing_dict": 7}]`), 0o644))
+	code, out, _ := runCaptured(t, "chain", path)
+	if code == 0 || !strings.Contains(out, "chain.json#1 readable") {
+		t.Errorf("exit %d\n%s", code, out)
+	}
+}
+
+func TestOrderByManifest(t *testing.T) {
+	tuples := []chainTuple{
+		{File: filepath.Join("a", "x.json"), RecordID: "r1"},
+		{File: filepath.Join("b", "x.json"), RecordID: "r2"},
+		{File: filepath.Join("b", "y.json"), RecordID: "r3"},
+	}
+	ordered, notOnDisk, notInManifest, err := orderByManifest(tuples, []string{"y.json", "./b/x.json", "r1", "z.json"})
+	var got []string
+	for _, o := range ordered {
+		got = append(got, o.RecordID)
+	}
+	if err != nil || strings.Join(got, " ") != "r3 r2 r1" {
+		t.Fatalf("ordered %v, err %v", got, err)
+	}
+	if len(notOnDisk) != 1 || notOnDisk[0] != "z.json" || len(notInManifest) != 0 {
+		t.Errorf("absent on disk %v, absent in manifest %v", notOnDisk, notInManifest)
+	}
+	for _, tt := range []struct {
+		manifest []string
+		err      string
+	}{
+		{[]string{"x.json"}, `manifest entry "x.json" matches 2 records`},
+		{[]string{"y.json", "r3"}, `manifest lists ` + tuples[2].File + ` twice, as "y.json" and "r3"`},
+	} {
+		if _, _, _, err := orderByManifest(tuples, tt.manifest); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%v: err %v, want %q", tt.manifest, err, tt.err)
+		}
+	}
+}
+
+func TestChainManifestSameBaseName(t *testing.T) {
+	root := t.TempDir()
+	a, b := filepath.Join(root, "a"), filepath.Join(root, "b")
+	must(t, os.Mkdir(a, 0o755))
+	must(t, os.Mkdir(b, 0o755))
+	writeChainDir(t, a, "alpha", 2)
+	beta := writeChainDir(t, b, "beta", 1)
+	must(t, os.Rename(beta[0], filepath.Join(b, "alpha-1.json")))
+
+	manifest := filepath.Join(root, "order.json")
+	for _, tt := range []struct {
+		entries string
+		code    int
+		out     string
+	}{
+		{`["alpha-0.json", "a/alpha-1.json", "b/alpha-1.json"]`, 0, "3 record(s) read"},
+		{`["alpha-0.json", "alpha-1.json", "b/alpha-1.json"]`, 2, `manifest entry "alpha-1.json" matches 2 records`},
+		{`["alpha-0.json", "a/alpha-1.json", "a/alpha-1.json", "b/alpha-1.json"]`, 2, "twice"},
+	} {
+		must(t, os.WriteFile(manifest, []byte(tt.entries), 0o644))
+		code, out, errOut := runCaptured(t, "chain", "-manifest", manifest, a, b)
+		if code != tt.code || !strings.Contains(out+errOut, tt.out) {
+			t.Errorf("%s: exit %d, want %d with %q\n%s%s", tt.entries, code, tt.code, tt.out, out, errOut)
+		}
+	}
+}
+
+func TestChainVerifiesEveryRecord(t *testing.T) {
+	files := writeChainDir(t, t.TempDir(), "alpha", 2)
+
+	// Rewrite r1's unsigned chain_dict and the hashes over it: the
+	// signature over signing_dict still verifies, C2 no longer catches it.
+	var bundle gefverify.ProofBundle
+	must(t, json.Unmarshal(mustRead(t, files[1]), &bundle))
+	bundle.ChainDict["payload"] = map[string]interface{}{"forged": true}
+	chainBytes, err := gefverify.Canonicalize(bundle.ChainDict)
+	must(t, err)
+	sum := sha256.Sum256(chainBytes)
+	bundle.ChainBytesHex, bundle.CausalHashOfThis = hex.EncodeToString(chainBytes), hex.EncodeToString(sum[:])
+	data, _ := json.Marshal(bundle)
+	must(t, os.WriteFile(files[1], data, 0o644))
+
+	if tuple, err := readChainTuple(files[1], nil, shardSpec{}); err != nil || tuple.ChainHash != "" {
+		t.Errorf("chain hash %q of a chain_dict C4 rejects, err %v", tuple.ChainHash, err)
+	}
+	code, out, _ := runCaptured(t, "chain", files[0], files[1])
+	if code != gefverify.VerdictTampered.ExitCode() {
+		t.Fatalf("exit %d, want TAMPERED\n%s", code, out)
+	}
+	for _, want := range []string{"alpha-1.json verifies under every contract", "C4.dict_identity", "alpha-1.json: link not checked"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestChainLinksUnderHashAlg(t *testing.T) {
+	dir := t.TempDir()
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte("h"), 32))
+	prev := ""
+	for i := 0; i < 2; i++ {
+		b := gefverify.NewRecord("alpha", "execution").Sequence(int64(i)).PreviousHash(prev)
+		if i == 0 {
+			b = gefverify.NewRecord("alpha", "genesis").Genesis()
+		}
+		_, bundle, err := b.Finalize(key)
+		must(t, err)
+		chainBytes, err := gefverify.Canonicalize(bundle.ChainDict)
+		must(t, err)
+		hash, ok := gefverify.NewVerifier().ChainHashHex("sha3-256", chainBytes)
+		if !ok {
+			t.Skip("sha3-256 needs Go 1.24")
+		}
+		bundle.HashAlg, bundle.CausalHashOfThis = "sha3-256", hash
+		prev = hash
+		data, _ := json.Marshal(bundle)
+		must(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("alpha-%d.json", i)), data, 0o644))
+	}
+	if code, out, errOut := runCaptured(t, "chain", dir); code != 0 {
+		t.Errorf("sha3-256 chain: exit %d\n%s%s", code, out, errOut)
+	}
+}
@@ -0,0 +1,216 @@
+// cross_lang_proof/chainrules.go
+//
+// Record-type chain rules (chain -chain-rules rules.json)
+// =======================================================
+//
+// Chain strictness per record_type: heartbeats are best-effort, audit
+// trails must be gapless. The file maps record types, plus "default" for
+// every type without an entry, to a rule:
+//
+//   {"default":      {"sequence_gaps": "fail", "timestamp_monotonic": "warn"},
+//    "heartbeat":    {"sequence_gaps": "warn", "linkage": "warn",
+//                     "max_interval": "2m", "max_interval_severity": "warn"},
+//    "admin_action": {"timestamp_monotonic": "fail", "max_interval": "24h"}}
+//
+// Each check is "off", "warn" or "fail"; a field left out inherits from
+// "default", and "default" from the built-in rule, which is pass 2 as it
+// runs without a rules file (linkage and sequence_gaps fail, everything
+// else off). -allow-gaps turns a sequence_gaps fail into warn.
+//
+//   linkage              causal_hash = previous chain hash (or genesis)
+//   sequence_gaps        sequence = previous sequence + 1
+//   timestamp_monotonic  timestamp not before the previous record's
+//   max_interval         timestamp at most this long after the previous
+//                        (Go duration; max_interval_severity, default fail)
+//   amendments           an amendment's original resolves, verified, and
+//                        the amendment keeps to its rules (amendments.go;
+//                        amendment records only, off by default)
+//
+// A record is judged by the rule of its own record_type, including the
+// checks that look back at its predecessor. warn findings are printed
+// with ⚠ and do not affect the verdict; every finding names its rule.
+// The file is validated before any bundle is read: unknown keys, record
+// types or severities are usage errors.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// severity says what a failed rule check does.
+type severity string
+
+const (
+	severityOff  severity = "off"
+	severityWarn severity = "warn"
+	severityFail severity = "fail"
+)
+
+// chainRule is the strictness applied to one record type.
+type chainRule struct {
+	Name                string        `json:"-"` // "" for the built-in rule
+	Linkage             severity      `json:"linkage"`
+	SequenceGaps        severity      `json:"sequence_gaps"`
+	TimestampMonotonic  severity      `json:"timestamp_monotonic"`
+	MaxInterval         time.Duration `json:"-"`
+	MaxIntervalSeverity severity      `json:"max_interval_severity"`
+	Amendments          severity      `json:"amendments"`
+}
+
+// builtinChainRule is pass 2 without -chain-rules.
+var builtinChainRule = chainRule{
+	Linkage:             severityFail,
+	SequenceGaps:        severityFail,
+	TimestampMonotonic:  severityOff,
+	MaxIntervalSeverity: severityFail,
+	Amendments:          severityOff,
+}
+
+// gapSeverity is the severity of sequence gaps under r; allowGaps is
+// -allow-gaps.
+func (r chainRule) gapSeverity(allowGaps bool) severity {
+	if allowGaps && r.SequenceGaps == severityFail {
+		return severityWarn
+	}
+	return r.SequenceGaps
+}
+
+// chainRules selects the rule for a record type.
+type chainRules struct {
+	Default chainRule
+	Types   map[string]chainRule
+	Digest  [sha256.Size]byte // SHA-256 of the rules file, zero for the built-in rules
+}
+
+func (rs *chainRules) forType(recordType string) chainRule {
+	if r, ok := rs.Types[recordType]; ok {
+		return r
+	}
+	return rs.Default
+}
+
+// ruleEntry is one entry of the rules file as written.
+type ruleEntry struct {
+	chainRule
+	MaxInterval string `json:"max_interval"`
+}
+
+// overlay returns base with the fields set in e replacing its own.
+func (e ruleEntry) overlay(name string, base chainRule) (chainRule, error) {
+	r := base
+	r.Name = name
+	for _, f := range []struct {
+		key string
+		src severity
+		dst *severity
+	}{
+		{"linkage", e.Linkage, &r.Linkage},
+		{"sequence_gaps", e.SequenceGaps, &r.SequenceGaps},
+		{"timestamp_monotonic", e.TimestampMonotonic, &r.TimestampMonotonic},
+		{"max_interval_severity", e.MaxIntervalSeverity, &r.MaxIntervalSeverity},
+		{"amendments", e.Amendments, &r.Amendments},
+	} {
+		switch f.src {
+		case "":
+		case severityOff, severityWarn, severityFail:
+			*f.dst = f.src
+		default:
+			return r, fmt.Errorf("%s: %s: severity %q is not off, warn or fail", name, f.key, f.src)
+		}
+	}
+	if e.MaxInterval != "" {
+		d, err := time.ParseDuration(e.MaxInterval)
+		if err != nil || d <= 0 {
+			return r, fmt.Errorf("%s: max_interval %q is not a positive duration", name, e.MaxInterval)
+		}
+		r.MaxInterval = d
+	}
+	return r, nil
+}
+
+// parseChainRules decodes and validates a rules file.
+func parseChainRules(data []byte) (*chainRules, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("chain rules: %w", err)
+	}
+	entries := make(map[string]ruleEntry, len(raw))
+	for name, msg := range raw {
+		if name != "default" && !isRecordType(name) {
+			return nil, fmt.Errorf("chain rules: %q is not \"default\" or a record type %v", name, gefverify.RecordTypes)
+		}
+		var e ruleEntry
+		dec := json.NewDecoder(bytes.NewReader(msg))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&e); err != nil {
+			return nil, fmt.Errorf("chain rules: %s: %w", name, err)
+		}
+		entries[name] = e
+	}
+
+	rs := &chainRules{Default: builtinChainRule, Types: make(map[string]chainRule)}
+	if e, ok := entries["default"]; ok {
+		r, err := e.overlay("default", builtinChainRule)
+		if err != nil {
+			return nil, fmt.Errorf("chain rules: %w", err)
+		}
+		rs.Default = r
+	}
+	for name, e := range entries {
+		if name == "default" {
+			continue
+		}
+		r, err := e.overlay(name, rs.Default)
+		if err != nil {
+			return nil, fmt.Errorf("chain rules: %w", err)
+		}
+		rs.Types[name] = r
+	}
+	return rs, nil
+}
+
+// loadChainRules reads and validates a rules file.
+func loadChainRules(path string) (*chainRules, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := parseChainRules(data)
+	if err != nil {
+		return nil, err
+	}
+	rs.Digest = sha256.Sum256(data)
+	return rs, nil
+}
+
+func isRecordType(t string) bool {
+	for _, rt := range gefverify.RecordTypes {
+		if t == rt {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleCheck records a check at sev: off skips it, warn prints a failure
+// as a finding without failing the run, fail is an ordinary check.
+func (c *chainRun) ruleCheck(rule chainRule, sev severity, category gefverify.Category, code, name string, passed bool, details string) {
+	if rule.Name != "" {
+		details = fmt.Sprintf("%s  [rule %s]", details, rule.Name)
+	}
+	switch {
+	case sev == severityOff:
+	case sev == severityWarn && !passed:
+		c.warnings++
+		fmt.Fprintf(stdout, "  %s  %-50s %s\n", console.warn, name, details+"  (warn)")
+	default:
+		c.check(category, code, name, passed, details)
+	}
+}
@@ -0,0 +1,143 @@
+// cross_lang_proof/chainrules_test.go
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+const matrixRules = `{
+  "heartbeat":    {"sequence_gaps": "warn", "linkage": "warn",
+                   "max_interval": "1m", "max_interval_severity": "warn"},
+  "admin_action": {"sequence_gaps": "fail", "timestamp_monotonic": "fail",
+                   "max_interval": "1m"}
+}`
+
+// writeAnomalyChain writes genesis plus records 1..3 of recordType for one
+// agent, with one anomaly at record 3: "gap" (seq 4, linked correctly),
+// "regression" (timestamp before record 2), "interval" (an hour late) or
+// "link" (wrong causal_hash).
+func writeAnomalyChain(t *testing.T, recordType, anomaly string) string {
+	t.Helper()
+	dir := t.TempDir()
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{3}, 32))
+	base := time.Date(2026, 2, 24, 12, 0, 0, 0, time.UTC)
+	prev := ""
+	for i := 0; i < 4; i++ {
+		at, seq := base.Add(time.Duration(i)*time.Second), int64(i)
+		b := gefverify.NewRecord("agent-m", recordType).Sequence(seq).PreviousHash(prev)
+		if i == 0 {
+			b = gefverify.NewRecord("agent-m", "genesis").Genesis()
+		}
+		if i == 3 {
+			switch anomaly {
+			case "gap":
+				b.Sequence(4)
+			case "regression":
+				at = base
+			case "interval":
+				at = base.Add(time.Hour)
+			case "link":
+				b.PreviousHash(strings.Repeat("e", 64))
+			}
+		}
+		_, bundle, err := b.At(at).Finalize(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prev = bundle.CausalHashOfThis
+		data, _ := json.Marshal(bundle)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("r%d.json", i)), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestChainRulesMatrix(t *testing.T) {
+	rulesPath := filepath.Join(t.TempDir(), "rules.json")
+	os.WriteFile(rulesPath, []byte(matrixRules), 0o644)
+
+	tests := []struct {
+		recordType, anomaly string
+		code                int
+		want                string // "" means no finding line at all
+	}{
+		{"heartbeat", "gap", 0, "⚠   r3.json follows seq 2"},
+		{"heartbeat", "regression", 0, ""},
+		{"heartbeat", "interval", 0, "⚠   r3.json within 1m0s of previous"},
+		{"heartbeat", "link", 0, "⚠   r3.json links to previous"},
+		{"admin_action", "gap", 5, "❌  r3.json follows seq 2"},
+		{"admin_action", "regression", 4, "❌  r3.json timestamp not before previous"},
+		{"admin_action", "interval", 4, "❌  r3.json within 1m0s of previous"},
+		{"admin_action", "link", 1, "❌  r3.json links to previous"},
+		// No entry: the built-in default, linkage and gaps.
+		{"tool_call", "gap", 5, "❌  r3.json follows seq 2"},
+		{"tool_call", "link", 1, "❌  r3.json links to previous"},
+	}
+	for _, tt := range tests {
+		name := tt.recordType + "/" + tt.anomaly
+		code, out, errOut := runCaptured(t, "chain", "-chain-rules", rulesPath, writeAnomalyChain(t, tt.recordType, tt.anomaly))
+		if code != tt.code {
+			t.Errorf("%s: exit %d, want %d\n%s%s", name, code, tt.code, out, errOut)
+			continue
+		}
+		findings := strings.Count(out, "⚠   r") + strings.Count(out, "❌  r")
+		switch {
+		case tt.want == "" && findings != 0:
+			t.Errorf("%s: unexpected finding:\n%s", name, out)
+		case tt.want != "" && !strings.Contains(out, tt.want):
+			t.Errorf("%s: missing %q:\n%s", name, tt.want, out)
+		case tt.want != "" && tt.recordType != "tool_call" && !strings.Contains(out, "[rule "+tt.recordType+"]"):
+			t.Errorf("%s: finding does not name rule %s:\n%s", name, tt.recordType, out)
+		}
+	}
+}
+
+func TestParseChainRules(t *testing.T) {
+	rs, err := parseChainRules([]byte(`{"default": {"sequence_gaps": "fail"},
+		"heartbeat": {"linkage": "warn", "max_interval": "90s"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hb := rs.forType("heartbeat")
+	if hb.Name != "heartbeat" || hb.Linkage != severityWarn || hb.SequenceGaps != severityFail ||
+		hb.TimestampMonotonic != severityOff || hb.MaxInterval != 90*time.Second || hb.MaxIntervalSeverity != severityFail {
+		t.Errorf("heartbeat = %+v, want linkage warn with the rest inherited", hb)
+	}
+	if d := rs.forType("tombstone"); d.Name != "default" || d.SequenceGaps != severityFail {
+		t.Errorf("unknown type got %+v, want the default rule", d)
+	}
+
+	for input, want := range map[string]string{
+		`{"audit": {}}`:                            `"audit" is not "default" or a record type`,
+		`{"heartbeat": {"linkage": "strict"}}`:     `severity "strict"`,
+		`{"heartbeat": {"gaps": "warn"}}`:          `unknown field "gaps"`,
+		`{"heartbeat": {"max_interval": "-1m"}}`:   "not a positive duration",
+		`{"heartbeat": {"max_interval": "often"}}`: "not a positive duration",
+		`[]`: "cannot unmarshal",
+	} {
+		if _, err := parseChainRules([]byte(input)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", input, err, want)
+		}
+	}
+}
+
+func TestChainRulesInvalidFileIsUsageError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	os.WriteFile(path, []byte(`{"heartbeat": {"linkage": "loud"}}`), 0o644)
+	code, out, errOut := runCaptured(t, "chain", "-chain-rules", path, t.TempDir())
+	if code != 2 || out != "" || !strings.HasPrefix(errOut, "FATAL: chain rules: heartbeat: linkage") {
+		t.Errorf("exit %d\nstdout=%q\nstderr=%q", code, out, errOut)
+	}
+}
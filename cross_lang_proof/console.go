@@ -0,0 +1,289 @@
+// cross_lang_proof/console.go
+//
+// Console rendering of check results — the pass/fail lines, section headings
+// and failure summaries shared by every subcommand.
+//
+// Verification, batch and chain take -quiet and -verbose. -quiet, for CI
+// logs, drops the report: each failed check goes to stderr as a FAILED
+// line and the one-line verdict to stdout, nothing else; the exit code
+// is unchanged. -verbose prints whole canonical hex strings, hashes,
+// signatures and envelope_json where the report shows prefixes.
+//
+// Every marker and rule comes from console, a reporter: emoji by default,
+// ASCII ([PASS], [FAIL], ===) with -no-color, which every subcommand
+// takes, or with NO_COLOR set (https://no-color.org), for log systems
+// that are not UTF-8.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// reporter holds the markers of the console report. Status markers take
+// the two columns of an emoji; the ASCII ones are wider.
+type reporter struct {
+	pass, fail, warn, note string // a check or finding
+	nested                 string // a nested bundle's result
+	bar, rule              string // banner and section rules
+}
+
+var (
+	emojiReporter = reporter{
+		pass: "✅", fail: "❌", warn: "⚠ ", note: "· ", nested: "↳",
+		bar:  strings.Repeat("═", 64),
+		rule: strings.Repeat("─", 60),
+	}
+	asciiReporter = reporter{
+		pass: "[PASS]", fail: "[FAIL]", warn: "[WARN]", note: "[NOTE]", nested: "->",
+		bar:  strings.Repeat("=", 64),
+		rule: strings.Repeat("-", 60),
+	}
+)
+
+// console is the reporter of the current run: asciiReporter with NO_COLOR
+// set or -no-color given (parseFlags), emojiReporter otherwise.
+var console = emojiReporter
+
+// reporterFor returns the reporter for the value of NO_COLOR.
+func reporterFor(noColor string) reporter {
+	if noColor != "" {
+		return asciiReporter
+	}
+	return emojiReporter
+}
+
+// mark is the marker of a passed or failed check.
+func (r reporter) mark(passed bool) string {
+	if passed {
+		return r.pass
+	}
+	return r.fail
+}
+
+// outputLevel is how much of a report reaches the console.
+type outputLevel int
+
+const (
+	levelDefault outputLevel = iota
+	levelQuiet
+	levelVerbose
+)
+
+// levelFlags registers -quiet and -verbose on fs. The returned function
+// gives the level they select once fs is parsed; both is an error.
+func levelFlags(fs *flag.FlagSet) func() (outputLevel, error) {
+	quiet := fs.Bool("quiet", false,
+		"print only failed checks (to stderr) and the one-line verdict")
+	verbose := fs.Bool("verbose", false,
+		"print whole hex values, hashes and envelope_json instead of prefixes")
+	return func() (outputLevel, error) {
+		switch {
+		case *quiet && *verbose:
+			return levelDefault, errors.New("-quiet and -verbose are mutually exclusive")
+		case *quiet:
+			return levelQuiet, nil
+		case *verbose:
+			return levelVerbose, nil
+		}
+		return levelDefault, nil
+	}
+}
+
+// silenceStdout discards everything written to stdout, for -quiet, and
+// returns the real stdout and the function that restores it.
+func silenceStdout() (io.Writer, func()) {
+	out := stdout
+	stdout = bufio.NewWriter(io.Discard)
+	return out, func() { stdout = out }
+}
+
+// printQuiet is the whole -quiet report on subject: its failed checks
+// on stderr and the verdict line, with summary, on w.
+func printQuiet(w io.Writer, subject string, failed []gefverify.CheckResult, verdict gefverify.Verdict, summary string) {
+	printQuietFailures(subject, failed)
+	fmt.Fprintf(w, "%s: %s  (%s)\n", subject, verdict, summary)
+}
+
+// printQuietFailures prints a FAILED line on stderr per failed check.
+func printQuietFailures(subject string, failed []gefverify.CheckResult) {
+	for _, c := range failed {
+		fmt.Fprintf(stderr, "FAILED: %s: %s [%s]: %s\n", subject, c.Name, c.ID, c.Details)
+	}
+}
+
+// printCheck prints one result line, plus its diagnostics on failure.
+func printCheck(c gefverify.CheckResult) {
+	icon, details := console.pass, c.Details
+	switch {
+	case !c.Passed && c.Exception != "":
+		icon, details = console.warn, details+"  [exception "+c.Exception+"]"
+	case !c.Passed:
+		icon = console.fail
+	}
+	fmt.Fprintf(stdout, "  %s  %-50s %s\n", icon, c.Name, details)
+	if !c.Passed && c.Exception == "" && len(c.Diagnostics) > 0 {
+		fmt.Fprintln(stdout)
+		for _, d := range c.Diagnostics {
+			fmt.Fprintf(stdout, "  %s\n", d)
+		}
+		fmt.Fprintln(stdout)
+	}
+}
+
+// printChecks prints results grouped under their section headings.
+func printChecks(checks []gefverify.CheckResult) {
+	section := ""
+	for i, c := range checks {
+		if c.Section != section {
+			if i > 0 {
+				fmt.Fprintln(stdout)
+			}
+			fmt.Fprintln(stdout, "  " + c.Section)
+			fmt.Fprintln(stdout, "  " + console.rule)
+			section = c.Section
+		}
+		printCheck(c)
+	}
+}
+
+// printNotes prints informational lines that are not checks, under a
+// heading of their own: a note is about a contract that ran no check,
+// and would otherwise read as part of the last section printed.
+func printNotes(notes []string) {
+	if len(notes) == 0 {
+		return
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  NOTES — Informational (verdict unaffected)")
+	fmt.Fprintln(stdout, "  " + console.rule)
+	for _, n := range notes {
+		fmt.Fprintf(stdout, "  %s  %s\n", console.note, n)
+	}
+}
+
+// printExceptions lists the failures accepted under a policy exception.
+func printExceptions(applied []gefverify.AppliedException) {
+	if len(applied) == 0 {
+		return
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  EXCEPTIONS — Failures accepted by policy (not passes)")
+	fmt.Fprintln(stdout, "  " + console.rule)
+	for _, e := range applied {
+		fmt.Fprintf(stdout, "  %s  %-8s %s  approved by %s, expires %s\n", console.warn,
+			e.ExceptionID, e.CheckID, e.Approver, e.Expires.UTC().Format(time.RFC3339))
+		fmt.Fprintf(stdout, "       reason: %s\n", e.Reason)
+	}
+}
+
+// keyTrustWarning is printed when no -trusted-keys were given.
+const keyTrustWarning = "key trust not checked: the bundle's own public key was used; give -trusted-keys to require a known signer"
+
+// printWarnings prints findings that do not affect the verdict.
+func printWarnings(warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  WARNINGS — Hygiene, relaxed size limits, gef_version, key trust (verdict unaffected)")
+	fmt.Fprintln(stdout, "  " + console.rule)
+	for _, w := range warnings {
+		fmt.Fprintf(stdout, "  %s  %s\n", console.warn, w)
+	}
+}
+
+// printIncomplete lists required contracts that did not run to completion.
+func printIncomplete(missing []gefverify.ContractResult) {
+	if len(missing) == 0 {
+		return
+	}
+	fmt.Fprintln(stdout, "  CONTRACTS NOT EXECUTED — result cannot be a pass")
+	fmt.Fprintln(stdout, "  " + console.rule)
+	for _, c := range missing {
+		fmt.Fprintf(stdout, "  %s  %-8s  %s\n", console.fail, c.Status, c.Section)
+	}
+}
+
+// printNested prints the nested-bundle result tree, one level per indent.
+func printNested(nested []gefverify.NestedReport, indent string) {
+	for _, n := range nested {
+		icon := console.mark(n.Err == nil && n.Report.OK())
+		fmt.Fprintf(stdout, "%s%s %s  %s  verdict=%s  (%d/%d checks)\n",
+			indent, console.nested, icon, n.Path, n.Report.Verdict, n.Report.Passed(), n.Report.Total())
+		if n.Err != nil {
+			fmt.Fprintf(stdout, "%s    FATAL: %v\n", indent, n.Err)
+		}
+		for _, c := range n.Report.Failed() {
+			fmt.Fprintf(stdout, "%s    %s  %s — %s\n", indent, console.fail, c.Name, c.Details)
+		}
+		printNested(n.Report.Nested, indent+"    ")
+	}
+}
+
+// printFieldTrace prints the full per-field canonicalization table.
+func printFieldTrace(traces []gefverify.FieldTrace) {
+	if len(traces) == 0 {
+		return
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  TRACE — Per-field canonical form (Go vs Python)")
+	fmt.Fprintln(stdout, "  " + console.rule)
+	for _, line := range gefverify.FieldTraceTable(traces, false) {
+		fmt.Fprintf(stdout, "  %s\n", line)
+	}
+}
+
+// printEnvelopeJSON prints envelope_json whole, for -verbose.
+func printEnvelopeJSON(envelope string) {
+	if envelope == "" {
+		return
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  ENVELOPE — envelope_json as carried by the bundle")
+	fmt.Fprintln(stdout, "  " + console.rule)
+	for _, line := range strings.Split(strings.TrimRight(envelope, "\n"), "\n") {
+		fmt.Fprintf(stdout, "  %s\n", line)
+	}
+}
+
+// printFailures prints the FAILED/Detail summary block.
+func printFailures(failed []gefverify.CheckResult) {
+	for _, r := range failed {
+		fmt.Fprintf(stdout, "  FAILED : %s\n", r.Name)
+		fmt.Fprintf(stdout, "  Detail : %s\n\n", r.Details)
+	}
+}
+
+// printProfile labels a verdict reached under a compatibility profile.
+func printProfile(profile string) {
+	if profile == "" {
+		return
+	}
+	fmt.Fprintf(stdout, "  %s  verified under the %s profile — a pre-GEF-SPEC-1.0 record, not a 1.0 one\n\n", console.warn, profile)
+}
+
+// printMutations prints the random mutation statistics, and in full any
+// mutated bytes the signature accepted.
+func printMutations(m *gefverify.MutationStats) {
+	if m == nil {
+		return
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  SAMPLING — Random single-bit mutations (evidence, not proof)")
+	fmt.Fprintln(stdout, "  " + console.rule)
+	fmt.Fprintf(stdout, "  %d random bit flips, seed %d: %d rejected, %d accepted\n",
+		m.Trials, m.Seed, m.Rejected, len(m.Accepted))
+	for _, a := range m.Accepted {
+		fmt.Fprintf(stdout, "  %s  ACCEPTED pos=%d bit=%d orig=0x%02X\n", console.fail, a.Position, a.Bit, a.Original)
+		fmt.Fprintf(stdout, "       mutated bytes: %s\n", a.MutatedHex)
+	}
+}
@@ -0,0 +1,105 @@
+// cross_lang_proof/convert.go
+//
+// JSON <-> CBOR bundle converter
+// ==============================
+//
+//   verify_proof convert [-out bundle.cbor] <bundle.json>
+//   verify_proof convert [-out bundle.json] <bundle.cbor>
+//
+// Converts a proof bundle between JSON and the CBOR form embedded agents
+// write (pkg/gefverify/cbor.go); the input's form is sniffed from the
+// self-describe tag or a .cbor extension. JSON comes out indented with
+// sorted keys, as fmt writes it, so JSON -> CBOR -> JSON gives the fmt
+// form of the input.
+//
+// As with fmt, the conversion must be a no-op for verification: the
+// values of fmt.go's semanticsOf are recomputed on the JSON form of the
+// input and of the output, and nothing is written if one differs. A
+// padded base64url cose_sign1 differs from the unpadded one CBOR gives
+// back, and is refused for that reason.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// isCBORInput reports whether the bundle read from name is CBOR.
+func isCBORInput(name string, data []byte) bool {
+	return gefverify.IsCBOR(data) || strings.HasSuffix(name, ".cbor")
+}
+
+// convertBundle converts data to the other form and returns the output
+// and the JSON forms of both.
+func convertBundle(name string, data []byte) (out, inJSON, outJSON []byte, err error) {
+	if isCBORInput(name, data) {
+		projected, err := gefverify.BundleCBORToJSON(data)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("cannot parse CBOR proof bundle: %w", err)
+		}
+		out, err = formatBundle(projected)
+		return out, projected, out, err
+	}
+	if out, err = gefverify.BundleJSONToCBOR(data); err != nil {
+		return nil, nil, nil, err
+	}
+	outJSON, err = gefverify.BundleCBORToJSON(out)
+	return out, data, outJSON, err
+}
+
+func runConvert(args []string) int {
+	fs := newFlagSet("convert")
+	outPath := fs.String("out", "", "write the converted bundle to `file` instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof convert [-out file] <bundle.json | bundle.cbor>")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	input := fs.Arg(0)
+	data, err := readInput(input)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", input, err)
+		return 2
+	}
+
+	out, inJSON, outJSON, err := convertBundle(input, data)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %s: %v\n", inputName(input), err)
+		return gefverify.VerdictMalformed.ExitCode()
+	}
+	before, err := semanticsOf(inJSON)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: input: %v\n", err)
+		return gefverify.VerdictMalformed.ExitCode()
+	}
+	after, err := semanticsOf(outJSON)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: converted output: %v\n", err)
+		return 1
+	}
+	if changed := diffSemantics(before, after); len(changed) > 0 {
+		fmt.Fprintf(stderr, "FATAL: refusing to convert %s — verification semantics changed:\n", inputName(input))
+		for _, name := range changed {
+			fmt.Fprintf(stderr, "  changed: %s\n", name)
+		}
+		return 1
+	}
+
+	if *outPath == "" {
+		stdout.Write(out)
+		return 0
+	}
+	if err := writeFileAtomic(*outPath, out, 0o644); err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", *outPath, err)
+		return 2
+	}
+	fmt.Fprintf(stderr, "converted %s -> %s (verification semantics preserved)\n", inputName(input), *outPath)
+	return 0
+}
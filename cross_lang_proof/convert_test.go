@@ -0,0 +1,57 @@
+// cross_lang_proof/convert_test.go
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+func TestConvertRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	cborPath := filepath.Join(dir, "bundle.cbor")
+	if code, _, errOut := runCaptured(t, "convert", "-out", cborPath, "proof_bundle.json"); code != 0 {
+		t.Fatalf("to CBOR: exit %d\n%s", code, errOut)
+	}
+	encoded := mustRead(t, cborPath)
+	if !gefverify.IsCBOR(encoded) {
+		t.Fatalf("output does not start with the self-describe tag: % x", encoded[:3])
+	}
+
+	code, out, errOut := runCaptured(t, "convert", cborPath)
+	if code != 0 {
+		t.Fatalf("to JSON: exit %d\n%s", code, errOut)
+	}
+	formatted, err := formatBundle(mustRead(t, "proof_bundle.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != string(formatted) {
+		t.Errorf("JSON -> CBOR -> JSON is not the fmt form:\n%s", out)
+	}
+
+	// The verifier reads CBOR by its tag, and by extension without it.
+	untagged := filepath.Join(dir, "untagged.cbor")
+	must(t, os.WriteFile(untagged, bytes.TrimPrefix(encoded, []byte("\xd9\xd9\xf7")), 0o644))
+	for _, path := range []string{cborPath, untagged} {
+		if code, out, _ := runCaptured(t, path); code != 0 || !strings.Contains(out, "verdict=VERIFIED") {
+			t.Errorf("%s: exit %d\n%s", path, code, out)
+		}
+	}
+}
+
+func TestConvertRefusesSemanticChange(t *testing.T) {
+	// A padded cose_sign1 comes back from CBOR unpadded.
+	path := writeBundle(t, func(s string) string {
+		return strings.Replace(s, `"gef_version"`, `"cose_sign1": "AA==", "gef_version"`, 1)
+	})
+	code, out, errOut := runCaptured(t, "convert", path)
+	if code != 1 || out != "" || !strings.Contains(errOut, "changed: bundle canonical form") {
+		t.Errorf("exit %d out=%q\n%s", code, out, errOut)
+	}
+}
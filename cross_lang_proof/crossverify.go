@@ -0,0 +1,150 @@
+// cross_lang_proof/crossverify.go
+//
+// Cross-verification against an independent verifier
+// ===================================================
+//
+//   verify_proof -cross-verify "python verify.py" bundle.json
+//
+// Runs an external verifier on the same bundle and compares its verdict
+// with ours. The command line is split on whitespace, the bundle path is
+// appended as the last argument, and the bundle bytes are also written to
+// the command's stdin, so either convention works. Exit status 0 means
+// the external verifier accepted the bundle; anything else, rejected.
+//
+// Agreement in both directions is the point: if one implementation
+// accepts and the other rejects, the cross-language contract is broken —
+// whichever of the two is right.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+const sectionCrossVerify = "CROSS-VERIFY — Independent Verifier Agreement"
+
+// externalResult is what the external verifier said.
+type externalResult struct {
+	Accepted bool
+	ExitCode int
+	Output   string
+}
+
+// runExternalVerifier executes cmdLine for the bundle at path (data on stdin).
+func runExternalVerifier(cmdLine, path string, data []byte, timeout time.Duration) (externalResult, error) {
+	var res externalResult
+
+	argv := strings.Fields(cmdLine)
+	if len(argv) == 0 {
+		return res, errors.New("empty -cross-verify command")
+	}
+	if path != "" {
+		argv = append(argv, path)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	// A child of the verifier ("sh -c", a wrapper script) may outlive the
+	// kill and hold the output pipe open; stop waiting for it.
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	res.Output = out.String()
+	if ctx.Err() == context.DeadlineExceeded {
+		return res, fmt.Errorf("external verifier timed out after %s", timeout)
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		res.Accepted = true
+	case errors.As(err, &exitErr):
+		res.ExitCode = exitErr.ExitCode()
+	default:
+		return res, fmt.Errorf("cannot run external verifier: %w", err)
+	}
+	return res, nil
+}
+
+// lastLines returns the last n non-empty lines of s.
+func lastLines(s string, n int) []string {
+	var lines []string
+	for _, l := range strings.Split(s, "\n") {
+		if strings.TrimSpace(l) != "" {
+			lines = append(lines, l)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// crossVerify appends the agreement check to report and re-derives the verdict.
+func crossVerify(report *gefverify.Report, cmdLine, path string, data []byte, timeout time.Duration) {
+	goAccepted := report.OK()
+
+	res, err := runExternalVerifier(cmdLine, path, data, timeout)
+	check := gefverify.CheckResult{
+		ID:       "X.cross_verify",
+		Section:  sectionCrossVerify,
+		Name:     "Go and external verifier agree",
+		Category: gefverify.CategoryIntegrity,
+	}
+	if err != nil {
+		check.Category = gefverify.CategoryCompleteness
+		check.Details = err.Error()
+		check.Diagnostics = lastLines(res.Output, 10)
+	} else {
+		check.Passed = res.Accepted == goAccepted
+		check.Details = fmt.Sprintf("go=%s  external=%s (exit %d)",
+			acceptedWord(goAccepted), acceptedWord(res.Accepted), res.ExitCode)
+		if !check.Passed {
+			check.Diagnostics = append([]string{"external verifier output (tail):"},
+				lastLines(res.Output, 10)...)
+		}
+	}
+
+	report.Checks = append(report.Checks, check)
+	report.Verdict = gefverify.DeriveVerdict(report.Checks)
+}
+
+func acceptedWord(accepted bool) string {
+	if accepted {
+		return "PASS"
+	}
+	return "FAIL"
+}
+
+// bundleFileFor returns a path the external verifier can read: the
+// original file, or a temp copy when the bundle did not come from disk.
+func bundleFileFor(path string, data []byte, fromDisk bool) (string, func(), error) {
+	if fromDisk {
+		return path, func() {}, nil
+	}
+	tmp, err := os.CreateTemp("", "gef-cross-verify-*.json")
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", nil, err
+	}
+	tmp.Close()
+	return tmp.Name(), func() { os.Remove(tmp.Name()) }, nil
+}
@@ -0,0 +1,84 @@
+// cross_lang_proof/crossverify_test.go
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// stubVerifier writes a shell script standing in for an external
+// verifier and returns its path.
+func stubVerifier(t *testing.T, body string) string {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh to run stub verifiers")
+	}
+	path := filepath.Join(t.TempDir(), "verifier.sh")
+	must(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755))
+	return path
+}
+
+func TestCrossVerify(t *testing.T) {
+	good := mustRead(t, "proof_bundle.json")
+	tampered := []byte(tamperSignature(string(good)))
+	// Reads the bundle both ways: the path argument and stdin agree.
+	accept := stubVerifier(t, `cmp -s "$1" - || exit 9`)
+	reject := stubVerifier(t, `echo "signature does not verify" >&2; exit 2`)
+
+	for _, tt := range []struct {
+		name     string
+		data     []byte
+		cmd      string
+		passed   bool
+		category gefverify.Category
+		details  string
+	}{
+		{"both accept", good, accept, true, gefverify.CategoryIntegrity, "go=PASS  external=PASS (exit 0)"},
+		{"both reject", tampered, reject, true, gefverify.CategoryIntegrity, "go=FAIL  external=FAIL (exit 2)"},
+		{"external rejects", good, reject, false, gefverify.CategoryIntegrity, "go=PASS  external=FAIL (exit 2)"},
+		{"external accepts", tampered, accept, false, gefverify.CategoryIntegrity, "go=FAIL  external=PASS (exit 0)"},
+		{"missing binary", good, filepath.Join(t.TempDir(), "absent"), false, gefverify.CategoryCompleteness, "cannot run external verifier"},
+		{"empty command", good, "  ", false, gefverify.CategoryCompleteness, "empty -cross-verify command"},
+	} {
+		bundle, err := gefverify.ParseBundle(tt.data)
+		must(t, err)
+		report, _ := gefverify.Verify(bundle, gefverify.VerifyOptions{})
+		path, cleanup, err := bundleFileFor("", tt.data, false)
+		must(t, err)
+		crossVerify(&report, tt.cmd, path, tt.data, time.Minute)
+		cleanup()
+
+		c := report.Checks[len(report.Checks)-1]
+		if c.ID != "X.cross_verify" || c.Passed != tt.passed || c.Category != tt.category || !strings.Contains(c.Details, tt.details) {
+			t.Errorf("%s: %+v", tt.name, c)
+		}
+		if report.Verdict != gefverify.DeriveVerdict(report.Checks) {
+			t.Errorf("%s: verdict %s not re-derived", tt.name, report.Verdict)
+		}
+	}
+}
+
+func TestCrossVerifyCLI(t *testing.T) {
+	reject := stubVerifier(t, `echo "signature does not verify" >&2; exit 2`)
+	code, out, _ := runCaptured(t, "-cross-verify", reject, "proof_bundle.json")
+	if code != gefverify.VerdictTampered.ExitCode() || !strings.Contains(out, "external=FAIL (exit 2)") || !strings.Contains(out, "signature does not verify") {
+		t.Errorf("disagreement: exit %d\n%s", code, out)
+	}
+
+	slow := stubVerifier(t, `sleep 5`)
+	start := time.Now()
+	code, out, _ = runCaptured(t, "-cross-verify", slow, "-cross-verify-timeout", "100ms", "proof_bundle.json")
+	if code != gefverify.VerdictUnverifiable.ExitCode() || !strings.Contains(out, "external verifier timed out after 100ms") {
+		t.Errorf("timeout: exit %d\n%s", code, out)
+	}
+	if d := time.Since(start); d > 4*time.Second {
+		t.Errorf("timeout took %s", d)
+	}
+}
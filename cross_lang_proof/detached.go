@@ -0,0 +1,113 @@
+// cross_lang_proof/detached.go
+//
+// Detached signature verification
+// ===============================
+//
+//   verify_proof verify-detached -pubkey hex -sig b64url [-canonical] <body.json | ->
+//
+// For edge proxies that receive the signature and signer key as HTTP
+// headers and the signing_dict as the body, with no bundle. The body is
+// verified with gefverify.VerifyDetached: the same canonicalization,
+// contracts and field checks as a full bundle, plus D.signer_binding.
+//
+// By default the body is re-canonicalized, so pretty-printed and already
+// canonical bodies both verify. -canonical instead verifies the bytes as
+// received and fails (MALFORMED) unless they are already JCS — the file
+// must then hold exactly the body, without a trailing newline.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+func runVerifyDetached(args []string) int {
+	fs := newFlagSet("verify-detached")
+	var opts gefverify.VerifyOptions
+	pubHex := fs.String("pubkey", "", "signer Ed25519 public key `hex` or env:NAME (required)")
+	sigB64 := fs.String("sig", "", "Ed25519 signature, `base64url` or env:NAME (required)")
+	fs.BoolVar(&opts.CanonicalBody, "canonical", false,
+		"verify the body bytes as received and require them to be JCS already")
+	fs.BoolVar(&opts.RejectWeakKeys, "reject-weak-keys", false,
+		"reject the 8 small-order Ed25519 public keys before verifying")
+	fs.DurationVar(&opts.Freshness, "freshness", 0,
+		"require record timestamp within this `window` of now")
+	fs.DurationVar(&opts.MaxClockSkew, "max-clock-skew", 0,
+		"refuse a record timestamp more than this `skew` after now")
+	fs.DurationVar(&opts.MaxAge, "max-age", 0,
+		"refuse a record timestamp more than this `age` before now")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof verify-detached -pubkey hex -sig b64url [flags] <body.json | ->")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+	if fs.NArg() != 1 || *pubHex == "" || *sigB64 == "" {
+		fs.Usage()
+		return 2
+	}
+
+	pubValue, err := inlineValue(*pubHex)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: -pubkey: %v\n", err)
+		return 2
+	}
+	sigValue, err := inlineValue(*sigB64)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: -sig: %v\n", err)
+		return 2
+	}
+	pub, err := gefverify.DecodePublicKey(pubValue)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: -pubkey: %v\n", err)
+		return 2
+	}
+	sig, err := gefverify.DecodeSignature(sigValue)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: -sig: %v\n", err)
+		return 2
+	}
+
+	bodyPath := fs.Arg(0)
+	body, err := readInput(bodyPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", bodyPath, err)
+		return 2
+	}
+
+	bar := console.bar
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout, "  GEF Detached Signature — Go Verifier")
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+
+	report, err := gefverify.NewVerifierFromOptions(opts).VerifyDetached(pub, json.RawMessage(body), sig)
+	if err != nil {
+		printChecks(report.Checks)
+		if len(report.Checks) > 0 {
+			fmt.Fprintln(stdout)
+		}
+		fatalMalformed(err)
+	}
+
+	printChecks(report.Checks)
+	printNotes(report.Notes)
+
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	verdict := report.Verdict
+	if report.OK() {
+		fmt.Fprintf(stdout, "  %s  DETACHED SIGNATURE VERIFIED  (%d/%d checks)  verdict=%s\n\n", console.pass,
+			report.Passed(), report.Total(), verdict)
+	} else {
+		fmt.Fprintf(stdout, "  %s  DETACHED SIGNATURE FAILED  (%d/%d checks passed)  verdict=%s\n\n", console.fail,
+			report.Passed(), report.Total(), verdict)
+		printFailures(report.Failed())
+	}
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+	return verdict.ExitCode()
+}
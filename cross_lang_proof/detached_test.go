@@ -0,0 +1,54 @@
+// cross_lang_proof/detached_test.go
+
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+func TestVerifyDetachedCLI(t *testing.T) {
+	key := ed25519.NewKeyFromSeed([]byte(strings.Repeat("d", 32)))
+	_, bundle, err := gefverify.NewRecord("edge-1", "genesis").Genesis().Finalize(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	canonical, _ := hex.DecodeString(bundle.CanonicalBytesHex)
+	sig, _ := hex.DecodeString(bundle.SignatureHex)
+	pub := hex.EncodeToString(key.Public().(ed25519.PublicKey))
+
+	dir := t.TempDir()
+	canonicalPath, prettyPath := filepath.Join(dir, "canonical.json"), filepath.Join(dir, "pretty.json")
+	os.WriteFile(canonicalPath, canonical, 0o644)
+	os.WriteFile(prettyPath, []byte(strings.ReplaceAll(string(canonical), ",", ",\n  ")), 0o644)
+
+	flags := []string{"verify-detached", "-pubkey", pub, "-sig", base64.RawURLEncoding.EncodeToString(sig)}
+	tests := []struct {
+		name  string
+		extra []string
+		code  int
+		want  string
+	}{
+		{"canonical", []string{canonicalPath}, 0, "DETACHED SIGNATURE VERIFIED"},
+		{"pretty", []string{prettyPath}, 0, "DETACHED SIGNATURE VERIFIED"},
+		{"canonical asserted", []string{"-canonical", canonicalPath}, 0, "body is already JCS canonical"},
+		{"pretty asserted", []string{"-canonical", prettyPath}, 3, "verdict=MALFORMED"},
+	}
+	for _, tt := range tests {
+		code, out, errOut := runCaptured(t, append(flags, tt.extra...)...)
+		if code != tt.code || !strings.Contains(out, tt.want) {
+			t.Errorf("%s: exit %d, want %d with %q\nstdout:\n%s\nstderr:\n%s", tt.name, code, tt.code, tt.want, out, errOut)
+		}
+	}
+
+	if code, _, errOut := runCaptured(t, "verify-detached", "-sig", "x", canonicalPath); code != 2 || !strings.Contains(errOut, "usage:") {
+		t.Errorf("missing -pubkey: exit %d, stderr %q", code, errOut)
+	}
+}
@@ -0,0 +1,344 @@
+// cross_lang_proof/dsse.go
+//
+// DSSE envelope transport
+// =======================
+//
+//   verify_proof verify-dsse (-pubkey hex | -keyring keys.json) [-prev hash] <envelope.json>
+//   verify_proof verify-dsse -export-dsse -key signer.key [-bundle b.json]
+//
+// For stores that speak DSSE, the in-toto / sigstore envelope:
+//
+//   {"payloadType": "application/vnd.gef.record+json",
+//    "payload":     BASE64(JCS(signing_dict)),
+//    "signatures":  [{"keyid": "sha256:...", "sig": BASE64(sig)}]}
+//
+// sig is Ed25519 over PAE(payloadType, payload), the DSSE v1
+// pre-authentication encoding:
+//
+//   "DSSEv1" SP LEN(type) SP type SP LEN(body) SP body
+//
+// with LEN the byte length as ASCII decimal. BASE64 is standard or
+// URL-safe, padded or not, as the spec allows; the exporter writes
+// standard padded.
+//
+// A signature is tried when -pubkey is given, or when its keyid is in
+// -keyring (a JSON object kid → public key hex, as for verify-paseto);
+// one valid signature is enough. Under sectionDSSE:
+//
+//   D.payload_type       the GEF record type, nothing else   (structure)
+//   D.key                a signature has a key to verify   (completeness)
+//   D.signature          one of them is valid                (integrity)
+//
+// then the payload, once the signature verifies, is verified as a PASETO
+// record is (paseto.go checkRecord): every contract of a proof bundle,
+// with the signature over PAE, so a payload that is not its own JCS form
+// fails CONTRACT 1; then causal_hash against -prev or genesis.
+//
+// -export-dsse wraps a bundle that verifies, signed with -key (keygen.go)
+// if it is the bundle's own key; keyid is the key fingerprint. Like a
+// JWS (jws.go), the envelope signature covers more than the canonical
+// bytes and is never signature_b64url: the key and the payload bind it
+// to the bundle.
+
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+const dssePayloadType = "application/vnd.gef.record+json"
+
+const sectionDSSE = "DSSE — Signed Envelope"
+
+// dsseEnvelope is a DSSE envelope as JSON.
+type dsseEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     string          `json:"payload"`
+	Signatures  []dsseSignature `json:"signatures"`
+}
+
+type dsseSignature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   string `json:"sig"`
+}
+
+// dssePAE is the DSSE v1 pre-authentication encoding of payload.
+func dssePAE(payloadType string, payload []byte) []byte {
+	out := []byte("DSSEv1 ")
+	out = strconv.AppendInt(out, int64(len(payloadType)), 10)
+	out = append(out, ' ')
+	out = append(out, payloadType...)
+	out = append(out, ' ')
+	out = strconv.AppendInt(out, int64(len(payload)), 10)
+	out = append(out, ' ')
+	return append(out, payload...)
+}
+
+// decodeDSSEBase64 decodes s in any of the encodings DSSE allows.
+func decodeDSSEBase64(s string) ([]byte, error) {
+	enc := base64.StdEncoding
+	if strings.ContainsAny(s, "-_") {
+		enc = base64.URLEncoding
+	}
+	if !strings.HasSuffix(s, "=") && len(s)%4 != 0 {
+		enc = enc.WithPadding(base64.NoPadding)
+	}
+	return enc.Strict().DecodeString(s)
+}
+
+// parseDSSE decodes an envelope and its payload. Errors are
+// *MalformedError.
+func parseDSSE(data []byte) (dsseEnvelope, []byte, error) {
+	var env dsseEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return env, nil, &gefverify.MalformedError{Reason: "not a DSSE envelope", Err: err}
+	}
+	if len(env.Signatures) == 0 {
+		return env, nil, &gefverify.MalformedError{Reason: "DSSE envelope",
+			Err: fmt.Errorf("no signatures")}
+	}
+	payload, err := decodeDSSEBase64(env.Payload)
+	if err != nil {
+		return env, nil, &gefverify.MalformedError{Reason: "DSSE payload", Err: err}
+	}
+	return env, payload, nil
+}
+
+// dsseRequest is everything verifyDSSE needs besides the envelope.
+type dsseRequest struct {
+	Pinned   ed25519.PublicKey            // -pubkey; wins over Keyring
+	Keyring  map[string]ed25519.PublicKey // keyid → key
+	PrevHash string                       // expected causal_hash, may be empty
+}
+
+// ── Verification ──────────────────────────────────────────────────────────────
+
+// verifyDSSE verifies an envelope and the GEF record it carries. The
+// error is set only for envelopes that cannot be decoded at all.
+func verifyDSSE(data []byte, req dsseRequest) (gefverify.Report, error) {
+	var report gefverify.Report
+	check := func(id string, category gefverify.Category, name string, passed bool, details string) {
+		report.Checks = append(report.Checks, gefverify.CheckResult{
+			ID: id, Section: sectionDSSE, Name: name, Passed: passed, Details: details, Category: category,
+		})
+	}
+	done := func() (gefverify.Report, error) {
+		report.Verdict = gefverify.DeriveVerdict(report.Checks)
+		return report, nil
+	}
+
+	env, payload, err := parseDSSE(data)
+	if err != nil {
+		report.Verdict = gefverify.VerdictMalformed
+		return report, err
+	}
+
+	check("D.payload_type", gefverify.CategoryStructure, "payloadType is the GEF record type",
+		env.PayloadType == dssePayloadType, fmt.Sprintf("payloadType=%q", env.PayloadType))
+
+	// ── Key selection ──
+	type candidate struct {
+		sig dsseSignature
+		pub ed25519.PublicKey
+	}
+	var candidates []candidate
+	source := "pinned -pubkey"
+	for _, s := range env.Signatures {
+		pub := req.Pinned
+		if pub == nil {
+			pub, source = req.Keyring[s.KeyID], "keyring"
+		}
+		if pub != nil {
+			candidates = append(candidates, candidate{s, pub})
+		}
+	}
+	if len(candidates) == 0 {
+		check("D.key", gefverify.CategoryCompleteness, "verification key available", false,
+			fmt.Sprintf("no keyid of %d signature(s) in keyring and no -pubkey was given", len(env.Signatures)))
+		return done()
+	}
+	check("D.key", gefverify.CategoryCompleteness, "verification key available", true,
+		fmt.Sprintf("%s  %d of %d signature(s)", source, len(candidates), len(env.Signatures)))
+
+	pae := dssePAE(env.PayloadType, payload)
+	var signer ed25519.PublicKey
+	var signature []byte
+	for _, c := range candidates {
+		sig, err := decodeDSSEBase64(c.sig.Sig)
+		if err == nil && ed25519.Verify(c.pub, pae, sig) {
+			signer, signature = c.pub, sig
+			break
+		}
+	}
+	details := fmt.Sprintf("payload=%d bytes  none of %d signature(s) verify", len(payload), len(candidates))
+	if signer != nil {
+		details = fmt.Sprintf("payload=%d bytes  pubkey=%s...", len(payload), hex.EncodeToString(signer)[:16])
+	}
+	check("D.signature", gefverify.CategoryIntegrity, "envelope signature valid over PAE", signer != nil, details)
+	if signer == nil {
+		return done()
+	}
+
+	encode := func(message []byte) []byte { return dssePAE(env.PayloadType, message) }
+	checkRecord(&report, payload, signer, signature, encode, req.Pinned, req.Keyring, req.PrevHash)
+	return done()
+}
+
+// exportDSSE wraps the signing_dict of bundle in an envelope signed with
+// key.
+func exportDSSE(bundle gefverify.ProofBundle, key ed25519.PrivateKey) (dsseEnvelope, error) {
+	canonical, err := gefverify.Canonicalize(bundle.SigningDict)
+	if err != nil {
+		return dsseEnvelope{}, err
+	}
+	sig := ed25519.Sign(key, dssePAE(dssePayloadType, canonical))
+	return dsseEnvelope{
+		PayloadType: dssePayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(canonical),
+		Signatures: []dsseSignature{{
+			KeyID: gefverify.KeyFingerprint(key.Public().(ed25519.PublicKey)),
+			Sig:   base64.StdEncoding.EncodeToString(sig),
+		}},
+	}, nil
+}
+
+// ── Subcommand ────────────────────────────────────────────────────────────────
+
+func runVerifyDSSE(args []string) int {
+	fs := newFlagSet("verify-dsse")
+	pubHex := fs.String("pubkey", "", "pinned Ed25519 public key `hex` or env:NAME")
+	keyringPath := fs.String("keyring", "", "JSON `file` (or env:NAME, -) mapping keyid to public key hex")
+	prevHash := fs.String("prev", "", "chain `hash` the record's causal_hash must link to")
+	export := fs.Bool("export-dsse", false, "print the bundle as a DSSE envelope signed with -key instead of verifying one")
+	keySpec := fs.String("key", "", "Ed25519 private key `file` or env:NAME, hex or PEM, for -export-dsse")
+	bundlePath := fs.String("bundle", "proof_bundle.json", "proof bundle `file` for -export-dsse (or env:NAME, -)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof verify-dsse (-pubkey hex | -keyring keys.json) [-prev hash] <envelope.json>")
+		fmt.Fprintln(stderr, "       verify_proof verify-dsse -export-dsse -key signer.key [-bundle b.json]")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+	if *export {
+		if fs.NArg() != 0 || *keySpec == "" {
+			fs.Usage()
+			return 2
+		}
+		return runExportDSSE(*bundlePath, *keySpec)
+	}
+	if fs.NArg() != 1 || (*pubHex == "" && *keyringPath == "") {
+		fs.Usage()
+		return 2
+	}
+
+	req := dsseRequest{PrevHash: *prevHash}
+	if *pubHex != "" {
+		value, err := inlineValue(*pubHex)
+		var pub ed25519.PublicKey
+		if err == nil {
+			pub, err = gefverify.DecodePublicKey(value)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: -pubkey: %v\n", err)
+			return 2
+		}
+		req.Pinned = pub
+	}
+	if *keyringPath != "" {
+		keys, err := loadKeyring(*keyringPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: -keyring: %v\n", err)
+			return 2
+		}
+		req.Keyring = keys
+	}
+
+	data, err := readInput(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", fs.Arg(0), err)
+		return 2
+	}
+
+	bar := console.bar
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout, "  GEF DSSE Envelope — Go Verifier")
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+
+	report, err := verifyDSSE(data, req)
+	if err != nil {
+		fatalMalformed(err)
+	}
+
+	printChecks(report.Checks)
+	printNotes(report.Notes)
+
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	verdict := report.Verdict
+	if verdict == gefverify.VerdictVerified {
+		fmt.Fprintf(stdout, "  %s  DSSE RECORD VERIFIED  (%d/%d checks)  verdict=%s\n\n", console.pass,
+			report.Passed(), report.Total(), verdict)
+	} else {
+		fmt.Fprintf(stdout, "  %s  DSSE RECORD FAILED  (%d/%d checks passed)  verdict=%s\n\n", console.fail,
+			report.Passed(), report.Total(), verdict)
+		printFailures(report.Failed())
+	}
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+	return verdict.ExitCode()
+}
+
+// runExportDSSE prints the bundle at bundlePath as an envelope signed
+// with the key at keySpec, if the bundle verifies and the key is its own.
+func runExportDSSE(bundlePath, keySpec string) int {
+	data, err := readInput(bundlePath)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", bundlePath, err)
+		return 2
+	}
+	bundle, err := gefverify.ParseBundle(data)
+	if err != nil {
+		fatalMalformed(parseError(inputName(bundlePath), data, err))
+	}
+	raw, err := readInput(keySpec)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read key %s: %v\n", keySpec, err)
+		return 2
+	}
+	key, err := parseSigningKey(string(raw))
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %s: %v\n", keySpec, err)
+		return 2
+	}
+	if pubHex := hex.EncodeToString(key.Public().(ed25519.PublicKey)); !strings.EqualFold(pubHex, bundle.PublicKeyHex) {
+		fmt.Fprintf(stderr, "FATAL: %s is not the bundle key (%s...)\n", keySpec, gefverify.ShortHex(bundle.PublicKeyHex, 16))
+		return 2
+	}
+	report, err := gefverify.Verify(bundle, gefverify.VerifyOptions{})
+	if err != nil {
+		fatalMalformed(err)
+	}
+	if !report.OK() {
+		fmt.Fprintf(stderr, "FATAL: bundle does not verify (verdict %s); not exporting it\n", report.Verdict)
+		return report.Verdict.ExitCode()
+	}
+	env, err := exportDSSE(bundle, key)
+	if err == nil {
+		data, err = json.MarshalIndent(env, "", "  ")
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, string(data))
+	return 0
+}
@@ -0,0 +1,151 @@
+// cross_lang_proof/dsse_test.go
+
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+type dsseVectors struct {
+	PAE []struct {
+		PayloadType string `json:"payload-type"`
+		Payload     string `json:"payload"`
+		PAEHex      string `json:"pae-hex"`
+	} `json:"pae"`
+	Envelope struct {
+		PublicKey string          `json:"public-key"`
+		JSON      json.RawMessage `json:"json"`
+	} `json:"envelope"`
+}
+
+func loadDSSEVectors(t *testing.T) dsseVectors {
+	t.Helper()
+	var v dsseVectors
+	if err := json.Unmarshal(mustRead(t, "testdata/dsse_vectors.json"), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// TestDSSEPAEVectors checks the pre-authentication encoding byte for
+// byte, including the example of the DSSE protocol.
+func TestDSSEPAEVectors(t *testing.T) {
+	for _, v := range loadDSSEVectors(t).PAE {
+		if got := hex.EncodeToString(dssePAE(v.PayloadType, []byte(v.Payload))); got != v.PAEHex {
+			t.Errorf("PAE(%q, %q)\n got %s\nwant %s", v.PayloadType, v.Payload, got, v.PAEHex)
+		}
+	}
+}
+
+func TestVerifyDSSE(t *testing.T) {
+	vectors := loadDSSEVectors(t)
+	pub, err := gefverify.DecodePublicKey(vectors.Envelope.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var env dsseEnvelope
+	if err := json.Unmarshal(vectors.Envelope.JSON, &env); err != nil {
+		t.Fatal(err)
+	}
+	seed, _ := hex.DecodeString(proofSeed)
+	priv := ed25519.NewKeyFromSeed(seed)
+	resign := func(e dsseEnvelope, payload []byte) dsseEnvelope {
+		e.Payload = base64.StdEncoding.EncodeToString(payload)
+		e.Signatures = []dsseSignature{{Sig: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, dssePAE(e.PayloadType, payload)))}}
+		return e
+	}
+	payload, _ := decodeDSSEBase64(env.Payload)
+
+	urlSafe := env
+	urlSafe.Payload = base64.RawURLEncoding.EncodeToString(payload)
+	sig, _ := decodeDSSEBase64(env.Signatures[0].Sig)
+	urlSafe.Signatures = []dsseSignature{{Sig: base64.RawURLEncoding.EncodeToString(sig)}}
+	tampered := env
+	tampered.Signatures = []dsseSignature{{Sig: base64.StdEncoding.EncodeToString(append([]byte{sig[0] ^ 1}, sig[1:]...))}}
+	otherType := env
+	otherType.PayloadType = "application/vnd.in-toto+json"
+	var dict map[string]interface{}
+	must(t, json.Unmarshal(payload, &dict))
+	indented, _ := json.MarshalIndent(dict, "", "  ")
+
+	pinned := dsseRequest{Pinned: pub}
+	keyring := dsseRequest{Keyring: map[string]ed25519.PublicKey{env.Signatures[0].KeyID: pub}}
+	tests := []struct {
+		name   string
+		env    dsseEnvelope
+		req    dsseRequest
+		failed []string
+	}{
+		{"pinned", env, pinned, nil},
+		{"keyring", env, keyring, nil},
+		{"url-safe unpadded", urlSafe, pinned, nil},
+		{"keyid not in keyring", urlSafe, keyring, []string{"D.key"}},
+		{"tampered signature", tampered, pinned, []string{"D.signature"}},
+		{"other payloadType", otherType, pinned, []string{"D.payload_type", "D.signature"}},
+		{"other payloadType, re-signed", resign(otherType, payload), pinned, []string{"D.payload_type"}},
+		// Signed as sent, but not the canonical bytes of GEF signatures.
+		{"indented payload", resign(env, indented), pinned, []string{"C1.canonical_bytes", "C3.signature_go", "C6.original_intact"}},
+	}
+	for _, tt := range tests {
+		data, _ := json.Marshal(tt.env)
+		report, err := verifyDSSE(data, tt.req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var failed []string
+		for _, c := range report.Failed() {
+			failed = append(failed, c.ID)
+		}
+		if strings.Join(failed, ",") != strings.Join(tt.failed, ",") {
+			t.Errorf("%s: failed %v, want %v", tt.name, failed, tt.failed)
+		}
+	}
+
+	for _, bad := range []string{`[]`, `{"payloadType":"x","payload":"e30="}`, `{"payload":"!!","signatures":[{"sig":""}]}`} {
+		if report, err := verifyDSSE([]byte(bad), pinned); err == nil || report.Verdict != gefverify.VerdictMalformed {
+			t.Errorf("%s: verdict=%s err=%v, want MALFORMED", bad, report.Verdict, err)
+		}
+	}
+}
+
+func TestExportDSSE(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "signer.key")
+	must(t, os.WriteFile(keyPath, []byte(proofSeed), 0o600))
+
+	code, out, errOut := runCaptured(t, "verify-dsse", "-export-dsse", "-key", keyPath)
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	// Ed25519 is deterministic: the same envelope as the Node vector.
+	var got, want dsseEnvelope
+	must(t, json.Unmarshal([]byte(out), &got))
+	vectors := loadDSSEVectors(t)
+	must(t, json.Unmarshal(vectors.Envelope.JSON, &want))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("exported %+v\nwant     %+v", got, want)
+	}
+
+	envPath := filepath.Join(dir, "record.dsse.json")
+	must(t, os.WriteFile(envPath, []byte(out), 0o644))
+	code, out, _ = runCaptured(t, "verify-dsse", "-pubkey", vectors.Envelope.PublicKey, envPath)
+	if code != 0 || !strings.Contains(out, "DSSE RECORD VERIFIED") {
+		t.Errorf("round trip: exit %d\n%s", code, out)
+	}
+
+	other := filepath.Join(dir, "other.key")
+	must(t, os.WriteFile(other, []byte(strings.Repeat("22", 32)), 0o600))
+	if code, out, errOut := runCaptured(t, "verify-dsse", "-export-dsse", "-key", other); code != 2 || out != "" || !strings.Contains(errOut, "not the bundle key") {
+		t.Errorf("wrong key: exit %d out=%q err=%q", code, out, errOut)
+	}
+}
@@ -0,0 +1,296 @@
+// cross_lang_proof/emit.go
+//
+// GEF Cross-Language Proof — Go Emitter (emit subcommand)
+// ========================================================
+//
+//   verify_proof emit -key seed.hex -agent a [-payload p.json] [-out b.json]
+//   verify_proof emit -key env:GEF_SEED -agent a -sequence 4 -prev <hash> ...
+//
+// The reverse of emit_proof.py: Go signs, Python verifies. emit builds
+// one record with gefverify.RecordBuilder (the 10-field signing_dict,
+// JCS, Ed25519, SHA-256 chain hash) and writes the proof bundle the way
+// emit_proof.py does, so either side's verifier reads either side's
+// output:
+//
+//   - the same fields in the same order, including canonical_bytes_b64
+//     and expected_results, which the Go verifier does not read
+//   - Python's json.dumps layout: indent=2, ", " and ": " in
+//     envelope_json, non-ASCII as \uXXXX, no trailing newline
+//
+// With emit_proof.py's seed and fixed fields, the output is
+// proof_bundle.json byte for byte (emit_test.go). Two caveats for other
+// payloads: nested object keys are written sorted, where Python keeps
+// insertion order, and numbers go through float64, so an integral float
+// such as 2.0 is written 2. Neither changes canonical bytes, signature or
+// chain hash.
+//
+// -key is the Ed25519 private key as hex: the 32-byte seed, or seed and
+// public key (64 bytes); or as PKCS#8 PEM (keygen.go). -payload is a JSON object file, default {}.
+// -sequence 0 without -prev is a genesis record. -nonce, -record-id and
+// -timestamp fix what is otherwise drawn or taken from the clock.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// goEmitterDescription is _description of the bundles emit writes.
+const goEmitterDescription = "GEF Cross-Language Proof Bundle. Go emitter → Python verifier. " +
+	"All values must match independently computed Python output."
+
+func runEmit(args []string) int {
+	fs := newFlagSet("emit")
+	keySpec := fs.String("key", "",
+		"Ed25519 private key `file`, or env:NAME: hex of the 32-byte seed or the 64-byte key, or PEM")
+	agent := fs.String("agent", "", "agent_id of the record")
+	recordType := fs.String("type", "execution", "record_type of the record")
+	payloadSpec := fs.String("payload", "", "JSON object `file` (or - or env:NAME) to use as payload; default {}")
+	sequence := fs.Int64("sequence", 0, "sequence of the record; 0 without -prev is a genesis record")
+	prev := fs.String("prev", "", "chain `hash` of the agent's previous record")
+	nonce := fs.String("nonce", "", "fixed nonce (32+ lowercase hex characters); default random")
+	recordID := fs.String("record-id", "", "fixed record_id; default gef-<random UUID>")
+	stamp := fs.String("timestamp", "", "fixed timestamp (RFC 3339); default now")
+	description := fs.String("description", goEmitterDescription, "_description of the bundle")
+	out := fs.String("out", "-", "write the bundle to this `file`; - for stdout")
+	parseFlags(fs, args)
+	if *keySpec == "" || *agent == "" || fs.NArg() > 0 {
+		fmt.Fprintln(stderr, "usage: verify_proof emit -key seed.hex -agent id [-type t] [-payload p.json] [-sequence n -prev hash] [-nonce n] [-record-id id] [-timestamp t] [-out b.json]")
+		fs.PrintDefaults()
+		return 2
+	}
+
+	raw, err := readInput(*keySpec)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read key %s: %v\n", *keySpec, err)
+		return 2
+	}
+	key, err := parseSigningKey(string(raw))
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %s: %v\n", *keySpec, err)
+		return 2
+	}
+
+	b := gefverify.NewRecord(*agent, *recordType).Nonce(*nonce).RecordID(*recordID)
+	if *sequence == 0 && *prev == "" {
+		b.Genesis()
+	} else {
+		b.Sequence(*sequence).PreviousHash(*prev)
+	}
+	if *payloadSpec != "" {
+		data, err := readInput(*payloadSpec)
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot read payload %s: %v\n", *payloadSpec, err)
+			return 2
+		}
+		var payload map[string]interface{}
+		if err := json.Unmarshal(data, &payload); err != nil || payload == nil {
+			fmt.Fprintf(stderr, "FATAL: payload %s must be a JSON object: %v\n", *payloadSpec, err)
+			return 2
+		}
+		b.Payload(payload)
+	}
+	if *stamp != "" {
+		at, err := time.Parse(time.RFC3339Nano, *stamp)
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: invalid -timestamp: %v\n", err)
+			return 2
+		}
+		b.At(at)
+	}
+
+	env, bundle, err := b.Finalize(key)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %v\n", err)
+		return 2
+	}
+	data := emitBundleJSON(env, bundle, *description)
+	if *out == "-" {
+		stdout.Write(data)
+		fmt.Fprintln(stdout)
+		return 0
+	}
+	if err := writeFileAtomic(*out, data, 0o644); err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", *out, err)
+		return 2
+	}
+	fmt.Fprintf(stderr, "proof bundle written to %s (causal_hash_of_this %s)\n", *out, bundle.CausalHashOfThis)
+	return 0
+}
+
+// emitBundleJSON lays out bundle as emit_proof.py does.
+func emitBundleJSON(env gefverify.Envelope, bundle gefverify.ProofBundle, description string) []byte {
+	canonical, _ := hex.DecodeString(bundle.CanonicalBytesHex)
+	ledger := pyObject{
+		{"agent_id", env.AgentID},
+		{"causal_hash", env.CausalHash},
+		{"gef_version", env.GEFVersion},
+		{"nonce", env.Nonce},
+		{"payload", bundle.SigningDict["payload"]},
+		{"record_id", env.RecordID},
+		{"record_type", env.RecordType},
+		{"sequence", env.Sequence},
+		{"signer_public_key", env.SignerPublicKey},
+		{"timestamp", env.Timestamp},
+		{"signature", env.Signature},
+	}
+	var envelope bytes.Buffer
+	writePyJSON(&envelope, ledger, "", "")
+
+	doc := pyObject{
+		{"_description", description},
+		{"gef_version", bundle.GEFVersion},
+		{"public_key_hex", bundle.PublicKeyHex},
+		{"signing_dict", bundle.SigningDict},
+		{"canonical_bytes_hex", bundle.CanonicalBytesHex},
+		{"canonical_bytes_b64", base64.StdEncoding.EncodeToString(canonical)},
+		{"chain_dict", bundle.ChainDict},
+		{"chain_bytes_hex", bundle.ChainBytesHex},
+		{"causal_hash_of_this", bundle.CausalHashOfThis},
+		{"signature_b64url", bundle.SignatureB64URL},
+		{"signature_hex", bundle.SignatureHex},
+		{"envelope_json", envelope.String()},
+		{"expected_results", pyObject{
+			{"canonical_bytes_match", true},
+			{"chain_hash_match", true},
+			{"signature_valid", true},
+		}},
+	}
+	var buf bytes.Buffer
+	writePyJSON(&buf, doc, "  ", "")
+	return buf.Bytes()
+}
+
+// ── Python json.dumps layout ───────────────────────────────────
+
+// pyObject is a JSON object whose members keep their order.
+type pyObject []pyMember
+
+type pyMember struct {
+	Key   string
+	Value interface{}
+}
+
+// writePyJSON writes v as Python's json.dumps(v, indent=...) would: with
+// indent "" on one line with ", " and ": ", else one member per line.
+// Maps are written with sorted keys.
+func writePyJSON(buf *bytes.Buffer, v interface{}, indent, prefix string) {
+	open := func(bracket byte, empty bool) (sep, inner string) {
+		buf.WriteByte(bracket)
+		if indent == "" || empty {
+			return ", ", prefix
+		}
+		inner = prefix + indent
+		buf.WriteString("\n" + inner)
+		return ",\n" + inner, inner
+	}
+	closed := func(bracket byte, empty bool) {
+		if indent != "" && !empty {
+			buf.WriteString("\n" + prefix)
+		}
+		buf.WriteByte(bracket)
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		obj := make(pyObject, len(keys))
+		for i, k := range keys {
+			obj[i] = pyMember{k, v[k]}
+		}
+		writePyJSON(buf, obj, indent, prefix)
+	case pyObject:
+		sep, inner := open('{', len(v) == 0)
+		for i, m := range v {
+			if i > 0 {
+				buf.WriteString(sep)
+			}
+			writePyString(buf, m.Key)
+			buf.WriteString(": ")
+			writePyJSON(buf, m.Value, indent, inner)
+		}
+		closed('}', len(v) == 0)
+	case []interface{}:
+		sep, inner := open('[', len(v) == 0)
+		for i, e := range v {
+			if i > 0 {
+				buf.WriteString(sep)
+			}
+			writePyJSON(buf, e, indent, inner)
+		}
+		closed(']', len(v) == 0)
+	case string:
+		writePyString(buf, v)
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		buf.WriteString(pyNumber(v))
+	case nil:
+		buf.WriteString("null")
+	default:
+		panic(fmt.Sprintf("writePyJSON: unexpected %T", v))
+	}
+}
+
+// pyNumber formats a decoded JSON number: integral values as Python ints,
+// others as Python's float repr.
+func pyNumber(f float64) string {
+	if f == math.Trunc(f) && math.Abs(f) < 1e16 {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	exp := math.Floor(math.Log10(math.Abs(f)))
+	if exp < -4 || exp >= 16 {
+		return strconv.FormatFloat(f, 'e', -1, 64)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// writePyString writes s as json.dumps does with ensure_ascii: short
+// escapes for the usual controls, \uXXXX for the rest and for anything
+// outside ASCII, as UTF-16 surrogate pairs beyond the BMP.
+func writePyString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"':
+			buf.WriteString(`\"`)
+		case r == '\\':
+			buf.WriteString(`\\`)
+		case r == '\n':
+			buf.WriteString(`\n`)
+		case r == '\r':
+			buf.WriteString(`\r`)
+		case r == '\t':
+			buf.WriteString(`\t`)
+		case r == '\b':
+			buf.WriteString(`\b`)
+		case r == '\f':
+			buf.WriteString(`\f`)
+		case r < 0x20 || (r > 0x7e && r < 0x10000) || r == utf8.RuneError:
+			fmt.Fprintf(buf, `\u%04x`, r)
+		case r >= 0x10000:
+			r -= 0x10000
+			fmt.Fprintf(buf, `\u%04x\u%04x`, 0xd800+(r>>10), 0xdc00+(r&0x3ff))
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
@@ -131,7 +131,7 @@ def main():
     print(f"  signature_b64url    : {sig_b64url[:32]}...")
     print(f"  signature_valid     : True")
     print()
-    print("Now run: go run verify_proof.go")
+    print("Now run: go run .")
 
 
 if __name__ == "__main__":
@@ -0,0 +1,112 @@
+// cross_lang_proof/emit_test.go
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// proofSeed is PROOF_SEED of emit_proof.py.
+const proofSeed = "deadbeefdeadbeefdeadbeefdeadbeefcafebabecafebabecafebabecafebabe"
+
+func TestEmitReproducesPythonBundle(t *testing.T) {
+	dir := t.TempDir()
+	payload := filepath.Join(dir, "payload.json")
+	must(t, os.WriteFile(payload, []byte(`{"proof": "cross-language", "version": "1.0"}`), 0o644))
+	t.Setenv("GEF_SEED", proofSeed)
+	out := filepath.Join(dir, "proof_bundle.json")
+
+	// The fixed fields of emit_proof.py.
+	code, _, errOut := runCaptured(t, "emit", "-key", "env:GEF_SEED", "-agent", "cross-lang-proof-agent",
+		"-payload", payload, "-nonce", "abcdef1234567890abcdef1234567890", "-record-id", "gef-cross-lang-proof-v1",
+		"-timestamp", "2026-02-25T00:00:00Z", "-out", out,
+		"-description", "GEF Cross-Language Proof Bundle. Python emitter → Go verifier. All values must match independently computed Go output.")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	if got, want := mustRead(t, out), mustRead(t, "proof_bundle.json"); !bytes.Equal(got, want) {
+		t.Errorf("Go emitter output differs from emit_proof.py's proof_bundle.json:\n%s", got)
+	}
+}
+
+func TestEmitRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	payload := filepath.Join(dir, "payload.json")
+	must(t, os.WriteFile(payload, []byte(`{"tool": "déploy → prod", "args": [1, 2.5, {"deep": null}], "ok": true}`), 0o644))
+	key := filepath.Join(dir, "seed.hex")
+	must(t, os.WriteFile(key, []byte(strings.Repeat("5a", 32)+"\n"), 0o600))
+
+	emit := func(out string, args ...string) {
+		t.Helper()
+		code, _, errOut := runCaptured(t, append([]string{"emit", "-key", key, "-agent", "go-agent", "-payload", payload, "-out", out}, args...)...)
+		if code != 0 {
+			t.Fatalf("emit %v: exit %d: %s", args, code, errOut)
+		}
+		contracts := fmt.Sprintf("%d/%d contracts", len(gefverify.RequiredContracts), len(gefverify.RequiredContracts))
+		code, report, _ := runCaptured(t, out)
+		if code != 0 || !strings.Contains(report, "CROSS-LANGUAGE PROOF PASSED") || !strings.Contains(report, contracts) {
+			t.Errorf("%s does not verify: exit %d\n%s", out, code, report)
+		}
+	}
+	genesis := filepath.Join(dir, "b0.json")
+	next := filepath.Join(dir, "b1.json")
+	emit(genesis)
+	emit(next, "-sequence", "1", "-prev", chainHashOf(t, genesis))
+	if code, out, _ := runCaptured(t, "chain", genesis, next); code != 0 {
+		t.Errorf("emitted chain does not link:\n%s", out)
+	}
+}
+
+func TestEmitUsageErrors(t *testing.T) {
+	key := filepath.Join(t.TempDir(), "seed.hex")
+	must(t, os.WriteFile(key, []byte(proofSeed), 0o600))
+	for _, args := range [][]string{
+		{"-agent", "a"},
+		{"-key", key},
+		{"-key", key, "-agent", "a", "-nonce", "short"},
+		{"-key", key, "-agent", "a", "-sequence", "3"},
+	} {
+		if code, _, _ := runCaptured(t, append([]string{"emit"}, args...)...); code != 2 {
+			t.Errorf("emit %v: exit %d, want 2", args, code)
+		}
+	}
+	if _, err := parseSigningKey(strings.Repeat("ab", 64)); err == nil {
+		t.Error("64-byte key with a mismatched public half accepted")
+	}
+}
+
+func TestWritePyJSON(t *testing.T) {
+	// Expected output from CPython's json.dumps.
+	v := pyObject{
+		{"b", []interface{}{1.0, 2.5, map[string]interface{}{}}},
+		{"a", "é→😀\n\t\"\\/<>&\x7f\x01"},
+	}
+	tests := []struct {
+		v      interface{}
+		indent string
+		want   string
+	}{
+		{v, "  ", "{\n  \"b\": [\n    1,\n    2.5,\n    {}\n  ],\n  \"a\": \"\\u00e9\\u2192\\ud83d\\ude00\\n\\t\\\"\\\\/<>&\\u007f\\u0001\"\n}"},
+		{v, "", "{\"b\": [1, 2.5, {}], \"a\": \"\\u00e9\\u2192\\ud83d\\ude00\\n\\t\\\"\\\\/<>&\\u007f\\u0001\"}"},
+		{[]interface{}{}, "  ", "[]"},
+		{1e-07, "", "1e-07"},
+		{1.5e16, "", "1.5e+16"},
+		{0.1, "", "0.1"},
+		{123.456, "", "123.456"},
+		{-2.0, "", "-2"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		writePyJSON(&buf, tt.v, tt.indent, "")
+		if buf.String() != tt.want {
+			t.Errorf("writePyJSON(%v, %q) = %q, want %q", tt.v, tt.indent, buf.String(), tt.want)
+		}
+	}
+}
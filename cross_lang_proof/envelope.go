@@ -0,0 +1,73 @@
+// cross_lang_proof/envelope.go
+//
+// Envelope-only verification (-envelope)
+// ======================================
+//
+//   verify_proof -envelope record.json
+//   tail -n 1 ledger.gef | verify_proof -envelope
+//
+// In production there is no proof bundle, only the envelope: the record
+// with its signature, one ledger line. -envelope reads one and verifies
+// it with gefverify.BundleFromEnvelope: "signature" is stripped, the
+// rest canonicalized with JCS, and the Ed25519 signature checked against
+// the record's own signer_public_key. The header shows the recomputed
+// chain hash in full, the value the next record's causal_hash must hold.
+//
+// Failures say which part is at fault:
+//
+//   signer_public_key  not 32 bytes of hex             MALFORMED
+//   signature          not 64 bytes of base64url       MALFORMED
+//   canonical bytes    the fields do not canonicalize  MALFORMED
+//   signature check    both decode, but do not match   TAMPERED, see below
+//
+// A signature that does not verify cannot tell an edited field from a
+// record signed by another key; the ENVELOPE section says so and shows
+// the key and the size and hash of the bytes it was checked over. The key
+// comes from the record: pin it to one you trust before acting on
+// VERIFIED.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// printEnvelopeHeader describes the envelope read from path.
+func printEnvelopeHeader(path string, bundle gefverify.ProofBundle) {
+	canonical, _ := hex.DecodeString(bundle.CanonicalBytesHex)
+	fmt.Fprintf(stdout, "  Envelope loaded from : %s\n", path)
+	fmt.Fprintf(stdout, "  GEF version          : %s\n", bundle.GEFVersion)
+	fmt.Fprintf(stdout, "  signer_public_key    : %s\n", bundle.PublicKeyHex)
+	fmt.Fprintf(stdout, "  Canonical bytes      : %d (every field but signature, JCS)\n", len(canonical))
+	fmt.Fprintf(stdout, "  Chain hash           : %s\n", bundle.CausalHashOfThis)
+	fmt.Fprintln(stdout, "                         (the next record's causal_hash)")
+	fmt.Fprintln(stdout)
+}
+
+// printEnvelopeDiagnosis explains a signature over the envelope that does
+// not verify. It prints nothing when the signature is valid.
+func printEnvelopeDiagnosis(report gefverify.Report, bundle gefverify.ProofBundle) {
+	failed := false
+	for _, c := range report.Failed() {
+		failed = failed || c.ID == "C3.signature_go"
+	}
+	if !failed {
+		return
+	}
+	canonical, _ := hex.DecodeString(bundle.CanonicalBytesHex)
+	sum := sha256.Sum256(canonical)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  ENVELOPE — why the signature does not verify")
+	fmt.Fprintln(stdout, "  "+console.rule)
+	fmt.Fprintln(stdout, "  Key and signature decode; the signature does not match the")
+	fmt.Fprintln(stdout, "  canonical bytes under signer_public_key. Either a field was")
+	fmt.Fprintln(stdout, "  changed after signing, or the record was signed by another key")
+	fmt.Fprintln(stdout, "  than the one it names.")
+	fmt.Fprintf(stdout, "    signer_public_key  %s\n", bundle.PublicKeyHex)
+	fmt.Fprintf(stdout, "    canonical bytes    %d, sha256 %x\n", len(canonical), sum)
+	fmt.Fprintf(stdout, "    signature          %s\n", bundle.SignatureB64URL)
+}
@@ -0,0 +1,82 @@
+// cross_lang_proof/envelope_test.go
+
+package main
+
+import (
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// writeEnvelopes writes a genesis envelope and the next record's, and
+// returns their paths and the next record's causal_hash.
+func writeEnvelopes(t *testing.T) (genesis, next, link string) {
+	t.Helper()
+	key := ed25519.NewKeyFromSeed([]byte(strings.Repeat("e", 32)))
+	_, first, err := gefverify.NewRecord("envelope-agent", "execution").Genesis().Finalize(key)
+	must(t, err)
+	env, second, err := gefverify.NewRecord("envelope-agent", "execution").
+		Sequence(1).PreviousHash(first.CausalHashOfThis).Finalize(key)
+	must(t, err)
+	dir := t.TempDir()
+	genesis, next = filepath.Join(dir, "genesis.json"), filepath.Join(dir, "next.json")
+	must(t, os.WriteFile(genesis, []byte(first.EnvelopeJSON), 0o644))
+	must(t, os.WriteFile(next, []byte(second.EnvelopeJSON), 0o644))
+	return genesis, next, env.CausalHash
+}
+
+func TestVerifyEnvelope(t *testing.T) {
+	genesis, _, link := writeEnvelopes(t)
+	code, out, errOut := runCaptured(t, "-envelope", genesis)
+	if code != 0 || !strings.Contains(out, "CROSS-LANGUAGE PROOF PASSED") {
+		t.Fatalf("exit %d\n%s%s", code, out, errOut)
+	}
+	if !strings.Contains(out, "Chain hash           : "+link) {
+		t.Errorf("chain hash is not the next record's causal_hash %s:\n%s", link, out)
+	}
+	if strings.Contains(out, "ENVELOPE —") {
+		t.Errorf("diagnosis printed for a valid signature:\n%s", out)
+	}
+}
+
+func TestVerifyEnvelopeFailures(t *testing.T) {
+	genesis, _, _ := writeEnvelopes(t)
+	good := string(mustRead(t, genesis))
+	edit := func(old, new string) string {
+		t.Helper()
+		if !strings.Contains(good, old) {
+			t.Fatalf("%q not in envelope", old)
+		}
+		path := filepath.Join(t.TempDir(), "record.json")
+		must(t, os.WriteFile(path, []byte(strings.Replace(good, old, new, 1)), 0o644))
+		return path
+	}
+
+	code, out, _ := runCaptured(t, "-envelope", edit(`"record_type":"execution"`, `"record_type":"executions"`))
+	if code != gefverify.VerdictTampered.ExitCode() || !strings.Contains(out, "ENVELOPE — why the signature does not verify") ||
+		!strings.Contains(out, "Either a field was") {
+		t.Errorf("edited field: exit %d\n%s", code, out)
+	}
+
+	for _, tt := range []struct{ old, new, want string }{
+		{`"signer_public_key":"`, `"signer_public_key":"00`, "invalid envelope signer_public_key: got 33 bytes"},
+		{`"signature":"`, `"signature":"!`, "invalid envelope signature"},
+		{`"signature":"`, `"signatures":"`, "signature missing"},
+	} {
+		code, _, errOut := runCaptured(t, "-envelope", edit(tt.old, tt.new))
+		if code != gefverify.VerdictMalformed.ExitCode() || !strings.Contains(errOut, tt.want) {
+			t.Errorf("%s: exit %d, stderr %q, want %q", tt.new, code, errOut, tt.want)
+		}
+	}
+}
+
+func TestVerifyEnvelopeBatchRejected(t *testing.T) {
+	genesis, next, _ := writeEnvelopes(t)
+	if code, _, _ := runCaptured(t, "-envelope", genesis, next); code != 2 {
+		t.Errorf("exit %d, want 2", code)
+	}
+}
@@ -0,0 +1,294 @@
+// cross_lang_proof/examples/ingestor/ingestor.go
+//
+// GEF ingestor (example)
+// ======================
+//
+// A small HTTP service built only on the public gefverify API, to prove
+// the library composes end to end:
+//
+//   POST /records                          one envelope (ledger line) per request
+//   GET  /records?agent_id=&record_type=   accepted records, in acceptance order
+//
+// An envelope is accepted only if, in this order:
+//
+//   1. it parses                          else 400, verdict MALFORMED
+//   2. signer_public_key is the key       else 403, verdict VERIFIED_UNTRUSTED_KEY
+//      pinned for its agent_id
+//   3. the Verifier reports OK            else 422 (400 if MALFORMED)
+//   4. its nonce was never accepted       else 409
+//   5. it extends the agent's chain:      else 409
+//      sequence = head + 1 and causal_hash = head chain hash, or
+//      sequence 0 and the genesis hash for a new agent
+//
+// Steps 4 and 5 and the index write happen under one lock, so a rejected
+// envelope consumes nothing: the real record can still be posted after a
+// tampered copy of it was refused.
+//
+// Stores are interfaces. The example ships the in-memory versions; the
+// module carries no database driver, so a persistent index (SQLite or
+// otherwise, via database/sql) is one Index implementation away.
+
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// maxEnvelopeBytes bounds a POST body.
+const maxEnvelopeBytes = 1 << 20
+
+// ── Stores ────────────────────────────────────────────────────────────────────
+
+// Record is one accepted envelope as indexed.
+type Record struct {
+	RecordID   string          `json:"record_id"`
+	AgentID    string          `json:"agent_id"`
+	RecordType string          `json:"record_type"`
+	Sequence   int64           `json:"sequence"`
+	Timestamp  string          `json:"timestamp"`
+	ChainHash  string          `json:"chain_hash"`
+	Envelope   json.RawMessage `json:"envelope"`
+}
+
+// Query selects records; empty fields match anything.
+type Query struct {
+	AgentID    string
+	RecordType string
+}
+
+func (q Query) match(r Record) bool {
+	return (q.AgentID == "" || q.AgentID == r.AgentID) &&
+		(q.RecordType == "" || q.RecordType == r.RecordType)
+}
+
+// Index stores accepted records.
+type Index interface {
+	Put(Record) error
+	Query(Query) ([]Record, error)
+}
+
+// NonceStore remembers the nonces of accepted records.
+type NonceStore interface {
+	Seen(nonce string) bool
+	Add(nonce string) error
+}
+
+type memoryIndex struct {
+	mu      sync.RWMutex
+	records []Record
+}
+
+func (m *memoryIndex) Put(r Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, r)
+	return nil
+}
+
+func (m *memoryIndex) Query(q Query) ([]Record, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []Record{}
+	for _, r := range m.records {
+		if q.match(r) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+type memoryNonces map[string]bool
+
+func (m memoryNonces) Seen(nonce string) bool { return m[nonce] }
+func (m memoryNonces) Add(nonce string) error { m[nonce] = true; return nil }
+
+// ── Ingestor ──────────────────────────────────────────────────────────────────
+
+// head is the last accepted record of one agent.
+type head struct {
+	Sequence  int64
+	ChainHash string
+}
+
+// Ingestor verifies envelopes and indexes the accepted ones.
+type Ingestor struct {
+	verifier *gefverify.Verifier
+	keys     map[string]ed25519.PublicKey // agent_id → pinned signer key
+	index    Index
+
+	mu     sync.Mutex // guards nonces, heads and the index write
+	nonces NonceStore
+	heads  map[string]head
+}
+
+// NewIngestor returns an Ingestor with in-memory stores.
+func NewIngestor(v *gefverify.Verifier, keys map[string]ed25519.PublicKey) *Ingestor {
+	return &Ingestor{
+		verifier: v,
+		keys:     keys,
+		index:    &memoryIndex{},
+		nonces:   memoryNonces{},
+		heads:    make(map[string]head),
+	}
+}
+
+// Decision is the response to one POST /records.
+type Decision struct {
+	Status   int                       `json:"-"`
+	Accepted bool                      `json:"accepted"`
+	Verdict  gefverify.Verdict         `json:"verdict"`
+	Reason   string                    `json:"reason,omitempty"`
+	Record   *Record                   `json:"record,omitempty"`
+	Report   *gefverify.ReportDocument `json:"report,omitempty"`
+}
+
+// Ingest decides on one envelope and, if accepted, indexes it.
+func (in *Ingestor) Ingest(data []byte) Decision {
+	bundle, err := gefverify.BundleFromEnvelope(data)
+	if err != nil {
+		return Decision{Status: http.StatusBadRequest, Verdict: gefverify.VerdictMalformed, Reason: err.Error()}
+	}
+	sd := bundle.SigningDict
+	agentID, _ := sd["agent_id"].(string)
+
+	pinned, ok := in.keys[agentID]
+	if !ok || hex.EncodeToString(pinned) != bundle.PublicKeyHex {
+		return Decision{
+			Status:  http.StatusForbidden,
+			Verdict: gefverify.VerdictVerifiedUntrustedKey,
+			Reason:  fmt.Sprintf("signer_public_key is not the key pinned for agent %q", agentID),
+		}
+	}
+
+	report, err := in.verifier.Verify(bundle)
+	if err != nil || !report.OK() {
+		doc := gefverify.NewReportDocument(report, "POST /records", bundle.GEFVersion)
+		d := Decision{Status: http.StatusUnprocessableEntity, Verdict: report.Verdict, Report: &doc}
+		if report.Verdict == gefverify.VerdictMalformed {
+			d.Status = http.StatusBadRequest
+		}
+		if err != nil {
+			d.Reason = err.Error()
+		} else {
+			d.Reason = fmt.Sprintf("%d check(s) failed", len(report.Failed()))
+		}
+		return d
+	}
+
+	seq, _ := sd["sequence"].(float64)
+	rec := Record{
+		RecordID:   str(sd["record_id"]),
+		AgentID:    agentID,
+		RecordType: str(sd["record_type"]),
+		Sequence:   int64(seq),
+		Timestamp:  str(sd["timestamp"]),
+		ChainHash:  bundle.CausalHashOfThis,
+		Envelope:   json.RawMessage(data),
+	}
+	nonce, causal := str(sd["nonce"]), str(sd["causal_hash"])
+	conflict := func(reason string) Decision {
+		return Decision{Status: http.StatusConflict, Verdict: report.Verdict, Reason: reason}
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if in.nonces.Seen(nonce) {
+		return conflict("nonce " + nonce + " already accepted (replay)")
+	}
+	h, known := in.heads[agentID]
+	switch {
+	case !known && (rec.Sequence != 0 || causal != gefverify.GenesisHash):
+		return conflict(fmt.Sprintf("chain break: agent %q has no records; expected genesis at sequence 0", agentID))
+	case known && rec.Sequence != h.Sequence+1:
+		return conflict(fmt.Sprintf("chain break: sequence %d, expected %d", rec.Sequence, h.Sequence+1))
+	case known && causal != h.ChainHash:
+		return conflict(fmt.Sprintf("chain break: causal_hash %s does not match head %s", causal, h.ChainHash))
+	}
+
+	if err := in.index.Put(rec); err != nil {
+		return Decision{Status: http.StatusInternalServerError, Verdict: report.Verdict, Reason: "index: " + err.Error()}
+	}
+	if err := in.nonces.Add(nonce); err != nil {
+		return Decision{Status: http.StatusInternalServerError, Verdict: report.Verdict, Reason: "nonce store: " + err.Error()}
+	}
+	in.heads[agentID] = head{Sequence: rec.Sequence, ChainHash: rec.ChainHash}
+	return Decision{Status: http.StatusCreated, Accepted: true, Verdict: report.Verdict, Record: &rec}
+}
+
+func str(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// ── HTTP ──────────────────────────────────────────────────────────────────────
+
+// Handler serves the ingest and query API.
+func (in *Ingestor) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/records", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxEnvelopeBytes))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				status := http.StatusBadRequest
+				if errors.As(err, &tooLarge) {
+					status = http.StatusRequestEntityTooLarge
+				}
+				http.Error(w, err.Error(), status)
+				return
+			}
+			d := in.Ingest(data)
+			writeJSON(w, d.Status, d)
+		case http.MethodGet:
+			q := Query{AgentID: r.URL.Query().Get("agent_id"), RecordType: r.URL.Query().Get("record_type")}
+			records, err := in.index.Query(q)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string][]Record{"records": records})
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// loadKeyring reads a JSON object mapping agent_id to public key hex.
+func loadKeyring(path string) (map[string]ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	keys := make(map[string]ed25519.PublicKey, len(raw))
+	for agent, pubHex := range raw {
+		pub, err := gefverify.DecodePublicKey(pubHex)
+		if err != nil {
+			return nil, fmt.Errorf("%s: agent %q: %w", path, agent, err)
+		}
+		keys[agent] = pub
+	}
+	return keys, nil
+}
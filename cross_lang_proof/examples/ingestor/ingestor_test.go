@@ -0,0 +1,149 @@
+// cross_lang_proof/examples/ingestor/ingestor_test.go
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+var (
+	agentKey = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, 32))
+	rogueKey = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{9}, 32))
+)
+
+// emitChain builds genesis plus n-1 linked records for agent-7.
+func emitChain(t *testing.T, n int) []gefverify.ProofBundle {
+	t.Helper()
+	_, genesis, err := gefverify.NewRecord("agent-7", "genesis").Genesis().Finalize(agentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := []gefverify.ProofBundle{genesis}
+	for i := 1; i < n; i++ {
+		_, b, err := gefverify.NewRecord("agent-7", "tool_call").
+			Payload(map[string]interface{}{"step": i}).
+			Sequence(int64(i)).
+			PreviousHash(chain[i-1].CausalHashOfThis).
+			Finalize(agentKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		chain = append(chain, b)
+	}
+	return chain
+}
+
+func post(t *testing.T, url, body string) (int, Decision) {
+	t.Helper()
+	resp, err := http.Post(url+"/records", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var d Decision
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, d
+}
+
+func query(t *testing.T, url, params string) []Record {
+	t.Helper()
+	resp, err := http.Get(url + "/records?" + params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var out struct{ Records []Record }
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	return out.Records
+}
+
+func TestIngestEndToEnd(t *testing.T) {
+	ing := NewIngestor(gefverify.NewVerifier(gefverify.WithRejectWeakKeys(true)),
+		map[string]ed25519.PublicKey{"agent-7": agentKey.Public().(ed25519.PublicKey)})
+	srv := httptest.NewServer(ing.Handler())
+	defer srv.Close()
+
+	chain := emitChain(t, 5)
+	_, rogue, err := gefverify.NewRecord("agent-7", "genesis").Genesis().Finalize(rogueKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := strings.Replace(chain[2].EnvelopeJSON, `"step":2`, `"step":20`, 1)
+	if tampered == chain[2].EnvelopeJSON {
+		t.Fatal("tamper edit did not apply")
+	}
+
+	steps := []struct {
+		name    string
+		body    string
+		status  int
+		verdict gefverify.Verdict
+		reason  string
+	}{
+		{"genesis", chain[0].EnvelopeJSON, 201, gefverify.VerdictVerified, ""},
+		{"record 1", chain[1].EnvelopeJSON, 201, gefverify.VerdictVerified, ""},
+		{"tampered record 2", tampered, 422, gefverify.VerdictTampered, "check(s) failed"},
+		{"record 2 after its tampered copy", chain[2].EnvelopeJSON, 201, gefverify.VerdictVerified, ""},
+		{"replay of record 1", chain[1].EnvelopeJSON, 409, gefverify.VerdictVerified, "replay"},
+		{"record 4 before 3", chain[4].EnvelopeJSON, 409, gefverify.VerdictVerified, "sequence 4, expected 3"},
+		{"record 3", chain[3].EnvelopeJSON, 201, gefverify.VerdictVerified, ""},
+		{"unpinned key", rogue.EnvelopeJSON, 403, gefverify.VerdictVerifiedUntrustedKey, "not the key pinned"},
+		{"not json", "{", 400, gefverify.VerdictMalformed, "cannot parse envelope"},
+	}
+	for _, s := range steps {
+		status, d := post(t, srv.URL, s.body)
+		if status != s.status || d.Verdict != s.verdict || d.Accepted != (s.status == 201) || !strings.Contains(d.Reason, s.reason) {
+			t.Errorf("%s: %d %+v, want %d %s %q", s.name, status, d, s.status, s.verdict, s.reason)
+		}
+		if s.verdict == gefverify.VerdictTampered && (d.Report == nil || d.Report.Verdict != gefverify.VerdictTampered) {
+			t.Errorf("%s: report = %+v, want the TAMPERED report", s.name, d.Report)
+		}
+	}
+
+	records := query(t, srv.URL, "agent_id=agent-7")
+	if len(records) != 4 {
+		t.Fatalf("indexed %d records, want 4", len(records))
+	}
+	for i, r := range records {
+		want := chain[i]
+		if r.Sequence != int64(i) || r.RecordID != want.SigningDict["record_id"] ||
+			r.ChainHash != want.CausalHashOfThis || string(r.Envelope) != want.EnvelopeJSON {
+			t.Errorf("record %d = %+v, want %s", i, r, want.EnvelopeJSON)
+		}
+	}
+	if got := query(t, srv.URL, "record_type=genesis"); len(got) != 1 || got[0].Sequence != 0 {
+		t.Errorf("record_type=genesis → %+v", got)
+	}
+	if got := query(t, srv.URL, "agent_id=agent-x"); len(got) != 0 {
+		t.Errorf("agent-x → %+v, want none", got)
+	}
+}
+
+func TestIngestChainMustStartAtGenesis(t *testing.T) {
+	ing := NewIngestor(gefverify.NewVerifier(),
+		map[string]ed25519.PublicKey{"agent-7": agentKey.Public().(ed25519.PublicKey)})
+	chain := emitChain(t, 2)
+	if d := ing.Ingest([]byte(chain[1].EnvelopeJSON)); d.Status != 409 || !strings.Contains(d.Reason, "expected genesis") {
+		t.Errorf("record 1 first: %+v", d)
+	}
+}
+
+func TestHandlerRejectsOtherMethods(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewIngestor(gefverify.NewVerifier(), nil).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/records", nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, POST" {
+		t.Errorf("DELETE → %d Allow=%q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
@@ -0,0 +1,49 @@
+// cross_lang_proof/examples/ingestor/main.go
+//
+//   go run ./examples/ingestor -keyring agents.json [-addr :8080] [-freshness 5m]
+//
+// agents.json pins one signer key per agent: {"agent-7": "<public key hex>"}.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen `address`")
+	keyringPath := flag.String("keyring", "", "JSON `file` mapping agent_id to pinned public key hex (required)")
+	freshness := flag.Duration("freshness", 0, "reject records whose timestamp is further than `d` from now (0 = off)")
+	flag.Parse()
+	if *keyringPath == "" || flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	keys, err := loadKeyring(*keyringPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: -keyring: %v\n", err)
+		os.Exit(2)
+	}
+	verifier := gefverify.NewVerifier(
+		gefverify.WithRejectWeakKeys(true),
+		gefverify.WithFreshness(*freshness),
+	)
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           NewIngestor(verifier, keys).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintf(os.Stderr, "ingestor: %d pinned agent key(s), listening on %s\n", len(keys), *addr)
+	if err := server.ListenAndServe(); err != nil {
+		fmt.Fprintf(os.Stderr, "FATAL: %v\n", err)
+		os.Exit(1)
+	}
+}
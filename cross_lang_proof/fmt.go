@@ -0,0 +1,219 @@
+// cross_lang_proof/fmt.go
+//
+// GEF Proof Bundle Formatter
+// ==========================
+//
+//   verify_proof fmt [-write] <bundle.json>
+//
+// Re-emits a proof bundle with two-space indentation and sorted keys so it
+// can be handed to a partner in readable form. Formatting is only allowed
+// when it is provably a no-op for verification: the formatter recomputes
+// every verification-relevant value on BOTH the input and the output and
+// refuses to emit anything if a single one differs.
+//
+// Compared values:
+//   - JCS(signing_dict)              → what the signature covers
+//   - JCS(chain_dict) and its SHA-256 → what the next record links to
+//   - Ed25519 signature validity
+//   - JCS(whole bundle)              → nothing else silently changed
+//
+// Numbers are decoded as json.Number so their textual form survives the
+// round trip; any fidelity loss shows up as a canonical-bytes mismatch.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/gowebpki/jcs"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// fmtSemantics holds every value that verification depends on.
+type fmtSemantics struct {
+	CanonicalHex   string
+	ChainBytesHex  string
+	ChainHashHex   string
+	SignatureValid bool
+	DocumentHex    string
+}
+
+// semanticsOf recomputes the verification-relevant values of a bundle.
+func semanticsOf(data []byte) (fmtSemantics, error) {
+	var sem fmtSemantics
+
+	bundle, err := gefverify.ParseBundle(data)
+	if err != nil {
+		return sem, err
+	}
+
+	canonical, err := gefverify.Canonicalize(bundle.SigningDict)
+	if err != nil {
+		return sem, fmt.Errorf("canonicalize signing_dict: %w", err)
+	}
+	chainBytes, err := gefverify.Canonicalize(bundle.ChainDict)
+	if err != nil {
+		return sem, fmt.Errorf("canonicalize chain_dict: %w", err)
+	}
+	chainHash := sha256.Sum256(chainBytes)
+
+	document, err := jcs.Transform(data)
+	if err != nil {
+		return sem, fmt.Errorf("jcs.Transform bundle: %w", err)
+	}
+
+	sem.CanonicalHex  = hex.EncodeToString(canonical)
+	sem.ChainBytesHex = hex.EncodeToString(chainBytes)
+	sem.ChainHashHex  = hex.EncodeToString(chainHash[:])
+	sem.DocumentHex   = hex.EncodeToString(document)
+
+	pubKey, keyErr := gefverify.DecodeBundleKey(bundle.SigAlg, bundle.PublicKeyHex)
+	sigBytes, sigErr := gefverify.DecodeBundleSignature(bundle.SigAlg, bundle.SignatureB64URL)
+	if keyErr == nil && sigErr == nil {
+		sem.SignatureValid = pubKey.Verify(canonical, sigBytes)
+	}
+	return sem, nil
+}
+
+// diffSemantics lists the names of every value that differs.
+func diffSemantics(in, out fmtSemantics) []string {
+	var changed []string
+	if in.CanonicalHex != out.CanonicalHex {
+		changed = append(changed, "canonical_bytes")
+	}
+	if in.ChainBytesHex != out.ChainBytesHex {
+		changed = append(changed, "chain_canonical_bytes")
+	}
+	if in.ChainHashHex != out.ChainHashHex {
+		changed = append(changed, "chain_hash")
+	}
+	if in.SignatureValid != out.SignatureValid {
+		changed = append(changed, "signature_valid")
+	}
+	if in.DocumentHex != out.DocumentHex {
+		changed = append(changed, "bundle canonical form")
+	}
+	return changed
+}
+
+// formatBundle re-emits data with indentation and sorted keys.
+func formatBundle(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("cannot parse proof bundle: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("cannot parse proof bundle: trailing data after JSON object")
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("re-encode bundle: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFileAtomic replaces path with data via a temp file + rename so a
+// crash mid-write never leaves a truncated bundle behind.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
+
+// runFmt implements the fmt subcommand and returns the process exit code.
+func runFmt(args []string) int {
+	fs := newFlagSet("fmt")
+	write := fs.Bool("write", false, "rewrite the bundle file in place (atomic)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof fmt [-write] <bundle.json>")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	bundlePath := fs.Arg(0)
+
+	info, err := os.Stat(bundlePath)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", bundlePath, err)
+		return 2
+	}
+	input, err := os.ReadFile(bundlePath)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", bundlePath, err)
+		return 2
+	}
+
+	output, err := formatBundle(input)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %v\n", err)
+		return gefverify.VerdictMalformed.ExitCode()
+	}
+
+	before, err := semanticsOf(input)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: input: %v\n", err)
+		return gefverify.VerdictMalformed.ExitCode()
+	}
+	after, err := semanticsOf(output)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: formatted output: %v\n", err)
+		return 1
+	}
+	if changed := diffSemantics(before, after); len(changed) > 0 {
+		fmt.Fprintf(stderr, "FATAL: refusing to format %s — verification semantics changed:\n", bundlePath)
+		for _, name := range changed {
+			fmt.Fprintf(stderr, "  changed: %s\n", name)
+		}
+		return 1
+	}
+
+	if !*write {
+		stdout.Write(output)
+		return 0
+	}
+	if err := writeFileAtomic(bundlePath, output, info.Mode().Perm()); err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", bundlePath, err)
+		return 2
+	}
+	fmt.Fprintf(stderr, "formatted %s (verification semantics preserved)\n", bundlePath)
+	return 0
+}
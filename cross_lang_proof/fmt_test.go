@@ -0,0 +1,87 @@
+// cross_lang_proof/fmt_test.go
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFmtWrite(t *testing.T) {
+	input := mustRead(t, "proof_bundle.json")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bundle.json")
+	must(t, os.WriteFile(path, input, 0o600))
+	before, err := os.Stat(path)
+	must(t, err)
+
+	code, out, _ := runCaptured(t, "fmt", path)
+	if code != 0 || string(mustRead(t, path)) != string(input) {
+		t.Fatalf("fmt without -write: exit %d, or the file changed", code)
+	}
+	if code, _, errOut := runCaptured(t, "fmt", "-write", path); code != 0 || !strings.Contains(errOut, "verification semantics preserved") {
+		t.Fatalf("fmt -write: exit %d\n%s", code, errOut)
+	}
+	if got := string(mustRead(t, path)); got != out {
+		t.Errorf("-write wrote\n%s\nwant the stdout of fmt\n%s", got, out)
+	}
+
+	// Replaced by rename, not rewritten: a new file, same mode, no temp
+	// file left behind.
+	after, err := os.Stat(path)
+	must(t, err)
+	if os.SameFile(before, after) {
+		t.Error("-write rewrote the file in place")
+	}
+	if after.Mode().Perm() != 0o600 {
+		t.Errorf("mode %v, want 0600", after.Mode().Perm())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("directory holds %d entries, want 1", len(entries))
+	}
+	if code, _, _ := runCaptured(t, path); code != 0 {
+		t.Errorf("formatted bundle: exit %d", code)
+	}
+}
+
+func TestFmtRefusesSemanticChange(t *testing.T) {
+	// encoding/json matches field names case-insensitively and keeps the
+	// last match. Sorting the keys puts SIGNING_DICT before signing_dict,
+	// so the formatted bundle would be read with the other signing dict.
+	data := string(mustRead(t, "proof_bundle.json"))
+	data = data[:strings.LastIndex(data, "}")] + ",\n  \"SIGNING_DICT\": {\"agent_id\": \"mallory\"}\n}\n"
+	path := filepath.Join(t.TempDir(), "bundle.json")
+	must(t, os.WriteFile(path, []byte(data), 0o644))
+
+	code, out, errOut := runCaptured(t, "fmt", "-write", path)
+	if code != 1 || out != "" || !strings.Contains(errOut, "verification semantics changed") || !strings.Contains(errOut, "changed: canonical_bytes") {
+		t.Errorf("exit %d, stdout %q\n%s", code, out, errOut)
+	}
+	if string(mustRead(t, path)) != data {
+		t.Error("refused -write modified the file")
+	}
+}
+
+func TestDiffSemantics(t *testing.T) {
+	input := string(mustRead(t, "proof_bundle.json"))
+	in, err := semanticsOf([]byte(input))
+	must(t, err)
+	if changed := diffSemantics(in, in); changed != nil {
+		t.Errorf("same bundle: %v", changed)
+	}
+
+	// In both dicts, without re-signing.
+	altered := strings.Replace(input, `"sequence": 0`, `"sequence": 1`, -1)
+	if altered == input {
+		t.Fatal("proof_bundle.json has no sequence 0")
+	}
+	out, err := semanticsOf([]byte(altered))
+	must(t, err)
+	want := []string{"canonical_bytes", "chain_canonical_bytes", "chain_hash", "signature_valid", "bundle canonical form"}
+	if changed := diffSemantics(in, out); !reflect.DeepEqual(changed, want) {
+		t.Errorf("altered sequence: %v, want %v", changed, want)
+	}
+}
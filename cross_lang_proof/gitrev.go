@@ -0,0 +1,54 @@
+// cross_lang_proof/gitrev.go
+//
+// Verify-from-git
+// ===============
+//
+//   verify_proof -git-rev <rev>:<path>
+//
+// Reads the bundle exactly as it was committed at <rev> by shelling out to
+// `git show <rev>:<path>`, so a historical bundle can be audited without
+// checking the revision out. <path> follows git's rules: relative to the
+// repository root, or to the current directory when prefixed with "./".
+//
+// A <rev> starting with "-" is refused: git would read it as an option,
+// and some (--output) write files. --end-of-options guards the argument
+// as well.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// readBundleAtRev returns the contents of <path> at <rev> from git.
+func readBundleAtRev(spec string) ([]byte, error) {
+	rev, path, ok := strings.Cut(spec, ":")
+	if !ok || rev == "" || path == "" {
+		return nil, fmt.Errorf("invalid -git-rev %q: expected <rev>:<path>", spec)
+	}
+	if strings.HasPrefix(rev, "-") {
+		return nil, fmt.Errorf("invalid -git-rev %q: a revision cannot start with \"-\"", spec)
+	}
+
+	gitBin, err := exec.LookPath("git")
+	if err != nil {
+		return nil, errors.New("git executable not found in PATH")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(gitBin, "show", "--end-of-options", rev+":"+path)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("git show %s:%s: %s", rev, path, msg)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,64 @@
+// cross_lang_proof/gitrev_test.go
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// gitRepo makes a repository in a temp directory holding each version
+// of bundle.json as one commit, and points git at it through GIT_DIR.
+func gitRepo(t *testing.T, versions ...[]byte) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not in PATH")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	for i, v := range versions {
+		must(t, os.WriteFile(filepath.Join(dir, "bundle.json"), v, 0o644))
+		git("add", "bundle.json")
+		git("commit", "-q", "-m", "version "+string(rune('1'+i)))
+	}
+	t.Setenv("GIT_DIR", filepath.Join(dir, ".git"))
+}
+
+func TestGitRev(t *testing.T) {
+	good := mustRead(t, "proof_bundle.json")
+	gitRepo(t, good, []byte(tamperSignature(string(good))))
+
+	if code, out, errOut := runCaptured(t, "-git-rev", "HEAD~1:bundle.json"); code != 0 || !strings.Contains(out, "git:HEAD~1:bundle.json") {
+		t.Errorf("HEAD~1: exit %d\n%s%s", code, out, errOut)
+	}
+	if code, _, _ := runCaptured(t, "-git-rev", "HEAD:bundle.json"); code != 1 {
+		t.Errorf("HEAD: exit %d, want 1 (tampered)", code)
+	}
+	if code, _, errOut := runCaptured(t, "-git-rev", "HEAD:absent.json"); code != 2 || !strings.Contains(errOut, "git show HEAD:absent.json") {
+		t.Errorf("absent path: exit %d, stderr %q", code, errOut)
+	}
+}
+
+func TestGitRevRejectsOptions(t *testing.T) {
+	gitRepo(t, mustRead(t, "proof_bundle.json"))
+	written := filepath.Join(t.TempDir(), "written")
+	for _, spec := range []string{"--output=" + written + ":bundle.json", "-p:bundle.json"} {
+		code, _, errOut := runCaptured(t, "-git-rev", spec)
+		if code != 2 || !strings.Contains(errOut, `a revision cannot start with "-"`) {
+			t.Errorf("%s: exit %d, stderr %q", spec, code, errOut)
+		}
+	}
+	if _, err := os.Stat(written); err == nil {
+		t.Error("git wrote --output")
+	}
+}
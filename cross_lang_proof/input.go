@@ -0,0 +1,108 @@
+// cross_lang_proof/input.go
+//
+// Inputs without files
+// ====================
+//
+//   KEY=$(cat pub.hex) verify_proof verify-detached -pubkey env:KEY -sig env:SIG - < body.json
+//   verify_proof -exceptions env:GEF_EXCEPTIONS -report-json - - < bundle.json
+//
+// For serverless and other containers with no usable filesystem, every
+// input can come from the environment or standard input instead:
+//
+//   env:NAME   the value of environment variable NAME (must be set)
+//   -          standard input, once per run
+//   otherwise  a file path
+//
+// verify also reads its bundle from stdin when no path is given and
+// stdin is not a terminal (emit_proof.py | verify_proof); with a
+// terminal it falls back to proof_bundle.json as always. Input that ends
+// mid-document — the producer died, the pipe broke — is reported as
+// truncated, never parsed in part.
+//
+// readInput takes all three: the bundle argument of verify and lint, the
+// verify-detached body, -field-aliases, -exceptions, chain -chain-rules,
+// and verify-paseto -keyring (inline JSON) and @token. Values that are
+// given inline — verify-detached -pubkey and -sig, verify-paseto -key —
+// take env:NAME through inlineValue. -report-json - writes the report
+// document to stdout (see output.go for the streams).
+//
+// Nothing else touches the filesystem unasked: the verifier keeps no
+// cache or checkpoint, and keeps a nonce store or writes a file only
+// where a flag names one (-nonce-db, -report-json, chain -snapshot, ...). The one exception is
+// -cross-verify, which stages a bundle that did not come from disk in
+// the temp directory for the external command.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const envPrefix = "env:"
+
+// readInput returns the data spec names: an environment variable, stdin
+// or a file.
+func readInput(spec string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(spec, envPrefix):
+		v, err := inlineValue(spec)
+		return []byte(v), err
+	case spec == "-":
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(spec)
+}
+
+// inlineValue resolves env:NAME to the variable's value and returns
+// anything else unchanged.
+func inlineValue(s string) (string, error) {
+	name, ok := strings.CutPrefix(s, envPrefix)
+	if !ok {
+		return s, nil
+	}
+	v, set := os.LookupEnv(name)
+	if !set || name == "" {
+		return "", fmt.Errorf("environment variable %q is not set", name)
+	}
+	return v, nil
+}
+
+// isFile reports whether spec names a file.
+func isFile(spec string) bool {
+	return spec != "-" && !strings.HasPrefix(spec, envPrefix)
+}
+
+// inputName is spec as shown in reports and messages.
+func inputName(spec string) string {
+	if spec == "-" {
+		return "(stdin)"
+	}
+	return spec
+}
+
+// stdinPiped reports whether stdin is a pipe or file rather than a
+// terminal.
+func stdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// parseError describes err, from parsing data read from name, for a
+// FATAL line: input cut short is said to be, rather than left to the
+// JSON decoder's "unexpected end".
+func parseError(name string, data []byte, err error) error {
+	var syntax *json.SyntaxError
+	switch {
+	case len(bytes.TrimSpace(data)) == 0:
+		return fmt.Errorf("%s: no input", name)
+	case errors.As(err, &syntax) && syntax.Offset == int64(len(data)):
+		return fmt.Errorf("%s: truncated: input ends after %d bytes, in the middle of the bundle", name, len(data))
+	}
+	return fmt.Errorf("%s: %w", name, err)
+}
@@ -0,0 +1,211 @@
+// cross_lang_proof/input_test.go
+
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// withStdin feeds data to os.Stdin through a pipe for the rest of the test.
+func withStdin(t *testing.T, data []byte) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		w.Write(data)
+		w.Close()
+	}()
+	saved := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() { os.Stdin = saved; r.Close() })
+}
+
+// inEmptyReadOnlyDir runs the rest of the test with an empty, read-only
+// working directory, as in a container without a filesystem.
+func inEmptyReadOnlyDir(t *testing.T) string {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0o555); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.Chdir(wd)
+		os.Chmod(dir, 0o755)
+	})
+	return dir
+}
+
+func assertEmpty(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("working directory touched: %v %v", entries, err)
+	}
+}
+
+func TestVerifyWithoutFiles(t *testing.T) {
+	bundle, err := os.ReadFile("proof_bundle.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GEF_FIELD_ALIASES", `{"1.0": {}}`)
+	t.Setenv("GEF_EXCEPTIONS", `[]`)
+	dir := inEmptyReadOnlyDir(t)
+	withStdin(t, bundle)
+
+	code, out, errOut := runCaptured(t, "-field-aliases", "env:GEF_FIELD_ALIASES", "-exceptions", "env:GEF_EXCEPTIONS",
+		"-report-json", "-", "-")
+	if code != 0 {
+		t.Fatalf("exit %d\nstderr:\n%s", code, errOut)
+	}
+	doc, err := gefverify.ParseReportDocument([]byte(out))
+	if err != nil {
+		t.Fatalf("stdout is not the report document: %v\n%s", err, out)
+	}
+	if doc.Verdict != gefverify.VerdictVerified || doc.Bundle != "(stdin)" {
+		t.Errorf("verdict %s, bundle %q", doc.Verdict, doc.Bundle)
+	}
+	if !strings.Contains(errOut, "CROSS-LANGUAGE PROOF PASSED") {
+		t.Errorf("human report not on stderr:\n%s", errOut)
+	}
+	assertEmpty(t, dir)
+}
+
+func TestVerifyDetachedWithoutFiles(t *testing.T) {
+	key := ed25519.NewKeyFromSeed([]byte(strings.Repeat("e", 32)))
+	_, bundle, err := gefverify.NewRecord("edge-env", "genesis").Genesis().Finalize(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal(bundle.SigningDict)
+	sig, _ := hex.DecodeString(bundle.SignatureHex)
+	t.Setenv("GEF_PUBKEY", hex.EncodeToString(key.Public().(ed25519.PublicKey)))
+	t.Setenv("GEF_SIG", base64.RawURLEncoding.EncodeToString(sig))
+	dir := inEmptyReadOnlyDir(t)
+	withStdin(t, body)
+
+	code, out, errOut := runCaptured(t, "verify-detached", "-pubkey", "env:GEF_PUBKEY", "-sig", "env:GEF_SIG", "-")
+	if code != 0 || !strings.Contains(out, "DETACHED SIGNATURE VERIFIED") {
+		t.Errorf("exit %d\nstdout:\n%s\nstderr:\n%s", code, out, errOut)
+	}
+	assertEmpty(t, dir)
+}
+
+func TestEnvInputUnset(t *testing.T) {
+	code, _, errOut := runCaptured(t, "-exceptions", "env:GEF_TEST_UNSET_VARIABLE", "proof_bundle.json")
+	if code != 2 || !strings.Contains(errOut, `environment variable "GEF_TEST_UNSET_VARIABLE" is not set`) {
+		t.Errorf("exit %d\n%s", code, errOut)
+	}
+}
+
+func TestVerifyPipedStdin(t *testing.T) {
+	bundle, err := os.ReadFile("proof_bundle.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		input []byte
+		code  int
+		want  string
+	}{
+		{"whole bundle", bundle, 0, "Bundle loaded from : (stdin)"},
+		{"truncated", bundle[:len(bundle)/2], gefverify.VerdictMalformed.ExitCode(),
+			"FATAL: (stdin): truncated: input ends after"},
+		{"empty", nil, gefverify.VerdictMalformed.ExitCode(), "FATAL: (stdin): no input"},
+	}
+	for _, tt := range tests {
+		inEmptyReadOnlyDir(t)
+		withStdin(t, tt.input)
+		// No path: stdin is a pipe, so it is read instead of proof_bundle.json.
+		code, out, errOut := runCaptured(t)
+		if code != tt.code || !strings.Contains(out+errOut, tt.want) {
+			t.Errorf("%s: exit %d, want %d and %q\n%s%s", tt.name, code, tt.code, tt.want, out, errOut)
+		}
+	}
+}
+
+// TestStdinMatchesFile pins that the bundle read from stdin verifies
+// exactly as the same bytes read from a file.
+func TestStdinMatchesFile(t *testing.T) {
+	path := writeBundle(t, tamperSignature)
+	docs := make([]gefverify.ReportDocument, 2)
+	for i, args := range [][]string{{path}, {"-"}} {
+		withStdin(t, mustRead(t, path))
+		out := filepath.Join(t.TempDir(), "r.json")
+		runCaptured(t, append([]string{"-report-json", out}, args...)...)
+		readJSON(t, out, &docs[i])
+		docs[i].Bundle = ""
+	}
+	if !reflect.DeepEqual(docs[0], docs[1]) {
+		t.Errorf("stdin and file reports differ:\nfile  %+v\nstdin %+v", docs[0], docs[1])
+	}
+}
+
+func TestShortBundleValues(t *testing.T) {
+	key := `"public_key_hex": "191d5a13a26d64f8d43b0406cda76bbcbf429e7507b88eadfdaa43ba3749dd2b"`
+	for _, edit := range []func(string) string{
+		func(s string) string { return strings.Replace(s, key, `"public_key_hex": "ab"`, 1) },
+		func(s string) string {
+			return strings.Replace(s, `"causal_hash_of_this": "69532200368ce75888f4280261b9cfd82c61588987a5a9cf79b27bbdfe06c42d"`,
+				`"causal_hash_of_this": "ab"`, 1)
+		},
+	} {
+		code, out, errOut := runCaptured(t, writeBundle(t, edit))
+		if code == 0 || strings.Contains(errOut, "internal error") {
+			t.Errorf("exit %d\n%s%s", code, out, errOut)
+		}
+	}
+}
+
+func TestInvalidBundleFieldsAllReported(t *testing.T) {
+	path := writeBundle(t, func(s string) string {
+		s = strings.Replace(s, `"public_key_hex": "191d`, `"public_key_hex": "z91d`, 1)
+		return strings.Replace(s, `"signature_b64url": "B`, `"signature_b64url": "B+`, 1)
+	})
+	code, out, errOut := runCaptured(t, path)
+	if code != gefverify.VerdictMalformed.ExitCode() {
+		t.Fatalf("exit %d\n%s%s", code, out, errOut)
+	}
+	for _, want := range []string{
+		"❌  public_key_hex well-formed",
+		"public_key_hex: illegal character 'z' at offset 0",
+		"❌  signature_b64url well-formed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report lacks %q:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(errOut, "FATAL: invalid proof bundle: public_key_hex: illegal character 'z' at offset 0; signature_b64url: illegal character at offset 1") {
+		t.Errorf("stderr %q", errOut)
+	}
+}
+
+func TestWrongTypeBundleFieldReported(t *testing.T) {
+	path := writeBundle(t, func(s string) string {
+		return strings.Replace(s, `"signature_hex": "`, `"signature_hex": 7, "unused": "`, 1)
+	})
+	code, out, errOut := runCaptured(t, path)
+	if code != gefverify.VerdictMalformed.ExitCode() || !strings.Contains(out, "signature_hex: is a JSON number, want a string") {
+		t.Errorf("exit %d\n%s%s", code, out, errOut)
+	}
+}
@@ -0,0 +1,195 @@
+// cross_lang_proof/junit.go
+//
+// JUnit XML output (-format junit)
+// ================================
+//
+//   verify_proof -format junit bundle.json > TEST-gef.xml
+//   verify_proof -junit TEST-gef.xml bundle.json    human report as usual
+//
+// For CI systems that render JUnit XML (Jenkins, GitLab): one
+// <testsuite> named after the bundle, one <testcase> per check.
+//
+//   classname   the check's section, e.g. "CONTRACT 3 — Ed25519 ..."
+//   name        check ID and name
+//   <failure>   a failed check: message is the name, type the category,
+//               the body the details and diagnostics
+//   <skipped>   a failure accepted under a policy exception, or a
+//               contract that did not apply, e.g. CONTRACT 1 without
+//               canonical_bytes_hex
+//   time        the check's share of its contract's duration: contracts
+//               are timed as phases (metrics.go), not check by check, so
+//               a contract's time is split evenly over its checks
+//
+// The verdict, gef_version and key fingerprint go into <properties>.
+// writeJUnit takes many suites: one is written as a bare <testsuite>,
+// more inside a <testsuites> root, as batch mode does (batch.go). A
+// bundle that cannot be parsed is a suite with one <error> case.
+
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// phaseTimer is a MetricsRecorder that keeps the phase durations.
+type phaseTimer map[string]time.Duration
+
+func (phaseTimer) IncCheckResult(string, string)                   {}
+func (phaseTimer) IncVerdict(gefverify.Verdict)                    {}
+func (p phaseTimer) ObserveDuration(phase string, d time.Duration) { p[phase] += d }
+
+// sectionPhases maps check sections to the phase that times them.
+var sectionPhases = map[string]string{
+	gefverify.SectionCanonicalBytes: gefverify.PhaseCanonicalize,
+	gefverify.SectionChainHash:      gefverify.PhaseChainHash,
+	gefverify.SectionSignature:      gefverify.PhaseSignature,
+	gefverify.SectionDictIdentity:   gefverify.PhaseDictIdentity,
+	gefverify.SectionFieldCount:     gefverify.PhaseFieldCount,
+	gefverify.SectionNegativeTest:   gefverify.PhaseNegativeTest,
+	gefverify.SectionVersionBinding: gefverify.PhaseVersionBinding,
+	gefverify.SectionEnvelope:       gefverify.PhaseEnvelope,
+	gefverify.SectionTimestamp:      gefverify.PhaseTimestamp,
+	gefverify.SectionKeyTrust:       gefverify.PhaseKeyTrust,
+	gefverify.SectionSignerBinding:  gefverify.PhaseSignerBinding,
+	gefverify.SectionFreshness:      gefverify.PhasePolicy,
+	gefverify.SectionSizeLimits:     gefverify.PhaseSizeLimits,
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	XMLName    xml.Name        `xml:"testsuite"`
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	Cases      []junitCase     `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitCase struct {
+	Classname string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure"`
+	Error     *junitFailure `xml:"error"`
+	Skipped   *junitSkipped `xml:"skipped"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// newJUnitSuite converts the report of the bundle named name, timing
+// its checks by timer.
+func newJUnitSuite(name, gefVersion string, report gefverify.Report, timer phaseTimer) junitSuite {
+	perSection := make(map[string]int)
+	for _, c := range report.Checks {
+		perSection[c.Section]++
+	}
+	suite := junitSuite{
+		Name:  name,
+		Tests: len(report.Checks),
+		Properties: []junitProperty{
+			{"verdict", string(report.Verdict)},
+			{"gef_version", gefVersion},
+			{"key_fingerprint", report.KeyFingerprint},
+		},
+	}
+	var total time.Duration
+	for _, c := range report.Checks {
+		var d time.Duration
+		if phase, ok := sectionPhases[c.Section]; ok {
+			d = timer[phase] / time.Duration(perSection[c.Section])
+		}
+		total += d
+		tc := junitCase{Classname: c.Section, Name: c.ID + ": " + c.Name, Time: junitTime(d)}
+		switch {
+		case !c.Passed && c.Exception != "":
+			suite.Skipped++
+			tc.Skipped = &junitSkipped{Message: "failure accepted under exception " + c.Exception}
+		case !c.Passed:
+			suite.Failures++
+			body := append([]string{c.Details}, c.Diagnostics...)
+			tc.Failure = &junitFailure{Message: c.Name, Type: c.Category.String(), Body: strings.Join(body, "\n")}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	for _, c := range report.Contracts {
+		if c.Status != gefverify.ContractNotApplicable {
+			continue
+		}
+		suite.Tests++
+		suite.Skipped++
+		suite.Cases = append(suite.Cases, junitCase{
+			Classname: c.Section, Name: "not applicable", Time: junitTime(0),
+			Skipped: &junitSkipped{Message: "the bundle makes no claim for this contract to check"},
+		})
+	}
+	suite.Time = junitTime(total)
+	return suite
+}
+
+// newJUnitErrorSuite is the suite of a bundle named name that could not
+// be verified at all.
+func newJUnitErrorSuite(name string, err error) junitSuite {
+	return junitSuite{
+		Name: name, Tests: 1, Errors: 1, Time: junitTime(0),
+		Properties: []junitProperty{{"verdict", string(gefverify.VerdictMalformed)}},
+		Cases: []junitCase{{
+			Classname: "bundle", Name: "bundle parses", Time: junitTime(0),
+			Error: &junitFailure{Message: err.Error(), Type: string(gefverify.VerdictMalformed)},
+		}},
+	}
+}
+
+// junitTime formats d as JUnit does, in seconds.
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.6f", d.Seconds())
+}
+
+// writeJUnit writes suites as JUnit XML.
+func writeJUnit(w io.Writer, suites []junitSuite) error {
+	var v interface{} = junitSuites{Suites: suites}
+	if len(suites) == 1 {
+		v = suites[0]
+	}
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, data)
+	return err
+}
+
+// writeJUnitFile writes suites as JUnit XML to path (-junit).
+func writeJUnitFile(path string, suites []junitSuite) error {
+	var buf bytes.Buffer
+	if err := writeJUnit(&buf, suites); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, buf.Bytes(), 0o644)
+}
@@ -0,0 +1,119 @@
+// cross_lang_proof/junit_test.go
+
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// tamperSignature breaks the bundle's signature, failing Contract 3
+// (and Contract 8: envelope_json keeps the original). signature_hex is
+// changed to the same bytes, 0x05 → 0x09, or validation fails first.
+func tamperSignature(s string) string {
+	s = strings.Replace(s, `"signature_b64url": "B`, `"signature_b64url": "C`, 1)
+	return strings.Replace(s, `"signature_hex": "05`, `"signature_hex": "09`, 1)
+}
+
+func TestJUnitGolden(t *testing.T) {
+	// Fixed phase times keep the golden files stable.
+	timer := phaseTimer{
+		gefverify.PhaseCanonicalize: 3 * time.Millisecond,
+		gefverify.PhaseSignature:    2 * time.Millisecond,
+		gefverify.PhaseNegativeTest: 4 * time.Millisecond,
+	}
+	tests := []struct {
+		golden string
+		edit   func(string) string
+	}{
+		{"junit_pass.golden.xml", func(s string) string { return s }},
+		{"junit_fail.golden.xml", tamperSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			bundle, err := gefverify.ParseBundle([]byte(tt.edit(string(mustRead(t, "proof_bundle.json")))))
+			must(t, err)
+			report, _ := gefverify.Verify(bundle, gefverify.VerifyOptions{})
+			var buf bytes.Buffer
+			must(t, writeJUnit(&buf, []junitSuite{newJUnitSuite("proof_bundle.json", bundle.GEFVersion, report, timer)}))
+			got  := buf.String()
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				must(t, os.WriteFile(path, []byte(got), 0o644))
+			}
+			if want := string(mustRead(t, path)); got != want {
+				t.Errorf("JUnit XML differs from %s (run go test -update):\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestJUnitSuites(t *testing.T) {
+	var buf bytes.Buffer
+	must(t, writeJUnit(&buf, []junitSuite{{Name: "a.json"}, {Name: "b.json"}}))
+	var doc junitSuites
+	must(t, xml.Unmarshal(buf.Bytes(), &doc))
+	if len(doc.Suites) != 2 || doc.Suites[1].Name != "b.json" {
+		t.Errorf("suites %+v", doc.Suites)
+	}
+}
+
+func TestFormatJUnit(t *testing.T) {
+	for _, tt := range []struct {
+		edit     func(string) string
+		code     int
+		failures int
+	}{
+		{func(s string) string { return s }, 0, 0},
+		{tamperSignature, gefverify.VerdictTampered.ExitCode(), 4},
+	} {
+		code, out, _ := runCaptured(t, "-format", "junit", writeBundle(t, tt.edit))
+		var suite junitSuite
+		if err := xml.Unmarshal([]byte(out), &suite); err != nil {
+			t.Fatalf("stdout is not JUnit XML: %v\n%s", err, out)
+		}
+		if code != tt.code || suite.Failures != tt.failures || suite.Tests == 0 {
+			t.Errorf("exit %d, %d/%d failed, want exit %d and %d failed", code, suite.Failures, suite.Tests, tt.code, tt.failures)
+		}
+		if suite.Time == junitTime(0) {
+			t.Errorf("suite time not recorded: %s", suite.Time)
+		}
+	}
+	if code, _, _ := runCaptured(t, "-format", "junit", "-report-json", "-", "proof_bundle.json"); code != 2 {
+		t.Errorf("-format junit with -report-json -: exit %d, want 2", code)
+	}
+}
+
+func TestJUnitFile(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		bundle string
+		errors int
+	}{
+		{"tampered", writeBundle(t, tamperSignature), 0},
+		{"malformed", writeBundle(t, func(string) string { return `{"signing_dict": ` }), 1},
+	} {
+		path := filepath.Join(t.TempDir(), "TEST-gef.xml")
+		code, out, _ := runCaptured(t, "-junit", path, tt.bundle)
+		var suite junitSuite
+		if err := xml.Unmarshal(mustRead(t, path), &suite); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if code == 0 || suite.Errors != tt.errors || suite.Name != tt.bundle {
+			t.Errorf("%s: exit %d, suite %+v", tt.name, code, suite)
+		}
+		if tt.errors == 0 && !strings.Contains(out, "CROSS-LANGUAGE PROOF FAILED") {
+			t.Errorf("%s: human report missing:\n%s", tt.name, out)
+		}
+	}
+	if code, _, _ := runCaptured(t, "-junit", "-", "proof_bundle.json"); code != 2 {
+		t.Errorf("-junit -: exit %d, want 2", code)
+	}
+}
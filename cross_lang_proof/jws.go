@@ -0,0 +1,300 @@
+// cross_lang_proof/jws.go
+//
+// Detached JWS transport (RFC 7797)
+// =================================
+//
+//   verify_proof verify-jws [-bundle b.json] <jws | @file>
+//   verify_proof verify-jws -export-jws -key signer.key [-bundle b.json]
+//
+// For partners on JOSE tooling: a compact JWS whose payload is detached
+// and unencoded (RFC 7797) and is the canonical bytes of the bundle's
+// signing_dict:
+//
+//   jws     BASE64URL(header) + ".." + BASE64URL(signature)
+//   header  {"alg":"EdDSA","b64":false,"crit":["b64"]}, "ES256" for
+//           an ecdsa-p256 bundle (sigalg.go)
+//   signed  BASE64URL(header) + "." + JCS(signing_dict)
+//
+// verify-jws verifies the bundle with every contract, and the JWS with
+// the bundle's public key, under sectionJWS:
+//
+//   J.detached   the payload part is empty                (structure)
+//   J.header     alg, b64 false and crit ["b64"], nothing else: kid,
+//                jwk, x5u, typ ... are rejected, not ignored (structure)
+//   J.signature  the JWS signature is valid               (integrity)
+//
+// The JWS signature is not signature_b64url and cannot be: JWS signs the
+// header with the payload (RFC 7515 §5.1). What binds the two is that
+// J.signature is checked with public_key_hex over JCS(signing_dict),
+// the key and bytes CONTRACT 3 checks signature_b64url with, so a
+// VERIFIED report means both signatures are the bundle key's over the
+// same record.
+//
+// -export-jws signs that JWS with -key (keygen.go) and prints it, once
+// the bundle verifies and only if the key is the bundle's. Only Ed25519
+// keys sign; ES256 JWS come from the KMS.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+const sectionJWS = "JWS — RFC 7797 Detached Payload"
+
+// jwsAlgs maps sig_alg to the JWS alg of its profile.
+var jwsAlgs = map[string]string{
+	gefverify.SigAlgEd25519:   "EdDSA",
+	gefverify.SigAlgECDSAP256: "ES256",
+}
+
+// compactJWS is a JWS in compact serialization.
+type compactJWS struct {
+	Header    string // BASE64URL(header), as signed
+	Payload   string // "" when detached
+	Signature []byte
+	Params    map[string]json.RawMessage
+}
+
+// parseCompactJWS splits and decodes s. Errors are *MalformedError.
+func parseCompactJWS(s string) (compactJWS, error) {
+	var j compactJWS
+	parts := strings.Split(strings.TrimSpace(s), ".")
+	if len(parts) != 3 {
+		return j, &gefverify.MalformedError{Reason: "not a compact JWS",
+			Err: fmt.Errorf("got %d dot-separated parts, expected 3", len(parts))}
+	}
+	j.Header, j.Payload = parts[0], parts[1]
+	header, err := base64.RawURLEncoding.Strict().DecodeString(parts[0])
+	if err != nil {
+		return j, &gefverify.MalformedError{Reason: "JWS header", Err: err}
+	}
+	if err := json.Unmarshal(header, &j.Params); err != nil || j.Params == nil {
+		if err == nil {
+			err = fmt.Errorf("not a JSON object")
+		}
+		return j, &gefverify.MalformedError{Reason: "JWS header", Err: err}
+	}
+	if j.Signature, err = base64.RawURLEncoding.Strict().DecodeString(parts[2]); err != nil {
+		return j, &gefverify.MalformedError{Reason: "JWS signature", Err: err}
+	}
+	return j, nil
+}
+
+// signingInput is what the signature of j covers for an unencoded
+// payload (RFC 7797 §3).
+func (j compactJWS) signingInput(payload []byte) []byte {
+	return append([]byte(j.Header+"."), payload...)
+}
+
+// headerProblems lists how the header of j departs from the profile for
+// alg, or returns nil.
+func (j compactJWS) headerProblems(alg string) []string {
+	var problems []string
+	var got struct {
+		Alg  string   `json:"alg"`
+		B64  *bool    `json:"b64"`
+		Crit []string `json:"crit"`
+	}
+	raw, _ := json.Marshal(j.Params)
+	if err := json.Unmarshal(raw, &got); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if got.Alg != alg {
+		problems = append(problems, fmt.Sprintf("alg %q, want %q", got.Alg, alg))
+	}
+	if got.B64 == nil || *got.B64 {
+		problems = append(problems, "b64 must be false")
+	}
+	if len(got.Crit) != 1 || got.Crit[0] != "b64" {
+		problems = append(problems, `crit must be ["b64"]`)
+	}
+	var extra []string
+	for name := range j.Params {
+		if name != "alg" && name != "b64" && name != "crit" {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		problems = append(problems, fmt.Sprintf("parameter %q not allowed", name))
+	}
+	return problems
+}
+
+// verifyJWS verifies bundle with opts and jws against it. The error is
+// set only when either cannot be decoded at all.
+func verifyJWS(bundle gefverify.ProofBundle, jws string, opts gefverify.VerifyOptions) (gefverify.Report, error) {
+	j, err := parseCompactJWS(jws)
+	if err != nil {
+		return gefverify.Report{Verdict: gefverify.VerdictMalformed}, err
+	}
+	report, err := gefverify.Verify(bundle, opts)
+	if err != nil {
+		return report, err
+	}
+	check := func(id string, category gefverify.Category, name string, passed bool, details string) {
+		report.Checks = append(report.Checks, gefverify.CheckResult{
+			ID: id, Section: sectionJWS, Name: name, Passed: passed, Details: details, Category: category,
+		})
+	}
+
+	check("J.detached", gefverify.CategoryStructure, "payload is detached", j.Payload == "",
+		fmt.Sprintf("payload part is %d characters", len(j.Payload)))
+
+	sigAlg := bundle.SigAlg
+	if sigAlg == "" {
+		sigAlg = gefverify.SigAlgEd25519
+	}
+	alg := jwsAlgs[sigAlg]
+	problems := j.headerProblems(alg)
+	details := fmt.Sprintf("alg=%s b64=false crit=[b64]", alg)
+	if len(problems) > 0 {
+		details = strings.Join(problems, "; ")
+	}
+	check("J.header", gefverify.CategoryStructure, "header is the RFC 7797 profile, nothing else", len(problems) == 0, details)
+
+	canonical, err := gefverify.Canonicalize(bundle.SigningDict)
+	if err != nil {
+		return report, &gefverify.MalformedError{Reason: "canonicalize signing_dict", Err: err}
+	}
+	key, _ := gefverify.DecodeBundleKey(bundle.SigAlg, bundle.PublicKeyHex)
+	// ES256 signatures are r || s (RFC 7518 §3.4), never DER.
+	sigValid := (alg != "ES256" || len(j.Signature) == 64) && key.Verify(j.signingInput(canonical), j.Signature)
+	check("J.signature", gefverify.CategoryIntegrity, "JWS signature valid over the canonical bytes", sigValid,
+		fmt.Sprintf("alg=%s  pubkey=%s...  payload=%d bytes", alg, gefverify.ShortHex(bundle.PublicKeyHex, 8), len(canonical)))
+
+	report.Verdict = gefverify.DeriveVerdict(report.Checks)
+	return report, nil
+}
+
+// exportJWS signs the detached JWS of bundle with key.
+func exportJWS(bundle gefverify.ProofBundle, key ed25519.PrivateKey) (string, error) {
+	canonical, err := gefverify.Canonicalize(bundle.SigningDict)
+	if err != nil {
+		return "", err
+	}
+	header, _ := json.Marshal(map[string]interface{}{"alg": "EdDSA", "b64": false, "crit": []string{"b64"}})
+	j := compactJWS{Header: base64.RawURLEncoding.EncodeToString(header)}
+	sig := ed25519.Sign(key, j.signingInput(canonical))
+	return j.Header + ".." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// ── Subcommand ────────────────────────────────────────────────────────────────
+
+func runVerifyJWS(args []string) int {
+	fs := newFlagSet("verify-jws")
+	bundlePath := fs.String("bundle", "proof_bundle.json", "proof bundle `file` the JWS signs (or env:NAME, -)")
+	export := fs.Bool("export-jws", false, "print the detached JWS of the bundle, signed with -key, instead of verifying one")
+	keySpec := fs.String("key", "", "Ed25519 private key `file` or env:NAME, hex or PEM, for -export-jws")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof verify-jws [-bundle b.json] <jws | @file>")
+		fmt.Fprintln(stderr, "       verify_proof verify-jws -export-jws -key signer.key [-bundle b.json]")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+	if (*export && (fs.NArg() != 0 || *keySpec == "")) || (!*export && fs.NArg() != 1) {
+		fs.Usage()
+		return 2
+	}
+
+	data, err := readInput(*bundlePath)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", *bundlePath, err)
+		return 2
+	}
+	bundle, err := gefverify.ParseBundle(data)
+	if err != nil {
+		fatalMalformed(parseError(inputName(*bundlePath), data, err))
+	}
+
+	if *export {
+		return runExportJWS(bundle, *keySpec)
+	}
+
+	jws := fs.Arg(0)
+	if strings.HasPrefix(jws, "@") {
+		raw, err := readInput(jws[1:])
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", jws[1:], err)
+			return 2
+		}
+		jws = string(bytes.TrimSpace(raw))
+	}
+
+	bar := console.bar
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout, "  GEF Detached JWS — Go Verifier")
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+
+	report, err := verifyJWS(bundle, jws, gefverify.VerifyOptions{})
+	if err != nil {
+		printChecks(report.Checks)
+		fatalMalformed(err)
+	}
+	printChecks(report.Checks)
+
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	verdict := report.Verdict
+	if verdict == gefverify.VerdictVerified {
+		fmt.Fprintf(stdout, "  %s  DETACHED JWS VERIFIED  (%d/%d checks)  verdict=%s\n\n", console.pass,
+			report.Passed(), report.Total(), verdict)
+	} else {
+		fmt.Fprintf(stdout, "  %s  DETACHED JWS FAILED  (%d/%d checks passed)  verdict=%s\n\n", console.fail,
+			report.Passed(), report.Total(), verdict)
+		printFailures(report.Failed())
+	}
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+	return verdict.ExitCode()
+}
+
+// runExportJWS prints the detached JWS of bundle, signed with the key
+// at keySpec, if the bundle verifies and the key is its own.
+func runExportJWS(bundle gefverify.ProofBundle, keySpec string) int {
+	raw, err := readInput(keySpec)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read key %s: %v\n", keySpec, err)
+		return 2
+	}
+	key, err := parseSigningKey(string(raw))
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %s: %v\n", keySpec, err)
+		return 2
+	}
+	if bundle.SigAlg != "" && bundle.SigAlg != gefverify.SigAlgEd25519 {
+		fmt.Fprintf(stderr, "FATAL: -export-jws signs Ed25519 bundles; this one is %s\n", bundle.SigAlg)
+		return 2
+	}
+	if pubHex := hex.EncodeToString(key.Public().(ed25519.PublicKey)); !strings.EqualFold(pubHex, bundle.PublicKeyHex) {
+		fmt.Fprintf(stderr, "FATAL: %s is not the bundle key (%s...)\n", keySpec, gefverify.ShortHex(bundle.PublicKeyHex, 16))
+		return 2
+	}
+	report, err := gefverify.Verify(bundle, gefverify.VerifyOptions{})
+	if err != nil {
+		fatalMalformed(err)
+	}
+	if !report.OK() {
+		fmt.Fprintf(stderr, "FATAL: bundle does not verify (verdict %s); not exporting it\n", report.Verdict)
+		return report.Verdict.ExitCode()
+	}
+	jws, err := exportJWS(bundle, key)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, jws)
+	return 0
+}
@@ -0,0 +1,139 @@
+// cross_lang_proof/jws_test.go
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+type jwsVector struct {
+	Name          string `json:"name"`
+	Profile       bool   `json:"profile"`
+	PublicKey     string `json:"public-key"`
+	JWS           string `json:"jws"`
+	SignedPayload string `json:"signed-payload"`
+	Bundle        string `json:"bundle"`
+}
+
+func loadJWSVectors(t *testing.T) []jwsVector {
+	t.Helper()
+	var vectors struct {
+		Tests []jwsVector `json:"tests"`
+	}
+	if err := json.Unmarshal(mustRead(t, "testdata/jws_vectors.json"), &vectors); err != nil {
+		t.Fatal(err)
+	}
+	return vectors.Tests
+}
+
+// TestJWSVectors checks the signing input and the header profile against
+// an RFC 8037 vector and a detached JWS over proof_bundle.json.
+func TestJWSVectors(t *testing.T) {
+	for _, v := range loadJWSVectors(t) {
+		j, err := parseCompactJWS(v.JWS)
+		if err != nil {
+			t.Fatalf("%s: %v", v.Name, err)
+		}
+		pub, err := gefverify.DecodeBundleKey("", v.PublicKey)
+		if err != nil {
+			t.Fatalf("%s: %v", v.Name, err)
+		}
+		payload := []byte(v.SignedPayload)
+		if v.Bundle != "" {
+			bundle, err := gefverify.ParseBundle(mustRead(t, v.Bundle))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if payload, err = gefverify.Canonicalize(bundle.SigningDict); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !pub.Verify(j.signingInput(payload), j.Signature) {
+			t.Errorf("%s: signature does not verify", v.Name)
+		}
+		if problems := j.headerProblems("EdDSA"); (len(problems) == 0) != v.Profile {
+			t.Errorf("%s: header problems %q, profile = %v", v.Name, problems, v.Profile)
+		}
+	}
+}
+
+func TestVerifyJWS(t *testing.T) {
+	bundle, err := gefverify.ParseBundle(mustRead(t, "proof_bundle.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	detached := loadJWSVectors(t)[1].JWS
+	header, sig, _ := strings.Cut(detached, "..")
+	withKid := "eyJhbGciOiJFZERTQSIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il0sImtpZCI6ImsxIn0.." + sig
+	tests := []struct {
+		name   string
+		jws    string
+		failed []string
+	}{
+		{"detached", detached, nil},
+		{"kid header", withKid, []string{"J.header", "J.signature"}},
+		{"tampered signature", header + "..A" + sig[1:], []string{"J.signature"}},
+		{"attached payload", header + ".e30." + sig, []string{"J.detached"}},
+	}
+	for _, tt := range tests {
+		report, err := verifyJWS(bundle, tt.jws, gefverify.VerifyOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var failed []string
+		for _, c := range report.Failed() {
+			failed = append(failed, c.ID)
+		}
+		if strings.Join(failed, ",") != strings.Join(tt.failed, ",") {
+			t.Errorf("%s: failed %v, want %v", tt.name, failed, tt.failed)
+		}
+		if want := len(tt.failed) == 0; report.OK() != want {
+			t.Errorf("%s: verdict %s", tt.name, report.Verdict)
+		}
+	}
+
+	for _, bad := range []string{"a.b", "!!..AAAA", "e30..!!"} {
+		if report, err := verifyJWS(bundle, bad, gefverify.VerifyOptions{}); err == nil || report.Verdict != gefverify.VerdictMalformed {
+			t.Errorf("%q: verdict=%s err=%v, want MALFORMED", bad, report.Verdict, err)
+		}
+	}
+}
+
+func TestExportJWS(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "signer.key")
+	must(t, os.WriteFile(keyPath, []byte(proofSeed+"\n"), 0o600))
+
+	code, out, errOut := runCaptured(t, "verify-jws", "-export-jws", "-key", keyPath)
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	// Ed25519 is deterministic: the same token as the Node vector.
+	jws := strings.TrimSpace(out)
+	if want := loadJWSVectors(t)[1].JWS; jws != want {
+		t.Errorf("exported %s\nwant     %s", jws, want)
+	}
+
+	jwsPath := filepath.Join(dir, "bundle.jws")
+	must(t, os.WriteFile(jwsPath, []byte(out), 0o644))
+	if code, out, _ := runCaptured(t, "verify-jws", "@"+jwsPath); code != 0 || !strings.Contains(out, "DETACHED JWS VERIFIED") {
+		t.Errorf("round trip: exit %d\n%s", code, out)
+	}
+
+	other := filepath.Join(dir, "other.key")
+	must(t, os.WriteFile(other, []byte(strings.Repeat("11", 32)), 0o600))
+	if code, out, errOut := runCaptured(t, "verify-jws", "-export-jws", "-key", other); code != 2 || out != "" || !strings.Contains(errOut, "not the bundle key") {
+		t.Errorf("wrong key: exit %d out=%q err=%q", code, out, errOut)
+	}
+
+	tampered := writeBundle(t, tamperSignature)
+	if code, out, _ := runCaptured(t, "verify-jws", "-export-jws", "-key", keyPath, "-bundle", tampered); code == 0 || out != "" {
+		t.Errorf("tampered bundle exported: exit %d out=%q", code, out)
+	}
+}
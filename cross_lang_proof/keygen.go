@@ -0,0 +1,178 @@
+// cross_lang_proof/keygen.go
+//
+// Key tooling (keygen and sign subcommands)
+// =========================================
+//
+//   verify_proof keygen -out signer.key [-pem] > signer.pub
+//   verify_proof sign -key signer.key <signing_dict.json | canonical.bin | ->
+//
+// keygen draws a new Ed25519 key pair, writes the private key to -out
+// with mode 0600 and prints the public key as hex, the encoding of
+// public_key_hex and signer_public_key. The private key is the 32-byte
+// seed in hex, as emit_proof.py's PROOF_SEED, or with -pem a PKCS#8
+// "PRIVATE KEY" block, as openssl genpkey -algorithm ed25519 writes it.
+// An existing file is not overwritten without -force.
+//
+// sign signs a signing_dict the way CONTRACT 3 verifies it: the input is
+// decoded and canonicalized with gefverify.Canonicalize, so a
+// pretty-printed signing_dict and its canonical bytes give the same
+// signature. With -canonical the bytes are signed as given and must
+// already be JCS. The signature is printed in both ProofBundle encodings,
+// signature_hex and signature_b64url; verify-detached checks it. A
+// COSE_Sign1 of the same bytes follows, for cose_sign1 (cose.go).
+//
+// Either key form is accepted by sign and emit, from a file or env:NAME.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+func runKeygen(args []string) int {
+	fs := newFlagSet("keygen")
+	out := fs.String("out", "", "write the private key to this `file` (required)")
+	asPEM := fs.Bool("pem", false, "write the private key as PKCS#8 PEM instead of the hex seed")
+	force := fs.Bool("force", false, "overwrite an existing key file")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof keygen -out signer.key [-pem] [-force]")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+	if *out == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+	if _, err := os.Stat(*out); err == nil && !*force {
+		fmt.Fprintf(stderr, "FATAL: %s exists; use -force to overwrite it\n", *out)
+		return 2
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot generate key: %v\n", err)
+		return 1
+	}
+	data := []byte(hex.EncodeToString(priv.Seed()) + "\n")
+	if *asPEM {
+		der, err := x509.MarshalPKCS8PrivateKey(priv)
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot encode key: %v\n", err)
+			return 1
+		}
+		data = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	}
+	if err := writeFileAtomic(*out, data, 0o600); err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", *out, err)
+		return 2
+	}
+	fmt.Fprintln(stdout, hex.EncodeToString(pub))
+	fmt.Fprintf(stderr, "private key written to %s (%s)\n", *out, gefverify.KeyFingerprint(pub))
+	return 0
+}
+
+func runSign(args []string) int {
+	fs := newFlagSet("sign")
+	keySpec := fs.String("key", "", "Ed25519 private key `file` or env:NAME, hex or PEM (required)")
+	canonical := fs.Bool("canonical", false, "sign the input bytes as given and require them to be JCS already")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof sign -key signer.key [-canonical] <signing_dict.json | canonical.bin | ->")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+	if *keySpec == "" || fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	raw, err := readInput(*keySpec)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read key %s: %v\n", *keySpec, err)
+		return 2
+	}
+	key, err := parseSigningKey(string(raw))
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %s: %v\n", *keySpec, err)
+		return 2
+	}
+	input := fs.Arg(0)
+	body, err := readInput(input)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", input, err)
+		return 2
+	}
+
+	var dict map[string]interface{}
+	if err := json.Unmarshal(body, &dict); err != nil || dict == nil {
+		fmt.Fprintf(stderr, "FATAL: %s is not a JSON object: %v\n", inputName(input), err)
+		return gefverify.VerdictMalformed.ExitCode()
+	}
+	signed, err := gefverify.Canonicalize(dict)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot canonicalize %s: %v\n", inputName(input), err)
+		return 1
+	}
+	if *canonical {
+		if !bytes.Equal(body, signed) {
+			fmt.Fprintf(stderr, "FATAL: %s is not JCS canonical (%d bytes, JCS form is %d)\n", inputName(input), len(body), len(signed))
+			return 1
+		}
+		signed = body
+	}
+	pubHex := hex.EncodeToString(key.Public().(ed25519.PublicKey))
+	if signer, ok := dict["signer_public_key"].(string); ok && !strings.EqualFold(signer, pubHex) {
+		fmt.Fprintf(stderr, "warning: signer_public_key %s is not this key (%s); C3 will fail\n", signer, pubHex)
+	}
+
+	sig := ed25519.Sign(key, signed)
+	fmt.Fprintf(stdout, "signature_hex     %s\n", hex.EncodeToString(sig))
+	fmt.Fprintf(stdout, "signature_b64url  %s\n", base64.RawURLEncoding.EncodeToString(sig))
+	fmt.Fprintf(stdout, "cose_sign1        %s\n", gefverify.SignCOSE1(key, signed))
+	return 0
+}
+
+// parseSigningKey decodes an Ed25519 private key: hex of the seed or of
+// the 64-byte key, or a PKCS#8 PEM block.
+func parseSigningKey(s string) (ed25519.PrivateKey, error) {
+	if block, _ := pem.Decode([]byte(s)); block != nil {
+		if block.Type != "PRIVATE KEY" {
+			return nil, fmt.Errorf("PEM block is %q, want PRIVATE KEY (PKCS#8)", block.Type)
+		}
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("PEM key is %T, not Ed25519", parsed)
+		}
+		return key, nil
+	}
+	raw, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("key is not hex or PEM: %v", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		key := ed25519.NewKeyFromSeed(raw[:ed25519.SeedSize])
+		if !bytes.Equal(key[ed25519.SeedSize:], raw[ed25519.SeedSize:]) {
+			return nil, fmt.Errorf("public half of the 64-byte key does not match its seed")
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("got %d key bytes, want %d (seed) or %d", len(raw), ed25519.SeedSize, ed25519.PrivateKeySize)
+}
@@ -0,0 +1,84 @@
+// cross_lang_proof/lint.go
+//
+// Structure-only validation (lint subcommand)
+// ===========================================
+//
+//   verify_proof lint <bundle.json>
+//
+// Runs gefverify.Lint: every non-cryptographic check at once, each
+// finding with its severity, JSON Pointer and a remediation hint. No key
+// is used and no signature or hash is checked; the output says so, and
+// the exit code reflects structure only:
+//
+//   0   no error findings (warnings allowed)
+//   3   at least one error finding — the verifier would report MALFORMED
+//       or fail a structural check
+//   1   the file cannot be read
+//
+// testdata/lint holds almost-right bundles, one mistake each, that
+// lint_test.go runs against the expected finding.
+
+package main
+
+import (
+	"fmt"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+func runLint(args []string) int {
+	fs := newFlagSet("lint")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof lint <bundle.json | - | env:NAME>")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	path := fs.Arg(0)
+	data, err := readInput(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", path, err)
+		return 2
+	}
+
+	findings := gefverify.Lint(data)
+	errs := gefverify.LintErrors(findings)
+
+	bar := console.bar
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout, "  GEF Lint — structure only, signatures NOT verified")
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintf(stdout, "  Bundle: %s\n\n", inputName(path))
+	for _, f := range findings {
+		icon := console.fail
+		if f.Severity == gefverify.LintWarning {
+			icon = console.warn
+		}
+		pointer := f.Pointer
+		if pointer == "" {
+			pointer = "(document)"
+		}
+		fmt.Fprintf(stdout, "  %s  %-7s %-32s %s\n", icon, f.Severity, pointer, f.CheckID)
+		fmt.Fprintf(stdout, "       %s\n", f.Message)
+		fmt.Fprintf(stdout, "       hint: %s\n", f.Hint)
+	}
+	if len(findings) > 0 {
+		fmt.Fprintln(stdout)
+	}
+	fmt.Fprintln(stdout, bar)
+	switch {
+	case errs > 0:
+		fmt.Fprintf(stdout, "  %s  STRUCTURALLY INVALID — %d error(s), %d warning(s)\n", console.fail, errs, len(findings)-errs)
+	default:
+		fmt.Fprintf(stdout, "  %s  WELL-FORMED — %d warning(s)\n", console.pass, len(findings))
+	}
+	fmt.Fprintln(stdout, "      Signatures and hashes were NOT checked; run verify_proof for a verdict.")
+	fmt.Fprintln(stdout, bar)
+	if errs > 0 {
+		return gefverify.VerdictMalformed.ExitCode()
+	}
+	return 0
+}
@@ -0,0 +1,60 @@
+// cross_lang_proof/lint_test.go
+
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLintFixtures(t *testing.T) {
+	for fixture, want := range map[string]string{
+		"duplicate_sequence":        "/signing_dict/sequence L.duplicate_key",
+		"genesis_not_zero":          "/signing_dict/causal_hash L.genesis_link",
+		"missing_nonce":             "/signing_dict/nonce C5.field_present.nonce",
+		"null_agent_id":             "/signing_dict/agent_id L.field_type",
+		"sequence_string":           "/signing_dict/sequence L.field_type",
+		"short_nonce":               "/signing_dict/nonce L.nonce_format",
+		"short_public_key":          "/public_key_hex L.hex_format",
+		"signature_in_signing_dict": "/signing_dict/signature C4.signature_excluded",
+		"signature_std_base64":      "/signature_b64url L.base64_format",
+		"timestamp_offset":          "/signing_dict/timestamp C9.timestamp_format",
+		"trailing_data":             "(document) L.json_syntax",
+		"unknown_record_type":       "/signing_dict/record_type L.record_type",
+		"version_mismatch":          "/gef_version C7.version_binding",
+	} {
+		t.Run(fixture, func(t *testing.T) {
+			code, out, _ := runCaptured(t, "lint", "testdata/lint/"+fixture+".json")
+			if code != 3 {
+				t.Errorf("exit = %d, want 3", code)
+			}
+			fields := strings.Fields(want)
+			line := regexp.MustCompile(`❌\s+error\s+` + regexp.QuoteMeta(fields[0]) + `\s+` + regexp.QuoteMeta(fields[1]) + `\n`)
+			if !line.MatchString(out) {
+				t.Errorf("no error %s in:\n%s", want, out)
+			}
+		})
+	}
+}
+
+func TestLintReferenceBundle(t *testing.T) {
+	code, out, _ := runCaptured(t, "lint", "proof_bundle.json")
+	if code != 0 {
+		t.Fatalf("exit = %d\n%s", code, out)
+	}
+	for _, want := range []string{"signatures NOT verified", "WELL-FORMED", "hint: "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestLintUsage(t *testing.T) {
+	if code, _, _ := runCaptured(t, "lint"); code != 2 {
+		t.Errorf("exit = %d, want 2", code)
+	}
+	if code, _, _ := runCaptured(t, "lint", "testdata/lint/absent.json"); code != 2 {
+		t.Errorf("exit = %d, want 2", code)
+	}
+}
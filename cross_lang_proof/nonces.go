@@ -0,0 +1,183 @@
+// cross_lang_proof/nonces.go
+//
+// Nonce replay detection (batch and chain modes, -nonce-db)
+// =========================================================
+//
+//   verify_proof -dir ./bundles [-nonce-db nonces.jsonl]
+//   verify_proof chain [-nonce-db nonces.jsonl] <dir>
+//
+// A signature proves a record was signed, not that it is delivered only
+// once: a validly signed record copied into a second file verifies
+// twice. Every record carries a nonce, so a batch or a chain checks that
+// no (agent_id, nonce) pair appears in two files — B.nonce_replay on
+// each bundle, chain.nonce_replay on each record, failing on the second
+// sighting, as an integrity failure, and naming the file of the first.
+// Batch order is the order of the arguments; chain order is causal
+// order. A record sighted again in the same file with the same chain
+// hash is the same record verified again, not a replay.
+//
+// Only records that verified are checked and register their nonce: one
+// that failed is rejected already, and a forged record cannot burn the
+// nonce of a real one. Single-bundle verification keeps no nonce state.
+//
+// -nonce-db keeps the registered nonces across runs, one JSON object a
+// line:
+//
+//   {"agent_id":"a","nonce":"…","file":"/abs/path.json","chain_hash":"…"}
+//
+// New sightings are appended at the end of a run in one write, so the
+// file only grows. A line that does not parse — the torn tail of an
+// interrupted write, a stray edit — is skipped with a warning, and the
+// next append starts on a fresh line; the rest of the index still holds.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// sectionNonces is the section of B.nonce_replay.
+const sectionNonces = "BATCH — Nonce replay"
+
+// nonceKey identifies a nonce within its agent's records.
+type nonceKey struct {
+	AgentID string
+	Nonce   string
+}
+
+// nonceEntry is one line of a nonce index.
+type nonceEntry struct {
+	AgentID   string `json:"agent_id"`
+	Nonce     string `json:"nonce"`
+	File      string `json:"file"` // absolute path
+	ChainHash string `json:"chain_hash"`
+}
+
+// nonceIndex is the nonces registered so far, and those to append.
+type nonceIndex struct {
+	path    string // -nonce-db, "" to keep the index in memory
+	seen    map[nonceKey]nonceEntry
+	added   []nonceEntry
+	skipped int  // lines of path that did not parse
+	torn    bool // path does not end in a newline
+}
+
+// loadNonceIndex reads the index at path; a file that does not exist
+// yet is an empty index. path "" is an index for this run only.
+func loadNonceIndex(path string) (*nonceIndex, error) {
+	idx := &nonceIndex{path: path, seen: make(map[nonceKey]nonceEntry)}
+	if path == "" {
+		return idx, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	idx.torn = len(data) > 0 && data[len(data)-1] != '\n'
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var e nonceEntry
+		if err := json.Unmarshal(line, &e); err != nil || e.AgentID == "" || e.Nonce == "" || e.File == "" {
+			idx.skipped++
+			continue
+		}
+		if _, dup := idx.seen[nonceKey{e.AgentID, e.Nonce}]; !dup {
+			idx.seen[nonceKey{e.AgentID, e.Nonce}] = e
+		}
+	}
+	return idx, nil
+}
+
+// observe looks up the nonce of a verified record in file, and registers
+// it if it was not seen before. It returns the earlier sighting, if the
+// record is a replay of it.
+func (idx *nonceIndex) observe(agentID, nonce, file, chainHash string) (first nonceEntry, replay bool) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		abs = file
+	}
+	key := nonceKey{agentID, nonce}
+	if first, ok := idx.seen[key]; ok {
+		return first, first.File != abs || first.ChainHash != chainHash
+	}
+	e := nonceEntry{AgentID: agentID, Nonce: nonce, File: abs, ChainHash: chainHash}
+	idx.seen[key] = e
+	idx.added = append(idx.added, e)
+	return nonceEntry{}, false
+}
+
+// check observes the nonce of a verified record in file, and returns
+// whether it passes and the details for the check line.
+func (idx *nonceIndex) check(agentID, nonce, file, chainHash string) (bool, string) {
+	first, replay := idx.observe(agentID, nonce, file, chainHash)
+	if replay {
+		return false, fmt.Sprintf("agent=%s nonce=%s also in %s", agentID, gefverify.ShortHex(nonce, 16), displayPath(first.File))
+	}
+	return true, fmt.Sprintf("agent=%s nonce=%s", agentID, gefverify.ShortHex(nonce, 16))
+}
+
+// warnSkipped reports the lines of the index file that were ignored.
+func (idx *nonceIndex) warnSkipped() {
+	if idx.skipped > 0 {
+		fmt.Fprintf(stderr, "warning: %s: %d unreadable line(s) skipped; the other entries still apply\n", idx.path, idx.skipped)
+	}
+}
+
+// save appends the nonces registered in this run to the index file.
+func (idx *nonceIndex) save() error {
+	if idx.path == "" || len(idx.added) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if idx.torn {
+		buf.WriteByte('\n')
+	}
+	for _, e := range idx.added {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(line, '\n'))
+	}
+	f, err := os.OpenFile(idx.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	idx.added, idx.torn = nil, false
+	return f.Close()
+}
+
+// displayPath shows an absolute path relative to the working directory
+// when it is below it.
+func displayPath(abs string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return abs
+	}
+	if rel, err := filepath.Rel(wd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return abs
+}
@@ -0,0 +1,102 @@
+// cross_lang_proof/nonces_test.go
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBatchNonceReplay(t *testing.T) {
+	dir := t.TempDir()
+	files := writeChainDir(t, dir, "alpha", 2)
+	replay := filepath.Join(dir, "replay.json")
+	must(t, os.WriteFile(replay, mustRead(t, files[0]), 0o644))
+
+	code, out, _ := runCaptured(t, files[0], files[1], replay)
+	if code != 1 {
+		t.Errorf("exit %d, want 1\n%s", code, out)
+	}
+	for _, want := range []string{
+		"VERIFIED               " + files[0],
+		"VERIFIED               " + files[1],
+		"TAMPERED               " + replay + "  (18/19 checks)",
+		"failed: B.nonce_replay",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q:\n%s", want, out)
+		}
+	}
+
+	_, out, errOut := runCaptured(t, "-quiet", files[0], replay)
+	if !strings.Contains(errOut, "[B.nonce_replay]") || !strings.Contains(errOut, "also in "+files[0]) || !strings.Contains(out, "1 passed, 1 failed") {
+		t.Errorf("quiet: stdout %q, stderr %q", out, errOut)
+	}
+
+	// The same file twice is the same record verified twice.
+	if code, out, _ := runCaptured(t, files[0], files[0]); code != 0 {
+		t.Errorf("same file twice: exit %d\n%s", code, out)
+	}
+}
+
+func TestNonceDB(t *testing.T) {
+	dir := t.TempDir()
+	files := writeChainDir(t, dir, "alpha", 2)
+	db := filepath.Join(t.TempDir(), "nonces.jsonl")
+
+	for run := 1; run <= 2; run++ {
+		if code, out, _ := runCaptured(t, "-nonce-db", db, "-dir", dir); code != 0 {
+			t.Fatalf("run %d: exit %d\n%s", run, code, out)
+		}
+	}
+	if lines := strings.Count(string(mustRead(t, db)), "\n"); lines != 2 {
+		t.Errorf("index has %d lines, want 2 (a rerun adds none)", lines)
+	}
+
+	// A torn last line and a stray edit are skipped, not fatal.
+	f, err := os.OpenFile(db, os.O_WRONLY|os.O_APPEND, 0)
+	must(t, err)
+	f.WriteString("not json\n{\"agent_id\": \"alpha\", \"non")
+	f.Close()
+
+	later := t.TempDir()
+	replay := filepath.Join(later, "replay.json")
+	must(t, os.WriteFile(replay, mustRead(t, files[1]), 0o644))
+	fresh := writeChainDir(t, later, "beta", 1)
+	code, out, errOut := runCaptured(t, "-nonce-db", db, replay, fresh[0])
+	if code != 1 || !strings.Contains(out, "TAMPERED               "+replay) || !strings.Contains(out, "VERIFIED               "+fresh[0]) {
+		t.Errorf("replay across runs: exit %d\n%s", code, out)
+	}
+	if !strings.Contains(errOut, "2 unreadable line(s) skipped") {
+		t.Errorf("stderr %q, want a warning about the skipped lines", errOut)
+	}
+
+	idx, err := loadNonceIndex(db)
+	if err != nil || len(idx.seen) != 3 || idx.skipped != 2 || idx.torn {
+		t.Errorf("index: %v, %d nonces, %d skipped, torn %v", err, len(idx.seen), idx.skipped, idx.torn)
+	}
+
+	// Single-bundle verification keeps no nonce state.
+	if code, _, _ := runCaptured(t, "-nonce-db", db, files[0]); code != 2 {
+		t.Errorf("-nonce-db with one bundle: exit %d, want 2", code)
+	}
+}
+
+func TestChainNonceReplay(t *testing.T) {
+	dir := t.TempDir()
+	files := writeChainDir(t, dir, "alpha", 3)
+	db := filepath.Join(t.TempDir(), "nonces.jsonl")
+	if code, out, _ := runCaptured(t, "chain", "-nonce-db", db, dir); code != 0 || !strings.Contains(out, "alpha-2.json nonce not seen before") {
+		t.Fatalf("exit %d\n%s", code, out)
+	}
+
+	// alpha-1 delivered again, in another corpus.
+	other := t.TempDir()
+	must(t, os.WriteFile(filepath.Join(other, "again.json"), mustRead(t, files[1]), 0o644))
+	code, out, _ := runCaptured(t, "chain", "-nonce-db", db, other)
+	if code != 1 || !strings.Contains(out, "also in "+files[1]) || !strings.Contains(out, "nonce replay") {
+		t.Errorf("exit %d\n%s", code, out)
+	}
+}
@@ -0,0 +1,439 @@
+// cross_lang_proof/ociimage.go
+//
+// GEF Proof Bundles attached to OCI images
+// ========================================
+//
+//   verify_proof verify-image [-token t | -username u -password-env VAR]
+//                             [-plain-http] <registry>/<repo>[:tag|@digest]
+//
+// Fetches the image manifest over the OCI distribution HTTP API and looks
+// for the build record's proof bundle in two layouts, in order:
+//
+//   annotation   manifest.annotations["dev.guardclaw.gef.proof-bundle"]
+//                holds the bundle JSON.
+//   referrers    GET /v2/<repo>/referrers/<digest> lists an artifact of
+//                type application/vnd.guardclaw.gef.bundle.v1+json whose
+//                first layer blob is the bundle JSON.
+//
+// The bundle then gets full verification plus one extra integrity check:
+// signing_dict.payload.image_digest must name the image. For referrers
+// that is the manifest digest (the artifact's subject). An annotation
+// lives inside the manifest and so cannot contain the manifest's own
+// digest; there the record binds the image config digest instead.
+//
+// Auth: -token sends a static bearer token. Otherwise a 401 with a Bearer
+// challenge is answered by fetching a token from the challenge realm,
+// with basic credentials when -username is set. Only stdlib net/http is
+// used; tests run against an httptest fake registry.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+const (
+	proofAnnotation    = "dev.guardclaw.gef.proof-bundle"
+	proofArtifactType  = "application/vnd.guardclaw.gef.bundle.v1+json"
+	payloadDigestField = "image_digest"
+
+	sectionImage = "IMAGE — Proof bound to OCI image"
+)
+
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// maxRegistryBody caps every registry response; bundles are small.
+const maxRegistryBody = 16 << 20
+
+// ── Image references ──────────────────────────────────────────────────────────
+
+// imageRef is a parsed <registry>/<repo>[:tag|@digest].
+type imageRef struct {
+	Registry   string
+	Repository string
+	Reference  string // tag or digest
+}
+
+func parseImageRef(s string) (imageRef, error) {
+	var ref imageRef
+	slash := strings.IndexByte(s, '/')
+	if slash <= 0 {
+		return ref, fmt.Errorf("image reference %q has no registry host", s)
+	}
+	ref.Registry, s = s[:slash], s[slash+1:]
+
+	if at := strings.IndexByte(s, '@'); at >= 0 {
+		ref.Repository, ref.Reference = s[:at], s[at+1:]
+	} else if colon := strings.LastIndexByte(s, ':'); colon >= 0 {
+		ref.Repository, ref.Reference = s[:colon], s[colon+1:]
+	} else {
+		ref.Repository, ref.Reference = s, "latest"
+	}
+	if ref.Repository == "" || ref.Reference == "" {
+		return ref, fmt.Errorf("invalid image reference %q", s)
+	}
+	return ref, nil
+}
+
+// ── Registry client ───────────────────────────────────────────────────────────
+
+// registryClient speaks the subset of the OCI distribution API we need.
+type registryClient struct {
+	HTTP     *http.Client
+	Scheme   string // "https", or "http" with -plain-http
+	Token    string // static bearer token, or one obtained from a challenge
+	Username string
+	Password string
+}
+
+// ociDescriptor is an OCI content descriptor.
+type ociDescriptor struct {
+	MediaType    string            `json:"mediaType"`
+	ArtifactType string            `json:"artifactType,omitempty"`
+	Digest       string            `json:"digest"`
+	Size         int64             `json:"size"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+// ociManifest is an image manifest or an image index; we read either.
+type ociManifest struct {
+	MediaType    string            `json:"mediaType"`
+	ArtifactType string            `json:"artifactType,omitempty"`
+	Config       ociDescriptor     `json:"config"`
+	Layers       []ociDescriptor   `json:"layers"`
+	Manifests    []ociDescriptor   `json:"manifests"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// get fetches path from ref's registry, answering one Bearer challenge.
+func (c *registryClient) get(ref imageRef, path string, accept ...string) ([]byte, error) {
+	u := fmt.Sprintf("%s://%s/v2/%s/%s", c.Scheme, ref.Registry, ref.Repository, path)
+	for attempt := 0; attempt < 2; attempt++ {
+		req, err := http.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(accept) > 0 {
+			req.Header.Set("Accept", strings.Join(accept, ", "))
+		}
+		if c.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.Token)
+		}
+		resp, err := c.HTTP.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxRegistryBody))
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			return body, nil
+		case resp.StatusCode == http.StatusUnauthorized && attempt == 0:
+			if err := c.authenticate(resp.Header.Get("WWW-Authenticate")); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, &registryError{URL: u, Status: resp.StatusCode}
+		}
+	}
+	return nil, &registryError{URL: u, Status: http.StatusUnauthorized}
+}
+
+// registryError is a non-200 registry response.
+type registryError struct {
+	URL    string
+	Status int
+}
+
+func (e *registryError) Error() string {
+	return fmt.Sprintf("GET %s: %d %s", e.URL, e.Status, http.StatusText(e.Status))
+}
+
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authenticate obtains a token for a `Bearer realm=...,service=...,scope=...`
+// challenge.
+func (c *registryClient) authenticate(challenge string) error {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return fmt.Errorf("registry requires unsupported auth %q", challenge)
+	}
+	// Quoted values may contain commas (scope="repository:x:pull,push").
+	params := map[string]string{}
+	for _, m := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[m[1]] = m[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return fmt.Errorf("registry auth challenge has no usable realm: %q", challenge)
+	}
+	q := realm.Query()
+	for _, k := range []string{"service", "scope"} {
+		if params[k] != "" {
+			q.Set(k, params[k])
+		}
+	}
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("registry token request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry token request: %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	var tok struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRegistryBody)).Decode(&tok); err != nil {
+		return fmt.Errorf("registry token response: %w", err)
+	}
+	c.Token = tok.Token
+	if c.Token == "" {
+		c.Token = tok.AccessToken
+	}
+	if c.Token == "" {
+		return errors.New("registry token response carries no token")
+	}
+	return nil
+}
+
+// manifest fetches ref's manifest and returns it with its digest.
+func (c *registryClient) manifest(ref imageRef, reference string) (ociManifest, string, error) {
+	var m ociManifest
+	body, err := c.get(ref, "manifests/"+reference, manifestMediaTypes...)
+	if err != nil {
+		return m, "", err
+	}
+	digest := sha256Digest(body)
+	if strings.HasPrefix(reference, "sha256:") && reference != digest {
+		return m, "", fmt.Errorf("registry returned manifest %s for %s", digest, reference)
+	}
+	if err := json.Unmarshal(body, &m); err != nil {
+		return m, "", fmt.Errorf("cannot parse manifest: %w", err)
+	}
+	return m, digest, nil
+}
+
+// blob fetches a blob and checks it against its digest.
+func (c *registryClient) blob(ref imageRef, digest string) ([]byte, error) {
+	body, err := c.get(ref, "blobs/"+digest)
+	if err != nil {
+		return nil, err
+	}
+	if got := sha256Digest(body); got != digest {
+		return nil, fmt.Errorf("blob %s has digest %s", digest, got)
+	}
+	return body, nil
+}
+
+// ── Locating the proof ────────────────────────────────────────────────────────
+
+// errNoProof means neither layout carries a proof bundle.
+var errNoProof = errors.New("no GEF proof bundle attached to image")
+
+// imageProof is a proof bundle found for an image.
+type imageProof struct {
+	Layout         string // "annotation" | "referrers"
+	Bundle         []byte
+	ManifestDigest string
+	// BindDigest is what payload.image_digest must equal.
+	BindDigest string
+}
+
+// findProof looks for the bundle as an annotation, then as a referrer.
+func findProof(c *registryClient, ref imageRef) (imageProof, error) {
+	m, digest, err := c.manifest(ref, ref.Reference)
+	if err != nil {
+		return imageProof{}, err
+	}
+	proof := imageProof{ManifestDigest: digest}
+
+	if raw, ok := m.Annotations[proofAnnotation]; ok {
+		proof.Layout     = "annotation"
+		proof.Bundle     = []byte(raw)
+		proof.BindDigest = m.Config.Digest
+		return proof, nil
+	}
+
+	body, err := c.get(ref, "referrers/"+digest+"?artifactType="+url.QueryEscape(proofArtifactType),
+		"application/vnd.oci.image.index.v1+json")
+	var regErr *registryError
+	if errors.As(err, &regErr) && regErr.Status == http.StatusNotFound {
+		return proof, errNoProof
+	}
+	if err != nil {
+		return proof, err
+	}
+	var index ociManifest
+	if err := json.Unmarshal(body, &index); err != nil {
+		return proof, fmt.Errorf("cannot parse referrers index: %w", err)
+	}
+	for _, d := range index.Manifests {
+		if d.ArtifactType != proofArtifactType {
+			continue // registries may ignore the artifactType filter
+		}
+		artifact, _, err := c.manifest(ref, d.Digest)
+		if err != nil {
+			return proof, err
+		}
+		if len(artifact.Layers) == 0 {
+			return proof, fmt.Errorf("proof artifact %s has no layers", d.Digest)
+		}
+		if proof.Bundle, err = c.blob(ref, artifact.Layers[0].Digest); err != nil {
+			return proof, err
+		}
+		proof.Layout     = "referrers"
+		proof.BindDigest = digest
+		return proof, nil
+	}
+	return proof, errNoProof
+}
+
+// ── Verification ──────────────────────────────────────────────────────────────
+
+// verifyImage locates and verifies the proof bundle for ref.
+func verifyImage(c *registryClient, ref imageRef, opts gefverify.VerifyOptions) (gefverify.Report, imageProof, error) {
+	proof, err := findProof(c, ref)
+	if errors.Is(err, errNoProof) {
+		missing := gefverify.CheckResult{
+			ID:       "I.proof_present",
+			Section:  sectionImage,
+			Name:     "proof bundle attached to image",
+			Details:  fmt.Sprintf("%s: no %q annotation and no %s referrer", proof.ManifestDigest, proofAnnotation, proofArtifactType),
+			Category: gefverify.CategoryCompleteness,
+		}
+		checks := []gefverify.CheckResult{missing}
+		return gefverify.Report{Checks: checks, Verdict: gefverify.DeriveVerdict(checks)}, proof, nil
+	}
+	if err != nil {
+		return gefverify.Report{}, proof, err
+	}
+
+	bundle, err := gefverify.ParseBundle(proof.Bundle)
+	if err != nil {
+		return gefverify.Report{Verdict: gefverify.VerdictMalformed}, proof, err
+	}
+	report, err := gefverify.Verify(bundle, opts)
+	if err != nil {
+		return report, proof, err
+	}
+
+	payload, _ := bundle.SigningDict["payload"].(map[string]interface{})
+	bound, _   := payload[payloadDigestField].(string)
+	report.Checks = append(report.Checks, gefverify.CheckResult{
+		ID:       "I.image_digest",
+		Section:  sectionImage,
+		Name:     "payload image_digest matches image",
+		Passed:   bound != "" && bound == proof.BindDigest,
+		Details:  fmt.Sprintf("layout=%s  payload=%q  image=%s", proof.Layout, bound, proof.BindDigest),
+		Category: gefverify.CategoryIntegrity,
+	})
+	report.Verdict = gefverify.DeriveVerdict(report.Checks)
+	return report, proof, nil
+}
+
+// runVerifyImage implements the verify-image subcommand.
+func runVerifyImage(args []string) int {
+	fs := newFlagSet("verify-image")
+	token       := fs.String("token", "", "static bearer `token` for the registry")
+	username    := fs.String("username", "", "`user` for the registry token endpoint")
+	passwordEnv := fs.String("password-env", "REGISTRY_PASSWORD", "environment `variable` holding the password")
+	plainHTTP   := fs.Bool("plain-http", false, "talk to the registry over http instead of https")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof verify-image [flags] <registry>/<repo>[:tag|@digest]")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	ref, err := parseImageRef(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %v\n", err)
+		return 2
+	}
+
+	client := &registryClient{
+		HTTP:     http.DefaultClient,
+		Scheme:   "https",
+		Token:    *token,
+		Username: *username,
+		Password: os.Getenv(*passwordEnv),
+	}
+	if *plainHTTP {
+		client.Scheme = "http"
+	}
+
+	bar := console.bar
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout, "  GEF Image Proof — Go Verifier")
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+
+	report, proof, err := verifyImage(client, ref, gefverify.VerifyOptions{})
+	if err != nil {
+		if report.Verdict == gefverify.VerdictMalformed {
+			fatalMalformed(err)
+		}
+		fmt.Fprintf(stderr, "FATAL: %s: %v\n", fs.Arg(0), err)
+		return 2
+	}
+
+	fmt.Fprintf(stdout, "  Image              : %s\n", fs.Arg(0))
+	fmt.Fprintf(stdout, "  Manifest digest    : %s\n", proof.ManifestDigest)
+	if proof.Layout != "" {
+		fmt.Fprintf(stdout, "  Proof layout       : %s\n", proof.Layout)
+	}
+	fmt.Fprintln(stdout)
+
+	printChecks(report.Checks)
+
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	verdict := report.Verdict
+	if verdict == gefverify.VerdictVerified {
+		fmt.Fprintf(stdout, "  %s  IMAGE PROOF VERIFIED  (%d/%d checks)  verdict=%s\n\n", console.pass,
+			report.Passed(), report.Total(), verdict)
+	} else {
+		fmt.Fprintf(stdout, "  %s  IMAGE PROOF FAILED  (%d/%d checks passed)  verdict=%s\n\n", console.fail,
+			report.Passed(), report.Total(), verdict)
+		printFailures(report.Failed())
+	}
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+	return verdict.ExitCode()
+}
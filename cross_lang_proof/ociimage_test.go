@@ -0,0 +1,229 @@
+// cross_lang_proof/ociimage_test.go
+
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// testBundleJSON returns a signed proof bundle around payload.
+func testBundleJSON(t *testing.T, payload interface{}) []byte {
+	t.Helper()
+	priv := ed25519.NewKeyFromSeed([]byte(strings.Repeat("k", 32)))
+	pubHex := hex.EncodeToString(priv.Public().(ed25519.PublicKey))
+	sd := map[string]interface{}{
+		"agent_id":          "build-agent",
+		"causal_hash":       genesisCausalHash,
+		"gef_version":       "1.0",
+		"nonce":             "00112233445566778899aabbccddeeff",
+		"payload":           payload,
+		"record_id":         "build-1",
+		"record_type":       "build",
+		"sequence":          0,
+		"signer_public_key": pubHex,
+		"timestamp":         "2026-02-24T12:00:00.000Z",
+	}
+	canonical, err := gefverify.Canonicalize(sd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := ed25519.Sign(priv, canonical)
+	hash := sha256.Sum256(canonical)
+	data, err := json.Marshal(map[string]interface{}{
+		"gef_version":         "1.0",
+		"public_key_hex":      pubHex,
+		"signing_dict":        sd,
+		"canonical_bytes_hex": hex.EncodeToString(canonical),
+		"chain_dict":          sd,
+		"chain_bytes_hex":     hex.EncodeToString(canonical),
+		"causal_hash_of_this": hex.EncodeToString(hash[:]),
+		"signature_b64url":    base64.RawURLEncoding.EncodeToString(sig),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// fakeRegistry serves fixed paths under /v2/app/ behind bearer auth.
+type fakeRegistry struct {
+	t       *testing.T
+	objects map[string][]byte // "manifests/<ref>", "blobs/<digest>", "referrers/<digest>"
+}
+
+func (f *fakeRegistry) put(key string, v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	f.objects[key] = data
+	return sha256Digest(data)
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/token" {
+		if u, p, ok := r.BasicAuth(); !ok || u != "ci" || p != "hunter2" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"token": "tok-123"})
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer tok-123" {
+		w.Header().Set("WWW-Authenticate",
+			`Bearer realm="http://`+r.Host+`/token",service="fake",scope="repository:app:pull,push"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, ok := f.objects[strings.TrimPrefix(r.URL.Path, "/v2/app/")]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Write(body)
+}
+
+// newImage stores a config blob and an image manifest, returning the
+// config digest and the manifest (not yet stored).
+func (f *fakeRegistry) newImage() (string, ociManifest) {
+	config := []byte(`{"architecture":"amd64","os":"linux"}`)
+	configDigest := sha256Digest(config)
+	f.objects["blobs/"+configDigest] = config
+	return configDigest, ociManifest{
+		MediaType: manifestMediaTypes[0],
+		Config:    ociDescriptor{MediaType: "application/vnd.oci.image.config.v1+json", Digest: configDigest, Size: int64(len(config))},
+	}
+}
+
+// attachReferrer stores bundle as a referrer artifact of imageDigest.
+func (f *fakeRegistry) attachReferrer(imageDigest string, bundle []byte) {
+	blobDigest := sha256Digest(bundle)
+	f.objects["blobs/"+blobDigest] = bundle
+	artifact, err := json.Marshal(ociManifest{
+		MediaType:    manifestMediaTypes[0],
+		ArtifactType: proofArtifactType,
+		Layers:       []ociDescriptor{{MediaType: proofArtifactType, Digest: blobDigest, Size: int64(len(bundle))}},
+	})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	artifactDigest := sha256Digest(artifact)
+	f.objects["manifests/"+artifactDigest] = artifact
+	f.put("referrers/"+imageDigest, ociManifest{
+		MediaType: "application/vnd.oci.image.index.v1+json",
+		Manifests: []ociDescriptor{{MediaType: manifestMediaTypes[0], ArtifactType: proofArtifactType, Digest: artifactDigest}},
+	})
+}
+
+func startRegistry(t *testing.T) (*fakeRegistry, *registryClient, imageRef) {
+	f := &fakeRegistry{t: t, objects: map[string][]byte{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	c := &registryClient{HTTP: srv.Client(), Scheme: "http", Username: "ci", Password: "hunter2"}
+	ref, err := parseImageRef(strings.TrimPrefix(srv.URL, "http://") + "/app:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, c, ref
+}
+
+func TestVerifyImageAnnotation(t *testing.T) {
+	f, c, ref := startRegistry(t)
+	configDigest, m := f.newImage()
+	m.Annotations = map[string]string{
+		proofAnnotation: string(testBundleJSON(t, map[string]interface{}{payloadDigestField: configDigest})),
+	}
+	f.put("manifests/v1", m)
+
+	report, proof, err := verifyImage(c, ref, gefverify.VerifyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proof.Layout != "annotation" || report.Verdict != gefverify.VerdictVerified {
+		t.Errorf("layout=%s verdict=%s failed=%v", proof.Layout, report.Verdict, report.Failed())
+	}
+}
+
+func TestVerifyImageReferrers(t *testing.T) {
+	f, c, ref := startRegistry(t)
+	_, m := f.newImage()
+	imageDigest := f.put("manifests/v1", m)
+	f.attachReferrer(imageDigest, testBundleJSON(t, map[string]interface{}{payloadDigestField: imageDigest}))
+
+	report, proof, err := verifyImage(c, ref, gefverify.VerifyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proof.Layout != "referrers" || report.Verdict != gefverify.VerdictVerified {
+		t.Errorf("layout=%s verdict=%s failed=%v", proof.Layout, report.Verdict, report.Failed())
+	}
+	if c.Token != "tok-123" {
+		t.Errorf("token = %q, want one obtained from the challenge", c.Token)
+	}
+}
+
+func TestVerifyImageMissingProof(t *testing.T) {
+	f, c, ref := startRegistry(t)
+	_, m := f.newImage()
+	f.put("manifests/v1", m)
+
+	report, _, err := verifyImage(c, ref, gefverify.VerifyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Verdict != gefverify.VerdictUnverifiable {
+		t.Errorf("verdict = %s, want %s", report.Verdict, gefverify.VerdictUnverifiable)
+	}
+}
+
+func TestVerifyImageDigestMismatch(t *testing.T) {
+	f, c, ref := startRegistry(t)
+	_, m := f.newImage()
+	imageDigest := f.put("manifests/v1", m)
+	f.attachReferrer(imageDigest, testBundleJSON(t, map[string]interface{}{
+		payloadDigestField: "sha256:" + strings.Repeat("0", 64),
+	}))
+
+	report, _, err := verifyImage(c, ref, gefverify.VerifyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Verdict != gefverify.VerdictTampered {
+		t.Errorf("verdict = %s, want %s", report.Verdict, gefverify.VerdictTampered)
+	}
+	last := report.Checks[len(report.Checks)-1]
+	if last.ID != "I.image_digest" || last.Passed {
+		t.Errorf("last check = %s passed=%v", last.ID, last.Passed)
+	}
+}
+
+func TestParseImageRef(t *testing.T) {
+	tests := []struct{ in, registry, repo, reference string }{
+		{"ghcr.io/acme/app:v1", "ghcr.io", "acme/app", "v1"},
+		{"localhost:5000/app", "localhost:5000", "app", "latest"},
+		{"r.example/app@sha256:abc", "r.example", "app", "sha256:abc"},
+	}
+	for _, tt := range tests {
+		ref, err := parseImageRef(tt.in)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		if ref.Registry != tt.registry || ref.Repository != tt.repo || ref.Reference != tt.reference {
+			t.Errorf("%s: got %+v", tt.in, ref)
+		}
+	}
+	if _, err := parseImageRef("app:v1"); err == nil {
+		t.Error("accepted a reference without registry host")
+	}
+}
@@ -0,0 +1,161 @@
+// cross_lang_proof/openmetrics.go
+//
+// OpenMetrics textfile export
+// ===========================
+//
+//   verify_proof chain -metrics-textfile /var/lib/node_exporter/gef.prom <dir>
+//
+// For batch jobs scraped through a textfile collector instead of a live
+// endpoint. At the end of the run the file is replaced atomically with:
+//
+//   gef_records_verified_total                       counter
+//   gef_check_failures_total{check_id}               counter
+//   gef_agent_failures_total{agent_id}               counter, top -metrics-max-agents
+//                                                    agents; the rest sum into
+//                                                    agent_id="_other"
+//   gef_record_verification_seconds                  histogram, per record (read,
+//                                                    canonicalize, verify)
+//   gef_run_duration_seconds                         gauge
+//   gef_run_throughput_records_per_second            gauge
+//
+// in OpenMetrics 1.0 text format, ending with "# EOF". These names are
+// the contract with dashboards: anything that exports GEF metrics live
+// must use the same constants.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metric family names. Counters get "_total" on their samples.
+const (
+	metricRecordsVerified = "gef_records_verified"
+	metricCheckFailures   = "gef_check_failures"
+	metricAgentFailures   = "gef_agent_failures"
+	metricRecordSeconds   = "gef_record_verification_seconds"
+	metricRunDuration     = "gef_run_duration_seconds"
+	metricRunThroughput   = "gef_run_throughput_records_per_second"
+	otherAgentsLabel      = "_other"
+	defaultMaxAgentSeries = 20
+)
+
+// recordSecondsBuckets are the upper bounds of gef_record_verification_seconds.
+var recordSecondsBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 1}
+
+// histogram is a fixed-bucket histogram; counts are per bucket, not
+// cumulative.
+type histogram struct {
+	bounds []float64
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+}
+
+func (h *histogram) observe(v float64) {
+	i := sort.SearchFloat64s(h.bounds, v) // first bound >= v; len = +Inf
+	h.counts[i]++
+	h.sum += v
+	h.count++
+}
+
+// chainMetrics is what one chain run exports.
+type chainMetrics struct {
+	Records       int
+	CheckFailures map[string]int // check ID → failed checks
+	AgentFailures map[string]int // agent_id → failed checks
+	RecordSeconds *histogram
+	Duration      time.Duration
+}
+
+func newChainMetrics() *chainMetrics {
+	return &chainMetrics{
+		CheckFailures: make(map[string]int),
+		AgentFailures: make(map[string]int),
+		RecordSeconds: newHistogram(recordSecondsBuckets),
+	}
+}
+
+// agentSeries returns at most max agents by descending failures, then
+// agent_id, with the remainder summed under otherAgentsLabel.
+func (m *chainMetrics) agentSeries(max int) []histogramEntry {
+	all := failureHistogram(m.AgentFailures).sorted()
+	if len(all) <= max {
+		return all
+	}
+	other := 0
+	for _, e := range all[max:] {
+		other += e.Count
+	}
+	return append(all[:max:max], histogramEntry{otherAgentsLabel, other})
+}
+
+// writeOpenMetrics writes m in OpenMetrics text format, "# EOF" last.
+func writeOpenMetrics(w io.Writer, m *chainMetrics, maxAgents int) error {
+	b := bufio.NewWriter(w)
+	family := func(name, typ, unit, help string) {
+		fmt.Fprintf(b, "# TYPE %s %s\n", name, typ)
+		if unit != "" {
+			fmt.Fprintf(b, "# UNIT %s %s\n", name, unit)
+		}
+		fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	}
+
+	family(metricRecordsVerified, "counter", "", "Records verified in this run.")
+	fmt.Fprintf(b, "%s_total %d\n", metricRecordsVerified, m.Records)
+
+	family(metricCheckFailures, "counter", "", "Failed checks by check ID.")
+	for _, e := range failureHistogram(m.CheckFailures).sorted() {
+		fmt.Fprintf(b, "%s_total{check_id=\"%s\"} %d\n", metricCheckFailures, escapeLabel(e.Code), e.Count)
+	}
+
+	family(metricAgentFailures, "counter", "",
+		fmt.Sprintf("Failed checks by agent_id, top %d agents, rest as %s.", maxAgents, otherAgentsLabel))
+	for _, e := range m.agentSeries(maxAgents) {
+		fmt.Fprintf(b, "%s_total{agent_id=\"%s\"} %d\n", metricAgentFailures, escapeLabel(e.Code), e.Count)
+	}
+
+	h := m.RecordSeconds
+	family(metricRecordSeconds, "histogram", "seconds", "Time to read, canonicalize and verify one record.")
+	var cumulative uint64
+	for i, bound := range h.bounds {
+		cumulative += h.counts[i]
+		fmt.Fprintf(b, "%s_bucket{le=\"%s\"} %d\n", metricRecordSeconds, formatFloat(bound), cumulative)
+	}
+	fmt.Fprintf(b, "%s_bucket{le=\"+Inf\"} %d\n", metricRecordSeconds, h.count)
+	fmt.Fprintf(b, "%s_sum %s\n", metricRecordSeconds, formatFloat(h.sum))
+	fmt.Fprintf(b, "%s_count %d\n", metricRecordSeconds, h.count)
+
+	seconds := m.Duration.Seconds()
+	family(metricRunDuration, "gauge", "seconds", "Wall time of the run.")
+	fmt.Fprintf(b, "%s %s\n", metricRunDuration, formatFloat(seconds))
+
+	throughput := 0.0
+	if seconds > 0 {
+		throughput = float64(m.Records) / seconds
+	}
+	family(metricRunThroughput, "gauge", "", "Records verified per second of wall time.")
+	fmt.Fprintf(b, "%s %s\n", metricRunThroughput, formatFloat(throughput))
+
+	fmt.Fprintln(b, "# EOF")
+	return b.Flush()
+}
+
+// escapeLabel escapes a label value: backslash, double quote, newline.
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
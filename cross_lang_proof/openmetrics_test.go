@@ -0,0 +1,253 @@
+// cross_lang_proof/openmetrics_test.go
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// ── Strict OpenMetrics text parser ────────────────────────────────────────────
+
+var (
+	omMeta   = regexp.MustCompile(`^# (TYPE|UNIT|HELP) ([a-zA-Z_:][a-zA-Z0-9_:]*) (.+)$`)
+	omSample = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(\{(.*)\})? (\S+)$`)
+	omLabel  = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*)="((?:[^"\\]|\\["\\n])*)"(,|$)`)
+)
+
+var omSuffixes = map[string][]string{
+	"counter":   {"_total"},
+	"gauge":     {""},
+	"histogram": {"_bucket", "_sum", "_count"},
+}
+
+type omFamily struct {
+	Type, Unit string
+	Samples    map[string]float64 // sample name + sorted label pairs → value
+}
+
+// parseOpenMetrics rejects anything outside the subset of OpenMetrics 1.0
+// the exporter may produce: no blank lines, TYPE first per family,
+// families not interleaved, units matching the name, suffixes matching
+// the type, cumulative buckets ending at +Inf == _count, and "# EOF" as
+// the final line.
+func parseOpenMetrics(text string) (map[string]*omFamily, error) {
+	if !strings.HasSuffix(text, "# EOF\n") {
+		return nil, fmt.Errorf("missing trailing # EOF")
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "# EOF\n"), "\n")
+	lines = lines[:len(lines)-1]
+	fams := make(map[string]*omFamily)
+	var cur string
+	var lastBucket float64
+	for n, line := range lines {
+		fail := func(format string, a ...interface{}) error {
+			return fmt.Errorf("line %d %q: %s", n+1, line, fmt.Sprintf(format, a...))
+		}
+		if m := omMeta.FindStringSubmatch(line); m != nil {
+			name := m[2]
+			switch m[1] {
+			case "TYPE":
+				if fams[name] != nil {
+					return nil, fail("family declared twice")
+				}
+				if omSuffixes[m[3]] == nil {
+					return nil, fail("unknown type")
+				}
+				fams[name] = &omFamily{Type: m[3], Samples: make(map[string]float64)}
+				cur, lastBucket = name, -1
+			case "UNIT":
+				if name != cur || !strings.HasSuffix(name, "_"+m[3]) {
+					return nil, fail("unit does not match family")
+				}
+				fams[name].Unit = m[3]
+			case "HELP":
+				if name != cur {
+					return nil, fail("HELP outside its family")
+				}
+			}
+			continue
+		}
+		m := omSample.FindStringSubmatch(line)
+		if m == nil || cur == "" {
+			return nil, fail("not a sample or metadata line")
+		}
+		fam := fams[cur]
+		suffix := strings.TrimPrefix(m[1], cur)
+		if suffix == m[1] && cur != "" {
+			return nil, fail("sample outside family %s", cur)
+		}
+		okSuffix := false
+		for _, s := range omSuffixes[fam.Type] {
+			okSuffix = okSuffix || s == suffix
+		}
+		if !okSuffix {
+			return nil, fail("suffix %q invalid for %s", suffix, fam.Type)
+		}
+		labels, rest := []string{}, m[3]
+		for rest != "" {
+			l := omLabel.FindStringSubmatch(rest)
+			if l == nil {
+				return nil, fail("bad label set")
+			}
+			labels = append(labels, l[1]+"="+l[2])
+			rest = rest[len(l[0]):]
+		}
+		value, err := strconv.ParseFloat(m[4], 64)
+		if err != nil {
+			return nil, fail("bad value")
+		}
+		if suffix == "_bucket" {
+			if value < lastBucket {
+				return nil, fail("bucket counts not cumulative")
+			}
+			lastBucket = value
+		}
+		key := m[1] + "{" + strings.Join(labels, ",") + "}"
+		if _, dup := fam.Samples[key]; dup {
+			return nil, fail("duplicate sample")
+		}
+		fam.Samples[key] = value
+	}
+	for name, f := range fams {
+		if f.Type == "histogram" && f.Samples[name+`_bucket{le=+Inf}`] != f.Samples[name+"_count{}"] {
+			return nil, fmt.Errorf("%s: +Inf bucket != _count", name)
+		}
+	}
+	return fams, nil
+}
+
+// ── Tests ─────────────────────────────────────────────────────────────────────
+
+// writeChainDir writes a signed chain of n records for agent into dir.
+func writeChainDir(t *testing.T, dir, agent string, n int) []string {
+	t.Helper()
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte(agent[:1]), 32))
+	var files []string
+	prev := ""
+	for i := 0; i < n; i++ {
+		b := gefverify.NewRecord(agent, "execution").Sequence(int64(i))
+		if i == 0 {
+			b = gefverify.NewRecord(agent, "genesis").Genesis()
+		} else {
+			b = b.PreviousHash(prev)
+		}
+		_, bundle, err := b.Finalize(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prev = bundle.CausalHashOfThis
+		data, _ := json.Marshal(bundle)
+		path := filepath.Join(dir, fmt.Sprintf("%s-%d.json", agent, i))
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, path)
+	}
+	return files
+}
+
+func TestChainMetricsTextfile(t *testing.T) {
+	dir := t.TempDir()
+	writeChainDir(t, dir, "alpha", 3)
+	beta := writeChainDir(t, dir, "beta", 2)
+
+	// Break beta's signature: the record still parses, and fails the
+	// record check and the five contract checks carried after it.
+	data, _ := os.ReadFile(beta[1])
+	var bundle gefverify.ProofBundle
+	json.Unmarshal(data, &bundle)
+	bundle.SigningDict["payload"] = map[string]interface{}{"forged": true}
+	data, _ = json.Marshal(bundle)
+	os.WriteFile(beta[1], data, 0o644)
+
+	out := filepath.Join(t.TempDir(), "gef.prom")
+	code, _, errOut := runCaptured(t, "chain", "-metrics-textfile", out, dir)
+	if code != 1 {
+		t.Fatalf("exit %d, want 1 (TAMPERED)\n%s", code, errOut)
+	}
+	text, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fams, err := parseOpenMetrics(string(text))
+	if err != nil {
+		t.Fatalf("%v\n%s", err, text)
+	}
+
+	want := map[string]float64{
+		"gef_records_verified_total{}":                               5,
+		"gef_check_failures_total{check_id=chain.record_unverified}": 1,
+		"gef_check_failures_total{check_id=C3.signature_go}":         1,
+		"gef_agent_failures_total{agent_id=beta}":                    6,
+		"gef_record_verification_seconds_count{}":                    5,
+	}
+	for key, v := range want {
+		family := key[:strings.IndexAny(key, "{")]
+		for _, s := range []string{"_total", "_count"} {
+			family = strings.TrimSuffix(family, s)
+		}
+		got, ok := fams[family].Samples[key]
+		if !ok || got != v {
+			t.Errorf("%s = %v (present %v), want %v", key, got, ok, v)
+		}
+	}
+	if len(fams[metricAgentFailures].Samples) != 1 {
+		t.Errorf("agent series = %v, want beta only", fams[metricAgentFailures].Samples)
+	}
+	if fams[metricRecordSeconds].Unit != "seconds" || fams[metricRunThroughput].Samples[metricRunThroughput+"{}"] <= 0 {
+		t.Errorf("histogram unit or throughput wrong:\n%s", text)
+	}
+}
+
+func TestOpenMetricsAgentSeriesCapped(t *testing.T) {
+	m := newChainMetrics()
+	m.AgentFailures = map[string]int{"a": 5, "b": 3, "c": 2, `d"q`: 1}
+	var buf bytes.Buffer
+	if err := writeOpenMetrics(&buf, m, 2); err != nil {
+		t.Fatal(err)
+	}
+	fams, err := parseOpenMetrics(buf.String())
+	if err != nil {
+		t.Fatalf("%v\n%s", err, buf.String())
+	}
+	got := fams[metricAgentFailures].Samples
+	want := map[string]float64{
+		"gef_agent_failures_total{agent_id=a}":      5,
+		"gef_agent_failures_total{agent_id=b}":      3,
+		"gef_agent_failures_total{agent_id=_other}": 3,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("series = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestParseOpenMetricsIsStrict(t *testing.T) {
+	for name, text := range map[string]string{
+		"no eof":         "# TYPE x gauge\nx 1\n",
+		"untyped sample": "x 1\n# EOF\n",
+		"bad suffix":     "# TYPE x counter\nx 1\n# EOF\n",
+		"blank line":     "# TYPE x gauge\n\nx 1\n# EOF\n",
+		"unit mismatch":  "# TYPE x_bytes gauge\n# UNIT x_bytes seconds\nx_bytes 1\n# EOF\n",
+		"inf != count":   "# TYPE h histogram\nh_bucket{le=\"+Inf\"} 2\nh_sum 1\nh_count 3\n# EOF\n",
+	} {
+		if _, err := parseOpenMetrics(text); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// cross_lang_proof/output.go
+//
+// Output streams and exit paths
+// =============================
+//
+// Stream policy, so that `verify_proof b.json > report.txt 2> errors.txt`
+// splits cleanly:
+//
+//   stdout   the human report (banner, checks, verdict) and the artifacts
+//            a subcommand emits in place of a file (fmt, badge without -out);
+//            with -report-json - the report document alone, the human
+//            report then going to stderr; with -format json the report
+//            document alone, with -format junit or sarif the JUnit XML or
+//            SARIF log alone, and no human report at all
+//   stderr   diagnostics: FATAL/VERDICT lines, usage and flag errors,
+//            progress such as "formatted x.json"
+//
+// Both are buffered. Nothing below run calls os.Exit: subcommands return
+// their exit code, and helpers that must abort from deep inside
+// (fatalMalformed, writeReportJSON, parseFlags) call exit, which unwinds
+// to run. run flushes both writers on every path — normal return, exit,
+// and panic, which it turns into "FATAL: internal error" on stderr and
+// exit code 1. main only calls os.Exit with what run returned.
+//
+// Order is preserved within each stream. Across the two streams nothing
+// is promised; they are meant to be read separately.
+//
+// Exit codes, so that a script can tell a tampered bundle from a missing
+// file (verify_proof reference lists them for the running binary):
+//
+//   0     VERIFIED
+//   1     TAMPERED; also a refused rewrite (fmt, convert) or an internal
+//         error
+//   2     usage: bad flags or arguments; I/O: an input that cannot be read
+//         (missing file, git show or registry failure), an output that
+//         cannot be written
+//   3     MALFORMED: input that was read but cannot be parsed — a bundle,
+//         a report document, a shard report, a snapshot
+//   4-6   POLICY_REJECTED, UNVERIFIABLE, VERIFIED_UNTRUSTED_KEY
+//
+// Codes 0, 1 and 3-6 are gefverify's Verdict.ExitCode; a batch, chain or
+// audit exits with that of its worst verdict.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime/debug"
+)
+
+var (
+	stdout = bufio.NewWriter(os.Stdout)
+	stderr = bufio.NewWriter(os.Stderr)
+)
+
+// exitCode is the panic value used by exit to unwind to run.
+type exitCode int
+
+// exit stops the run with code. Deferred functions still run, and run
+// flushes the output before main exits.
+func exit(code int) {
+	panic(exitCode(code))
+}
+
+// run dispatches args to a subcommand or the default verification and
+// returns the process exit code, with stdout and stderr flushed.
+func run(args []string) (code int) {
+	defer func() {
+		if r := recover(); r != nil {
+			if c, ok := r.(exitCode); ok {
+				code = int(c)
+			} else {
+				fmt.Fprintf(stderr, "FATAL: internal error: %v\n%s", r, debug.Stack())
+				code = 1
+			}
+		}
+		stdout.Flush()
+		stderr.Flush()
+	}()
+
+	console = reporterFor(os.Getenv("NO_COLOR"))
+	if len(args) > 0 {
+		if cmd, ok := subcommands[args[0]]; ok {
+			return cmd(args[1:])
+		}
+	}
+	return runVerify(args)
+}
+
+// noColor is the -no-color flag of the flag set parsed last.
+var noColor bool
+
+// newFlagSet returns a flag set that reports to stderr and leaves exiting
+// to parseFlags, so a usage error still flushes the output. Every one
+// has -no-color (console.go).
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.BoolVar(&noColor, "no-color", false, "ASCII markers ([PASS], [FAIL]) instead of emoji; also NO_COLOR")
+	return fs
+}
+
+// parseFlags parses args into fs; -h exits 0 and any other error exits 2,
+// as flag.ExitOnError would.
+func parseFlags(fs *flag.FlagSet, args []string) {
+	err := fs.Parse(args)
+	if noColor {
+		console = asciiReporter
+	}
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		exit(0)
+	case err != nil:
+		exit(2)
+	}
+}
@@ -0,0 +1,381 @@
+// cross_lang_proof/output_test.go
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// runCaptured runs the CLI with stdout and stderr captured separately.
+func runCaptured(t *testing.T, args ...string) (code int, out, errOut string) {
+	t.Helper()
+	var o, e bytes.Buffer
+	savedOut, savedErr := stdout, stderr
+	stdout, stderr = bufio.NewWriter(&o), bufio.NewWriter(&e)
+	defer func() { stdout, stderr = savedOut, savedErr }()
+	code = run(args)
+	return code, o.String(), e.String()
+}
+
+// writeBundle writes the repo's proof bundle, with edit applied, to a
+// temp file.
+func writeBundle(t *testing.T, edit func(string) string) string {
+	t.Helper()
+	data, err := os.ReadFile("proof_bundle.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "bundle.json")
+	if err := os.WriteFile(path, []byte(edit(string(data))), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestStreamsOnSuccess(t *testing.T) {
+	code, out, errOut := runCaptured(t, "proof_bundle.json")
+	if code != 0 {
+		t.Fatalf("exit %d\nstdout:\n%s\nstderr:\n%s", code, out, errOut)
+	}
+	if !strings.Contains(out, "GEF Cross-Language Proof — Go Verifier") || !strings.Contains(out, "CROSS-LANGUAGE PROOF PASSED") {
+		t.Errorf("stdout lacks banner or verdict:\n%s", out)
+	}
+	if errOut != "" {
+		t.Errorf("stderr = %q, want empty", errOut)
+	}
+}
+
+func TestStreamsOnFailure(t *testing.T) {
+	path := writeBundle(t, tamperSignature)
+	code, out, errOut := runCaptured(t, path)
+	if code != 1 {
+		t.Fatalf("exit %d, want 1 (TAMPERED)\nstderr:\n%s", code, errOut)
+	}
+	if !strings.Contains(out, "CROSS-LANGUAGE PROOF FAILED") || !strings.Contains(out, "verdict=TAMPERED") {
+		t.Errorf("stdout lacks failure verdict:\n%s", out)
+	}
+	if errOut != "" {
+		t.Errorf("stderr = %q, want empty", errOut)
+	}
+}
+
+func TestStreamsOnFatal(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "absent.json")
+	code, out, errOut := runCaptured(t, missing)
+	if code != 2 {
+		t.Errorf("exit %d, want 2", code)
+	}
+	if !strings.HasPrefix(errOut, "FATAL: cannot read "+missing) || strings.Count(errOut, "\n") != 1 {
+		t.Errorf("stderr = %q, want the single FATAL line", errOut)
+	}
+	if strings.Contains(out, "FATAL") || !strings.Contains(out, "Go Verifier") {
+		t.Errorf("stdout = %q, want banner only", out)
+	}
+}
+
+func TestStreamsOnMalformed(t *testing.T) {
+	path := writeBundle(t, func(string) string { return "{not json" })
+	code, out, errOut := runCaptured(t, path)
+	if code != 3 {
+		t.Errorf("exit %d, want 3 (MALFORMED)", code)
+	}
+	if !strings.HasPrefix(errOut, "FATAL: ") || !strings.HasSuffix(errOut, "VERDICT: MALFORMED\n") {
+		t.Errorf("stderr = %q", errOut)
+	}
+	if strings.Contains(out, "FATAL") || strings.Contains(out, "VERDICT") {
+		t.Errorf("diagnostics leaked to stdout:\n%s", out)
+	}
+}
+
+func TestMalformedReportDocument(t *testing.T) {
+	path := writeBundle(t, func(s string) string {
+		return strings.Replace(s, `"public_key_hex": "`, `"public_key_hex": "zz`, 1)
+	})
+	const failed = "V.bundle_field.public_key_hex"
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	for _, args := range [][]string{
+		{"-format", "json", path},
+		{"-report-json", reportPath, path},
+	} {
+		code, out, _ := runCaptured(t, args...)
+		if args[0] == "-report-json" {
+			out = string(mustRead(t, reportPath))
+		}
+		var doc gefverify.ReportDocument
+		if err := json.Unmarshal([]byte(out), &doc); err != nil || code != 3 {
+			t.Fatalf("%v: exit %d, err %v\n%s", args, code, err, out)
+		}
+		if doc.Verdict != gefverify.VerdictMalformed || len(doc.Checks) != 1 || doc.Checks[0].ID != failed {
+			t.Errorf("%v: document %+v", args, doc)
+		}
+	}
+	code, out, _ := runCaptured(t, "-format", "sarif", path)
+	if code != 3 || !strings.Contains(out, `"check_id": "`+failed+`"`) {
+		t.Errorf("sarif: exit %d\n%s", code, out)
+	}
+}
+
+func TestStreamsOnUsageError(t *testing.T) {
+	code, out, errOut := runCaptured(t, "chain", "-no-such-flag")
+	if code != 2 {
+		t.Errorf("exit %d, want 2", code)
+	}
+	if out != "" || !strings.Contains(errOut, "flag provided but not defined") || !strings.Contains(errOut, "usage: verify_proof chain") {
+		t.Errorf("stdout = %q\nstderr = %q", out, errOut)
+	}
+}
+
+func TestStreamsWithFormatJSON(t *testing.T) {
+	tampered := writeBundle(t, tamperSignature)
+	for _, tt := range []struct {
+		path    string
+		code    int
+		verdict gefverify.Verdict
+	}{
+		{"proof_bundle.json", 0, gefverify.VerdictVerified},
+		{tampered, 1, gefverify.VerdictTampered},
+	} {
+		code, out, errOut := runCaptured(t, "--format", "json", tt.path)
+		if code != tt.code || errOut != "" {
+			t.Errorf("%s: exit %d, want %d\nstderr:\n%s", tt.path, code, tt.code, errOut)
+		}
+		var doc gefverify.ReportDocument
+		if err := json.Unmarshal([]byte(out), &doc); err != nil {
+			t.Fatalf("%s: stdout is not one JSON document: %v\n%s", tt.path, err, out)
+		}
+		if doc.Verdict != tt.verdict || doc.Bundle != tt.path || doc.GEFVersion != "1.0" ||
+			!strings.HasPrefix(doc.KeyFingerprint, "sha256:") || len(doc.Checks) != doc.Total || doc.Checks[0].ID == "" {
+			t.Errorf("%s: document %+v", tt.path, doc)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"-format", "yaml", "proof_bundle.json"},
+		{"-format", "json", "-report-json", "-", "proof_bundle.json"},
+	} {
+		if code, out, _ := runCaptured(t, args...); code != 2 || out != "" {
+			t.Errorf("%v: exit %d stdout %q, want a usage error", args, code, out)
+		}
+	}
+}
+
+func TestStreamsOnPanic(t *testing.T) {
+	subcommands["test-panic"] = func([]string) int {
+		stdout.WriteString("written before the panic\n")
+		panic("boom")
+	}
+	defer delete(subcommands, "test-panic")
+
+	code, out, errOut := runCaptured(t, "test-panic")
+	if code != 1 {
+		t.Errorf("exit %d, want 1", code)
+	}
+	if out != "written before the panic\n" {
+		t.Errorf("stdout = %q, want the buffered line flushed", out)
+	}
+	if !strings.HasPrefix(errOut, "FATAL: internal error: boom\n") {
+		t.Errorf("stderr = %q", errOut)
+	}
+}
+
+// TestExitCodes checks that a script can tell the failures apart: a
+// tampered bundle (1), an input that cannot be read (2) and one that
+// cannot be parsed (3), whichever subcommand reads it.
+func TestExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "absent.json")
+	garbage := filepath.Join(dir, "garbage.json")
+	must(t, os.WriteFile(garbage, []byte("{not json"), 0o644))
+	tampered := writeBundle(t, tamperSignature)
+
+	for _, tc := range []struct {
+		want int
+		args []string
+	}{
+		{0, []string{"proof_bundle.json"}},
+		{1, []string{tampered}},
+		{2, []string{missing}},
+		{2, []string{"-git-rev", "HEAD:proof_bundle.json", "proof_bundle.json"}},
+		{2, []string{"lint", missing}},
+		{2, []string{"fmt", missing}},
+		{2, []string{"badge", "-from", missing}},
+		{2, []string{"report", "merge", missing}},
+		{2, []string{"chain", "-manifest", missing, "proof_bundle.json"}},
+		{2, []string{"chain", dir + "/none"}},
+		{3, []string{garbage}},
+		{3, []string{"fmt", garbage}},
+		{3, []string{"badge", "-from", garbage}},
+		{3, []string{"report", "merge", garbage}},
+		{3, []string{"snapshot", "-to-json", garbage}},
+		{4, []string{"-max-clock-skew", "5m", "-now", "2026-02-24T23:54:59Z", "proof_bundle.json"}},
+		{4, []string{"-max-age", "24h", "-now", "2026-02-26T00:00:01Z", "proof_bundle.json"}},
+		{0, []string{"-max-clock-skew", "5m", "-max-age", "24h", "-now", "2026-02-26T00:00:00Z", "proof_bundle.json"}},
+	} {
+		if code, _, errOut := runCaptured(t, tc.args...); code != tc.want {
+			t.Errorf("%v: exit %d, want %d\n%s", tc.args, code, tc.want, errOut)
+		}
+	}
+}
+
+func TestTrustedKeys(t *testing.T) {
+	bundle, err := gefverify.ParseBundle(mustRead(t, "proof_bundle.json"))
+	must(t, err)
+	key, err := gefverify.DecodePublicKey(bundle.PublicKeyHex)
+	must(t, err)
+	dir := t.TempDir()
+	trusted, stranger := filepath.Join(dir, "trusted.json"), filepath.Join(dir, "stranger.json")
+	must(t, os.WriteFile(trusted, []byte(`[{"public_key_hex": "`+bundle.PublicKeyHex+`"}]`), 0o644))
+	must(t, os.WriteFile(stranger, []byte(`[{"public_key_hex": "`+strings.Repeat("ab", 32)+`"}]`), 0o644))
+
+	code, out, _ := runCaptured(t, "-trusted-keys", trusted, "proof_bundle.json")
+	if code != 0 || !strings.Contains(out, "signed by a key in -trusted-keys") || strings.Contains(out, keyTrustWarning) {
+		t.Errorf("trusted: exit %d\n%s", code, out)
+	}
+	code, out, _ = runCaptured(t, "-trusted-keys", stranger, "proof_bundle.json")
+	if code != gefverify.VerdictVerifiedUntrustedKey.ExitCode() || !strings.Contains(out, "untrusted key "+gefverify.KeyFingerprint(key)) {
+		t.Errorf("untrusted: exit %d\n%s", code, out)
+	}
+	if code, out, _ := runCaptured(t, "proof_bundle.json"); code != 0 || !strings.Contains(out, keyTrustWarning) {
+		t.Errorf("no -trusted-keys: exit %d\n%s", code, out)
+	} else if notes, note := strings.Index(out, "NOTES —"), strings.Index(out, "key trust not checked: no trusted keys"); notes < 0 || note < notes {
+		// CONTRACT 10 ran no check: its note is not printed under CONTRACT 11.
+		t.Errorf("no -trusted-keys: C10 note outside NOTES\n%s", out)
+	}
+	if code, _, _ := runCaptured(t, "-trusted-keys", filepath.Join(dir, "absent.json"), "proof_bundle.json"); code != 2 {
+		t.Errorf("missing keys file: exit %d, want 2", code)
+	}
+}
+
+func TestWithoutCanonicalBytesHex(t *testing.T) {
+	bundle, err := gefverify.ParseBundle(mustRead(t, "proof_bundle.json"))
+	must(t, err)
+	path := writeBundle(t, func(s string) string {
+		return strings.Replace(s, `"canonical_bytes_hex": "`+bundle.CanonicalBytesHex+`"`, `"canonical_bytes_hex": ""`, 1)
+	})
+	code, out, _ := runCaptured(t, path)
+	if code != 0 || !strings.Contains(out, "(16/16 checks, 11/11 contracts)") || !strings.Contains(out, "NOT APPLICABLE: no canonical_bytes_hex") {
+		t.Errorf("exit %d\n%s", code, out)
+	}
+	_, out, _ = runCaptured(t, "-format", "junit", path)
+	if !strings.Contains(out, `tests="17" failures="0" errors="0" skipped="1"`) || !strings.Contains(out, `name="not applicable"`) {
+		t.Errorf("-format junit:\n%s", out)
+	}
+}
+
+func TestPassBannerNamesAlgorithms(t *testing.T) {
+	for path, want := range map[string][]string{
+		"proof_bundle.json":                           {"Chain hash (sha256)   → ", "Signature (ed25519)   → "},
+		"pkg/gefverify/testdata/hash_alg/sha512.json": {"Chain hash (sha512)   → ", "Signature (ed25519)   → "},
+	} {
+		code, out, _ := runCaptured(t, path)
+		for _, w := range want {
+			if code != 0 || !strings.Contains(out, w) {
+				t.Errorf("%s: exit %d, no %q\n%s", path, code, w, out)
+			}
+		}
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	tampered := writeBundle(t, tamperSignature)
+	dir := t.TempDir()
+	writeChainDir(t, dir, "alpha", 3)
+
+	for _, tc := range []struct {
+		args         []string
+		code         int
+		out, failure string
+	}{
+		{[]string{"-quiet", "proof_bundle.json"}, 0, "proof_bundle.json: VERIFIED  (18/18 checks)\n", ""},
+		{[]string{"-quiet", tampered}, 1, tampered + ": TAMPERED  (14/18 checks)\n", "[C3.signature_go]"},
+		{[]string{"-quiet", "proof_bundle.json", tampered}, 1, "batch: TAMPERED  (1 passed, 1 failed of 2 bundle(s))\n", "[C3.signature_go]"},
+		{[]string{"chain", "-quiet", dir}, 0, "chain: VERIFIED  (3 records, 11/11 checks)\n", ""},
+	} {
+		code, out, errOut := runCaptured(t, tc.args...)
+		if code != tc.code || out != tc.out {
+			t.Errorf("%v: exit %d, stdout %q; want %d, %q", tc.args, code, out, tc.code, tc.out)
+		}
+		for _, line := range strings.SplitAfter(errOut, "\n") {
+			if line != "" && !strings.HasPrefix(line, "FAILED: "+tampered+": ") && !strings.HasPrefix(line, "failure distribution: ") {
+				t.Errorf("%v: stderr line %q", tc.args, line)
+			}
+		}
+		if !strings.Contains(errOut, tc.failure) || (tc.failure == "") != (errOut == "") {
+			t.Errorf("%v: stderr %q, want the failed checks", tc.args, errOut)
+		}
+	}
+
+	if code, _, errOut := runCaptured(t, "-quiet", "-format", "json", "proof_bundle.json"); code != 2 || !strings.Contains(errOut, "-quiet") {
+		t.Errorf("-quiet -format json: exit %d, stderr %q", code, errOut)
+	}
+	if code, _, _ := runCaptured(t, "chain", "-quiet", "-verbose", dir); code != 2 {
+		t.Errorf("-quiet -verbose: exit %d, want 2", code)
+	}
+}
+
+func TestVerbose(t *testing.T) {
+	bundle, err := gefverify.ParseBundle(mustRead(t, "proof_bundle.json"))
+	must(t, err)
+	_, out, _ := runCaptured(t, "-verbose", "proof_bundle.json")
+	for _, want := range []string{
+		"go=" + bundle.CanonicalBytesHex + "  python=" + bundle.CanonicalBytesHex,
+		"python=" + bundle.CausalHashOfThis,
+		"Public key         : " + bundle.PublicKeyHex + "\n",
+		"  " + bundle.EnvelopeJSON + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("-verbose report lacks %q", want)
+		}
+	}
+	if _, out, _ := runCaptured(t, "proof_bundle.json"); strings.Contains(out, bundle.CausalHashOfThis) {
+		t.Error("default report prints the whole chain hash")
+	}
+
+	_, out, _ = runCaptured(t, "-verbose", "proof_bundle.json", "proof_bundle.json")
+	if strings.Count(out, "python="+bundle.CausalHashOfThis) != 2 {
+		t.Errorf("batch -verbose does not print each bundle's checks:\n%s", out)
+	}
+	dir := t.TempDir()
+	files := writeChainDir(t, dir, "alpha", 2)
+	if _, out, _ := runCaptured(t, "chain", "-verbose", dir); !strings.Contains(out, "chain_hash="+chainHashOf(t, files[1])) {
+		t.Errorf("chain -verbose lacks the chain hashes:\n%s", out)
+	}
+}
+
+func TestNoColor(t *testing.T) {
+	tampered := writeBundle(t, tamperSignature)
+	emoji := func(s string) bool { return strings.ContainsAny(s, "✅❌⚠·═─↳") }
+
+	for _, tc := range []struct {
+		env    string
+		args   []string
+		failed bool
+	}{
+		{"", []string{"-no-color", tampered}, true},
+		{"1", []string{tampered}, true},
+		{"1", []string{"proof_bundle.json", tampered}, true},
+		{"", []string{"lint", "-no-color", "proof_bundle.json"}, false},
+	} {
+		t.Setenv("NO_COLOR", tc.env)
+		_, out, _ := runCaptured(t, tc.args...)
+		if emoji(out) || !strings.Contains(out, "[PASS]") || !strings.Contains(out, strings.Repeat("=", 64)) {
+			t.Errorf("NO_COLOR=%q %v:\n%s", tc.env, tc.args, out)
+		}
+		if strings.Contains(out, "[FAIL]") != tc.failed {
+			t.Errorf("NO_COLOR=%q %v: [FAIL] markers:\n%s", tc.env, tc.args, out)
+		}
+	}
+
+	t.Setenv("NO_COLOR", "")
+	if _, out, _ := runCaptured(t, "proof_bundle.json"); !emoji(out) || strings.Contains(out, "[PASS]") {
+		t.Errorf("emoji not restored after -no-color:\n%s", out)
+	}
+}
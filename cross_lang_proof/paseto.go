@@ -0,0 +1,338 @@
+// cross_lang_proof/paseto.go
+//
+// PASETO v4.public transport
+// ==========================
+//
+//   verify_proof verify-paseto (-key hex | -keyring keys.json) [-implicit s]
+//                              [-prev hash] <token | @file>
+//
+// Some partners carry GEF records as PASETO v4.public tokens instead of
+// proof bundles:
+//
+//   v4.public.<base64url(message || sig)>[.<base64url(footer)>]
+//
+// sig is Ed25519 over PAE("v4.public.", message, footer, implicit), where
+// PAE is the pre-authentication encoding of the PASETO spec: LE64 piece
+// count, then LE64 length and bytes of every piece (MSB of each LE64
+// cleared). The footer is authenticated but not encrypted; when it is a
+// JSON object with "kid", the kid selects the key from -keyring. A pinned
+// -key always wins over the keyring.
+//
+// Once the token verifies, message must be the GEF record JSON, in its
+// JCS form: the signing_dict. It is verified as a proof bundle would be,
+// every contract with the token signature over PAE in place of the
+// bundle's (gefverify.VerifyCarried); the pinned key or the keyring are
+// the trusted keys of CONTRACT 10. Then causal_hash is checked against
+// -prev, or against the genesis hash at sequence 0 (R.causal_link), and
+// the chain hash of the record is left as a note.
+//
+// Only v4.public is accepted; every other version or purpose (v3.*,
+// v4.local, ...) is rejected as MALFORMED rather than guessed at.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+const pasetoV4Public = "v4.public."
+
+const (
+	sectionPaseto = "PASETO — v4.public Token"
+	sectionRecord = "RECORD — Embedded GEF Record"
+)
+
+// pasetoToken is a decoded v4.public token.
+type pasetoToken struct {
+	Message   []byte
+	Signature []byte
+	Footer    []byte
+}
+
+// parsePasetoV4Public splits and decodes token. Any other version or
+// purpose is an error.
+func parsePasetoV4Public(token string) (pasetoToken, error) {
+	var t pasetoToken
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) < 3 || len(parts) > 4 {
+		return t, &gefverify.MalformedError{Reason: "not a PASETO token",
+			Err: fmt.Errorf("got %d dot-separated parts, expected 3 or 4", len(parts))}
+	}
+	if header := parts[0] + "." + parts[1] + "."; header != pasetoV4Public {
+		return t, &gefverify.MalformedError{Reason: "unsupported PASETO token",
+			Err: fmt.Errorf("header %q: only %q is accepted", header, pasetoV4Public)}
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return t, &gefverify.MalformedError{Reason: "PASETO payload", Err: err}
+	}
+	if len(body) < ed25519.SignatureSize {
+		return t, &gefverify.MalformedError{Reason: "PASETO payload",
+			Err: fmt.Errorf("got %d bytes, shorter than a signature", len(body))}
+	}
+	split := len(body) - ed25519.SignatureSize
+	t.Message, t.Signature = body[:split], body[split:]
+
+	if len(parts) == 4 {
+		if t.Footer, err = base64.RawURLEncoding.DecodeString(parts[3]); err != nil {
+			return t, &gefverify.MalformedError{Reason: "PASETO footer", Err: err}
+		}
+	}
+	return t, nil
+}
+
+// pae is the PASETO pre-authentication encoding of pieces.
+func pae(pieces ...[]byte) []byte {
+	le64 := func(n int) []byte {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, uint64(n)&^(1<<63))
+		return b
+	}
+	out := le64(len(pieces))
+	for _, p := range pieces {
+		out = append(out, le64(len(p))...)
+		out = append(out, p...)
+	}
+	return out
+}
+
+// verify checks the token signature under pub.
+func (t pasetoToken) verify(pub ed25519.PublicKey, implicit []byte) bool {
+	return ed25519.Verify(pub, pae([]byte(pasetoV4Public), t.Message, t.Footer, implicit), t.Signature)
+}
+
+// kid returns the key id of a JSON footer, or "" if there is none.
+func (t pasetoToken) kid() string {
+	var footer struct {
+		KID string `json:"kid"`
+	}
+	if len(t.Footer) == 0 || json.Unmarshal(t.Footer, &footer) != nil {
+		return ""
+	}
+	return footer.KID
+}
+
+// loadKeyring reads a JSON object mapping key ids to public key hex.
+func loadKeyring(path string) (map[string]ed25519.PublicKey, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	keys := make(map[string]ed25519.PublicKey, len(raw))
+	for kid, pubHex := range raw {
+		pub, err := gefverify.DecodePublicKey(pubHex)
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %q: %w", path, kid, err)
+		}
+		keys[kid] = pub
+	}
+	return keys, nil
+}
+
+// pasetoRequest is everything verifyPaseto needs besides the token.
+type pasetoRequest struct {
+	Pinned   ed25519.PublicKey            // -key; wins over Keyring
+	Keyring  map[string]ed25519.PublicKey // kid → key
+	Implicit []byte                       // implicit assertion, may be empty
+	PrevHash string                       // expected causal_hash, may be empty
+}
+
+// ── Verification ──────────────────────────────────────────────────────────────
+
+// verifyPaseto verifies a v4.public token and the GEF record it carries.
+// The error is set only for tokens that cannot be decoded at all.
+func verifyPaseto(token string, req pasetoRequest) (gefverify.Report, error) {
+	var report gefverify.Report
+	check := func(id, section string, category gefverify.Category, name string, passed bool, details string) {
+		report.Checks = append(report.Checks, gefverify.CheckResult{
+			ID: id, Section: section, Name: name, Passed: passed, Details: details, Category: category,
+		})
+	}
+	done := func() (gefverify.Report, error) {
+		report.Verdict = gefverify.DeriveVerdict(report.Checks)
+		return report, nil
+	}
+
+	t, err := parsePasetoV4Public(token)
+	if err != nil {
+		report.Verdict = gefverify.VerdictMalformed
+		return report, err
+	}
+
+	// ── Key selection ──
+	kid := t.kid()
+	pub, source := req.Pinned, "pinned -key"
+	if pub == nil {
+		pub, source = req.Keyring[kid], fmt.Sprintf("keyring kid=%q", kid)
+	}
+	if pub == nil {
+		details := "token footer carries no kid and no -key was given"
+		if kid != "" {
+			details = fmt.Sprintf("kid %q not in keyring", kid)
+		}
+		check("P.key", sectionPaseto, gefverify.CategoryCompleteness, "verification key available", false, details)
+		return done()
+	}
+	check("P.key", sectionPaseto, gefverify.CategoryCompleteness, "verification key available", true,
+		fmt.Sprintf("%s  pubkey=%s...", source, hex.EncodeToString(pub)[:16]))
+
+	sigValid := t.verify(pub, req.Implicit)
+	check("P.signature", sectionPaseto, gefverify.CategoryIntegrity, "v4.public signature valid", sigValid,
+		fmt.Sprintf("message=%d bytes  footer=%d bytes  implicit=%d bytes",
+			len(t.Message), len(t.Footer), len(req.Implicit)))
+	if !sigValid {
+		return done()
+	}
+
+	encode := func(message []byte) []byte {
+		return pae([]byte(pasetoV4Public), message, t.Footer, req.Implicit)
+	}
+	checkRecord(&report, t.Message, pub, t.Signature, encode, req.Pinned, req.Keyring, req.PrevHash)
+	return done()
+}
+
+// checkRecord verifies message, the GEF record a transport signed with
+// pub as encode(message), under every contract of a proof bundle
+// (gefverify.VerifyCarried), and adds the checks to report after the
+// transport's own. The keys the caller offered, pinned or in a keyring,
+// are the trusted keys of CONTRACT 10.
+func checkRecord(report *gefverify.Report, message []byte, pub ed25519.PublicKey, sig []byte,
+	encode func([]byte) []byte, pinned ed25519.PublicKey, keyring map[string]ed25519.PublicKey, prevHash string) {
+	trusted := []gefverify.TrustedKey{{PublicKeyHex: hex.EncodeToString(pinned)}}
+	if pinned == nil {
+		trusted = trusted[:0]
+		for _, k := range keyring {
+			trusted = append(trusted, gefverify.TrustedKey{PublicKeyHex: hex.EncodeToString(k)})
+		}
+	}
+	carried, err := gefverify.NewVerifier(gefverify.WithTrustedKeys(trusted)).VerifyCarried(pub, message, sig, encode)
+	transport := report.Checks
+	*report = carried
+	report.Checks = append(transport, carried.Checks...)
+	if err != nil {
+		report.Checks = append(report.Checks, gefverify.CheckResult{
+			ID: "R.record_json", Section: sectionRecord, Name: "message is a GEF record", Details: err.Error(),
+			Category: gefverify.CategoryStructure,
+		})
+		return
+	}
+
+	var record map[string]interface{}
+	if json.Unmarshal(message, &record) != nil {
+		return // VerifyCarried decoded it
+	}
+	causal, _ := record["causal_hash"].(string)
+	seq, _    := record["sequence"].(float64)
+	want      := normalizeHash(prevHash)
+	if want == "" && seq == 0 {
+		want = genesisCausalHash
+	}
+	if want == "" {
+		report.Notes = append(report.Notes, fmt.Sprintf(
+			"causal_hash not checked: sequence %d needs -prev", int64(seq)))
+		return
+	}
+	report.Checks = append(report.Checks, gefverify.CheckResult{
+		ID: "R.causal_link", Section: sectionRecord, Name: "causal_hash links to previous record",
+		Passed:   causal == want,
+		Details:  fmt.Sprintf("causal_hash=%s...  expected=%s...", gefverify.ShortHex(causal, 16), gefverify.ShortHex(want, 16)),
+		Category: gefverify.CategoryIntegrity,
+	})
+}
+
+// ── Subcommand ────────────────────────────────────────────────────────────────
+
+func runVerifyPaseto(args []string) int {
+	fs := newFlagSet("verify-paseto")
+	keyHex      := fs.String("key", "", "pinned Ed25519 public key `hex` or env:NAME")
+	keyringPath := fs.String("keyring", "", "JSON `file` (or env:NAME, -) mapping footer kid to public key hex")
+	implicit    := fs.String("implicit", "", "implicit assertion `string` bound into the signature")
+	prevHash    := fs.String("prev", "", "chain `hash` the record's causal_hash must link to")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof verify-paseto (-key hex | -keyring keys.json) [flags] <token | @file>")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+	if fs.NArg() != 1 || (*keyHex == "" && *keyringPath == "") {
+		fs.Usage()
+		return 2
+	}
+
+	var req pasetoRequest
+	req.Implicit = []byte(*implicit)
+	req.PrevHash = *prevHash
+	if *keyHex != "" {
+		value, err := inlineValue(*keyHex)
+		var pub ed25519.PublicKey
+		if err == nil {
+			pub, err = gefverify.DecodePublicKey(value)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: -key: %v\n", err)
+			return 2
+		}
+		req.Pinned = pub
+	}
+	if *keyringPath != "" {
+		keys, err := loadKeyring(*keyringPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: -keyring: %v\n", err)
+			return 2
+		}
+		req.Keyring = keys
+	}
+
+	token := fs.Arg(0)
+	if strings.HasPrefix(token, "@") {
+		data, err := readInput(token[1:])
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", token[1:], err)
+			return 2
+		}
+		token = string(bytes.TrimSpace(data))
+	}
+
+	bar := console.bar
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout, "  GEF PASETO Record — Go Verifier")
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+
+	report, err := verifyPaseto(token, req)
+	if err != nil {
+		fatalMalformed(err)
+	}
+
+	printChecks(report.Checks)
+	printNotes(report.Notes)
+
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	verdict := report.Verdict
+	if verdict == gefverify.VerdictVerified {
+		fmt.Fprintf(stdout, "  %s  PASETO RECORD VERIFIED  (%d/%d checks)  verdict=%s\n\n", console.pass,
+			report.Passed(), report.Total(), verdict)
+	} else {
+		fmt.Fprintf(stdout, "  %s  PASETO RECORD FAILED  (%d/%d checks passed)  verdict=%s\n\n", console.fail,
+			report.Passed(), report.Total(), verdict)
+		printFailures(report.Failed())
+	}
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+	return verdict.ExitCode()
+}
@@ -0,0 +1,156 @@
+// cross_lang_proof/paseto_test.go
+
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// TestPasetoSpecVectors checks PAE and signature handling against the
+// public-purpose vectors of the PASETO spec.
+func TestPasetoSpecVectors(t *testing.T) {
+	data, err := os.ReadFile("testdata/paseto_v4_public.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors struct {
+		Tests []struct {
+			Name       string `json:"name"`
+			ExpectFail bool   `json:"expect-fail"`
+			PublicKey  string `json:"public-key"`
+			Token      string `json:"token"`
+			Payload    string `json:"payload"`
+			Footer     string `json:"footer"`
+			Implicit   string `json:"implicit-assertion"`
+		} `json:"tests"`
+	}
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range vectors.Tests {
+		pub, err := gefverify.DecodePublicKey(v.PublicKey)
+		if err != nil {
+			t.Fatalf("%s: %v", v.Name, err)
+		}
+		tok, err := parsePasetoV4Public(v.Token)
+		if err != nil {
+			t.Errorf("%s: %v", v.Name, err)
+			continue
+		}
+		if string(tok.Message) != v.Payload || string(tok.Footer) != v.Footer {
+			t.Errorf("%s: message=%q footer=%q", v.Name, tok.Message, tok.Footer)
+		}
+		if ok := tok.verify(pub, []byte(v.Implicit)); ok == v.ExpectFail {
+			t.Errorf("%s: verify = %v, expect-fail = %v", v.Name, ok, v.ExpectFail)
+		}
+		if tok.verify(pub, []byte(v.Implicit+"x")) {
+			t.Errorf("%s: verified with a different implicit assertion", v.Name)
+		}
+	}
+}
+
+func TestPasetoRejectsOtherVersions(t *testing.T) {
+	for _, tok := range []string{
+		"v3.public.AAAA",
+		"v4.local.AAAA",
+		"v2.public.AAAA.BBBB",
+		"not-a-token",
+	} {
+		if _, err := parsePasetoV4Public(tok); err == nil {
+			t.Errorf("%s: accepted", tok)
+		}
+		if report, err := verifyPaseto(tok, pasetoRequest{}); err == nil || report.Verdict != gefverify.VerdictMalformed {
+			t.Errorf("%s: verdict=%s err=%v, want MALFORMED", tok, report.Verdict, err)
+		}
+	}
+}
+
+// signPaseto makes a v4.public token around the signing_dict of a test
+// bundle, signed with the same key.
+func signPaseto(t *testing.T, footer string, edit func(record map[string]interface{})) (string, ed25519.PublicKey) {
+	t.Helper()
+	var bundle gefverify.ProofBundle
+	if err := json.Unmarshal(testBundleJSON(t, map[string]interface{}{"step": "deploy"}), &bundle); err != nil {
+		t.Fatal(err)
+	}
+	if edit != nil {
+		edit(bundle.SigningDict)
+	}
+	msg, err := json.Marshal(bundle.SigningDict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv := ed25519.NewKeyFromSeed([]byte(strings.Repeat("k", 32)))
+	sig := ed25519.Sign(priv, pae([]byte(pasetoV4Public), msg, []byte(footer), nil))
+	tok := pasetoV4Public + base64.RawURLEncoding.EncodeToString(append(msg, sig...))
+	if footer != "" {
+		tok += "." + base64.RawURLEncoding.EncodeToString([]byte(footer))
+	}
+	return tok, priv.Public().(ed25519.PublicKey)
+}
+
+func TestVerifyPasetoRecord(t *testing.T) {
+	tok, pub := signPaseto(t, `{"kid":"build-2026"}`, nil)
+
+	tests := []struct {
+		name string
+		req  pasetoRequest
+		want gefverify.Verdict
+	}{
+		{"pinned", pasetoRequest{Pinned: pub}, gefverify.VerdictVerified},
+		{"keyring kid", pasetoRequest{Keyring: map[string]ed25519.PublicKey{"build-2026": pub}}, gefverify.VerdictVerified},
+		{"unknown kid", pasetoRequest{Keyring: map[string]ed25519.PublicKey{"other": pub}}, gefverify.VerdictUnverifiable},
+		{"wrong key", pasetoRequest{Pinned: make(ed25519.PublicKey, ed25519.PublicKeySize)}, gefverify.VerdictTampered},
+		{"implicit mismatch", pasetoRequest{Pinned: pub, Implicit: []byte("tenant-a")}, gefverify.VerdictTampered},
+		{"prev mismatch", pasetoRequest{Pinned: pub, PrevHash: strings.Repeat("1", 64)}, gefverify.VerdictTampered},
+	}
+	for _, tt := range tests {
+		report, err := verifyPaseto(tok, tt.req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if report.Verdict != tt.want {
+			t.Errorf("%s: verdict = %s, want %s (failed: %v)", tt.name, report.Verdict, tt.want, report.Failed())
+		}
+	}
+}
+
+func TestVerifyPasetoRecordChecks(t *testing.T) {
+	other := hex.EncodeToString(make([]byte, ed25519.PublicKeySize))
+	tests := []struct {
+		name   string
+		edit   func(map[string]interface{})
+		failed string
+		want   gefverify.Verdict
+	}{
+		{"extra field", func(r map[string]interface{}) { r["signature"] = "x" }, "C5.field_count", gefverify.VerdictMalformed},
+		{"missing field", func(r map[string]interface{}) { delete(r, "nonce") }, "C5.field_present.nonce", gefverify.VerdictMalformed},
+		{"sequence as string", func(r map[string]interface{}) { r["sequence"] = "0" }, "C5.field_type.sequence", gefverify.VerdictMalformed},
+		{"signer mismatch", func(r map[string]interface{}) { r["signer_public_key"] = other }, "C11.signer_binding", gefverify.VerdictTampered},
+		{"bad timestamp", func(r map[string]interface{}) { r["timestamp"] = "yesterday" }, "C9.timestamp_format", gefverify.VerdictMalformed},
+		{"broken genesis", func(r map[string]interface{}) { r["causal_hash"] = strings.Repeat("f", 64) }, "R.causal_link", gefverify.VerdictTampered},
+	}
+	for _, tt := range tests {
+		tok, pub := signPaseto(t, "", tt.edit)
+		report, err := verifyPaseto(tok, pasetoRequest{Pinned: pub})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		failed := report.Failed()
+		found := false
+		for _, c := range failed {
+			found = found || c.ID == tt.failed
+		}
+		if report.Verdict != tt.want || !found {
+			t.Errorf("%s: verdict=%s failed=%v, want %s on %s", tt.name, report.Verdict, failed, tt.want, tt.failed)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// cross_lang_proof/pkg/gefverify/aliases.go
+//
+// Field-name aliases (schema evolution)
+// =====================================
+//
+// When a spec version renames a signing_dict field, e.g.
+// signer_public_key → signer_key, the required-field contract (CHECK 5)
+// should accept the new name for records signed under that version
+// instead of forcing a verifier fork. Aliases affect CHECK 5 only: the
+// signature is always verified over the signing_dict exactly as signed.
+//
+// The table is keyed by the *signed* gef_version — the advertised one is
+// not covered by the signature. A version absent from the table has no
+// aliases. JSON form, as accepted by -field-aliases:
+//
+//   { "1.1": { "signer_public_key": "signer_key" } }
+
+package gefverify
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// FieldAliases maps gef_version → required field name → accepted new name.
+type FieldAliases map[string]map[string]string
+
+// DefaultFieldAliases is the built-in table. GEF 1.0 renames nothing.
+var DefaultFieldAliases = FieldAliases{
+	"1.0": {},
+}
+
+// ParseFieldAliases decodes the JSON form of a FieldAliases table.
+func ParseFieldAliases(data []byte) (FieldAliases, error) {
+	var a FieldAliases
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("cannot parse field aliases: %w", err)
+	}
+	for version, renames := range a {
+		for field, alias := range renames {
+			if alias == "" || alias == field {
+				return nil, fmt.Errorf("field aliases %s: invalid alias %q for %q", version, alias, field)
+			}
+		}
+	}
+	return a, nil
+}
+
+// resolve returns the key under which the required field is present in
+// dict for version, and whether that key is an alias. ok is false if
+// neither the field nor its alias is present.
+func (a FieldAliases) resolve(version, field string, dict map[string]interface{}) (key string, aliased, ok bool) {
+	if _, found := dict[field]; found {
+		return field, false, true
+	}
+	if alias, has := a[version][field]; has {
+		if _, found := dict[alias]; found {
+			return alias, true, true
+		}
+	}
+	return "", false, false
+}
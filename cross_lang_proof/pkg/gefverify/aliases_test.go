@@ -0,0 +1,71 @@
+// cross_lang_proof/pkg/gefverify/aliases_test.go
+
+package gefverify
+
+import "testing"
+
+// renamedBundle returns the reference bundle with signer_public_key
+// renamed to signer_key under gef_version "1.1" (signature no longer
+// verifies, which is irrelevant to the field contract).
+func renamedBundle(t *testing.T) ProofBundle {
+	bundle := loadProofBundle(t)
+	sd := make(map[string]interface{}, len(bundle.SigningDict))
+	for k, val := range bundle.SigningDict {
+		sd[k] = val
+	}
+	sd["signer_key"] = sd["signer_public_key"]
+	delete(sd, "signer_public_key")
+	sd["gef_version"] = "1.1"
+	bundle.SigningDict = sd
+	return bundle
+}
+
+func fieldContractPassed(t *testing.T, report Report) bool {
+	t.Helper()
+	for _, c := range report.Checks {
+		if c.Section == SectionFieldCount && !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFieldAliases(t *testing.T) {
+	aliases, err := ParseFieldAliases([]byte(`{"1.1": {"signer_public_key": "signer_key"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := NewVerifier().Verify(renamedBundle(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fieldContractPassed(t, report) {
+		t.Error("renamed field satisfied the contract without an alias table")
+	}
+
+	report, err = NewVerifier(WithFieldAliases(aliases)).Verify(renamedBundle(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fieldContractPassed(t, report) {
+		t.Errorf("alias not honoured: %v", report.Failed())
+	}
+
+	// Aliases are per version: the same rename under 1.0 is still missing.
+	bundle := renamedBundle(t)
+	bundle.SigningDict["gef_version"] = "1.0"
+	report, err = NewVerifier(WithFieldAliases(aliases)).Verify(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fieldContractPassed(t, report) {
+		t.Error("1.1 alias applied to a 1.0 record")
+	}
+}
+
+func TestParseFieldAliasesRejectsSelfAlias(t *testing.T) {
+	if _, err := ParseFieldAliases([]byte(`{"1.1": {"nonce": "nonce"}}`)); err == nil {
+		t.Error("expected error for a field aliased to itself")
+	}
+}
@@ -0,0 +1,300 @@
+// cross_lang_proof/pkg/gefverify/builder.go
+//
+// Record builder
+// ==============
+//
+// For Go services that emit GEF records instead of only verifying them.
+// Hand-rolled signing maps tend to get a field name wrong or sign the
+// signature; the builder makes both impossible:
+//
+//   env, bundle, err := gefverify.NewRecord("agent-7", "execution").
+//       Payload(map[string]interface{}{"tool": "deploy"}).
+//       Sequence(12).
+//       PreviousHash(prev.CausalHashOfThis).
+//       Finalize(privateKey)
+//
+// Finalize fills nonce (16 random bytes, hex), timestamp (GEF wire format,
+// UTC milliseconds) and record_id ("gef-" + UUIDv4) as ExecutionEnvelope
+// in guardclaw/core/models.py does, then canonicalizes, signs and hashes.
+// Nonce, At and RecordID fix them instead, to reproduce a record such as
+// the one emit_proof.py publishes.
+// signing_dict is built from RequiredFields and nothing else, so it has
+// exactly the 10 fields and never the signature.
+//
+// The returned ProofBundle carries the maps as a reader would decode them
+// (numbers as float64), so it verifies the same in memory and on disk.
+
+package gefverify
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// GEFVersion is the protocol version the builder emits.
+const GEFVersion = "1.0"
+
+// GenesisHash is the causal_hash of the first record of a chain.
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// TimestampLayout is the GEF wire format for signing_dict.timestamp.
+const TimestampLayout = "2006-01-02T15:04:05.000Z"
+
+// RecordTypes are the record_type values of GEF-SPEC-v1.0.
+var RecordTypes = []string{
+	"genesis", "agent_registration", "intent", "execution", "result",
+	"failure", "delegation", "heartbeat", "tool_call", "tombstone",
+	"admin_action", "amendment",
+}
+
+// Envelope is a signed GEF record as written to the JSONL ledger: the
+// signing_dict fields plus the signature, in ledger key order.
+type Envelope struct {
+	AgentID         string                 `json:"agent_id"`
+	CausalHash      string                 `json:"causal_hash"`
+	GEFVersion      string                 `json:"gef_version"`
+	Nonce           string                 `json:"nonce"`
+	Payload         map[string]interface{} `json:"payload"`
+	RecordID        string                 `json:"record_id"`
+	RecordType      string                 `json:"record_type"`
+	Sequence        int64                  `json:"sequence"`
+	SignerPublicKey string                 `json:"signer_public_key"`
+	Timestamp       string                 `json:"timestamp"`
+	Signature       string                 `json:"signature"`
+}
+
+// RecordBuilder assembles one record. Setters only record values;
+// every check happens in Finalize.
+type RecordBuilder struct {
+	agentID    string
+	recordType string
+	payload    map[string]interface{}
+	sequence   int64
+	prevHash   string
+	genesis    bool
+	at         time.Time
+	rand       io.Reader
+	nonce      string
+	recordID   string
+}
+
+// NewRecord starts a record of recordType for agentID.
+func NewRecord(agentID, recordType string) *RecordBuilder {
+	return &RecordBuilder{agentID: agentID, recordType: recordType}
+}
+
+// Payload sets the payload; nil means an empty object.
+func (b *RecordBuilder) Payload(p map[string]interface{}) *RecordBuilder {
+	b.payload = p
+	return b
+}
+
+// Sequence sets the record's position in the agent's chain.
+func (b *RecordBuilder) Sequence(n int64) *RecordBuilder {
+	b.sequence = n
+	return b
+}
+
+// PreviousHash links the record to the chain hash of the agent's
+// previous record.
+func (b *RecordBuilder) PreviousHash(h string) *RecordBuilder {
+	b.prevHash = h
+	return b
+}
+
+// Genesis marks the record as the first of its chain: sequence 0,
+// causal_hash GenesisHash.
+func (b *RecordBuilder) Genesis() *RecordBuilder {
+	b.genesis = true
+	return b
+}
+
+// At fixes the timestamp instead of the system clock.
+func (b *RecordBuilder) At(t time.Time) *RecordBuilder {
+	b.at = t
+	return b
+}
+
+// Rand sets the source for nonce and record_id instead of crypto/rand.
+func (b *RecordBuilder) Rand(r io.Reader) *RecordBuilder {
+	b.rand = r
+	return b
+}
+
+// Nonce fixes the nonce instead of drawing it, e.g. to reproduce a
+// published record; it must be 32 or more lowercase hex characters.
+func (b *RecordBuilder) Nonce(n string) *RecordBuilder {
+	b.nonce = n
+	return b
+}
+
+// RecordID fixes record_id instead of "gef-" + a random UUID.
+func (b *RecordBuilder) RecordID(id string) *RecordBuilder {
+	b.recordID = id
+	return b
+}
+
+// validate reports the first invalid state, or nil.
+func (b *RecordBuilder) validate() error {
+	switch {
+	case b.agentID == "":
+		return errors.New("agent_id is empty")
+	case !isRecordType(b.recordType):
+		return fmt.Errorf("record_type %q is not one of %v", b.recordType, RecordTypes)
+	case b.sequence < 0:
+		return fmt.Errorf("sequence %d is negative", b.sequence)
+	case b.genesis && b.prevHash != "":
+		return errors.New("genesis record cannot have a previous hash")
+	case b.genesis && b.sequence != 0:
+		return fmt.Errorf("genesis record must have sequence 0, got %d", b.sequence)
+	case !b.genesis && b.prevHash == "":
+		return errors.New("previous hash unset: call PreviousHash or Genesis")
+	case !b.genesis && b.sequence == 0:
+		return errors.New("sequence 0 must be the genesis record")
+	}
+	if b.nonce != "" {
+		if _, err := hex.DecodeString(b.nonce); err != nil || len(b.nonce) < 32 || strings.ToLower(b.nonce) != b.nonce {
+			return fmt.Errorf("nonce %q is not 32 or more lowercase hex characters", b.nonce)
+		}
+	}
+	if !b.genesis {
+		if raw, err := hex.DecodeString(b.prevHash); err != nil || len(raw) != sha256.Size {
+			return fmt.Errorf("previous hash %q is not 64 hex characters", b.prevHash)
+		}
+	}
+	return nil
+}
+
+func isRecordType(t string) bool {
+	for _, rt := range RecordTypes {
+		if t == rt {
+			return true
+		}
+	}
+	return false
+}
+
+// Finalize checks the record, fills nonce, timestamp and record_id, and
+// signs it with signer, which must hold an Ed25519 key (an
+// ed25519.PrivateKey or e.g. an HSM-backed crypto.Signer).
+func (b *RecordBuilder) Finalize(signer crypto.Signer) (Envelope, ProofBundle, error) {
+	if err := b.validate(); err != nil {
+		return Envelope{}, ProofBundle{}, fmt.Errorf("gefverify: invalid record: %w", err)
+	}
+	pub, ok := signer.Public().(ed25519.PublicKey)
+	if !ok {
+		return Envelope{}, ProofBundle{}, fmt.Errorf("gefverify: signer key is %T, not Ed25519", signer.Public())
+	}
+
+	random := b.rand
+	if random == nil {
+		random = rand.Reader
+	}
+	entropy := make([]byte, 32)
+	if _, err := io.ReadFull(random, entropy); err != nil {
+		return Envelope{}, ProofBundle{}, fmt.Errorf("gefverify: nonce: %w", err)
+	}
+	at := b.at
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	env := Envelope{
+		AgentID:         b.agentID,
+		CausalHash:      b.prevHash,
+		GEFVersion:      GEFVersion,
+		Nonce:           hex.EncodeToString(entropy[:16]),
+		Payload:         b.payload,
+		RecordID:        "gef-" + uuid4(entropy[16:]),
+		RecordType:      b.recordType,
+		Sequence:        b.sequence,
+		SignerPublicKey: hex.EncodeToString(pub),
+		Timestamp:       at.UTC().Format(TimestampLayout),
+	}
+	if b.nonce != "" {
+		env.Nonce = b.nonce
+	}
+	if b.recordID != "" {
+		env.RecordID = b.recordID
+	}
+	if b.genesis {
+		env.CausalHash = GenesisHash
+	}
+	if env.Payload == nil {
+		env.Payload = map[string]interface{}{}
+	}
+
+	signingDict, err := env.signingDict()
+	if err != nil {
+		return Envelope{}, ProofBundle{}, err
+	}
+	canonical, err := Canonicalize(signingDict)
+	if err != nil {
+		return Envelope{}, ProofBundle{}, fmt.Errorf("gefverify: canonicalize: %w", err)
+	}
+	sig, err := signer.Sign(nil, canonical, crypto.Hash(0))
+	if err != nil {
+		return Envelope{}, ProofBundle{}, fmt.Errorf("gefverify: sign: %w", err)
+	}
+	env.Signature = base64.RawURLEncoding.EncodeToString(sig)
+
+	envelopeJSON, err := json.Marshal(env)
+	if err != nil {
+		return Envelope{}, ProofBundle{}, fmt.Errorf("gefverify: envelope: %w", err)
+	}
+	chainDict, _ := env.signingDict()
+	chainHash    := sha256.Sum256(canonical)
+	bundle := ProofBundle{
+		Description:       "GEF proof bundle built by gefverify.RecordBuilder",
+		GEFVersion:        GEFVersion,
+		PublicKeyHex:      env.SignerPublicKey,
+		SigningDict:       signingDict,
+		CanonicalBytesHex: hex.EncodeToString(canonical),
+		ChainDict:         chainDict,
+		ChainBytesHex:     hex.EncodeToString(canonical),
+		CausalHashOfThis:  hex.EncodeToString(chainHash[:]),
+		SignatureB64URL:   env.Signature,
+		SignatureHex:      hex.EncodeToString(sig),
+		EnvelopeJSON:      string(envelopeJSON),
+	}
+	return env, bundle, nil
+}
+
+// signingDict returns exactly the RequiredFields of env, decoded the way
+// ParseBundle would decode them.
+func (env Envelope) signingDict() (map[string]interface{}, error) {
+	unsigned := env
+	unsigned.Signature = ""
+	raw, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("gefverify: signing_dict: %w", err)
+	}
+	var all map[string]interface{}
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return nil, fmt.Errorf("gefverify: signing_dict: %w", err)
+	}
+	sd := make(map[string]interface{}, len(RequiredFields))
+	for _, f := range RequiredFields {
+		sd[f] = all[f]
+	}
+	return sd, nil
+}
+
+// uuid4 formats 16 random bytes as a version 4 UUID.
+func uuid4(b []byte) string {
+	u := make([]byte, 16)
+	copy(u, b)
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
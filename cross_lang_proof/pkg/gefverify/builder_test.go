@@ -0,0 +1,151 @@
+// cross_lang_proof/pkg/gefverify/builder_test.go
+
+package gefverify
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+var builderKey = ed25519.NewKeyFromSeed([]byte(strings.Repeat("b", 32)))
+
+// requirePerfect fails unless bundle verifies with every check passing,
+// both in memory and after a round trip through JSON.
+func requirePerfect(t *testing.T, bundle ProofBundle) {
+	t.Helper()
+	raw, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ParseBundle(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, b := range map[string]ProofBundle{"in memory": bundle, "decoded": decoded} {
+		report, err := Verify(b, VerifyOptions{Hygiene: true})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !report.OK() || report.Verdict != VerdictVerified || len(report.Warnings) != 0 {
+			t.Errorf("%s: verdict=%s %d/%d failed=%v warnings=%v",
+				name, report.Verdict, report.Passed(), report.Total(), report.Failed(), report.Warnings)
+		}
+	}
+}
+
+func TestBuilderChainRoundTrip(t *testing.T) {
+	at := time.Date(2026, 2, 24, 12, 0, 0, 123e6, time.FixedZone("CET", 3600))
+	_, genesis, err := NewRecord("agent-7", "genesis").Genesis().At(at).Finalize(builderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requirePerfect(t, genesis)
+
+	env, next, err := NewRecord("agent-7", "execution").
+		Payload(map[string]interface{}{"tool": "deploy", "attempt": 2}).
+		Sequence(1).
+		PreviousHash(genesis.CausalHashOfThis).
+		At(at.Add(30 * time.Second)).
+		Finalize(builderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requirePerfect(t, next)
+
+	if env.CausalHash != genesis.CausalHashOfThis || next.SigningDict["causal_hash"] != genesis.CausalHashOfThis {
+		t.Errorf("record 1 does not link to genesis")
+	}
+	if env.Timestamp != "2026-02-24T11:00:30.123Z" {
+		t.Errorf("timestamp = %s, want UTC wire format", env.Timestamp)
+	}
+	if !strings.HasPrefix(env.RecordID, "gef-") || len(env.RecordID) != 40 || len(env.Nonce) != 32 {
+		t.Errorf("record_id=%s nonce=%s", env.RecordID, env.Nonce)
+	}
+}
+
+func TestBuilderSigningDictInvariants(t *testing.T) {
+	env, bundle, err := NewRecord("agent-7", "genesis").Genesis().Finalize(builderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bundle.SigningDict) != len(RequiredFields) {
+		t.Errorf("signing_dict has %d fields", len(bundle.SigningDict))
+	}
+	for _, f := range RequiredFields {
+		if _, ok := bundle.SigningDict[f]; !ok {
+			t.Errorf("signing_dict lacks %s", f)
+		}
+	}
+	if _, ok := bundle.SigningDict["signature"]; ok {
+		t.Error("signing_dict contains the signature")
+	}
+
+	var ledger map[string]interface{}
+	if err := json.Unmarshal([]byte(bundle.EnvelopeJSON), &ledger); err != nil {
+		t.Fatal(err)
+	}
+	if ledger["signature"] != env.Signature || len(ledger) != len(RequiredFields)+1 {
+		t.Errorf("envelope_json = %s", bundle.EnvelopeJSON)
+	}
+}
+
+func TestBuilderDeterministicWithRand(t *testing.T) {
+	build := func() ProofBundle {
+		_, b, err := NewRecord("agent-7", "genesis").Genesis().
+			At(time.Unix(1700000000, 0)).
+			Rand(bytes.NewReader(bytes.Repeat([]byte{7}, 32))).
+			Finalize(builderKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	if a, b := build(), build(); a.EnvelopeJSON != b.EnvelopeJSON {
+		t.Errorf("same clock and rand gave different records:\n%s\n%s", a.EnvelopeJSON, b.EnvelopeJSON)
+	}
+}
+
+func TestBuilderRejectsInvalidStates(t *testing.T) {
+	prev := strings.Repeat("a", 64)
+	tests := []struct {
+		name string
+		b    *RecordBuilder
+		want string
+	}{
+		{"missing agent", NewRecord("", "execution").Genesis(), "agent_id is empty"},
+		{"unknown type", NewRecord("a", "forward").Genesis(), "record_type"},
+		{"no previous hash", NewRecord("a", "execution").Sequence(3), "previous hash unset"},
+		{"genesis with previous", NewRecord("a", "genesis").Genesis().PreviousHash(prev), "cannot have a previous hash"},
+		{"genesis at sequence 5", NewRecord("a", "genesis").Genesis().Sequence(5), "sequence 0"},
+		{"sequence 0 not genesis", NewRecord("a", "execution").PreviousHash(prev), "must be the genesis"},
+		{"short previous hash", NewRecord("a", "execution").Sequence(1).PreviousHash("abc"), "64 hex"},
+		{"negative sequence", NewRecord("a", "execution").Sequence(-1).PreviousHash(prev), "negative"},
+	}
+	for _, tt := range tests {
+		_, _, err := tt.b.Finalize(builderKey)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestBuilderFixedNonceAndRecordID(t *testing.T) {
+	env, _, err := NewRecord("agent-7", "genesis").Genesis().
+		Nonce("abcdef1234567890abcdef1234567890").RecordID("gef-fixed-1").
+		Finalize(builderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.Nonce != "abcdef1234567890abcdef1234567890" || env.RecordID != "gef-fixed-1" {
+		t.Errorf("nonce %s, record_id %s", env.Nonce, env.RecordID)
+	}
+	for _, nonce := range []string{"abcdef", "ABCDEF1234567890ABCDEF1234567890", strings.Repeat("g", 32)} {
+		if _, _, err := NewRecord("a", "genesis").Genesis().Nonce(nonce).Finalize(builderKey); err == nil {
+			t.Errorf("nonce %q accepted", nonce)
+		}
+	}
+}
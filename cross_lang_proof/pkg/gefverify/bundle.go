@@ -0,0 +1,159 @@
+// cross_lang_proof/pkg/gefverify/bundle.go
+//
+// Proof bundle structure and canonicalization
+// ===========================================
+//
+// A proof bundle is the debugging artifact written by emit_proof.py: the
+// record plus EVERY intermediate value the emitter computed, so that an
+// independent implementation can recompute and compare each one.
+//
+// JCS library: github.com/gowebpki/jcs v1.0.1 (RFC 8785 compliant, tagged release)
+// API: jcs.Transform([]byte) ([]byte, error)
+//   Takes already-marshaled JSON bytes, returns canonical JSON bytes.
+
+package gefverify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gowebpki/jcs"
+)
+
+// ProofBundle mirrors proof_bundle.json.
+type ProofBundle struct {
+	Description       string                 `json:"_description"`
+	GEFVersion        string                 `json:"gef_version"`
+	PublicKeyHex      string                 `json:"public_key_hex"`
+	SigningDict        map[string]interface{} `json:"signing_dict"`
+	CanonicalBytesHex string                 `json:"canonical_bytes_hex"`
+	ChainDict         map[string]interface{} `json:"chain_dict"`
+	ChainBytesHex     string                 `json:"chain_bytes_hex"`
+	CausalHashOfThis  string                 `json:"causal_hash_of_this"`
+	SignatureB64URL   string                 `json:"signature_b64url"`
+	SignatureHex      string                 `json:"signature_hex"`
+	EnvelopeJSON      string                 `json:"envelope_json"`
+	HashAlg           string                 `json:"hash_alg,omitempty"`   // chain hash, "" for sha256 (hashalg.go)
+	SigAlg            string                 `json:"sig_alg,omitempty"`    // signature, "" for ed25519 (sigalg.go)
+	CoseSign1         string                 `json:"cose_sign1,omitempty"` // COSE_Sign1 signature, base64url (cose.go)
+
+	// Fields ParseBundle left zero because their JSON type was wrong,
+	// reported by Validate.
+	decodeErrs ValidationErrors
+
+	// signing_dict and chain_dict as ParseBundle read them.
+	signingRaw, chainRaw json.RawMessage
+
+	// ParseBundle found chain_hash_alg, which Validate refuses.
+	chainHashAlg bool
+}
+
+// MalformedError means the input cannot be verified at all — there are
+// no check results to derive a verdict from, so the verdict is MALFORMED.
+type MalformedError struct {
+	Reason string
+	Err    error
+}
+
+func (e *MalformedError) Error() string { return e.Reason + ": " + e.Err.Error() }
+func (e *MalformedError) Unwrap() error { return e.Err }
+
+// ParseBundle decodes a proof bundle from JSON. A field of the wrong
+// JSON type is left zero, and the error is a *MalformedError wrapping
+// the ValidationErrors of every such field; the bundle is still returned,
+// and verifying it reports them, with the rest of Validate, as checks.
+// data starting with the CBOR self-describe tag is read with
+// ParseBundleCBOR (cbor.go).
+func ParseBundle(data []byte) (ProofBundle, error) {
+	if IsCBOR(data) {
+		return ParseBundleCBOR(data)
+	}
+	var bundle ProofBundle
+	err := json.Unmarshal(data, &bundle)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		bundle = ProofBundle{}
+		bundle.decodeErrs = decodeFields(data, &bundle)
+		return bundle, &MalformedError{"invalid proof bundle", bundle.decodeErrs}
+	}
+	if err != nil {
+		return bundle, &MalformedError{"cannot parse proof bundle", err}
+	}
+	var segments struct {
+		SigningDict json.RawMessage `json:"signing_dict"`
+		ChainDict   json.RawMessage `json:"chain_dict"`
+
+		ChainHashAlg json.RawMessage `json:"chain_hash_alg"`
+	}
+	if json.Unmarshal(data, &segments) == nil {
+		bundle.signingRaw, bundle.chainRaw = segments.SigningDict, segments.ChainDict
+		bundle.chainHashAlg = segments.ChainHashAlg != nil
+	}
+	return bundle, nil
+}
+
+// RawSigningDict is the signing_dict JSON as ParseBundle read it, nil
+// for a bundle built in code. It does not follow edits of SigningDict:
+// Verify canonicalizes the map, so a tampered map is always seen.
+func (b ProofBundle) RawSigningDict() json.RawMessage { return b.signingRaw }
+
+// RawChainDict is the chain_dict JSON as ParseBundle read it; see
+// RawSigningDict.
+func (b ProofBundle) RawChainDict() json.RawMessage { return b.chainRaw }
+
+// decodeFields decodes the object data into b field by field, and
+// returns the fields that have the wrong JSON type.
+func decodeFields(data []byte, b *ProofBundle) ValidationErrors {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return ValidationErrors{{"", err.Error()}}
+	}
+	var errs ValidationErrors
+	v := reflect.ValueOf(b).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		value, ok := raw[name]
+		if name == "" || !ok {
+			continue
+		}
+		if err := json.Unmarshal(value, v.Field(i).Addr().Interface()); err != nil {
+			problem := err.Error()
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				want := "a string"
+				if typeErr.Type.Kind() == reflect.Map {
+					want = "an object"
+				}
+				problem = fmt.Sprintf("is a JSON %s, want %s", typeErr.Value, want)
+			}
+			errs = append(errs, ValidationError{name, problem})
+		}
+	}
+	return errs
+}
+
+// Canonicalize takes a map, marshals to JSON, then applies RFC 8785 JCS.
+// gowebpki/jcs.Transform takes []byte, not interface{} — this is the adapter.
+func Canonicalize(v map[string]interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+	return CanonicalizeRaw(raw)
+}
+
+// CanonicalizeRaw applies RFC 8785 JCS to JSON that is already
+// serialized, such as RawSigningDict, skipping the json.Marshal of a map
+// that dominates Canonicalize on large records. The canonical bytes are
+// those of Canonicalize over the decoded object, except that a duplicate
+// key is an error here where decoding keeps the last value.
+func CanonicalizeRaw(raw []byte) ([]byte, error) {
+	canonical, err := jcs.Transform(raw)
+	if err != nil {
+		return nil, fmt.Errorf("jcs.Transform: %w", err)
+	}
+	return canonical, nil
+}
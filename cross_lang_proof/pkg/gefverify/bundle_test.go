@@ -0,0 +1,85 @@
+// cross_lang_proof/pkg/gefverify/bundle_test.go
+
+package gefverify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCanonicalizeRaw(t *testing.T) {
+	bundle := loadProofBundle(t)
+	for name, c := range map[string]struct {
+		raw  json.RawMessage
+		dict map[string]interface{}
+	}{
+		"signing_dict": {bundle.RawSigningDict(), bundle.SigningDict},
+		"chain_dict":   {bundle.RawChainDict(), bundle.ChainDict},
+	} {
+		if c.raw == nil {
+			t.Fatalf("%s: ParseBundle kept no raw segment", name)
+		}
+		got, err := CanonicalizeRaw(c.raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := Canonicalize(c.dict)
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s:\nraw  %s\nmap  %s", name, got, want)
+		}
+	}
+
+	// Numbers and escapes a map marshal rewrites come out the same.
+	quirks := `{"b": 1.50, "a": [1e2, -0.0, 12345678901234567890], "s": "<\u00e9>"}`
+	var dict map[string]interface{}
+	if err := json.Unmarshal([]byte(quirks), &dict); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := CanonicalizeRaw([]byte(quirks))
+	want, _ := Canonicalize(dict)
+	if !bytes.Equal(got, want) {
+		t.Errorf("quirks:\nraw  %s\nmap  %s", got, want)
+	}
+
+	if _, err := CanonicalizeRaw([]byte(`{"a": 1, "a": 2}`)); err == nil {
+		t.Error("duplicate key accepted")
+	}
+	if built := signedBundle(t, 1, "p"); built.RawSigningDict() != nil || built.RawChainDict() != nil {
+		t.Error("bundle built in code has raw segments")
+	}
+}
+
+// BenchmarkCanonicalize compares the two routes to the canonical bytes
+// of a signing_dict with a large payload:
+//
+//	go test -run '^$' -bench Canonicalize -benchmem
+func BenchmarkCanonicalize(b *testing.B) {
+	var items []string
+	for i := 0; i < 2000; i++ {
+		items = append(items, fmt.Sprintf(`{"id": %d, "path": "/var/log/%d", "ok": true}`, i, i))
+	}
+	raw := []byte(`{"agent_id": "agent-bench", "payload": {"items": [` + strings.Join(items, ",") + `]}}`)
+	var dict map[string]interface{}
+	if err := json.Unmarshal(raw, &dict); err != nil {
+		b.Fatal(err)
+	}
+	b.Run("map", func(b *testing.B) {
+		b.SetBytes(int64(len(raw)))
+		for i := 0; i < b.N; i++ {
+			if _, err := Canonicalize(dict); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("raw", func(b *testing.B) {
+		b.SetBytes(int64(len(raw)))
+		for i := 0; i < b.N; i++ {
+			if _, err := CanonicalizeRaw(raw); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,83 @@
+// cross_lang_proof/pkg/gefverify/carried.go
+//
+// Records carried by a signing transport
+// ======================================
+//
+// DSSE envelopes and PASETO tokens carry the signing_dict as their
+// message and sign it themselves: the signature covers an encoding of
+// the message (a pre-authentication encoding, with the payload type or
+// the footer), not the bytes alone. VerifyCarried builds the bundle from
+// the message and runs the same contracts, every signature check over
+// encode of the bytes it verifies:
+//
+//   signing_dict = chain_dict = record (decoded)
+//   canonical_bytes_hex       = record as carried
+//   public key   = pub        signature = sig, the transport's
+//
+// CONTRACT 1 compares the carried bytes with JCS(record), so a message
+// that is not canonical fails it; C3.signature_python verifies what the
+// transport signed, C3.signature_go and CONTRACT 6 encode(JCS(record)).
+// As with VerifyDetached, C2/C4/C7 hold by construction and there is no
+// envelope_json for C8. The chain hash, under the Verifier's ChainHash,
+// is left as a note for the caller to link the next record to.
+
+package gefverify
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// VerifyCarried verifies record, the signing_dict as JSON, that a
+// transport signed with pub as encode(record): sig is the transport's
+// signature. Errors are *MalformedError, as from Verify. record, pub
+// and sig are not modified.
+func (v *Verifier) VerifyCarried(pub ed25519.PublicKey, record json.RawMessage, sig []byte, encode func([]byte) []byte) (Report, error) {
+	r := newRun()
+	r.encode = encode
+	malformed := func(reason string, err error) (Report, error) {
+		v.metrics.IncVerdict(VerdictMalformed)
+		return Report{Verdict: VerdictMalformed}, &MalformedError{reason, err}
+	}
+	var dict map[string]interface{}
+	if err := json.Unmarshal(record, &dict); err != nil {
+		return malformed("cannot parse carried record", err)
+	}
+	if dict == nil {
+		return malformed("cannot parse carried record", errors.New("not a JSON object"))
+	}
+	canonical, err := Canonicalize(dict)
+	if err != nil {
+		return malformed("canonicalize carried record", err)
+	}
+	// The contract details print the first 8 bytes of what they compare.
+	if len(canonical) < 8 {
+		return malformed("carried record too short", fmt.Errorf("%s is not a signing_dict", canonical))
+	}
+
+	chainHash := v.hashHex(canonical)
+	r.notes = append(r.notes, fmt.Sprintf("chain hash of this record (%s): %s", v.chainAlg, chainHash))
+
+	version, _ := dict["gef_version"].(string)
+	chainDict := make(map[string]interface{}, len(dict))
+	for k, val := range dict {
+		chainDict[k] = val
+	}
+	bundle := ProofBundle{
+		Description:       "GEF proof bundle derived from a carried record",
+		GEFVersion:        version,
+		PublicKeyHex:      hex.EncodeToString(pub),
+		SigningDict:       dict,
+		CanonicalBytesHex: hex.EncodeToString(record),
+		ChainDict:         chainDict,
+		ChainBytesHex:     hex.EncodeToString(canonical),
+		CausalHashOfThis:  chainHash,
+		SignatureB64URL:   base64.RawURLEncoding.EncodeToString(sig),
+		SignatureHex:      hex.EncodeToString(sig),
+	}
+	return v.verifyTop(r, bundle)
+}
@@ -0,0 +1,68 @@
+// cross_lang_proof/pkg/gefverify/carried_test.go
+
+package gefverify
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// carriedEncode stands in for a transport's pre-authentication encoding.
+func carriedEncode(message []byte) []byte {
+	return append([]byte("transport v1\n"), message...)
+}
+
+func TestVerifyCarried(t *testing.T) {
+	pub, body, _ := detachedInput(t)
+	sig := ed25519.Sign(builderKey, carriedEncode(body))
+
+	report, err := NewVerifier(WithRejectWeakKeys(true)).VerifyCarried(pub, body, sig, carriedEncode)
+	if err != nil || report.Verdict != VerdictVerified {
+		t.Fatalf("err=%v verdict=%s failed=%v", err, report.Verdict, failedIDs(report))
+	}
+	for _, id := range []string{"C1.canonical_bytes", "C3.weak_key", "C3.signature_go", "C3.signature_python",
+		"C5.field_types", "C6.flip_byte", "C9.timestamp_format", "C11.signer_binding"} {
+		if c, ok := checkByID(report, id); !ok || !c.Passed {
+			t.Errorf("%s: %+v", id, c)
+		}
+	}
+
+	// The signature is over the encoding, not the bytes alone.
+	if report, _ := verifyCarried(t, pub, body, ed25519.Sign(builderKey, body)); !reflect.DeepEqual(failedIDs(report),
+		[]string{"C3.signature_go", "C3.signature_python", "C6.original_intact"}) {
+		t.Errorf("signature over the bytes: failed %v", failedIDs(report))
+	}
+
+	// Signed as sent, but not the canonical form.
+	var dict map[string]interface{}
+	json.Unmarshal(body, &dict)
+	pretty, _ := json.MarshalIndent(dict, "", "  ")
+	report, _ = verifyCarried(t, pub, pretty, ed25519.Sign(builderKey, carriedEncode(pretty)))
+	if got := failedIDs(report); !reflect.DeepEqual(got, []string{"C1.canonical_bytes", "C3.signature_go", "C6.original_intact"}) {
+		t.Errorf("pretty record: failed %v", got)
+	}
+
+	// The chain hash follows the Verifier's ChainHash.
+	report, _ = NewVerifier(WithChainHash(sha512.New)).VerifyCarried(pub, body, sig, carriedEncode)
+	if !report.OK() || !strings.HasPrefix(strings.Join(report.Notes, "\n"), "chain hash of this record (sha512): ") {
+		t.Errorf("sha512: failed %v, notes %q", failedIDs(report), report.Notes)
+	}
+
+	var malformed *MalformedError
+	for _, bad := range []string{`[]`, `null`, `{}`, `{"a":`} {
+		if report, err := verifyCarried(t, pub, []byte(bad), sig); !errors.As(err, &malformed) || report.Verdict != VerdictMalformed {
+			t.Errorf("%s: verdict %s, err %v", bad, report.Verdict, err)
+		}
+	}
+}
+
+// verifyCarried verifies a carried record with default options.
+func verifyCarried(t *testing.T, pub ed25519.PublicKey, record, sig []byte) (Report, error) {
+	t.Helper()
+	return NewVerifier().VerifyCarried(pub, record, sig, carriedEncode)
+}
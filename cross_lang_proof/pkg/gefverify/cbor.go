@@ -0,0 +1,347 @@
+// cross_lang_proof/pkg/gefverify/cbor.go
+//
+// CBOR proof bundles
+// ==================
+//
+// Embedded agents may write a proof bundle as CBOR (RFC 8949) instead of
+// JSON: the same fields, as a map of text keys, behind the self-describe
+// tag 55799 (d9 d9 f7), which is how ParseBundle tells the two apart.
+// The bundle is read through its JSON projection:
+//
+//   unsigned and negative integers   JSON numbers, exactly
+//   floats (16, 32, 64 bits)         JSON numbers; NaN and ±Inf rejected
+//   text strings, arrays, maps       strings, arrays, objects (text keys)
+//   byte strings                     base64url strings, unpadded
+//   false, true, null                the same
+//
+// Any other tag, undefined, indefinite lengths and non-text map keys are
+// rejected. The contracts then run on the projection exactly as on a
+// JSON bundle: JCS of signing_dict is still what is signed and hashed,
+// so Go, Python and CBOR canonical bytes compare byte for byte. The one
+// field a CBOR bundle writes as bytes is cose_sign1 (cose.go).
+//
+// BundleJSONToCBOR goes the other way, with the map keys sorted as RFC
+// 8949 §4.2.1 deterministic encoding sorts them, and integers and floats
+// in their shortest form; the convert subcommand uses both.
+
+package gefverify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// cborMagic is the self-describe tag 55799 that starts a CBOR bundle.
+const cborMagic = "\xd9\xd9\xf7"
+
+// cborMaxDepth bounds nesting, as the JSON decoder does.
+const cborMaxDepth = 1000
+
+// CBOR major types.
+const (
+	cborUint   = 0
+	cborNeg    = 1
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborTag    = 6
+	cborSimple = 7
+)
+
+// IsCBOR reports whether data starts with the self-describe tag.
+func IsCBOR(data []byte) bool { return bytes.HasPrefix(data, []byte(cborMagic)) }
+
+// ParseBundleCBOR decodes a CBOR proof bundle, with or without the
+// self-describe tag, through its JSON projection; errors are as
+// ParseBundle's.
+func ParseBundleCBOR(data []byte) (ProofBundle, error) {
+	projected, err := BundleCBORToJSON(data)
+	if err != nil {
+		return ProofBundle{}, &MalformedError{"cannot parse CBOR proof bundle", err}
+	}
+	return ParseBundle(projected)
+}
+
+// BundleCBORToJSON returns the compact JSON projection of a CBOR bundle.
+func BundleCBORToJSON(data []byte) ([]byte, error) {
+	d := cborDecoder{data: bytes.TrimPrefix(data, []byte(cborMagic))}
+	var out bytes.Buffer
+	if err := d.value(&out, 0); err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("%d bytes of trailing data after the bundle", len(d.data)-d.pos)
+	}
+	return out.Bytes(), nil
+}
+
+// cborDecoder reads one CBOR data item at a time from data.
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+// head reads the initial byte and argument of an item.
+func (d *cborDecoder) head() (major byte, arg uint64, info byte, err error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, 0, io.ErrUnexpectedEOF
+	}
+	b := d.data[d.pos]
+	d.pos++
+	major, info = b>>5, b&0x1f
+	switch {
+	case info < 24:
+		return major, uint64(info), info, nil
+	case info == 31:
+		return 0, 0, 0, fmt.Errorf("offset %d: indefinite length not allowed", d.pos-1)
+	case info > 27:
+		return 0, 0, 0, fmt.Errorf("offset %d: reserved additional information %d", d.pos-1, info)
+	}
+	n := 1 << (info - 24)
+	if len(d.data)-d.pos < n {
+		return 0, 0, 0, io.ErrUnexpectedEOF
+	}
+	for _, c := range d.data[d.pos : d.pos+n] {
+		arg = arg<<8 | uint64(c)
+	}
+	d.pos += n
+	return major, arg, info, nil
+}
+
+// take returns the next n bytes.
+func (d *cborDecoder) take(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// value reads one item and writes its JSON projection to out. With out
+// nil it skips the item, and map keys of any type are allowed, as in
+// COSE headers.
+func (d *cborDecoder) value(out *bytes.Buffer, depth int) error {
+	if depth > cborMaxDepth {
+		return fmt.Errorf("nested deeper than %d", cborMaxDepth)
+	}
+	start := d.pos
+	major, arg, info, err := d.head()
+	if err != nil {
+		return err
+	}
+	write := func(s string) {
+		if out != nil {
+			out.WriteString(s)
+		}
+	}
+	switch major {
+	case cborUint:
+		write(strconv.FormatUint(arg, 10))
+	case cborNeg:
+		n := new(big.Int).SetUint64(arg)
+		write(n.Neg(n.Add(n, big.NewInt(1))).String())
+	case cborBytes, cborText:
+		b, err := d.take(arg)
+		if err != nil {
+			return err
+		}
+		s := base64.RawURLEncoding.EncodeToString(b)
+		if major == cborText {
+			if !utf8.Valid(b) {
+				return fmt.Errorf("offset %d: text string is not UTF-8", start)
+			}
+			s = string(b)
+		}
+		if out != nil {
+			enc := json.NewEncoder(out)
+			enc.SetEscapeHTML(false)
+			enc.Encode(s)
+			out.Truncate(out.Len() - 1) // Encode's newline
+		}
+	case cborArray, cborMap:
+		if arg > uint64(len(d.data)-d.pos) {
+			return io.ErrUnexpectedEOF // every item is at least a byte
+		}
+		open, close := "[", "]"
+		if major == cborMap {
+			open, close = "{", "}"
+		}
+		write(open)
+		for i := uint64(0); i < arg; i++ {
+			if i > 0 {
+				write(",")
+			}
+			if major == cborMap {
+				if out != nil && d.pos < len(d.data) && d.data[d.pos]>>5 != cborText {
+					return fmt.Errorf("offset %d: map key is not a text string", d.pos)
+				}
+				if err := d.value(out, depth+1); err != nil {
+					return err
+				}
+				write(":")
+			}
+			if err := d.value(out, depth+1); err != nil {
+				return err
+			}
+		}
+		write(close)
+	case cborTag:
+		return fmt.Errorf("offset %d: tag %d not allowed", start, arg)
+	case cborSimple:
+		var f float64
+		switch info {
+		case 20, 21, 22:
+			write(map[byte]string{20: "false", 21: "true", 22: "null"}[info])
+			return nil
+		case 25:
+			f = halfToFloat(uint16(arg))
+		case 26:
+			f = float64(math.Float32frombits(uint32(arg)))
+		case 27:
+			f = math.Float64frombits(arg)
+		default:
+			return fmt.Errorf("offset %d: simple value %d not allowed", start, arg)
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("offset %d: %v has no JSON form", start, f)
+		}
+		write(strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return nil
+}
+
+// halfToFloat decodes an IEEE 754 half-precision float.
+func halfToFloat(h uint16) float64 {
+	exp, mant := int(h>>10)&0x1f, float64(h&0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		f = math.Inf(1)
+		if mant != 0 {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
+
+// BundleJSONToCBOR encodes a JSON bundle as CBOR, behind the
+// self-describe tag. cose_sign1 is written as the bytes it encodes.
+func BundleJSONToCBOR(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("cannot parse proof bundle: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("cannot parse proof bundle: trailing data after JSON object")
+	}
+	if s, ok := doc["cose_sign1"].(string); ok {
+		raw, err := decodeBase64URL(s)
+		if err != nil {
+			return nil, fmt.Errorf("cose_sign1: %w", err)
+		}
+		doc["cose_sign1"] = raw
+	}
+	out := []byte(cborMagic)
+	return appendCBOR(out, doc)
+}
+
+// cborHead appends the initial byte and shortest argument of an item.
+func cborHead(out []byte, major byte, n uint64) []byte {
+	m := major << 5
+	switch {
+	case n < 24:
+		return append(out, m|byte(n))
+	case n <= math.MaxUint8:
+		return append(out, m|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(out, m|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(out, m|26), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(out, m|27), n)
+}
+
+// appendCBOR appends v, a value decoded from JSON with UseNumber.
+func appendCBOR(out []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(out, 0xf6), nil
+	case bool:
+		if v {
+			return append(out, 0xf5), nil
+		}
+		return append(out, 0xf4), nil
+	case string:
+		return append(cborHead(out, cborText, uint64(len(v))), v...), nil
+	case []byte:
+		return append(cborHead(out, cborBytes, uint64(len(v))), v...), nil
+	case json.Number:
+		if n, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			if n < 0 {
+				return cborHead(out, cborNeg, uint64(-1-n)), nil
+			}
+			return cborHead(out, cborUint, uint64(n)), nil
+		}
+		if n, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return cborHead(out, cborUint, n), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("number %s: %w", v, err)
+		}
+		if float64(float32(f)) == f {
+			return binary.BigEndian.AppendUint32(append(out, 0xfa), math.Float32bits(float32(f))), nil
+		}
+		return binary.BigEndian.AppendUint64(append(out, 0xfb), math.Float64bits(f)), nil
+	case []interface{}:
+		out = cborHead(out, cborArray, uint64(len(v)))
+		for _, e := range v {
+			var err error
+			if out, err = appendCBOR(out, e); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case map[string]interface{}:
+		type entry struct {
+			encoded []byte
+			key     string
+		}
+		entries := make([]entry, 0, len(v))
+		for k := range v {
+			entries = append(entries, entry{append(cborHead(nil, cborText, uint64(len(k))), k...), k})
+		}
+		sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].encoded, entries[j].encoded) < 0 })
+		out = cborHead(out, cborMap, uint64(len(v)))
+		for _, e := range entries {
+			out = append(out, e.encoded...)
+			var err error
+			if out, err = appendCBOR(out, v[e.key]); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("cannot encode %T as CBOR", v)
+}
@@ -0,0 +1,151 @@
+// cross_lang_proof/pkg/gefverify/cbor_test.go
+
+package gefverify
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+)
+
+// projectHex is the JSON projection of the CBOR item in hex.
+func projectHex(t *testing.T, itemHex string) (string, error) {
+	t.Helper()
+	item, err := hex.DecodeString(itemHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := BundleCBORToJSON(item)
+	return string(out), err
+}
+
+// TestCBORDecodeVectors decodes the examples of RFC 8949 Appendix A that
+// have a JSON form.
+func TestCBORDecodeVectors(t *testing.T) {
+	for _, v := range []struct{ hex, json string }{
+		{"00", "0"},
+		{"17", "23"},
+		{"1818", "24"},
+		{"1903e8", "1000"},
+		{"1a000f4240", "1000000"},
+		{"1b000000e8d4a51000", "1000000000000"},
+		{"1bffffffffffffffff", "18446744073709551615"},
+		{"3bffffffffffffffff", "-18446744073709551616"},
+		{"20", "-1"},
+		{"3903e7", "-1000"},
+		{"f90000", "0"},
+		{"f98000", "-0"},
+		{"f93c00", "1"},
+		{"fb3ff199999999999a", "1.1"},
+		{"f93e00", "1.5"},
+		{"f97bff", "65504"},
+		{"fa47c35000", "100000"},
+		{"fa7f7fffff", "3.4028234663852886e+38"},
+		{"fb7e37e43c8800759c", "1e+300"},
+		{"f90001", "5.960464477539063e-08"},
+		{"f90400", "6.103515625e-05"},
+		{"f9c400", "-4"},
+		{"fbc010666666666666", "-4.1"},
+		{"f4", "false"},
+		{"f5", "true"},
+		{"f6", "null"},
+		{"40", `""`},
+		{"4401020304", `"AQIDBA"`},
+		{"60", `""`},
+		{"6449455446", `"IETF"`},
+		{"62225c", `"\"\\"`},
+		{"62c3bc", `"ü"`},
+		{"63e6b0b4", `"水"`},
+		{"8301820203820405", "[1,[2,3],[4,5]]"},
+		{"a26161016162820203", `{"a":1,"b":[2,3]}`},
+		{"826161a161626163", `["a",{"b":"c"}]`},
+	} {
+		got, err := projectHex(t, v.hex)
+		if err != nil || got != v.json {
+			t.Errorf("%s: got %s, %v; want %s", v.hex, got, err, v.json)
+		}
+	}
+}
+
+func TestCBORDecodeRejects(t *testing.T) {
+	for _, v := range []struct{ hex, want string }{
+		{"f97c00", "no JSON form"}, // Infinity
+		{"f97e00", "no JSON form"}, // NaN
+		{"f7", "simple value"},     // undefined
+		{"c074323031332d30332d32315432303a30343a30305a", "tag 0"},
+		{"9fff", "indefinite length"},
+		{"a10102", "map key is not a text string"}, // {1: 2}
+		{"62ff", "unexpected EOF"},
+		{"62c328", "not UTF-8"},
+		{"9b00000000ffffffff", "unexpected EOF"}, // a huge array
+		{"0000", "trailing data"},
+		{"1c", "reserved"},
+	} {
+		if _, err := projectHex(t, v.hex); err == nil || !strings.Contains(err.Error(), v.want) {
+			t.Errorf("%s: error %v, want %q", v.hex, err, v.want)
+		}
+	}
+}
+
+func TestCBOREncode(t *testing.T) {
+	for _, v := range []struct{ json, hex string }{
+		{`{"a": 1, "b": [2, 3]}`, "a26161016162820203"},
+		// Shorter keys sort first, as RFC 8949 §4.2.1 encodes them.
+		{`{"bb": -1, "a": "IETF", "c": null}`, "a3616164494554466163f662626220"},
+		// Floats take 32 bits when that is exact.
+		{`{"n": 1000000, "f": 1.5, "g": 1.1, "t": true}`, "a46166fa3fc000006167fb3ff199999999999a616e1a000f42406174f5"},
+	} {
+		out, err := BundleJSONToCBOR([]byte(v.json))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(bytes.TrimPrefix(out, []byte(cborMagic))); got != v.hex {
+			t.Errorf("%s: got %s, want %s", v.json, got, v.hex)
+		}
+	}
+}
+
+// TestCBORBundle verifies the reference bundle in CBOR form: the same
+// checks pass over the same canonical bytes.
+func TestCBORBundle(t *testing.T) {
+	data, err := os.ReadFile("../../proof_bundle.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := BundleJSONToCBOR(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsCBOR(encoded) || len(encoded) >= len(data) {
+		t.Errorf("CBOR bundle is %d bytes, JSON %d", len(encoded), len(data))
+	}
+
+	for name, input := range map[string][]byte{
+		"tagged":   encoded,
+		"untagged": encoded[len(cborMagic):],
+	} {
+		parse := ParseBundle
+		if name == "untagged" {
+			parse = ParseBundleCBOR
+		}
+		bundle, err := parse(input)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		report, err := Verify(bundle, VerifyOptions{})
+		if err != nil || !report.OK() {
+			t.Errorf("%s: verdict %s, err %v, failed %v", name, report.Verdict, err, failedIDs(report))
+		}
+		want := loadProofBundle(t)
+		got, _ := Canonicalize(bundle.SigningDict)
+		if hex.EncodeToString(got) != want.CanonicalBytesHex {
+			t.Errorf("%s: canonical bytes differ from the JSON bundle's", name)
+		}
+	}
+
+	if _, err := ParseBundle(append([]byte(cborMagic), 0x9f)); err == nil {
+		t.Error("indefinite-length CBOR bundle parsed")
+	}
+}
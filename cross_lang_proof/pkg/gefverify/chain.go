@@ -0,0 +1,127 @@
+// cross_lang_proof/pkg/gefverify/chain.go
+//
+// Chain verification (VerifyChain)
+// ================================
+//
+// CausalHashOfThis proves one record's chain hash; tamper-evidence across
+// a log needs the links between records as well. VerifyChain takes one
+// agent's records in sequence order, runs every contract on each, then
+// checks that record i's chain_dict.causal_hash is the chain hash
+// recomputed from record i-1's chain_dict. The first record must be the
+// genesis record (sequence 0, linked to GenesisHash).
+//
+// "sequence contiguous" (K.sequence_contiguous) is one check over the
+// whole chain: sequence must run 0, 1, 2, ... with no gap, duplicate or
+// step back, and it reports the first offending pair, e.g. "seq jumped
+// 4 -> 6 at record 5". Two records sharing a sequence are a fork. A
+// record deleted from the middle of a log fails both this check and the
+// causal link after it.
+//
+// Per-record checks keep their IDs and sections, with "record i: " in
+// front of Details; linkage checks name the record index where the chain
+// breaks. The verdict is DeriveVerdict over all of them. Ordering,
+// sharding and per-type rules for whole directories stay with the CLI's
+// chain subcommand.
+
+package gefverify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SectionChainLinkage is the heading for the links between records.
+const SectionChainLinkage = "CHAIN — Causal Linkage Across Records"
+
+// ErrEmptyChain is returned by VerifyChain for a chain without records.
+var ErrEmptyChain = errors.New("gefverify: chain has no records")
+
+// VerifyChain verifies records as one chain with opts. It is shorthand
+// for NewVerifierFromOptions(opts).VerifyChain(records).
+func VerifyChain(records []ProofBundle, opts VerifyOptions) ([]CheckResult, error) {
+	return NewVerifierFromOptions(opts).VerifyChain(records)
+}
+
+// VerifyChain verifies every record and the causal links between them,
+// records being one agent's in sequence order. A non-nil error means a
+// record could not be verified (it wraps that record's *MalformedError)
+// or records is empty; the checks then stop at that record.
+func (v *Verifier) VerifyChain(records []ProofBundle) ([]CheckResult, error) {
+	if len(records) == 0 {
+		return nil, ErrEmptyChain
+	}
+	var checks []CheckResult
+	for i, bundle := range records {
+		report, err := v.Verify(bundle)
+		for _, c := range report.Checks {
+			c.Details = fmt.Sprintf("record %d: %s", i, c.Details)
+			checks = append(checks, c)
+		}
+		if err != nil {
+			return checks, fmt.Errorf("gefverify: record %d: %w", i, err)
+		}
+	}
+
+	r := &run{section: SectionChainLinkage}
+	causal, _ := records[0].ChainDict["causal_hash"].(string)
+	r.check("K.genesis_link", CategoryIntegrity, "record 0 links to genesis", causal == GenesisHash,
+		fmt.Sprintf("causal_hash=%s expected=%s", causal, GenesisHash))
+	for i := 1; i < len(records); i++ {
+		prev, cur := records[i-1], records[i]
+		canonical, err := Canonicalize(prev.ChainDict)
+		if err != nil {
+			return append(checks, r.checks...), fmt.Errorf("gefverify: record %d: %w", i-1, &MalformedError{"canonicalize chain_dict", err})
+		}
+		causal, _ = cur.ChainDict["causal_hash"].(string)
+		expected, ok := v.ChainHashHex(prev.HashAlg, canonical)
+		if !ok {
+			expected = "unsupported hash_alg " + prev.HashAlg
+		}
+		r.check("K.causal_link", CategoryIntegrity, fmt.Sprintf("record %d links to record %d", i, i-1), causal == expected,
+			fmt.Sprintf("causal_hash=%s expected=%s", causal, expected))
+	}
+	ok, details := sequenceContiguous(records)
+	r.check("K.sequence_contiguous", CategoryCompleteness, "sequence contiguous", ok, details)
+	return append(checks, r.checks...), nil
+}
+
+// sequenceContiguous checks that records run 0, 1, 2, ... and describes
+// the first offending record or pair.
+func sequenceContiguous(records []ProofBundle) (bool, string) {
+	var prev int64
+	for i, b := range records {
+		seq, ok := chainSequence(b)
+		switch {
+		case !ok:
+			return false, fmt.Sprintf("record %d: sequence %v is not an integer", i, b.ChainDict["sequence"])
+		case i == 0 && seq != 0:
+			return false, fmt.Sprintf("missing genesis: record 0 has seq %d", seq)
+		case i == 0:
+		case seq == prev:
+			return false, fmt.Sprintf("fork: records %d and %d share seq %d", i-1, i, seq)
+		case seq < prev:
+			return false, fmt.Sprintf("out of order: seq went back %d -> %d at record %d", prev, seq, i)
+		case seq != prev+1:
+			return false, fmt.Sprintf("seq jumped %d -> %d at record %d", prev, seq, i)
+		}
+		prev = seq
+	}
+	return true, fmt.Sprintf("seq 0 -> %d, %d record(s)", prev, len(records))
+}
+
+// chainSequence is the integral sequence of b's chain_dict.
+func chainSequence(b ProofBundle) (int64, bool) {
+	switch n := b.ChainDict["sequence"].(type) {
+	case float64:
+		return int64(n), n == float64(int64(n))
+	case json.Number:
+		seq, err := n.Int64()
+		return seq, err == nil
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,132 @@
+// cross_lang_proof/pkg/gefverify/chain_test.go
+
+package gefverify
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// buildChain builds n linked records of agent, starting at genesis.
+func buildChain(t *testing.T, agent string, n int) []ProofBundle {
+	t.Helper()
+	at := time.Date(2026, 2, 24, 12, 0, 0, 0, time.UTC)
+	var records []ProofBundle
+	for i := 0; i < n; i++ {
+		b := NewRecord(agent, "execution").At(at.Add(time.Duration(i) * time.Second))
+		if i == 0 {
+			b.Genesis()
+		} else {
+			b.Sequence(int64(i)).PreviousHash(records[i-1].CausalHashOfThis)
+		}
+		_, bundle, err := b.Finalize(builderKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, bundle)
+	}
+	return records
+}
+
+// chainCheck returns the check with id and a name starting with name.
+func chainCheck(t *testing.T, checks []CheckResult, id, name string) CheckResult {
+	t.Helper()
+	for _, c := range checks {
+		if c.ID == id && strings.HasPrefix(c.Name, name) {
+			return c
+		}
+	}
+	t.Fatalf("no %s check %q", id, name)
+	return CheckResult{}
+}
+
+func TestVerifyChain(t *testing.T) {
+	records := buildChain(t, "agent-chain", 3)
+	checks, err := VerifyChain(records, VerifyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := DeriveVerdict(checks); v != VerdictVerified {
+		t.Fatalf("verdict %s, failed %v", v, failedIDs(Report{Checks: checks}))
+	}
+	chainCheck(t, checks, "K.genesis_link", "record 0")
+	chainCheck(t, checks, "K.causal_link", "record 2 links to record 1")
+	if c := chainCheck(t, checks, "C3.signature_go", ""); !strings.HasPrefix(c.Details, "record 0: ") {
+		t.Errorf("per-record details not prefixed: %q", c.Details)
+	}
+}
+
+func TestVerifyChainBrokenLink(t *testing.T) {
+	records := buildChain(t, "agent-chain", 3)
+	other := buildChain(t, "agent-chain", 3)
+	records[2] = other[2] // valid on its own, links to the other chain's record 1
+
+	checks, err := VerifyChain(records, VerifyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := DeriveVerdict(checks); v != VerdictTampered {
+		t.Errorf("verdict %s, want %s", v, VerdictTampered)
+	}
+	if got := failedIDs(Report{Checks: checks}); len(got) != 1 || got[0] != "K.causal_link" {
+		t.Fatalf("failed %v, want only K.causal_link", got)
+	}
+	c := chainCheck(t, checks, "K.causal_link", "record 2 links to record 1")
+	want := "causal_hash=" + other[1].CausalHashOfThis + " expected=" + records[1].CausalHashOfThis
+	if c.Passed || c.Details != want {
+		t.Errorf("got %+v, want details %q", c, want)
+	}
+}
+
+func TestVerifyChainSequenceContiguous(t *testing.T) {
+	records := buildChain(t, "agent-chain", 7)
+	r := records
+	for _, tt := range []struct {
+		name  string
+		chain []ProofBundle
+		want  string
+	}{
+		{"gap", []ProofBundle{r[0], r[1], r[2], r[3], r[4], r[6]}, "seq jumped 4 -> 6 at record 5"},
+		{"fork", []ProofBundle{r[0], r[1], r[1], r[2]}, "fork: records 1 and 2 share seq 1"},
+		{"order", []ProofBundle{r[0], r[2], r[1]}, "seq jumped 0 -> 2 at record 1"},
+		{"back", []ProofBundle{r[0], r[1], r[2], r[1]}, "out of order: seq went back 2 -> 1 at record 3"},
+		{"no genesis", r[3:], "missing genesis: record 0 has seq 3"},
+	} {
+		checks, err := VerifyChain(tt.chain, VerifyOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := chainCheck(t, checks, "K.sequence_contiguous", "sequence contiguous")
+		if c.Passed || c.Details != tt.want {
+			t.Errorf("%s: %+v, want details %q", tt.name, c, tt.want)
+		}
+		if DeriveVerdict(checks) == VerdictVerified {
+			t.Errorf("%s: verdict VERIFIED", tt.name)
+		}
+	}
+
+	checks, _ := VerifyChain(records, VerifyOptions{})
+	if c := chainCheck(t, checks, "K.sequence_contiguous", ""); !c.Passed || c.Details != "seq 0 -> 6, 7 record(s)" {
+		t.Errorf("contiguous chain: %+v", c)
+	}
+}
+
+func TestVerifyChainErrors(t *testing.T) {
+	if _, err := VerifyChain(nil, VerifyOptions{}); !errors.Is(err, ErrEmptyChain) {
+		t.Errorf("empty chain: %v", err)
+	}
+	records := buildChain(t, "agent-chain", 3)
+	records[1].PublicKeyHex = "zz"
+	checks, err := VerifyChain(records, VerifyOptions{})
+	var malformed *MalformedError
+	if !errors.As(err, &malformed) || !strings.Contains(err.Error(), "record 1") {
+		t.Fatalf("err = %v, want a *MalformedError for record 1", err)
+	}
+	for _, c := range checks {
+		if strings.HasPrefix(c.Details, "record 2: ") || c.Section == SectionChainLinkage {
+			t.Errorf("checks ran past the malformed record: %+v", c)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// cross_lang_proof/pkg/gefverify/cose.go
+//
+// COSE_Sign1 signatures (CONTRACT 3)
+// ==================================
+//
+// A bundle, CBOR or JSON, may also carry its signature as a COSE_Sign1
+// structure (RFC 9052 §4.2) in cose_sign1: the CBOR bytes, base64url in
+// JSON. The payload is JCS(signing_dict), detached (nil) or embedded:
+//
+//   18([ h'a10127', {}, nil, signature ])     protected {1: -8}, EdDSA
+//
+// and the signature is over the Sig_structure
+//
+//   ["Signature1", protected, h'', JCS(signing_dict)]
+//
+// with the bundle key, alg EdDSA (-8) for ed25519 and ES256 (-7, r || s)
+// for ecdsa-p256 bundles. When cose_sign1 is present CONTRACT 3 verifies
+// it as C3.cose_sign1, next to signature_b64url: the two signatures
+// cannot be the same bytes, as the Sig_structure is not the canonical
+// bytes, so both are checked with the same key over the same record.
+
+package gefverify
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"math"
+)
+
+// COSE algorithm identifiers (RFC 9053).
+const (
+	coseAlgEdDSA = -8
+	coseAlgES256 = -7
+)
+
+// coseTagSign1 is the CBOR tag of a COSE_Sign1 message.
+const coseTagSign1 = 18
+
+// coseAlgs maps sig_alg to its COSE algorithm.
+var coseAlgs = map[string]int64{
+	"":              coseAlgEdDSA,
+	SigAlgEd25519:   coseAlgEdDSA,
+	SigAlgECDSAP256: coseAlgES256,
+}
+
+// coseSign1 is a decoded COSE_Sign1 message.
+type coseSign1 struct {
+	Protected []byte // the serialized protected header, as signed
+	Alg       int64
+	Payload   []byte // nil when detached
+	Detached  bool
+	Signature []byte
+}
+
+// sigStructure is the Sig_structure m signs over payload.
+func (m coseSign1) sigStructure(payload []byte) []byte {
+	out := cborHead(nil, cborArray, 4)
+	out = append(cborHead(out, cborText, 10), "Signature1"...)
+	out = append(cborHead(out, cborBytes, uint64(len(m.Protected))), m.Protected...)
+	out = cborHead(out, cborBytes, 0)
+	return append(cborHead(out, cborBytes, uint64(len(payload))), payload...)
+}
+
+// parseCOSESign1 decodes a COSE_Sign1 message, tagged or not.
+func parseCOSESign1(data []byte) (coseSign1, error) {
+	var m coseSign1
+	d := cborDecoder{data: data}
+	major, arg, _, err := d.head()
+	if err == nil && major == cborTag {
+		if arg != coseTagSign1 {
+			return m, fmt.Errorf("tag %d, not COSE_Sign1 (%d)", arg, coseTagSign1)
+		}
+		major, arg, _, err = d.head()
+	}
+	if err != nil {
+		return m, err
+	}
+	if major != cborArray || arg != 4 {
+		return m, fmt.Errorf("not a 4-element COSE_Sign1 array")
+	}
+	bstr := func(what string, nilOK bool) ([]byte, bool, error) {
+		if nilOK && d.pos < len(d.data) && d.data[d.pos] == 0xf6 {
+			d.pos++
+			return nil, true, nil
+		}
+		major, n, _, err := d.head()
+		if err == nil && major != cborBytes {
+			err = fmt.Errorf("%s is not a byte string", what)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		b, err := d.take(n)
+		return b, false, err
+	}
+	if m.Protected, _, err = bstr("protected header", false); err != nil {
+		return m, err
+	}
+	if m.Alg, err = coseHeaderAlg(m.Protected); err != nil {
+		return m, err
+	}
+	if d.pos >= len(d.data) || d.data[d.pos]>>5 != cborMap {
+		return m, fmt.Errorf("unprotected header is not a map")
+	}
+	if err := d.value(nil, 0); err != nil {
+		return m, fmt.Errorf("unprotected header: %w", err)
+	}
+	if m.Payload, m.Detached, err = bstr("payload", true); err != nil {
+		return m, err
+	}
+	if m.Signature, _, err = bstr("signature", false); err != nil {
+		return m, err
+	}
+	if d.pos != len(d.data) {
+		return m, fmt.Errorf("%d bytes of trailing data", len(d.data)-d.pos)
+	}
+	return m, nil
+}
+
+// coseHeaderAlg reads alg (label 1) from a serialized protected header.
+func coseHeaderAlg(protected []byte) (int64, error) {
+	d := cborDecoder{data: protected}
+	major, n, _, err := d.head()
+	if err != nil || major != cborMap {
+		return 0, fmt.Errorf("protected header is not a map")
+	}
+	for i := uint64(0); i < n; i++ {
+		start := d.pos
+		major, label, _, err := d.head()
+		if err != nil {
+			return 0, err
+		}
+		if major == cborUint && label == 1 {
+			major, arg, _, err := d.head()
+			switch {
+			case err != nil:
+				return 0, err
+			case arg > math.MaxInt64:
+				return 0, fmt.Errorf("alg %d out of range", arg)
+			case major == cborNeg:
+				return -1 - int64(arg), nil
+			case major == cborUint:
+				return int64(arg), nil
+			}
+			return 0, fmt.Errorf("alg is not an integer")
+		}
+		d.pos = start
+		if err := d.value(nil, 0); err != nil { // the label
+			return 0, err
+		}
+		if err := d.value(nil, 0); err != nil { // its value
+			return 0, err
+		}
+	}
+	return 0, fmt.Errorf("protected header has no alg")
+}
+
+// verifyCOSESign1 checks the base64url COSE_Sign1 coseB64 of a bundle
+// with key over canonical, and describes the result.
+func verifyCOSESign1(key PublicKey, sigAlg, coseB64 string, canonical []byte) (bool, string) {
+	data, err := decodeBase64URL(coseB64)
+	if err != nil {
+		return false, "cose_sign1 is not base64url: " + err.Error()
+	}
+	m, err := parseCOSESign1(data)
+	if err != nil {
+		return false, "cose_sign1: " + err.Error()
+	}
+	want := coseAlgs[sigAlg]
+	if m.Alg != want {
+		return false, fmt.Sprintf("alg %d, the bundle's %s is %d", m.Alg, key.Alg, want)
+	}
+	if !m.Detached && !bytes.Equal(m.Payload, canonical) {
+		return false, fmt.Sprintf("embedded payload (%d bytes) is not the canonical bytes (%d)", len(m.Payload), len(canonical))
+	}
+	valid := (m.Alg != coseAlgES256 || len(m.Signature) == 64) && key.Verify(m.sigStructure(canonical), m.Signature)
+	payload := "detached"
+	if !m.Detached {
+		payload = "embedded"
+	}
+	return valid, fmt.Sprintf("alg=%d  payload %s  sig=%d bytes", m.Alg, payload, len(m.Signature))
+}
+
+// SignCOSE1 signs canonical, the JCS bytes of a signing_dict, with an
+// Ed25519 key as a tagged COSE_Sign1 with detached payload, and returns
+// it base64url-encoded for ProofBundle.CoseSign1.
+func SignCOSE1(key ed25519.PrivateKey, canonical []byte) string {
+	m := coseSign1{Protected: []byte{0xa1, 0x01, 0x27}} // {1: -8}
+	sig := ed25519.Sign(key, m.sigStructure(canonical))
+	out := cborHead(nil, cborTag, coseTagSign1)
+	out = cborHead(out, cborArray, 4)
+	out = append(cborHead(out, cborBytes, uint64(len(m.Protected))), m.Protected...)
+	out = cborHead(out, cborMap, 0)
+	out = append(out, 0xf6)
+	out = append(cborHead(out, cborBytes, uint64(len(sig))), sig...)
+	return base64.RawURLEncoding.EncodeToString(out)
+}
@@ -0,0 +1,92 @@
+// cross_lang_proof/pkg/gefverify/cose_test.go
+
+package gefverify
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestSigStructure(t *testing.T) {
+	m := coseSign1{Protected: []byte{0xa1, 0x01, 0x27}}
+	// ["Signature1", h'a10127', h'', h'7b7d']
+	if got, want := hex.EncodeToString(m.sigStructure([]byte("{}"))), "846a5369676e61747572653143a1012740427b7d"; got != want {
+		t.Errorf("Sig_structure %s, want %s", got, want)
+	}
+}
+
+// TestCOSESign1Vector checks SignCOSE1 and C3.cose_sign1 against a
+// COSE_Sign1 built and signed outside Go.
+func TestCOSESign1Vector(t *testing.T) {
+	data, err := os.ReadFile("testdata/cose_sign1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vector struct {
+		Seed      string `json:"seed"`
+		CoseSign1 string `json:"cose_sign1"`
+	}
+	if err := json.Unmarshal(data, &vector); err != nil {
+		t.Fatal(err)
+	}
+	seed, _ := hex.DecodeString(vector.Seed)
+	key := ed25519.NewKeyFromSeed(seed)
+
+	bundle := loadProofBundle(t)
+	canonical, _ := hex.DecodeString(bundle.CanonicalBytesHex)
+	if got := SignCOSE1(key, canonical); got != vector.CoseSign1 {
+		t.Errorf("SignCOSE1 %s\nwant       %s", got, vector.CoseSign1)
+	}
+
+	bundle.CoseSign1 = vector.CoseSign1
+	report, err := Verify(bundle, VerifyOptions{})
+	if c, ok := checkByID(report, "C3.cose_sign1"); err != nil || !report.OK() || !ok || !c.Passed {
+		t.Errorf("verdict %s, err %v, failed %v", report.Verdict, err, failedIDs(report))
+	}
+}
+
+func TestCOSESign1Failures(t *testing.T) {
+	bundle := loadProofBundle(t)
+	seed, _ := hex.DecodeString("deadbeefdeadbeefdeadbeefdeadbeefcafebabecafebabecafebabecafebabe")
+	key := ed25519.NewKeyFromSeed(seed)
+	canonical, _ := hex.DecodeString(bundle.CanonicalBytesHex)
+	valid, _ := base64.RawURLEncoding.DecodeString(SignCOSE1(key, canonical))
+	sig := valid[len(valid)-64:]
+	// embed replaces the nil payload with payload.
+	embed := func(payload []byte) []byte {
+		out := append([]byte(nil), valid[:7]...) // tag, array, protected, {}
+		out = append(cborHead(out, cborBytes, uint64(len(payload))), payload...)
+		return append(out, valid[8:]...)
+	}
+	flipped := append([]byte(nil), valid...)
+	flipped[len(flipped)-1] ^= 1
+	es256 := append([]byte{0xd2, 0x84, 0x43, 0xa1, 0x01, 0x26, 0xa0, 0xf6, 0x58, 0x40}, sig...)
+
+	for _, tc := range []struct {
+		name   string
+		cose   []byte
+		passed bool
+	}{
+		{"embedded payload", embed(canonical), true},
+		{"flipped signature", flipped, false},
+		{"other embedded payload", embed([]byte("{}")), false},
+		{"ES256 on an Ed25519 bundle", es256, false},
+		{"untagged", valid[1:], true},
+		{"not COSE", []byte{0x01}, false},
+	} {
+		b := bundle
+		b.CoseSign1 = base64.RawURLEncoding.EncodeToString(tc.cose)
+		report, err := Verify(b, VerifyOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		c, ok := checkByID(report, "C3.cose_sign1")
+		if !ok || c.Passed != tc.passed || report.OK() != tc.passed {
+			t.Errorf("%s: C3.cose_sign1 passed=%v (%s), verdict %s", tc.name, c.Passed, c.Details, report.Verdict)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// cross_lang_proof/pkg/gefverify/detached.go
+//
+// Detached verification
+// =====================
+//
+// Edge proxies get no bundle: the signature and the signer key arrive in
+// HTTP headers and the body is the signing_dict itself. VerifyDetached
+// builds the bundle from those three and runs the same contracts, so a
+// proxy canonicalizes and checks exactly as full verification does:
+//
+//   signing_dict = chain_dict = body (decoded)
+//   public key   = pub          signature = sig
+//   signed bytes = JCS(body), or with CanonicalBody the body as received
+//
+// Two checks run before the contracts, under SectionDetached:
+//
+//   D.signer_binding  body signer_public_key is pub       (integrity)
+//   D.body_canonical  body == JCS(body), CanonicalBody only (structure)
+//
+// The body's content type is never consulted; any bytes that decode as a
+// JSON object are accepted, pretty-printed or already canonical. As with
+// BundleFromEnvelope, C1/C2/C4/C7 hold by construction; the body is no
+// envelope, so the bundle has no envelope_json for C8.
+
+package gefverify
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SectionDetached is the heading for the detached-input checks.
+const SectionDetached = "DETACHED — Body and Header Key Binding"
+
+// VerifyDetached verifies body against pub and sig with default options.
+// It is shorthand for NewVerifier().VerifyDetached(pub, body, sig).
+func VerifyDetached(pub ed25519.PublicKey, body json.RawMessage, sig []byte) (Report, error) {
+	return NewVerifier().VerifyDetached(pub, body, sig)
+}
+
+// VerifyDetached verifies body, the signing_dict as JSON, against the
+// public key and raw signature that travelled beside it. Errors are
+// *MalformedError, as from Verify. body, pub and sig are not modified.
+func (v *Verifier) VerifyDetached(pub ed25519.PublicKey, body json.RawMessage, sig []byte) (Report, error) {
+	r := newRun()
+	var dict map[string]interface{}
+	if err := json.Unmarshal(body, &dict); err != nil {
+		return r.report(), &MalformedError{"cannot parse detached body", err}
+	}
+	if dict == nil {
+		return r.report(), &MalformedError{"cannot parse detached body", errors.New("not a JSON object")}
+	}
+	canonical, err := Canonicalize(dict)
+	if err != nil {
+		return r.report(), &MalformedError{"canonicalize detached body", err}
+	}
+	// The contract details print the first 8 bytes of what they compare.
+	if len(canonical) < 8 {
+		return r.report(), &MalformedError{"detached body too short", fmt.Errorf("%s is not a signing_dict", canonical)}
+	}
+
+	r.section = SectionDetached
+	pubHex := hex.EncodeToString(pub)
+	signer, _ := dict["signer_public_key"].(string)
+	r.check(
+		"D.signer_binding",
+		CategoryIntegrity,
+		"body signer_public_key is the header key",
+		strings.EqualFold(signer, pubHex),
+		fmt.Sprintf("body=%q", signer),
+	)
+
+	signed := canonical
+	if v.opts.CanonicalBody {
+		isCanonical := bytes.Equal(body, canonical)
+		details := fmt.Sprintf("%d bytes, byte-identical to JCS", len(body))
+		if !isCanonical {
+			details = fmt.Sprintf("%d bytes received, JCS form is %d bytes", len(body), len(canonical))
+		}
+		r.check(
+			"D.body_canonical",
+			CategoryStructure,
+			"body is already JCS canonical",
+			isCanonical,
+			details,
+		)
+		signed = body
+	}
+
+	version, _ := dict["gef_version"].(string)
+	chainDict  := make(map[string]interface{}, len(dict))
+	for k, val := range dict {
+		chainDict[k] = val
+	}
+	bundle := ProofBundle{
+		Description:       "GEF proof bundle derived from a detached signature",
+		GEFVersion:        version,
+		PublicKeyHex:      pubHex,
+		SigningDict:       dict,
+		CanonicalBytesHex: hex.EncodeToString(signed),
+		ChainDict:         chainDict,
+		ChainBytesHex:     hex.EncodeToString(canonical),
+		CausalHashOfThis:  v.hashHex(canonical),
+		SignatureB64URL:   base64.RawURLEncoding.EncodeToString(sig),
+		SignatureHex:      hex.EncodeToString(sig),
+	}
+	return v.verifyTop(r, bundle)
+}
@@ -0,0 +1,100 @@
+// cross_lang_proof/pkg/gefverify/detached_test.go
+
+package gefverify
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// detachedInput returns the three values an edge proxy receives for one
+// builder record: header key, canonical body, header signature.
+func detachedInput(t *testing.T) (ed25519.PublicKey, []byte, []byte) {
+	t.Helper()
+	_, bundle, err := NewRecord("agent-7", "execution").
+		Payload(map[string]interface{}{"tool": "deploy"}).
+		Sequence(3).
+		PreviousHash(strings.Repeat("ab", 32)).
+		Finalize(builderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := hex.DecodeString(bundle.CanonicalBytesHex)
+	sig, _ := hex.DecodeString(bundle.SignatureHex)
+	return builderKey.Public().(ed25519.PublicKey), body, sig
+}
+
+func failedIDs(r Report) []string {
+	var ids []string
+	for _, c := range r.Failed() {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestVerifyDetachedCanonicalAndPrettyBodies(t *testing.T) {
+	pub, body, sig := detachedInput(t)
+	var dict map[string]interface{}
+	json.Unmarshal(body, &dict)
+	pretty, _ := json.MarshalIndent(dict, "", "  ")
+
+	for name, b := range map[string][]byte{"canonical": body, "pretty": pretty} {
+		report, err := VerifyDetached(pub, b, sig)
+		if err != nil || !report.OK() || report.Verdict != VerdictVerified {
+			t.Errorf("%s: err=%v verdict=%s failed=%v", name, err, report.Verdict, failedIDs(report))
+		}
+		if report.Checks[0].ID != "D.signer_binding" || report.Checks[0].Section != SectionDetached {
+			t.Errorf("%s: first check = %+v", name, report.Checks[0])
+		}
+	}
+
+	assert := NewVerifier(WithCanonicalBody(true))
+	if report, err := assert.VerifyDetached(pub, body, sig); err != nil || !report.OK() {
+		t.Errorf("canonical body with CanonicalBody: err=%v failed=%v", err, failedIDs(report))
+	}
+	report, err := assert.VerifyDetached(pub, pretty, sig)
+	if err != nil || report.Verdict != VerdictMalformed {
+		t.Fatalf("pretty body with CanonicalBody: err=%v verdict=%s", err, report.Verdict)
+	}
+	if ids := strings.Join(failedIDs(report), " "); !strings.Contains(ids, "D.body_canonical") {
+		t.Errorf("failed = %s, want D.body_canonical", ids)
+	}
+}
+
+func TestVerifyDetachedRejectsTamperingAndWrongKey(t *testing.T) {
+	pub, body, sig := detachedInput(t)
+
+	tampered := []byte(strings.Replace(string(body), `"deploy"`, `"destroy"`, 1))
+	if report, _ := VerifyDetached(pub, tampered, sig); report.Verdict != VerdictTampered {
+		t.Errorf("tampered body: verdict = %s", report.Verdict)
+	}
+
+	other := ed25519.NewKeyFromSeed(make([]byte, 32)).Public().(ed25519.PublicKey)
+	report, _ := VerifyDetached(other, body, sig)
+	if report.Verdict != VerdictTampered || failedIDs(report)[0] != "D.signer_binding" {
+		t.Errorf("header key mismatch: verdict=%s failed=%v", report.Verdict, failedIDs(report))
+	}
+}
+
+func TestVerifyDetachedMalformed(t *testing.T) {
+	pub, body, sig := detachedInput(t)
+	for name, tt := range map[string]struct {
+		body, sig []byte
+		want      string
+	}{
+		"not json":  {[]byte("{"), sig, "cannot parse detached body"},
+		"array":     {[]byte("[]"), sig, "cannot parse detached body"},
+		"tiny":      {[]byte(`{"a":1}`), sig, "too short"},
+		"short sig": {body, sig[:10], "signature_b64url: decodes to 10 bytes"},
+	} {
+		_, err := VerifyDetached(pub, tt.body, tt.sig)
+		var malformed *MalformedError
+		if !errors.As(err, &malformed) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want MalformedError %q", name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// cross_lang_proof/pkg/gefverify/document.go
+//
+// Report document (report.json)
+// =============================
+//
+// The serialized form of a Report, for tools that consume a verification
+// run after the fact (badges, dashboards). Field names are a contract:
+// any incompatible change bumps ReportSchemaVersion, and consumers must
+// refuse schema versions they do not know rather than guess.
+
+package gefverify
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ReportSchemaVersion is the schema_version written by NewReportDocument.
+const ReportSchemaVersion = 1
+
+// Version identifies this verifier in report documents. Kept in step
+// with the guardclaw release in pyproject.toml.
+const Version = "0.7.1"
+
+// ReportDocument is the JSON form of one verification run.
+type ReportDocument struct {
+	SchemaVersion   int                 `json:"schema_version"`
+	VerifierVersion string              `json:"verifier_version"`
+	Bundle          string              `json:"bundle"`
+	GEFVersion      string              `json:"gef_version"`
+	KeyFingerprint  string              `json:"key_fingerprint,omitempty"`
+	Profile         string              `json:"profile,omitempty"`
+	Verdict         Verdict             `json:"verdict"`
+	Passed          int                 `json:"passed"`
+	Total           int                 `json:"total"`
+	Checks          []CheckDocument     `json:"checks"`
+	Contracts       []ContractDocument  `json:"contracts"`
+	Notes           []string            `json:"notes,omitempty"`
+	Warnings        []string            `json:"warnings,omitempty"`
+	Nested          []NestedDocument    `json:"nested,omitempty"`
+	Exceptions      []ExceptionDocument `json:"exceptions,omitempty"`
+	Mutations       *MutationDocument   `json:"mutations,omitempty"`
+}
+
+// MutationDocument is the JSON form of MutationStats.
+type MutationDocument struct {
+	Evidence string                   `json:"evidence"` // always "sampling, not proof"
+	Seed     int64                    `json:"seed"`
+	Trials   int                      `json:"trials"`
+	Rejected int                      `json:"rejected"`
+	Accepted []MutationAcceptDocument `json:"accepted"`
+}
+
+// MutationAcceptDocument is the JSON form of one MutationAccept.
+type MutationAcceptDocument struct {
+	Position   int    `json:"position"`
+	Bit        int    `json:"bit"`
+	Original   string `json:"original"`
+	MutatedHex string `json:"mutated_hex"`
+}
+
+// ExceptionDocument is the JSON form of one AppliedException.
+type ExceptionDocument struct {
+	ID       string `json:"id"`
+	Check    string `json:"check"`
+	Reason   string `json:"reason"`
+	Approver string `json:"approver"`
+	Expires  string `json:"expires"`
+}
+
+// NestedDocument is the JSON form of one NestedReport.
+type NestedDocument struct {
+	Path   string         `json:"path"`
+	Depth  int            `json:"depth"`
+	Error  string         `json:"error,omitempty"`
+	Report ReportDocument `json:"report"`
+}
+
+// CheckDocument is the JSON form of one CheckResult.
+type CheckDocument struct {
+	ID       string `json:"id"`
+	Section  string `json:"section"`
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Details  string `json:"details"`
+	Category string `json:"category"`
+	// Exception is set when the failure was accepted under it.
+	Exception string `json:"exception,omitempty"`
+}
+
+// ContractDocument is the JSON form of one ContractResult.
+type ContractDocument struct {
+	Section string         `json:"section"`
+	Status  ContractStatus `json:"status"`
+}
+
+// NewReportDocument converts report for the bundle read from source.
+func NewReportDocument(report Report, source, gefVersion string) ReportDocument {
+	doc := ReportDocument{
+		SchemaVersion:   ReportSchemaVersion,
+		VerifierVersion: Version,
+		Bundle:          source,
+		GEFVersion:      gefVersion,
+		KeyFingerprint:  report.KeyFingerprint,
+		Profile:         report.Profile,
+		Verdict:         report.Verdict,
+		Passed:          report.Passed(),
+		Total:           report.Total(),
+		Checks:          make([]CheckDocument, 0, len(report.Checks)),
+		Notes:           report.Notes,
+		Warnings:        report.Warnings,
+	}
+	for _, c := range report.Checks {
+		doc.Checks = append(doc.Checks, CheckDocument{
+			ID:        c.ID,
+			Section:   c.Section,
+			Name:      c.Name,
+			Passed:    c.Passed,
+			Details:   c.Details,
+			Category:  c.Category.String(),
+			Exception: c.Exception,
+		})
+	}
+	for _, e := range report.Exceptions {
+		doc.Exceptions = append(doc.Exceptions, ExceptionDocument{
+			ID:       e.ExceptionID,
+			Check:    e.CheckID,
+			Reason:   e.Reason,
+			Approver: e.Approver,
+			Expires:  e.Expires.UTC().Format(time.RFC3339),
+		})
+	}
+	for _, n := range report.Nested {
+		nd := NestedDocument{
+			Path:   n.Path,
+			Depth:  n.Depth,
+			Report: NewReportDocument(n.Report, source+"#"+n.Path, n.GEFVersion),
+		}
+		if n.Err != nil {
+			nd.Error = n.Err.Error()
+		}
+		doc.Nested = append(doc.Nested, nd)
+	}
+	if m := report.Mutations; m != nil {
+		md := &MutationDocument{
+			Evidence: "sampling, not proof",
+			Seed:     m.Seed,
+			Trials:   m.Trials,
+			Rejected: m.Rejected,
+			Accepted: []MutationAcceptDocument{},
+		}
+		for _, a := range m.Accepted {
+			md.Accepted = append(md.Accepted, MutationAcceptDocument{
+				a.Position, a.Bit, fmt.Sprintf("0x%02X", a.Original), a.MutatedHex,
+			})
+		}
+		doc.Mutations = md
+	}
+	for _, c := range report.Contracts {
+		doc.Contracts = append(doc.Contracts, ContractDocument{c.Section, c.Status})
+	}
+	return doc
+}
+
+// ParseReportDocument decodes a report document, refusing any
+// schema_version other than ReportSchemaVersion.
+func ParseReportDocument(data []byte) (ReportDocument, error) {
+	var probe struct {
+		SchemaVersion *int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return ReportDocument{}, fmt.Errorf("cannot parse report: %w", err)
+	}
+	if probe.SchemaVersion == nil {
+		return ReportDocument{}, fmt.Errorf("report has no schema_version")
+	}
+	if *probe.SchemaVersion != ReportSchemaVersion {
+		return ReportDocument{}, fmt.Errorf("unsupported report schema_version %d (this verifier understands %d)",
+			*probe.SchemaVersion, ReportSchemaVersion)
+	}
+
+	var doc ReportDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return ReportDocument{}, fmt.Errorf("cannot parse report: %w", err)
+	}
+	return doc, nil
+}
@@ -0,0 +1,209 @@
+// cross_lang_proof/pkg/gefverify/envelope.go
+//
+// Envelopes as received
+// =====================
+//
+// Services that ingest records get a ledger line — the signed fields plus
+// "signature" — not a proof bundle. BundleFromEnvelope derives the bundle
+// from the envelope alone so the same Verifier runs on it:
+//
+//   signing_dict = chain_dict = every key of the envelope except "signature"
+//   public key   = signing_dict.signer_public_key
+//   expected     = values recomputed in Go
+//
+// With no emitter-side values to compare against, C1, C2, C4 and C7 hold
+// by construction; what is left to fail is the signature, the required
+// fields and the policies. Note the key comes from the record itself: a
+// caller must pin signer_public_key to a key it trusts before treating a
+// VERIFIED report as authentic.
+//
+// The other way round, CONTRACT 8 checks a bundle's envelope_json against
+// what was signed: its fields other than signature must canonicalize to
+// signing_dict's, and its signature must decode to the bytes of
+// signature_b64url. A bundle without envelope_json has nothing to
+// compare; the contract runs with no checks and leaves a note.
+
+package gefverify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BundleFromEnvelope builds a proof bundle from one JSON envelope. The
+// error is a *MalformedError when the envelope is not a JSON object,
+// lacks a string signature or signer_public_key, or either does not
+// decode; its Reason names the field.
+func BundleFromEnvelope(data []byte) (ProofBundle, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return ProofBundle{}, &MalformedError{"cannot parse envelope", err}
+	}
+	if fields == nil {
+		return ProofBundle{}, &MalformedError{"cannot parse envelope", errors.New("not a JSON object")}
+	}
+	sig, ok := fields["signature"].(string)
+	if !ok {
+		return ProofBundle{}, &MalformedError{"invalid envelope", errors.New("signature missing or not a string")}
+	}
+	pubHex, ok := fields["signer_public_key"].(string)
+	if !ok {
+		return ProofBundle{}, &MalformedError{"invalid envelope", errors.New("signer_public_key missing or not a string")}
+	}
+	if _, err := DecodePublicKey(pubHex); err != nil {
+		return ProofBundle{}, &MalformedError{"invalid envelope signer_public_key", err}
+	}
+	sigBytes, err := DecodeSignature(sig)
+	if err != nil {
+		return ProofBundle{}, &MalformedError{"invalid envelope signature", err}
+	}
+	delete(fields, "signature")
+
+	canonical, err := Canonicalize(fields)
+	if err != nil {
+		return ProofBundle{}, &MalformedError{"canonicalize envelope", err}
+	}
+	chainHash  := sha256.Sum256(canonical)
+	version, _ := fields["gef_version"].(string)
+	chainDict  := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		chainDict[k] = v
+	}
+	return ProofBundle{
+		Description:       "GEF proof bundle derived from an envelope",
+		GEFVersion:        version,
+		PublicKeyHex:      pubHex,
+		SigningDict:       fields,
+		CanonicalBytesHex: hex.EncodeToString(canonical),
+		ChainDict:         chainDict,
+		ChainBytesHex:     hex.EncodeToString(canonical),
+		CausalHashOfThis:  hex.EncodeToString(chainHash[:]),
+		SignatureB64URL:   sig,
+		SignatureHex:      hex.EncodeToString(sigBytes),
+		EnvelopeJSON:      string(data),
+	}, nil
+}
+
+// checkEnvelopeJSON runs CONTRACT 8 on bundle, whose signature decoded
+// to sig.
+func (v *Verifier) checkEnvelopeJSON(r *run, bundle ProofBundle, sig []byte) {
+	if bundle.EnvelopeJSON == "" {
+		r.notes = append(r.notes, "envelope_json absent: CONTRACT 8 has nothing to compare")
+		return
+	}
+	var envelope map[string]interface{}
+	if err := json.Unmarshal([]byte(bundle.EnvelopeJSON), &envelope); err != nil || envelope == nil {
+		if err == nil {
+			err = errors.New("not a JSON object")
+		}
+		r.check("C8.envelope_fields", CategoryIntegrity, "envelope_json fields == signing_dict", false,
+			fmt.Sprintf("envelope_json does not parse: %v", err))
+		r.check("C8.envelope_signature", CategoryIntegrity, "envelope signature == signature_b64url", false,
+			"envelope_json does not parse")
+		return
+	}
+
+	differs, diagnostics := envelopeFieldDiff(envelope, bundle.SigningDict)
+	details := fmt.Sprintf("%d fields, canonical bytes identical", len(withoutSignature(envelope)))
+	if len(differs) > 0 {
+		details = strings.Join(differs, "; ")
+	}
+	r.check("C8.envelope_fields", CategoryIntegrity, "envelope_json fields == signing_dict", len(differs) == 0,
+		details, diagnostics...)
+
+	encoded, _ := envelope["signature"].(string)
+	envelopeSig, err := DecodeBundleSignature(bundle.SigAlg, encoded)
+	switch {
+	case encoded == "":
+		details = "envelope_json has no signature"
+	case err != nil:
+		details = fmt.Sprintf("envelope signature does not decode: %v", err)
+	case !bytes.Equal(envelopeSig, sig):
+		details = fmt.Sprintf("envelope=%s  bundle=%s",
+			v.abbrev(hex.EncodeToString(envelopeSig), 16), v.abbrev(hex.EncodeToString(sig), 16))
+	default:
+		details = fmt.Sprintf("%d bytes identical", len(sig))
+	}
+	r.check("C8.envelope_signature", CategoryIntegrity, "envelope signature == signature_b64url",
+		err == nil && bytes.Equal(envelopeSig, sig), details)
+}
+
+// envelopeFieldDiff compares the fields of envelope and signingDict other
+// than signature, each canonicalized, and describes the differences:
+// field names for the details, both values for the diagnostics.
+func envelopeFieldDiff(envelope, signingDict map[string]interface{}) (differs, diagnostics []string) {
+	envelope, signingDict = withoutSignature(envelope), withoutSignature(signingDict)
+	a, errA := Canonicalize(envelope)
+	b, errB := Canonicalize(signingDict)
+	if errA == nil && errB == nil && bytes.Equal(a, b) {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(envelope)+len(signingDict))
+	for k := range envelope {
+		keys = append(keys, k)
+	}
+	for k := range signingDict {
+		if _, ok := envelope[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var changed, onlyEnvelope, onlySigned []string
+	for _, k := range keys {
+		ev, inEnvelope := envelope[k]
+		sv, inSigned := signingDict[k]
+		switch {
+		case !inSigned:
+			onlyEnvelope = append(onlyEnvelope, k)
+		case !inEnvelope:
+			onlySigned = append(onlySigned, k)
+		default:
+			ec, sc := canonicalValue(ev), canonicalValue(sv)
+			if ec != sc {
+				changed = append(changed, k)
+				diagnostics = append(diagnostics,
+					fmt.Sprintf("%s: envelope_json=%s", k, truncate(ec, 60)),
+					fmt.Sprintf("%s: signing_dict =%s", k, truncate(sc, 60)))
+			}
+		}
+	}
+	for _, d := range []struct {
+		label  string
+		fields []string
+	}{{"differs", changed}, {"only in envelope_json", onlyEnvelope}, {"only in signing_dict", onlySigned}} {
+		if len(d.fields) > 0 {
+			differs = append(differs, d.label+": "+strings.Join(d.fields, ", "))
+		}
+	}
+	if len(differs) == 0 {
+		differs = []string{"canonicalization failed"}
+	}
+	return differs, diagnostics
+}
+
+// withoutSignature returns the fields of m other than signature.
+func withoutSignature(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if k != "signature" {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// canonicalValue is the JCS form of one field value.
+func canonicalValue(v interface{}) string {
+	c, err := Canonicalize(map[string]interface{}{"v": v})
+	if err != nil {
+		return fmt.Sprintf("(%v)", err)
+	}
+	return string(c[len(`{"v":`) : len(c)-1])
+}
@@ -0,0 +1,136 @@
+// cross_lang_proof/pkg/gefverify/envelope_test.go
+
+package gefverify
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBundleFromEnvelopeVerifies(t *testing.T) {
+	_, built, err := NewRecord("agent-7", "genesis").Genesis().Finalize(builderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := BundleFromEnvelope([]byte(built.EnvelopeJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bundle.CausalHashOfThis != built.CausalHashOfThis {
+		t.Errorf("chain hash = %s, want %s", bundle.CausalHashOfThis, built.CausalHashOfThis)
+	}
+	report, err := Verify(bundle, VerifyOptions{})
+	if err != nil || !report.OK() {
+		t.Fatalf("err=%v failed=%v", err, report.Failed())
+	}
+}
+
+func TestBundleFromEnvelopeDetectsTampering(t *testing.T) {
+	_, built, err := NewRecord("agent-7", "execution").
+		Payload(map[string]interface{}{"amount": 10}).
+		Sequence(1).
+		PreviousHash(GenesisHash[:63] + "1").
+		Finalize(builderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := strings.Replace(built.EnvelopeJSON, `"amount":10`, `"amount":1000`, 1)
+	bundle, err := BundleFromEnvelope([]byte(tampered))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, _ := Verify(bundle, VerifyOptions{})
+	if report.Verdict != VerdictTampered {
+		t.Errorf("verdict = %s, want TAMPERED", report.Verdict)
+	}
+
+	extra := strings.Replace(built.EnvelopeJSON, `{`, `{"extra":1,`, 1)
+	bundle, _ = BundleFromEnvelope([]byte(extra))
+	report, _ = Verify(bundle, VerifyOptions{})
+	if report.OK() {
+		t.Error("envelope with an injected field verified")
+	}
+}
+
+func TestBundleFromEnvelopeMalformed(t *testing.T) {
+	for input, want := range map[string]string{
+		`[1]`:                          "cannot parse envelope",
+		`null`:                         "not a JSON object",
+		`{"signer_public_key":"aa"}`:   "signature missing",
+		`{"signature":"x"}`:            "signer_public_key missing",
+		`{"signature":5,"payload":{}}`: "signature missing",
+		`{"signature":"AAAA","signer_public_key":"aa"}`:                               "invalid envelope signer_public_key: got 1 bytes",
+		`{"signature":"AAAA","signer_public_key":"` + strings.Repeat("ab", 32) + `"}`: "invalid envelope signature: got 3 bytes",
+	} {
+		_, err := BundleFromEnvelope([]byte(input))
+		var malformed *MalformedError
+		if !errors.As(err, &malformed) || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want MalformedError %q", input, err, want)
+		}
+	}
+}
+
+func TestEnvelopeConsistency(t *testing.T) {
+	pb := loadProofBundle(t)
+	edited := func(old, new string) ProofBundle {
+		t.Helper()
+		if !strings.Contains(pb.EnvelopeJSON, old) {
+			t.Fatalf("%q not in envelope_json", old)
+		}
+		b := pb
+		b.EnvelopeJSON = strings.Replace(pb.EnvelopeJSON, old, new, 1)
+		return b
+	}
+	for _, tt := range []struct {
+		name    string
+		bundle  ProofBundle
+		failed  string
+		details string
+	}{
+		{"payload", edited(`"proof": "cross-language"`, `"proof": "forged"`), "C8.envelope_fields", "differs: payload"},
+		{"extra field", edited(`{"agent_id"`, `{"admin": true, "agent_id"`), "C8.envelope_fields", "only in envelope_json: admin"},
+		{"missing field", edited(`"nonce": "abcdef1234567890abcdef1234567890", `, ``), "C8.envelope_fields", "only in signing_dict: nonce"},
+		{"signature", edited(`"signature": "B`, `"signature": "C`), "C8.envelope_signature", "envelope="},
+		{"no signature", edited(`"signature": "`, `"signatures": "`), "C8.envelope_signature", "envelope_json has no signature"},
+		{"not JSON", edited(`{`, `[`), "C8.envelope_fields", "envelope_json does not parse"},
+	} {
+		report, err := Verify(tt.bundle, VerifyOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if c, _ := checkByID(report, "C3.signature_go"); !c.Passed {
+			t.Errorf("%s: the detached signature should still verify", tt.name)
+		}
+		c, _ := checkByID(report, tt.failed)
+		if c.Passed || !strings.HasPrefix(c.Details, tt.details) || report.Verdict != VerdictTampered {
+			t.Errorf("%s: %s %+v, verdict %s", tt.name, tt.failed, c, report.Verdict)
+		}
+	}
+
+	c, _ := checkByID(mustVerify(t, edited(`"proof": "cross-language"`, `"proof": "forged"`)), "C8.envelope_fields")
+	if len(c.Diagnostics) != 2 || !strings.Contains(c.Diagnostics[0], `"proof":"forged"`) {
+		t.Errorf("diagnostics do not show both values: %q", c.Diagnostics)
+	}
+}
+
+func TestEnvelopeConsistencyWithoutEnvelope(t *testing.T) {
+	report := mustVerify(t, signedBundle(t, 1, map[string]interface{}{}))
+	if _, ok := checkByID(report, "C8.envelope_fields"); ok || report.Verdict != VerdictVerified {
+		t.Errorf("verdict %s, checks %v", report.Verdict, report.Checks)
+	}
+	// The other note is CONTRACT 10's, with no trusted keys.
+	if len(report.Notes) != 2 || !strings.Contains(report.Notes[0], "envelope_json absent") {
+		t.Errorf("notes %q", report.Notes)
+	}
+}
+
+func mustVerify(t *testing.T, b ProofBundle) Report {
+	t.Helper()
+	report, err := Verify(b, VerifyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
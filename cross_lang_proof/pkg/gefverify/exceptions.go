@@ -0,0 +1,135 @@
+// cross_lang_proof/pkg/gefverify/exceptions.go
+//
+// Policy exceptions (optional, VerifyOptions.Exceptions)
+// ======================================================
+//
+// A temporary, approved acceptance of one known failure, e.g. "accept the
+// field-count failure from agent-x until Friday while its emitter is
+// fixed", without weakening the check for everyone else:
+//
+//   [{"id": "EX-12", "check": "C5.field_count", "agent_id": "agent-x",
+//     "expires": "2026-03-06T18:00:00Z", "reason": "emitter bug #431",
+//     "approver": "sec-oncall"}]
+//
+// An exception matches a FAILED check with the same ID, on a record whose
+// agent_id and record_id match the exception's (omitted means any), while
+// the reference time is before expires. A matching check is not turned
+// into a pass: it stays failed and carries the exception ID, which drops
+// it out of the verdict and Failed() — consoles show it as a warning —
+// and it is listed in Report.Exceptions. Metrics count it with outcome
+// "excepted". After expiry the entry is ignored and the failure counts
+// again.
+//
+// Exceptions are read per verification. A long-running embedder reloads
+// by calling LoadPolicyExceptions again and building a new Verifier.
+
+package gefverify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// PolicyException accepts failures of one check for a scope of records.
+type PolicyException struct {
+	ID       string    `json:"id"`
+	Check    string    `json:"check"`
+	AgentID  string    `json:"agent_id,omitempty"`
+	RecordID string    `json:"record_id,omitempty"`
+	Expires  time.Time `json:"expires"`
+	Reason   string    `json:"reason"`
+	Approver string    `json:"approver"`
+}
+
+// AppliedException records one failure accepted under an exception.
+type AppliedException struct {
+	ExceptionID string
+	CheckID     string
+	Reason      string
+	Approver    string
+	Expires     time.Time
+}
+
+// ParsePolicyExceptions decodes a JSON array of exceptions. Every entry
+// needs id, check, expires, reason and approver; ids must be unique.
+func ParsePolicyExceptions(data []byte) ([]PolicyException, error) {
+	var list []PolicyException
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("policy exceptions: %w", err)
+	}
+	seen := make(map[string]bool, len(list))
+	for i, e := range list {
+		var missing []string
+		for field, empty := range map[string]bool{
+			"id": e.ID == "", "check": e.Check == "", "expires": e.Expires.IsZero(),
+			"reason": e.Reason == "", "approver": e.Approver == "",
+		} {
+			if empty {
+				missing = append(missing, field)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return nil, fmt.Errorf("policy exceptions: entry %d: missing %v", i, missing)
+		}
+		if seen[e.ID] {
+			return nil, fmt.Errorf("policy exceptions: duplicate id %q", e.ID)
+		}
+		seen[e.ID] = true
+	}
+	return list, nil
+}
+
+// LoadPolicyExceptions reads and parses an exceptions file.
+func LoadPolicyExceptions(path string) ([]PolicyException, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePolicyExceptions(data)
+}
+
+// matchException returns the first live exception covering check on the
+// record signingDict.
+func matchException(list []PolicyException, check CheckResult, signingDict map[string]interface{}, now time.Time) (PolicyException, bool) {
+	agentID, _ := signingDict["agent_id"].(string)
+	recordID, _ := signingDict["record_id"].(string)
+	for _, e := range list {
+		if e.Check != check.ID || !now.Before(e.Expires) {
+			continue
+		}
+		if (e.AgentID != "" && e.AgentID != agentID) || (e.RecordID != "" && e.RecordID != recordID) {
+			continue
+		}
+		return e, true
+	}
+	return PolicyException{}, false
+}
+
+// exceptions marks the failed checks covered by v.opts.Exceptions.
+func (v *Verifier) exceptions(r *run, bundle ProofBundle) {
+	if len(v.opts.Exceptions) == 0 {
+		return
+	}
+	now := v.now()
+	for i, c := range r.checks {
+		if c.Passed {
+			continue
+		}
+		e, ok := matchException(v.opts.Exceptions, c, bundle.SigningDict, now)
+		if !ok {
+			continue
+		}
+		r.checks[i].Exception = e.ID
+		r.exceptions = append(r.exceptions, AppliedException{
+			ExceptionID: e.ID,
+			CheckID:     c.ID,
+			Reason:      e.Reason,
+			Approver:    e.Approver,
+			Expires:     e.Expires,
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// cross_lang_proof/pkg/gefverify/exceptions_test.go
+
+package gefverify
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// staleOptions fails P.freshness for signedBundle records (timestamp
+// 2026-02-24T12:00:00Z) at reference time at.
+func staleOptions(at time.Time, exceptions ...PolicyException) VerifyOptions {
+	return VerifyOptions{Freshness: time.Minute, ReferenceTime: at, Exceptions: exceptions}
+}
+
+var freshnessException = PolicyException{
+	ID:       "EX-1",
+	Check:    "P.freshness",
+	AgentID:  "agent-nested",
+	Expires:  time.Date(2026, 3, 6, 18, 0, 0, 0, time.UTC),
+	Reason:   "replay of archived records",
+	Approver: "sec-oncall",
+}
+
+func TestExceptionAcceptsMatchingFailure(t *testing.T) {
+	metrics := newRecordingMetrics()
+	opts := staleOptions(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), freshnessException)
+	opts.Metrics = metrics
+	report, err := Verify(signedBundle(t, 1, map[string]interface{}{"k": "v"}), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Verdict != VerdictVerified || !report.OK() || len(report.Failed()) != 0 {
+		t.Fatalf("verdict=%s failed=%v", report.Verdict, report.Failed())
+	}
+
+	excepted := report.Excepted()
+	if len(excepted) != 1 || excepted[0].ID != "P.freshness" || excepted[0].Passed || excepted[0].Exception != "EX-1" {
+		t.Errorf("excepted = %+v, want P.freshness still failed under EX-1", excepted)
+	}
+	if report.Passed() == report.Total() {
+		t.Error("excepted failure counted as a pass")
+	}
+	if len(report.Exceptions) != 1 || report.Exceptions[0].ExceptionID != "EX-1" || report.Exceptions[0].Approver != "sec-oncall" {
+		t.Errorf("Exceptions = %+v", report.Exceptions)
+	}
+	if metrics.outcomes["P.freshness"] != OutcomeExcepted {
+		t.Errorf("metrics outcome = %q, want %q", metrics.outcomes["P.freshness"], OutcomeExcepted)
+	}
+
+	doc := NewReportDocument(report, "b.json", "1.0")
+	if len(doc.Exceptions) != 1 || doc.Exceptions[0].Expires != "2026-03-06T18:00:00Z" {
+		t.Errorf("document exceptions = %+v", doc.Exceptions)
+	}
+}
+
+func TestExceptionIgnoredAfterExpiry(t *testing.T) {
+	opts := staleOptions(time.Date(2026, 3, 6, 18, 0, 0, 0, time.UTC), freshnessException)
+	report, err := Verify(signedBundle(t, 1, "x"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Verdict != VerdictPolicyRejected || len(report.Exceptions) != 0 {
+		t.Errorf("verdict=%s exceptions=%v, want POLICY_REJECTED with none applied", report.Verdict, report.Exceptions)
+	}
+}
+
+func TestExceptionNonMatchingFallsThrough(t *testing.T) {
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	otherAgent, otherCheck, otherRecord := freshnessException, freshnessException, freshnessException
+	otherAgent.AgentID = "agent-x"
+	otherCheck.Check = "C3.signature_go"
+	otherRecord.RecordID = "rec-2"
+
+	for name, e := range map[string]PolicyException{
+		"other agent": otherAgent, "other check": otherCheck, "other record": otherRecord,
+	} {
+		report, err := Verify(signedBundle(t, 1, "x"), staleOptions(at, e))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Verdict != VerdictPolicyRejected || len(report.Excepted()) != 0 {
+			t.Errorf("%s: verdict=%s excepted=%v", name, report.Verdict, report.Excepted())
+		}
+	}
+}
+
+func TestExceptionNeverTouchesPassingChecks(t *testing.T) {
+	passing := freshnessException
+	passing.Check = "C3.signature_go"
+	report, err := Verify(signedBundle(t, 1, "x"), VerifyOptions{Exceptions: []PolicyException{passing}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Exceptions) != 0 || !report.OK() {
+		t.Errorf("exceptions=%v ok=%v", report.Exceptions, report.OK())
+	}
+}
+
+func TestParsePolicyExceptions(t *testing.T) {
+	list, err := ParsePolicyExceptions([]byte(`[{"id":"EX-1","check":"P.freshness","agent_id":"a",
+		"expires":"2026-03-06T18:00:00Z","reason":"r","approver":"p"}]`))
+	if err != nil || len(list) != 1 || list[0].AgentID != "a" || list[0].Expires.Day() != 6 {
+		t.Fatalf("list=%+v err=%v", list, err)
+	}
+
+	for input, want := range map[string]string{
+		`[{"id":"EX-1","check":"P.freshness"}]`: "missing [approver expires reason]",
+		`[{"id":"EX-1","check":"c","expires":"2026-03-06T18:00:00Z","reason":"r","approver":"p"},
+		  {"id":"EX-1","check":"d","expires":"2026-03-06T18:00:00Z","reason":"r","approver":"p"}]`: "duplicate id",
+		`{"id":"EX-1"}`: "cannot unmarshal",
+	} {
+		if _, err := ParsePolicyExceptions([]byte(input)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", input, err, want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// cross_lang_proof/pkg/gefverify/fieldtrace.go
+//
+// Per-field canonicalization trace
+// ================================
+//
+// A canonical_bytes mismatch says "somewhere in these 600 bytes". The
+// trace says "in timestamp". JCS of an object is the sorted members with
+// each value canonicalized on its own, so the comparison can be done per
+// top-level field:
+//
+//   Go      JCS(value) for every field of signing_dict
+//   Python  the exact bytes of each member value in canonical_bytes_hex,
+//           split out with json.RawMessage (no re-encoding)
+//
+// Fields present on only one side are reported as such.
+
+package gefverify
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gowebpki/jcs"
+)
+
+// FieldTrace is the per-field comparison of one signing_dict member.
+type FieldTrace struct {
+	Field  string
+	Go     []byte // nil if absent on the Go side
+	Python []byte // nil if absent from Python's canonical bytes
+}
+
+// Match reports whether both sides canonicalize the field identically.
+func (f FieldTrace) Match() bool {
+	return f.Go != nil && f.Python != nil && bytes.Equal(f.Go, f.Python)
+}
+
+// Status is "match", "MISMATCH", "only in Go" or "only in Python".
+func (f FieldTrace) Status() string {
+	switch {
+	case f.Python == nil:
+		return "only in Go"
+	case f.Go == nil:
+		return "only in Python"
+	case f.Match():
+		return "match"
+	}
+	return "MISMATCH"
+}
+
+// TraceFields canonicalizes each field of signingDict independently and
+// compares it with the same member of Python's canonical bytes.
+func TraceFields(signingDict map[string]interface{}, pythonCanonicalHex string) ([]FieldTrace, error) {
+	pythonBytes, err := hex.DecodeString(pythonCanonicalHex)
+	if err != nil {
+		return nil, fmt.Errorf("canonical_bytes_hex: %w", err)
+	}
+	var pythonFields map[string]json.RawMessage
+	if err := json.Unmarshal(pythonBytes, &pythonFields); err != nil {
+		return nil, fmt.Errorf("canonical_bytes_hex is not a JSON object: %w", err)
+	}
+
+	names := make(map[string]bool, len(signingDict)+len(pythonFields))
+	for k := range signingDict {
+		names[k] = true
+	}
+	for k := range pythonFields {
+		names[k] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for k := range names {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	traces := make([]FieldTrace, 0, len(sorted))
+	for _, k := range sorted {
+		t := FieldTrace{Field: k}
+		if v, ok := signingDict[k]; ok {
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: json.Marshal: %w", k, err)
+			}
+			if t.Go, err = jcs.Transform(raw); err != nil {
+				return nil, fmt.Errorf("field %s: jcs.Transform: %w", k, err)
+			}
+		}
+		if raw, ok := pythonFields[k]; ok {
+			t.Python = []byte(raw)
+		}
+		traces = append(traces, t)
+	}
+	return traces, nil
+}
+
+// FieldTraceTable renders traces as aligned lines. With onlyDiffs, matching
+// fields are left out and both canonical forms follow each difference.
+func FieldTraceTable(traces []FieldTrace, onlyDiffs bool) []string {
+	var lines []string
+	for _, t := range traces {
+		if onlyDiffs && t.Match() {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%-20s %-15s go=%d bytes  python=%d bytes",
+			t.Field, t.Status(), len(t.Go), len(t.Python)))
+		if onlyDiffs {
+			lines = append(lines,
+				"    go     : "+truncate(string(t.Go), 120),
+				"    python : "+truncate(string(t.Python), 120))
+		}
+	}
+	return lines
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,57 @@
+// cross_lang_proof/pkg/gefverify/fieldtrace_test.go
+
+package gefverify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTraceFieldsIsolatesDivergentField(t *testing.T) {
+	bundle := loadProofBundle(t)
+	sd := make(map[string]interface{}, len(bundle.SigningDict))
+	for k, v := range bundle.SigningDict {
+		sd[k] = v
+	}
+	sd["timestamp"] = strings.TrimSuffix(sd["timestamp"].(string), "Z") + "+00:00"
+	delete(sd, "nonce")
+	sd["extra"] = true
+
+	traces, err := TraceFields(sd, bundle.CanonicalBytesHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, tr := range traces {
+		got[tr.Field] = tr.Status()
+	}
+	want := map[string]string{
+		"timestamp": "MISMATCH",
+		"nonce":     "only in Python",
+		"extra":     "only in Go",
+		"agent_id":  "match",
+		"payload":   "match",
+	}
+	for field, status := range want {
+		if got[field] != status {
+			t.Errorf("%s: status %q, want %q", field, got[field], status)
+		}
+	}
+}
+
+func TestFieldTraceAttachedOnCanonicalMismatch(t *testing.T) {
+	bundle := loadProofBundle(t)
+	bundle.SigningDict["record_type"] = "tampered"
+
+	report, err := NewVerifier().Verify(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.FieldTrace) == 0 {
+		t.Fatal("no field trace on canonical mismatch")
+	}
+	diag := strings.Join(report.Checks[0].Diagnostics, "\n")
+	if !strings.Contains(diag, "record_type") || strings.Contains(diag, "agent_id ") {
+		t.Errorf("diagnostics should name only the divergent field:\n%s", diag)
+	}
+}
@@ -0,0 +1,126 @@
+// cross_lang_proof/pkg/gefverify/freshness.go
+//
+// Timestamp contract and policies
+// ===============================
+//
+//   NewVerifier(WithMaxClockSkew(5*time.Minute), WithMaxAge(24*time.Hour))
+//   verify_proof -max-clock-skew 5m -max-age 24h [-now 2026-02-25T00:00:00Z] bundle.json
+//   verify_proof -freshness 5m bundle.json
+//
+// A valid signature proves WHO signed and WHAT, not WHEN: the signer
+// chose the timestamp. CONTRACT 9 is structural and always runs: the
+// timestamp must parse as RFC 3339. Whether the time is acceptable is
+// policy, off unless asked for, and reported in the POLICY section:
+//
+//   P.clock_skew   timestamp - reference <= MaxClockSkew   (not from the future)
+//   P.max_age      reference - timestamp <= MaxAge         (not stale)
+//   P.freshness    |reference - timestamp| <= Freshness    (records with a nonce)
+//
+// All three bounds are inclusive. For records that carry a nonce too,
+// the nonce makes each record unique and the window bounds how long a
+// captured record stays replayable.
+//
+// The reference time is the system clock unless WithReferenceTime (-now)
+// pins it, which keeps test runs reproducible.
+
+package gefverify
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrFreshnessNotApplicable means the record lacks a timestamp or nonce.
+var ErrFreshnessNotApplicable = errors.New("record has no timestamp and nonce pair")
+
+// TimestampDelta returns reference - timestamp for a signing dict.
+func TimestampDelta(signingDict map[string]interface{}, reference time.Time) (time.Duration, error) {
+	ts, _ := signingDict["timestamp"].(string)
+	nonce, _ := signingDict["nonce"].(string)
+	if ts == "" || nonce == "" {
+		return 0, ErrFreshnessNotApplicable
+	}
+	signedAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return 0, err
+	}
+	return reference.Sub(signedAt), nil
+}
+
+// checkTimestamp runs CONTRACT 9 and returns the signed time, and
+// whether there is one. An absent timestamp is left to CONTRACT 5.
+func checkTimestamp(r *run, signingDict map[string]interface{}) (time.Time, bool) {
+	raw, present := signingDict["timestamp"]
+	if !present {
+		r.notes = append(r.notes, "timestamp absent: CONTRACT 9 has nothing to parse")
+		return time.Time{}, false
+	}
+	ts, isString := raw.(string)
+	var signedAt time.Time
+	var err error
+	if isString {
+		signedAt, err = time.Parse(time.RFC3339Nano, ts)
+	} else {
+		err = fmt.Errorf("timestamp is %T, not a string", raw)
+	}
+	details := "timestamp=" + ts
+	if err != nil {
+		details = err.Error()
+	}
+	r.check(
+		"C9.timestamp_format",
+		CategoryStructure,
+		"timestamp parses as RFC 3339",
+		err == nil,
+		details,
+	)
+	return signedAt, err == nil
+}
+
+// timestampPolicy runs the enabled timestamp policies against signedAt.
+func (v *Verifier) timestampPolicy(r *run, signingDict map[string]interface{}, signedAt time.Time) {
+	reference := v.now().UTC()
+	age := reference.Sub(signedAt)
+	details := fmt.Sprintf("delta=%s  reference=%s", age, reference.Format(time.RFC3339Nano))
+
+	if v.opts.MaxClockSkew > 0 {
+		r.check(
+			"P.clock_skew",
+			CategoryPolicy,
+			fmt.Sprintf("timestamp at most %s ahead of reference", v.opts.MaxClockSkew),
+			-age <= v.opts.MaxClockSkew,
+			details,
+		)
+	}
+	if v.opts.MaxAge > 0 {
+		r.check(
+			"P.max_age",
+			CategoryPolicy,
+			fmt.Sprintf("timestamp at most %s behind reference", v.opts.MaxAge),
+			age <= v.opts.MaxAge,
+			details,
+		)
+	}
+	if v.opts.Freshness > 0 {
+		if _, err := TimestampDelta(signingDict, reference); err != nil {
+			r.notes = append(r.notes, "freshness not evaluated: "+err.Error())
+			return
+		}
+		r.check(
+			"P.freshness",
+			CategoryPolicy,
+			fmt.Sprintf("timestamp within %s of reference", v.opts.Freshness),
+			absDuration(age) <= v.opts.Freshness,
+			details,
+		)
+	}
+}
+
+// absDuration returns |d|.
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,104 @@
+// cross_lang_proof/pkg/gefverify/freshness_test.go
+
+package gefverify
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimestampDelta(t *testing.T) {
+	ref := time.Date(2026, 2, 25, 0, 5, 0, 0, time.UTC)
+	sd := map[string]interface{}{
+		"timestamp": "2026-02-25T00:00:00.000Z",
+		"nonce":     "abcdef1234567890abcdef1234567890",
+	}
+	delta, err := TimestampDelta(sd, ref)
+	if err != nil || delta != 5*time.Minute {
+		t.Fatalf("delta = %v, %v; want 5m, nil", delta, err)
+	}
+
+	future := time.Date(2026, 2, 24, 23, 58, 0, 0, time.UTC)
+	if delta, _ := TimestampDelta(sd, future); absDuration(delta) != 2*time.Minute {
+		t.Errorf("future delta = %v, want |2m|", delta)
+	}
+
+	if _, err := TimestampDelta(map[string]interface{}{"timestamp": "2026-02-25T00:00:00Z"}, ref); !errors.Is(err, ErrFreshnessNotApplicable) {
+		t.Errorf("missing nonce: err = %v, want ErrFreshnessNotApplicable", err)
+	}
+
+	sd["timestamp"] = "25/02/2026"
+	if _, err := TimestampDelta(sd, ref); err == nil || errors.Is(err, ErrFreshnessNotApplicable) {
+		t.Errorf("malformed timestamp: err = %v, want parse error", err)
+	}
+}
+
+func TestTimestampContract(t *testing.T) {
+	for _, ts := range []interface{}{"25/02/2026", "2026-02-25 00:00:00Z", "2026-02-30T00:00:00Z", "", 1772006400} {
+		report, err := Verify(editedBundle(t, map[string]interface{}{"timestamp": ts}), VerifyOptions{})
+		if err != nil {
+			t.Fatalf("%v: %v", ts, err)
+		}
+		c, ok := checkByID(report, "C9.timestamp_format")
+		if !ok || c.Passed || report.OK() {
+			t.Errorf("%v: C9.timestamp_format present=%v passed=%v, verdict %s", ts, ok, c.Passed, report.Verdict)
+		}
+	}
+
+	report, _ := Verify(editedBundle(t, map[string]interface{}{"timestamp": "2026-02-25T01:00:00.5+01:00"}), VerifyOptions{})
+	if c, ok := checkByID(report, "C9.timestamp_format"); !ok || !c.Passed {
+		t.Errorf("offset timestamp: %s", c.Details)
+	}
+
+	// A 2099 timestamp is well-formed; only the policies refuse it.
+	report, _ = Verify(editedBundle(t, map[string]interface{}{"timestamp": "2099-01-01T00:00:00Z"}), VerifyOptions{})
+	if !report.OK() {
+		t.Errorf("2099 without policy: verdict %s, failed %v", report.Verdict, failedIDs(report))
+	}
+}
+
+func TestTimestampPolicy(t *testing.T) {
+	bundle := loadProofBundle(t) // signed at 2026-02-25T00:00:00.000Z
+	signedAt := time.Date(2026, 2, 25, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name      string
+		reference time.Time
+		opts      VerifyOptions
+		id        string
+		passed    bool
+	}{
+		{"skew: exactly at the boundary", signedAt.Add(-5 * time.Minute), VerifyOptions{MaxClockSkew: 5 * time.Minute}, "P.clock_skew", true},
+		{"skew: 1ms in the future past it", signedAt.Add(-5*time.Minute - time.Millisecond), VerifyOptions{MaxClockSkew: 5 * time.Minute}, "P.clock_skew", false},
+		{"skew: old records are not skewed", signedAt.AddDate(1, 0, 0), VerifyOptions{MaxClockSkew: 5 * time.Minute}, "P.clock_skew", true},
+		{"age: exactly at the boundary", signedAt.Add(24 * time.Hour), VerifyOptions{MaxAge: 24 * time.Hour}, "P.max_age", true},
+		{"age: 1ms older", signedAt.Add(24*time.Hour + time.Millisecond), VerifyOptions{MaxAge: 24 * time.Hour}, "P.max_age", false},
+		{"age: future records are not old", signedAt.AddDate(-1, 0, 0), VerifyOptions{MaxAge: 24 * time.Hour}, "P.max_age", true},
+	} {
+		tc.opts.ReferenceTime = tc.reference
+		report, err := Verify(bundle, tc.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		c, ok := checkByID(report, tc.id)
+		if !ok || c.Passed != tc.passed || c.Category != CategoryPolicy {
+			t.Errorf("%s: %s present=%v passed=%v (%s)", tc.name, tc.id, ok, c.Passed, c.Details)
+		}
+		want := VerdictVerified
+		if !tc.passed {
+			want = VerdictPolicyRejected
+		}
+		if report.Verdict != want {
+			t.Errorf("%s: verdict %s, want %s", tc.name, report.Verdict, want)
+		}
+	}
+
+	// With a malformed timestamp the policies have nothing to measure.
+	opts := VerifyOptions{MaxClockSkew: time.Minute, MaxAge: time.Hour, Freshness: time.Minute}
+	report, _ := Verify(editedBundle(t, map[string]interface{}{"timestamp": "yesterday"}), opts)
+	for _, id := range []string{"P.clock_skew", "P.max_age", "P.freshness"} {
+		if _, ok := checkByID(report, id); ok {
+			t.Errorf("%s ran on a malformed timestamp", id)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// cross_lang_proof/pkg/gefverify/gefverifytest/gefverifytest.go
+//
+// Golden report tests for emitters
+// ================================
+//
+//   func TestEmitter(t *testing.T) {
+//       gefverifytest.VerifyGolden(t, bundleBytes, "testdata/report.golden.json")
+//   }
+//
+//   go test ./... -update     rewrite the golden files instead of comparing
+//
+// VerifyGolden verifies a bundle and compares its report document,
+// normalized by gefverify.MarshalNormalizedReport, with a golden file.
+// Normalization is the library's, so a golden diff means the same thing
+// as any other report comparison: volatile fields never show up, and
+// everything else does.
+
+package gefverifytest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+var update = flag.Bool("update", false, "rewrite golden report files instead of comparing")
+
+// Updating reports whether -update was given, for tests with golden
+// files of their own.
+func Updating() bool { return *update }
+
+// VerifyGolden verifies bundle with opts and compares the normalized
+// report document with the golden file, or rewrites it under -update.
+// A mismatch is reported with t.Errorf; the report is returned so the
+// caller can assert more.
+func VerifyGolden(t testing.TB, bundle []byte, golden string, opts ...gefverify.Option) gefverify.Report {
+	t.Helper()
+	parsed, err := gefverify.ParseBundle(bundle)
+	if err != nil {
+		t.Fatalf("gefverifytest: %v", err)
+	}
+	// A verification error still leaves a report (verdict MALFORMED),
+	// which is what the golden file records.
+	report, _ := gefverify.NewVerifier(opts...).Verify(parsed)
+	gefVersion, _ := parsed.SigningDict["gef_version"].(string)
+	doc := gefverify.NewReportDocument(report, filepath.Base(golden), gefVersion)
+	got, err := gefverify.MarshalNormalizedReport(doc)
+	if err != nil {
+		t.Fatalf("gefverifytest: %v", err)
+	}
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+			t.Fatalf("gefverifytest: %v", err)
+		}
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatalf("gefverifytest: %v", err)
+		}
+		return report
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("gefverifytest: %v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("report differs from %s (run go test -update):\n%s", golden, lineDiff(want, got))
+	}
+	return report
+}
+
+// lineDiff lists the lines of want and got that differ, position by
+// position, which is enough for the small, stable layout of a report.
+func lineDiff(want, got []byte) string {
+	w, g := bytes.Split(want, []byte("\n")), bytes.Split(got, []byte("\n"))
+	var out bytes.Buffer
+	for i := 0; i < len(w) || i < len(g); i++ {
+		var wl, gl []byte
+		if i < len(w) {
+			wl = w[i]
+		}
+		if i < len(g) {
+			gl = g[i]
+		}
+		if !bytes.Equal(wl, gl) {
+			out.WriteString("- " + string(wl) + "\n+ " + string(gl) + "\n")
+		}
+	}
+	return out.String()
+}
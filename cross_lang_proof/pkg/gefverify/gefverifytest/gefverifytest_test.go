@@ -0,0 +1,71 @@
+// cross_lang_proof/pkg/gefverify/gefverifytest/gefverifytest_test.go
+
+package gefverifytest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recordingTB captures Errorf and turns Fatalf into a recorded failure
+// that stops the helper.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+type fatal struct{}
+
+func (r *recordingTB) Helper() {}
+func (r *recordingTB) Errorf(format string, a ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, a...))
+}
+func (r *recordingTB) Fatalf(format string, a ...interface{}) {
+	r.Errorf(format, a...)
+	panic(fatal{})
+}
+
+func run(tb *recordingTB, f func()) {
+	defer func() {
+		if p := recover(); p != nil && p != (fatal{}) {
+			panic(p)
+		}
+	}()
+	f()
+}
+
+func TestVerifyGoldenUpdateThenCompare(t *testing.T) {
+	bundle, err := os.ReadFile("../../../proof_bundle.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join(t.TempDir(), "sub", "report.golden.json")
+
+	tb := &recordingTB{TB: t}
+	run(tb, func() { VerifyGolden(tb, bundle, golden) })
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "run go test -update to create it") {
+		t.Fatalf("missing golden: errors = %q", tb.errors)
+	}
+
+	*update = true
+	run(tb, func() { VerifyGolden(tb, bundle, golden) })
+	*update = false
+	written, err := os.ReadFile(golden)
+	if err != nil || !strings.Contains(string(written), `"verifier_version": "<redacted>"`) {
+		t.Fatalf("-update wrote %q, %v", written, err)
+	}
+
+	tb.errors = nil
+	if report := VerifyGolden(tb, bundle, golden); !report.OK() || len(tb.errors) != 0 {
+		t.Errorf("unchanged bundle: OK=%v errors=%q", report.OK(), tb.errors)
+	}
+
+	os.WriteFile(golden, []byte(strings.Replace(string(written), `"verdict": "VERIFIED"`, `"verdict": "TAMPERED"`, 1)), 0o644)
+	VerifyGolden(tb, bundle, golden)
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], `-   "verdict": "TAMPERED"`) {
+		t.Errorf("changed verdict: errors = %q", tb.errors)
+	}
+}
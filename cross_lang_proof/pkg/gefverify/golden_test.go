@@ -0,0 +1,50 @@
+// cross_lang_proof/pkg/gefverify/golden_test.go
+
+package gefverify_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+	"gef_cross_lang_proof/pkg/gefverify/gefverifytest"
+)
+
+func proofBundleBytes(t *testing.T, edit func(*gefverify.ProofBundle)) []byte {
+	t.Helper()
+	data, err := os.ReadFile("../../proof_bundle.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edit == nil {
+		return data
+	}
+	bundle, err := gefverify.ParseBundle(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit(&bundle)
+	data, _ = json.Marshal(bundle)
+	return data
+}
+
+// All eleven contracts execute and pass on the reference bundle.
+func TestGoldenAllContractsExecuted(t *testing.T) {
+	gefverifytest.VerifyGolden(t, proofBundleBytes(t, nil), "testdata/report_reference.golden.json")
+}
+
+// A signature that does not decode aborts before any contract runs, and
+// every contract is recorded as skipped.
+func TestGoldenAbortedVerificationRecordsSkippedContracts(t *testing.T) {
+	bundle := proofBundleBytes(t, func(b *gefverify.ProofBundle) { b.SignatureB64URL = "AAAA" })
+	gefverifytest.VerifyGolden(t, bundle, "testdata/report_aborted.golden.json")
+}
+
+// Freshness against the system clock: delta and reference are redacted,
+// the verdict is not.
+func TestGoldenFreshnessAgainstClock(t *testing.T) {
+	gefverifytest.VerifyGolden(t, proofBundleBytes(t, nil), "testdata/report_stale.golden.json",
+		gefverify.WithFreshness(time.Minute))
+}
@@ -0,0 +1,117 @@
+// cross_lang_proof/pkg/gefverify/hashalg.go
+//
+// Chain hash algorithms (CONTRACT 2)
+// ==================================
+//
+// A bundle may name the algorithm its causal_hash_of_this was computed
+// with in hash_alg. HashAlgorithms maps that name to the hash, like
+// VersionTable and SizeLimitTable:
+//
+//   sha256     crypto/sha256, also used when hash_alg is absent
+//   sha512     crypto/sha512
+//   sha3-256   crypto/sha3 (hashalg_sha3.go, Go 1.24 and later)
+//
+// hash_alg is the only name: a bundle with chain_hash_alg fails
+// validation rather than being hashed with SHA-256 whatever it says.
+// A bundle without hash_alg is hashed with VerifyOptions.ChainHash,
+// SHA-256 by default; one that names an algorithm is hashed with it,
+// whatever ChainHash says. A name missing from the table fails
+// C2.hash_alg_supported and CONTRACT 2 compares nothing. Validate sizes
+// causal_hash_of_this by the digest of the named algorithm. The details
+// of C2.chain_hash name the algorithm used; a ChainHash is named by the
+// table entry it matches, or "custom".
+
+package gefverify
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"sort"
+	"strings"
+)
+
+// DefaultHashAlg is the algorithm of a bundle without hash_alg.
+const DefaultHashAlg = "sha256"
+
+// HashAlgorithms maps hash_alg → hash constructor.
+type HashAlgorithms map[string]func() hash.Hash
+
+// DefaultHashAlgorithms is the built-in table.
+var DefaultHashAlgorithms = HashAlgorithms{
+	DefaultHashAlg: sha256.New,
+	"sha512":       sha512.New,
+}
+
+// known returns the names in t, sorted.
+func (t HashAlgorithms) known() []string {
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// bundleHash returns the chain hash of a bundle naming alg, or ok false
+// if v does not know alg.
+func (v *Verifier) bundleHash(alg string) (h func() hash.Hash, ok bool) {
+	if alg == "" {
+		return v.chainHash, true
+	}
+	h, ok = v.hashAlgs[alg]
+	return h, ok
+}
+
+// ChainHashHex is the chain hash of canonical, a canonical chain_dict,
+// in a bundle naming alg, hex-encoded, as CONTRACT 2 and VerifyChain
+// compute it. ok is false if v does not know alg.
+func (v *Verifier) ChainHashHex(alg string, canonical []byte) (hash string, ok bool) {
+	newHash, ok := v.bundleHash(alg)
+	if !ok {
+		return "", false
+	}
+	return sumHex(newHash, canonical), true
+}
+
+// nameOf returns the name in t of the hash h, matched by its digest of
+// a probe, or "custom".
+func (t HashAlgorithms) nameOf(h func() hash.Hash) string {
+	probe := []byte("gef chain hash")
+	want := sumHex(h, probe)
+	for _, name := range t.known() {
+		if sumHex(t[name], probe) == want {
+			return name
+		}
+	}
+	return "custom"
+}
+
+// hashAlgName names the algorithm bundleHash(alg) hashes with.
+func (v *Verifier) hashAlgName(alg string) string {
+	if alg != "" {
+		return alg
+	}
+	return v.chainAlg
+}
+
+// hashSize is the digest size Validate expects of causal_hash_of_this
+// in a bundle naming alg: 0, any size, under a custom ChainHash or for
+// an algorithm CONTRACT 2 will reject.
+func (v *Verifier) hashSize(alg string) int {
+	if alg == "" && v.opts.ChainHash != nil {
+		return 0
+	}
+	h, ok := v.bundleHash(alg)
+	if !ok {
+		return 0
+	}
+	return h().Size()
+}
+
+// unsupportedHashAlg describes alg for C2.hash_alg_supported.
+func (v *Verifier) unsupportedHashAlg(alg string) string {
+	return fmt.Sprintf("unsupported hash algorithm %q: this verifier knows %s",
+		alg, strings.Join(v.hashAlgs.known(), ", "))
+}
@@ -0,0 +1,17 @@
+// cross_lang_proof/pkg/gefverify/hashalg_sha3.go
+//
+// crypto/sha3 is in the standard library from Go 1.24; built with an
+// older Go, sha3-256 bundles fail C2.hash_alg_supported.
+
+//go:build go1.24
+
+package gefverify
+
+import (
+	"crypto/sha3"
+	"hash"
+)
+
+func init() {
+	DefaultHashAlgorithms["sha3-256"] = func() hash.Hash { return sha3.New256() }
+}
@@ -0,0 +1,22 @@
+// cross_lang_proof/pkg/gefverify/hashalg_sha3_test.go
+
+//go:build go1.24
+
+package gefverify
+
+import "testing"
+
+func TestHashAlgSHA3(t *testing.T) {
+	b := loadHashAlgBundle(t, "sha3-256")
+	report, err := Verify(b, VerifyOptions{})
+	if err != nil || !report.OK() {
+		t.Fatalf("err=%v failed=%v", err, failedIDs(report))
+	}
+
+	// Same size as SHA-256, different digest.
+	b.HashAlg = "sha256"
+	report, _ = Verify(b, VerifyOptions{})
+	if c, _ := checkByID(report, "C2.chain_hash"); c.Passed {
+		t.Errorf("sha3-256 digest passed as sha256: %+v", c)
+	}
+}
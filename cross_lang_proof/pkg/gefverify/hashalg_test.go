@@ -0,0 +1,122 @@
+// cross_lang_proof/pkg/gefverify/hashalg_test.go
+
+package gefverify
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+// loadHashAlgBundle reads testdata/hash_alg/{alg}.json, whose
+// causal_hash_of_this Python's hashlib computed.
+func loadHashAlgBundle(t *testing.T, alg string) ProofBundle {
+	t.Helper()
+	data, err := os.ReadFile("testdata/hash_alg/" + alg + ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b ProofBundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestHashAlgBundles(t *testing.T) {
+	for _, alg := range []string{"sha256", "sha512"} {
+		b := loadHashAlgBundle(t, alg)
+		if b.HashAlg != alg {
+			t.Fatalf("%s.json: hash_alg %q", alg, b.HashAlg)
+		}
+		report, err := Verify(b, VerifyOptions{})
+		if err != nil || !report.OK() {
+			t.Errorf("%s: err=%v failed=%v", alg, err, failedIDs(report))
+		}
+		if c, _ := checkByID(report, "C2.chain_hash"); !strings.HasPrefix(c.Details, "alg="+alg+"  go=") {
+			t.Errorf("%s: C2.chain_hash details %q do not name the algorithm", alg, c.Details)
+		}
+		if report.HashAlg != alg || report.SigAlg != SigAlgEd25519 {
+			t.Errorf("%s: report names %s, %s", alg, report.HashAlg, report.SigAlg)
+		}
+		// hash_alg wins over ChainHash.
+		report, _ = NewVerifier(WithChainHash(sha256.New)).Verify(b)
+		if !report.OK() {
+			t.Errorf("%s under WithChainHash: %v", alg, failedIDs(report))
+		}
+	}
+
+	// The SHA-512 digest is not the SHA-256 one.
+	b := loadHashAlgBundle(t, "sha512")
+	b.HashAlg = ""
+	if problems := b.validate(sha256.Size); len(problems) != 1 || problems[0].Path != "causal_hash_of_this" {
+		t.Errorf("sha512 digest validated as sha256: %v", problems)
+	}
+}
+
+func TestUnsupportedHashAlg(t *testing.T) {
+	b := loadHashAlgBundle(t, "sha512")
+	b.HashAlg = "md5"
+	report, err := Verify(b, VerifyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := checkByID(report, "C2.hash_alg_supported")
+	if !ok || c.Passed || !strings.Contains(c.Details, `unsupported hash algorithm "md5": this verifier knows `) {
+		t.Fatalf("C2.hash_alg_supported %+v", c)
+	}
+	if _, ok := checkByID(report, "C2.chain_hash"); ok {
+		t.Error("C2.chain_hash compared under an unknown hash_alg")
+	}
+	if report.Verdict != VerdictMalformed {
+		t.Errorf("verdict %s", report.Verdict)
+	}
+
+	// A table without sha512 does not know it.
+	report, _ = NewVerifier(WithHashAlgorithms(HashAlgorithms{"sha256": sha256.New})).
+		Verify(loadHashAlgBundle(t, "sha512"))
+	if c, _ := checkByID(report, "C2.hash_alg_supported"); c.Details != `unsupported hash algorithm "sha512": this verifier knows sha256` {
+		t.Errorf("custom table: %+v", c)
+	}
+}
+
+func TestChainHashAlgRefused(t *testing.T) {
+	data, err := os.ReadFile("testdata/hash_alg/sha512.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, edit := range []string{`"hash_alg": "sha512"`, `"hash_alg": "sha256"`} {
+		misnamed := strings.Replace(string(data), `"hash_alg": "sha512"`, `"chain_hash_alg": "sha512", `+edit, 1)
+		if misnamed == string(data) {
+			t.Fatal("sha512.json has no hash_alg")
+		}
+		report, err := VerifyBytes([]byte(misnamed), VerifyOptions{})
+		if err == nil || report.Verdict != VerdictMalformed {
+			t.Fatalf("%s: verdict %s, err %v", edit, report.Verdict, err)
+		}
+		if c, ok := checkByID(report, "V.bundle_field.chain_hash_alg"); !ok || c.Passed || !strings.Contains(c.Details, "name the chain hash algorithm in hash_alg") {
+			t.Errorf("%s: %+v", edit, c)
+		}
+		found := false
+		for _, f := range Lint([]byte(misnamed)) {
+			found = found || f.CheckID == "L.unknown_field" && f.Severity == LintError && f.Pointer == "/chain_hash_alg"
+		}
+		if !found {
+			t.Errorf("%s: lint %v", edit, Lint([]byte(misnamed)))
+		}
+	}
+}
+
+func TestLintHashAlgSize(t *testing.T) {
+	for _, alg := range []string{"sha256", "sha512"} {
+		data, err := os.ReadFile("testdata/hash_alg/" + alg + ".json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := LintErrors(Lint(data)); n != 0 {
+			t.Errorf("%s: %d lint errors: %v", alg, n, Lint(data))
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// cross_lang_proof/pkg/gefverify/hygiene.go
+//
+// String hygiene (optional, WithHygiene)
+// ======================================
+//
+// A signature proves the bytes are the bytes — not that they are sane. An
+// agent_id with an invisible U+FEFF verifies fine and then fails every
+// downstream join. The hygiene scan walks every string in signing_dict
+// and reports, with JSON path and code points:
+//
+//   - byte-order marks (U+FEFF) anywhere
+//   - C0 / DEL / C1 control characters (tab, LF, CR allowed outside ids)
+//   - U+FFFD, i.e. the source bytes were not valid UTF-8
+//   - lead/continuation pairs that look like UTF-8 decoded as Windows-1252
+//   - in identifier fields: invisible format characters and mixed scripts
+//
+// Findings are warnings. They never change checks or the verdict.
+
+package gefverify
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// identifierFields are signing_dict keys compared verbatim downstream.
+var identifierFields = map[string]bool{
+	"agent_id":    true,
+	"record_type": true,
+	"record_id":   true,
+}
+
+// scripts checked for mixing in identifier fields. Common and Inherited
+// (digits, punctuation, combining marks) never count as a script.
+var scripts = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"Latin", unicode.Latin},
+	{"Greek", unicode.Greek},
+	{"Cyrillic", unicode.Cyrillic},
+	{"Armenian", unicode.Armenian},
+	{"Hebrew", unicode.Hebrew},
+	{"Arabic", unicode.Arabic},
+	{"Devanagari", unicode.Devanagari},
+	{"Thai", unicode.Thai},
+	{"Han", unicode.Han},
+	{"Hiragana", unicode.Hiragana},
+	{"Katakana", unicode.Katakana},
+	{"Hangul", unicode.Hangul},
+}
+
+// cp1252High are the characters Windows-1252 puts at 0x80–0x9F. After a
+// UTF-8 lead byte decoded as Latin-1/1252, they mark a mangled sequence.
+const cp1252High = "€‚ƒ„…†‡ˆ‰Š‹ŒŽ‘’“”•–—˜™š›œžŸ"
+
+// HygieneFinding is one suspicious character (or pair) in a string value.
+type HygieneFinding struct {
+	Path       string // e.g. "signing_dict.payload.items[2]"
+	Issue      string
+	CodePoints string // e.g. "U+FEFF", "U+00C3 U+00A9"
+	Offset     int    // rune index within the string
+}
+
+func (f HygieneFinding) String() string {
+	if f.CodePoints == "" {
+		return f.Path + ": " + f.Issue
+	}
+	return fmt.Sprintf("%s: %s %s at rune %d", f.Path, f.Issue, f.CodePoints, f.Offset)
+}
+
+// ScanStrings walks v (a decoded JSON value) and returns every hygiene
+// finding, in deterministic order. root names v in the reported paths.
+func ScanStrings(v interface{}, root string) []HygieneFinding {
+	var findings []HygieneFinding
+	walkStrings(v, root, false, &findings)
+	return findings
+}
+
+func walkStrings(v interface{}, path string, identifier bool, out *[]HygieneFinding) {
+	switch val := v.(type) {
+	case string:
+		*out = append(*out, scanString(val, path, identifier)...)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			// Keys are strings too; a BOM in a key is just as invisible.
+			*out = append(*out, scanString(k, path+"{key "+k+"}", false)...)
+			isID := !strings.Contains(path, ".") && identifierFields[k]
+			walkStrings(val[k], path+"."+k, isID, out)
+		}
+	case []interface{}:
+		for i, e := range val {
+			walkStrings(e, fmt.Sprintf("%s[%d]", path, i), false, out)
+		}
+	}
+}
+
+func scanString(s, path string, identifier bool) []HygieneFinding {
+	var findings []HygieneFinding
+	add := func(issue string, offset int, rs ...rune) {
+		cps := make([]string, len(rs))
+		for i, r := range rs {
+			cps[i] = fmt.Sprintf("U+%04X", r)
+		}
+		findings = append(findings, HygieneFinding{path, issue, strings.Join(cps, " "), offset})
+	}
+
+	runes := []rune(s)
+	seen := map[string]bool{}
+	for i, r := range runes {
+		switch {
+		case r == '\uFEFF':
+			add("byte-order mark", i, r)
+		case r == '\uFFFD':
+			add("replacement character (source was not valid UTF-8)", i, r)
+		case r < 0x20 || r == 0x7F:
+			if identifier || (r != '\t' && r != '\n' && r != '\r') {
+				add("C0/DEL control character", i, r)
+			}
+		case r >= 0x80 && r <= 0x9F:
+			add("C1 control character", i, r)
+		case identifier && unicode.Is(unicode.Cf, r):
+			add("invisible format character", i, r)
+		}
+
+		if r >= 0xC2 && r <= 0xF4 && i+1 < len(runes) {
+			next := runes[i+1]
+			if (next >= 0x80 && next <= 0xBF) || strings.ContainsRune(cp1252High, next) {
+				add("looks like UTF-8 decoded as Windows-1252", i, r, next)
+			}
+		}
+
+		if identifier && unicode.IsLetter(r) {
+			for _, sc := range scripts {
+				if unicode.Is(sc.table, r) {
+					seen[sc.name] = true
+					break
+				}
+			}
+		}
+	}
+
+	if len(seen) > 1 {
+		names := make([]string, 0, len(seen))
+		for n := range seen {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		findings = append(findings, HygieneFinding{
+			Path:  path,
+			Issue: "mixed scripts in identifier (" + strings.Join(names, "+") + ")",
+		})
+	}
+	return findings
+}
@@ -0,0 +1,63 @@
+// cross_lang_proof/pkg/gefverify/hygiene_test.go
+
+package gefverify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScanStrings(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  []string // substrings, one per expected finding, in order
+	}{
+		{"clean", map[string]interface{}{"agent_id": "agent-7", "payload": map[string]interface{}{"msg": "héllo wörld\n"}}, nil},
+		{"bom in agent_id", map[string]interface{}{"agent_id": "\uFEFFagent-7"}, []string{"sd.agent_id: byte-order mark U+FEFF at rune 0"}},
+		{"c1 in payload", map[string]interface{}{"payload": []interface{}{"ok", "a\u0085b"}}, []string{"sd.payload[1]: C1 control character U+0085 at rune 1"}},
+		{"newline in identifier", map[string]interface{}{"record_type": "tool\n"}, []string{"C0/DEL control character U+000A"}},
+		{"newline in payload", map[string]interface{}{"payload": "line1\nline2"}, nil},
+		{"cp1252 mojibake", map[string]interface{}{"payload": "donâ€™t"}, []string{"looks like UTF-8 decoded as Windows-1252 U+00E2 U+20AC"}},
+		{"latin1 mojibake", map[string]interface{}{"payload": "cafÃ©"}, []string{"U+00C3 U+00A9 at rune 3"}},
+		{"replacement char", map[string]interface{}{"payload": "bad\uFFFD"}, []string{"replacement character"}},
+		{"zero-width in identifier", map[string]interface{}{"record_id": "rec\u200B1"}, []string{"invisible format character U+200B"}},
+		{"zero-width joiner in payload", map[string]interface{}{"payload": "👩\u200D💻"}, nil},
+		{"mixed scripts", map[string]interface{}{"agent_id": "p\u0430ypal"}, []string{"sd.agent_id: mixed scripts in identifier (Cyrillic+Latin)"}},
+		{"mixed scripts outside identifiers", map[string]interface{}{"payload": "p\u0430ypal"}, nil},
+		{"nested agent_id is not an identifier", map[string]interface{}{"payload": map[string]interface{}{"agent_id": "p\u0430ypal"}}, nil},
+		{"bom in key", map[string]interface{}{"payload": map[string]interface{}{"\uFEFFk": 1.0}}, []string{"sd.payload{key \uFEFFk}: byte-order mark"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := ScanStrings(tt.value, "sd")
+			if len(findings) != len(tt.want) {
+				t.Fatalf("got %d findings %v, want %d", len(findings), findings, len(tt.want))
+			}
+			for i, f := range findings {
+				if !strings.Contains(f.String(), tt.want[i]) {
+					t.Errorf("finding %d = %q, want it to contain %q", i, f, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHygieneNeverChangesVerdict(t *testing.T) {
+	bundle := loadProofBundle(t)
+	plain, err := NewVerifier().Verify(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanned, err := NewVerifier(WithHygiene(true)).Verify(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scanned.Verdict != plain.Verdict || scanned.Total() != plain.Total() {
+		t.Errorf("hygiene changed outcome: %s/%d vs %s/%d",
+			scanned.Verdict, scanned.Total(), plain.Verdict, plain.Total())
+	}
+	if len(scanned.Warnings) != 0 {
+		t.Errorf("unexpected warnings on the reference bundle: %v", scanned.Warnings)
+	}
+}
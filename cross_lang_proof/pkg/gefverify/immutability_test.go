@@ -0,0 +1,170 @@
+// cross_lang_proof/pkg/gefverify/immutability_test.go
+
+package gefverify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// immutabilityCase is one call into the package and every input it is
+// handed, bundle maps, byte slices and option structures alike.
+type immutabilityCase struct {
+	name   string
+	inputs []interface{}
+	run    func()
+}
+
+// fingerprint hashes v with its types, so a float64 replaced by an int
+// of the same value still counts as a change. Map keys print sorted.
+func fingerprint(v interface{}) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%#v", v)))
+	return hex.EncodeToString(sum[:])
+}
+
+// everyOption turns on every policy, with limits and exceptions that
+// fail and accept checks on the reference bundle.
+func everyOption(t *testing.T) VerifyOptions {
+	t.Helper()
+	aliases, err := ParseFieldAliases([]byte(`{"1.0": {"nonce": "record_nonce"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return VerifyOptions{
+		RejectWeakKeys: true,
+		Freshness:      time.Minute,
+		ReferenceTime:  time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC),
+		Hygiene:        true,
+		SizeLimits:     SizeLimitsStrict,
+		SizeLimitTable: SizeLimitTable{"1.0": {Payload: 16, Identifier: 8}},
+		FieldAliases:   aliases,
+		TraceFields:    true,
+		Recursive:      true,
+		MaxDepth:       1,
+		Exceptions: []PolicyException{{
+			ID: "EX-immutable", Check: "P.freshness", Expires: time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC),
+			Reason: "archived records", Approver: "sec-oncall",
+		}},
+	}
+}
+
+func immutabilityCases(t *testing.T) []immutabilityCase {
+	t.Helper()
+	data, err := os.ReadFile("../../proof_bundle.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reference := loadProofBundle(t)
+	opts := everyOption(t)
+
+	tampered := loadProofBundle(t)
+	tampered.SigningDict["record_type"] = "tampered"
+	flipped := loadProofBundle(t)
+	flipped.SignatureB64URL = strings.Repeat("A", len(flipped.SignatureB64URL))
+
+	inner := signedBundle(t, 1, map[string]interface{}{"msg": "hello"})
+	innerJSON, err := json.Marshal(inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	middle := signedBundle(t, 2, string(innerJSON))
+	outer := signedBundle(t, 3, asPayload(t, middle))
+
+	pub, body, sig := detachedInput(t)
+	pretty := json.RawMessage(strings.Replace(string(body), ",", ",\n  ", -1))
+
+	_, built, err := NewRecord("agent-7", "genesis").Genesis().Finalize(builderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope := []byte(built.EnvelopeJSON)
+
+	return []immutabilityCase{
+		{"reference, every option", []interface{}{reference, opts}, func() {
+			Verify(reference, opts)
+		}},
+		{"tampered signing_dict", []interface{}{tampered, opts}, func() {
+			Verify(tampered, VerifyOptions{})
+			Verify(tampered, opts)
+		}},
+		{"flipped signature", []interface{}{flipped}, func() {
+			Verify(flipped, VerifyOptions{RejectWeakKeys: true})
+		}},
+		{"nested, object and string payloads", []interface{}{outer, opts}, func() {
+			Verify(outer, VerifyOptions{Recursive: true})
+			Verify(outer, opts)
+		}},
+		{"detached", []interface{}{pub, body, pretty, sig}, func() {
+			for _, canonicalBody := range []bool{false, true} {
+				v := NewVerifier(WithCanonicalBody(canonicalBody))
+				v.VerifyDetached(pub, body, sig)
+				v.VerifyDetached(pub, pretty, sig)
+			}
+		}},
+		{"carried", []interface{}{pub, body, pretty, sig}, func() {
+			NewVerifier().VerifyCarried(pub, body, sig, carriedEncode)
+			NewVerifier().VerifyCarried(pub, pretty, sig, carriedEncode)
+		}},
+		{"envelope", []interface{}{envelope, opts}, func() {
+			b, err := BundleFromEnvelope(envelope)
+			if err == nil {
+				Verify(b, opts)
+			}
+		}},
+		{"parse, verify bytes and lint", []interface{}{data, opts}, func() {
+			ParseBundle(data)
+			VerifyBytes(data, opts)
+			Lint(data)
+		}},
+	}
+}
+
+func TestVerificationNeverMutatesInputs(t *testing.T) {
+	for _, c := range immutabilityCases(t) {
+		t.Run(c.name, func(t *testing.T) {
+			before := make([]string, len(c.inputs))
+			for i, in := range c.inputs {
+				before[i] = fingerprint(in)
+			}
+			c.run()
+			for i, in := range c.inputs {
+				if fingerprint(in) != before[i] {
+					t.Errorf("input %d (%T) modified", i, in)
+				}
+			}
+		})
+	}
+}
+
+// Run with -race: a write to any input while another goroutine reads it
+// fails the test.
+func TestConcurrentReadsDuringVerification(t *testing.T) {
+	for _, c := range immutabilityCases(t) {
+		t.Run(c.name, func(t *testing.T) {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for i := 0; i < 10; i++ {
+					c.run()
+				}
+			}()
+			for reading := true; reading; {
+				select {
+				case <-done:
+					reading = false
+				default:
+					for _, in := range c.inputs {
+						fingerprint(in)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// cross_lang_proof/pkg/gefverify/keys.go
+//
+// Key and signature decoding
+// ==========================
+//
+// Public keys travel as raw 32-byte Ed25519 keys in hex; signatures as
+// base64url, as stored in the JSONL ledger.
+//
+// Small-order Ed25519 public keys
+// -------------------------------
+//
+// The Ed25519 curve has 8 points of small order (1, 2, 4 or 8). A public
+// key equal to one of them provides no security: with a crafted signature
+// (R small-order, S = 0) it "verifies" for many messages, because the
+// verification equation collapses to a small-order identity.
+//
+// crypto/ed25519 does not reject these keys, so WithRejectWeakKeys checks
+// the raw 32-byte encoding against this blocklist BEFORE verification.
+// Encodings are the canonical little-endian forms (same list as libsodium).
+
+package gefverify
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// DecodePublicKey decodes a raw 32-byte Ed25519 public key from hex.
+func DecodePublicKey(pubHex string) (ed25519.PublicKey, error) {
+	pubKeyBytes, err := hex.DecodeString(pubHex)
+	if err != nil {
+		return nil, err
+	}
+	if len(pubKeyBytes) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("got %d bytes, expected %d",
+			len(pubKeyBytes), ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(pubKeyBytes), nil
+}
+
+// ShortHex is the first n characters of s for display, or all of s if
+// it is shorter: bundle values are untrusted and may be truncated.
+func ShortHex(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// KeyFingerprint identifies pub, the Bytes of a PublicKey, in reports:
+// "sha256:" and the hex SHA-256 of those bytes. For Ed25519 they are the
+// raw 32 key bytes, for P-256 the uncompressed SEC1 point however the
+// key was written.
+func KeyFingerprint(pub []byte) string {
+	sum := sha256.Sum256(pub)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// DecodeSignature decodes a base64url Ed25519 signature: unpadded, as
+// emit_proof.py and the builder write it, or with exactly the padding
+// RFC 4648 §5 requires.
+func DecodeSignature(sigB64 string) ([]byte, error) {
+	sigBytes, err := decodeBase64URL(sigB64)
+	if err != nil {
+		return nil, err
+	}
+	if len(sigBytes) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("got %d bytes, expected %d",
+			len(sigBytes), ed25519.SignatureSize)
+	}
+	return sigBytes, nil
+}
+
+// decodeBase64URL decodes s as unpadded base64url, or as padded if it
+// ends in "=". Partial padding, and trailing bits that are not zero, are
+// rejected, so that each value has one spelling of each form.
+func decodeBase64URL(s string) ([]byte, error) {
+	enc := base64.RawURLEncoding
+	if strings.HasSuffix(s, "=") {
+		enc = base64.URLEncoding
+	}
+	return enc.Strict().DecodeString(s)
+}
+
+var smallOrderPublicKeysHex = []string{
+	"0100000000000000000000000000000000000000000000000000000000000000", // order 1 (identity)
+	"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f", // order 2
+	"0000000000000000000000000000000000000000000000000000000000000000", // order 4
+	"0000000000000000000000000000000000000000000000000000000000000080", // order 4
+	"26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc05", // order 8
+	"26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85", // order 8
+	"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a", // order 8
+	"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac03fa", // order 8
+}
+
+// IsWeakPublicKey reports whether pub is one of the 8 small-order points.
+func IsWeakPublicKey(pub ed25519.PublicKey) bool {
+	for _, h := range smallOrderPublicKeysHex {
+		weak, _ := hex.DecodeString(h)
+		if bytes.Equal(pub, weak) {
+			return true
+		}
+	}
+	return false
+}
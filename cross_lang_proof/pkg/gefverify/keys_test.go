@@ -0,0 +1,106 @@
+// cross_lang_proof/pkg/gefverify/keys_test.go
+
+package gefverify
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeSignaturePadding(t *testing.T) {
+	// emit_proof.py writes base64.urlsafe_b64encode(sig).rstrip(b"="):
+	// 86 characters, no padding.
+	const unpadded = "BcOedBWG3o6b2c0vvgIakmJn7DmPfXQb8mRkStiik6Bdu3ckCCmr-ct7cI9YX5iukSqDkb8f8VTpxFnTFpsUCw"
+	want, _ := hex.DecodeString(loadProofBundle(t).SignatureHex)
+	for _, s := range []string{unpadded, unpadded + "=="} {
+		got, err := DecodeSignature(s)
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("%q: %x, %v", s, got, err)
+		}
+	}
+	for _, s := range []string{
+		unpadded + "=",                      // partial padding
+		unpadded + "===",                    // too much padding
+		unpadded[:85] + "x",                 // non-zero trailing bits: a second spelling
+		unpadded[:40] + "=" + unpadded[40:], // padding inside
+	} {
+		if _, err := DecodeSignature(s); err == nil {
+			t.Errorf("%q decoded", s)
+		}
+	}
+}
+
+// weakKeyBundle is a bundle under the identity point, pub = 0100…00. Its
+// signature, R = identity and S = 0, verifies for every message.
+func weakKeyBundle(t *testing.T) ProofBundle {
+	t.Helper()
+	pub := smallOrderPublicKeysHex[0]
+	b := signedBundle(t, 3, nil)
+	b.SigningDict["signer_public_key"] = pub
+	canonical, err := Canonicalize(b.SigningDict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, _ := hex.DecodeString(pub + strings.Repeat("00", 32))
+	if !ed25519.Verify(mustHex(t, pub), canonical, sig) {
+		t.Fatal("crypto/ed25519 rejects the identity-point signature")
+	}
+	hash := sha256.Sum256(canonical)
+	b.PublicKeyHex = pub
+	b.CanonicalBytesHex = hex.EncodeToString(canonical)
+	b.ChainBytesHex = b.CanonicalBytesHex
+	b.CausalHashOfThis = hex.EncodeToString(hash[:])
+	b.SignatureB64URL = base64.RawURLEncoding.EncodeToString(sig)
+	b.SignatureHex = hex.EncodeToString(sig)
+	return b
+}
+
+func TestRejectWeakKeys(t *testing.T) {
+	for _, h := range smallOrderPublicKeysHex {
+		if !IsWeakPublicKey(mustHex(t, h)) {
+			t.Errorf("%s is not weak", h)
+		}
+	}
+	if IsWeakPublicKey(mustHex(t, loadProofBundle(t).PublicKeyHex)) {
+		t.Error("reference key is weak")
+	}
+
+	b := weakKeyBundle(t)
+	report, err := NewVerifier(WithRejectWeakKeys(true)).Verify(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, ok := checkByID(report, "C3.weak_key")
+	if !ok || c.Passed || c.Details != "weak/low-order public key rejected" {
+		t.Errorf("C3.weak_key %+v", c)
+	}
+	// The signature verifies; CONTRACT 6 sees that it verifies a flipped
+	// message too, and integrity outranks policy.
+	if c, _ := checkByID(report, "C3.signature_go"); !c.Passed {
+		t.Errorf("C3.signature_go %+v", c)
+	}
+	want := []string{"C3.weak_key", "C6.flip_byte", "C6.flip_bit"}
+	if got := failedIDs(report); report.Verdict != VerdictTampered || !reflect.DeepEqual(got, want) {
+		t.Errorf("verdict %s, failed %v, want TAMPERED with %v", report.Verdict, got, want)
+	}
+
+	report, _ = Verify(b, VerifyOptions{})
+	if _, ok := checkByID(report, "C3.weak_key"); ok || report.Verdict != VerdictTampered {
+		t.Errorf("without RejectWeakKeys: verdict %s, failed %v", report.Verdict, failedIDs(report))
+	}
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -0,0 +1,67 @@
+// cross_lang_proof/pkg/gefverify/legacy.go
+//
+// Legacy signing-field profile (gef_version 0.9)
+// ==============================================
+//
+// The pilot emitter that predates GEF-SPEC-1.0 signed an 11-field dict:
+// the ten required fields plus "signature": "", a member the spec later
+// excluded (§5.2). Those signatures are genuine over that form, so under
+// this profile Contracts 4 and 5 expect the extra member rather than
+// reject it:
+//
+//   C4.legacy_signature_member   signature present and ""  (instead of C4.signature_excluded)
+//   C5.field_count               11 fields instead of 10
+//
+// Nothing else changes; the signature is still checked over the
+// canonical bytes of the dict exactly as signed, i.e. the 11-field form.
+//
+// The profile applies when the signed gef_version is LegacyGEFVersion,
+// and with VerifyOptions.LegacySigningField to any other 0.x version.
+// It is refused for 1.0 and later, where the exclusion rule holds: the
+// contracts then run as usual and a note says the profile was refused.
+// A report verified under the profile carries Report.Profile, and the
+// report document and console say so.
+
+package gefverify
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// LegacyGEFVersion selects the legacy signing-field profile by itself.
+const LegacyGEFVersion = "0.9"
+
+// ProfileLegacySigningField is Report.Profile under the legacy profile.
+const ProfileLegacySigningField = "legacy-signing-field"
+
+var preSpecVersion = regexp.MustCompile(`^0\.[0-9]+(\.[0-9]+)*$`)
+
+// legacyProfile reports whether the legacy profile applies to a record
+// signed under version, noting in r when it was asked for and refused.
+func (v *Verifier) legacyProfile(r *run, version string) bool {
+	switch {
+	case version == LegacyGEFVersion:
+		return true
+	case !v.opts.LegacySigningField:
+		return false
+	case preSpecVersion.MatchString(version):
+		return true
+	}
+	r.notes = append(r.notes, fmt.Sprintf(
+		"legacy signing-field profile refused: gef_version %q is not a pre-1.0 version", version))
+	return false
+}
+
+// legacySignatureMember checks the empty signature member the legacy
+// form carries, returning the outcome and the check details.
+func legacySignatureMember(dict map[string]interface{}) (bool, string) {
+	member, ok := dict["signature"]
+	switch {
+	case !ok:
+		return false, `signature member missing — the legacy form signed "signature": ""`
+	case member != "":
+		return false, fmt.Sprintf(`signature member is %s %v, the legacy form signed ""`, jsonType(member), member)
+	}
+	return true, `signed form carries "signature": "" (pre-GEF-SPEC-1.0 emitter)`
+}
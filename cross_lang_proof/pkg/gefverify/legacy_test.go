@@ -0,0 +1,127 @@
+// cross_lang_proof/pkg/gefverify/legacy_test.go
+
+package gefverify
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// pilotBundle is a record as the pre-1.0 pilot emitter signed it: the
+// ten required fields plus "signature": "" under version.
+func pilotBundle(t *testing.T, version string, member interface{}) ProofBundle {
+	t.Helper()
+	b := editedBundle(t, map[string]interface{}{"gef_version": version, "signature": member})
+	b.GEFVersion = version
+	return b
+}
+
+func checkByID(r Report, id string) (CheckResult, bool) {
+	for _, c := range r.Checks {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return CheckResult{}, false
+}
+
+func TestLegacyProfileVerifiesPilotRecords(t *testing.T) {
+	for name, opts := range map[string]VerifyOptions{
+		"0.9 by version": {},
+		"0.9 with flag":  {LegacySigningField: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			report, err := Verify(pilotBundle(t, "0.9", ""), opts)
+			if err != nil || report.Verdict != VerdictVerified {
+				t.Fatalf("err=%v verdict=%s failed=%v", err, report.Verdict, failedIDs(report))
+			}
+			if report.Profile != ProfileLegacySigningField {
+				t.Errorf("profile %q, want %q", report.Profile, ProfileLegacySigningField)
+			}
+			if c, ok := checkByID(report, "C5.field_count"); !ok || c.Name != "signing_dict has exactly 11 fields" {
+				t.Errorf("field count check %+v", c)
+			}
+			if _, ok := checkByID(report, "C4.signature_excluded"); ok {
+				t.Error("C4.signature_excluded ran under the legacy profile")
+			}
+			doc := NewReportDocument(report, "pilot.json", "0.9")
+			if doc.Profile != ProfileLegacySigningField {
+				t.Errorf("report document profile %q", doc.Profile)
+			}
+		})
+	}
+}
+
+func TestLegacyProfileOnlyForPreSpecVersions(t *testing.T) {
+	for _, tc := range []struct {
+		version string
+		flag    bool
+		profile bool
+	}{
+		{"0.8", true, true},
+		{"0.8", false, false},
+		{"1.0", false, false},
+		{"1.0", true, false},
+		{"1.1", true, false},
+		{"0.9-rc", true, false},
+	} {
+		report, err := Verify(pilotBundle(t, tc.version, ""), VerifyOptions{LegacySigningField: tc.flag})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := report.Profile != ""; got != tc.profile {
+			t.Errorf("%s flag=%v: profile applied %v, want %v", tc.version, tc.flag, got, tc.profile)
+		}
+		if tc.profile {
+			continue
+		}
+		// Refused or not asked for: the exclusion rule holds as for 1.0.
+		if report.OK() {
+			t.Errorf("%s flag=%v: 11-field record verified outside the legacy profile", tc.version, tc.flag)
+		}
+		refused := strings.Contains(strings.Join(report.Notes, "\n"), "legacy signing-field profile refused")
+		if refused != tc.flag {
+			t.Errorf("%s flag=%v: refusal noted %v, notes %v", tc.version, tc.flag, refused, report.Notes)
+		}
+	}
+}
+
+func TestLegacyProfileKeepsChecksStrict(t *testing.T) {
+	for name, b := range map[string]ProofBundle{
+		"non-empty member": pilotBundle(t, "0.9", "c2ln"),
+		"array member":     editedBundle(t, map[string]interface{}{"gef_version": "0.9", "signature": []interface{}{}}),
+		"member missing":   editedBundle(t, map[string]interface{}{"gef_version": "0.9"}),
+	} {
+		b.GEFVersion = "0.9"
+		report, err := Verify(b, VerifyOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c, ok := checkByID(report, "C4.legacy_signature_member"); !ok || c.Passed {
+			t.Errorf("%s: C4.legacy_signature_member %+v", name, c)
+		}
+		if report.OK() {
+			t.Errorf("%s: verified", name)
+		}
+	}
+}
+
+func TestLintAcceptsPilotRecordsWithWarning(t *testing.T) {
+	data, err := json.Marshal(pilotBundle(t, "0.9", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var legacy []LintFinding
+	for _, f := range Lint(data) {
+		switch {
+		case f.CheckID == "C4.legacy_signature_member":
+			legacy = append(legacy, f)
+		case strings.HasPrefix(f.CheckID, "C4.") || strings.HasPrefix(f.CheckID, "C5."):
+			t.Errorf("pilot record flagged: %+v", f)
+		}
+	}
+	if len(legacy) != 1 || legacy[0].Severity != LintWarning {
+		t.Errorf("legacy findings %+v, want one warning", legacy)
+	}
+}
@@ -0,0 +1,476 @@
+// cross_lang_proof/pkg/gefverify/lint.go
+//
+// Structure-only lint
+// ===================
+//
+//   findings := gefverify.Lint(bundleJSON)
+//   verify_proof lint <bundle.json>
+//
+// For emitter authors who want feedback on bundle structure before their
+// signatures are right. Lint runs every check that needs no key and no
+// hash — the lint profile of the check registry (CheckSpec.Lint) — and
+// reports every finding at once instead of stopping at the first:
+//
+//   JSON        UTF-8, no BOM, one value, no duplicate keys (L.json_syntax,
+//               L.duplicate_key)
+//   fields      presence, JSON type and nullability of the signing_dict
+//               fields and of the bundle fields the verifier reads
+//               (C5.*, L.field_type, L.unknown_field, C4.signature_excluded)
+//   encodings   hex and base64url lengths, lowercase hex, unpadded
+//               base64url (L.hex_format, L.base64_format)
+//   formats     timestamp, record_id, nonce, record_type, sequence and
+//               the genesis link (C9.timestamp_format, L.*)
+//   numbers     integers beyond ±2^53 lose precision in other languages
+//               (L.number_range, warning)
+//
+// Every finding carries a severity, an RFC 6901 JSON Pointer into the
+// bundle and a remediation hint. Lint says nothing about signatures: a
+// bundle without error findings is well-formed, not verified.
+
+package gefverify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SectionLint heads the lint checks in the registry.
+const SectionLint = "LINT — Structure only, signatures NOT verified"
+
+// LintSeverity grades a finding: errors make the bundle structurally
+// invalid, warnings do not.
+type LintSeverity string
+
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+)
+
+// LintFinding is one structural problem in a bundle.
+type LintFinding struct {
+	CheckID  string
+	Severity LintSeverity
+	Pointer  string // RFC 6901, "" for the whole document
+	Message  string
+	Hint     string
+}
+
+// LintErrors counts the error findings.
+func LintErrors(findings []LintFinding) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity == LintError {
+			n++
+		}
+	}
+	return n
+}
+
+// maxSafeInteger is 2^53, the largest integer every JSON reader keeps.
+const maxSafeInteger = 1 << 53
+
+var (
+	lowerHex  = regexp.MustCompile(`^[0-9a-f]*$`)
+	anyHex    = regexp.MustCompile(`^[0-9a-fA-F]*$`)
+	uuidv4    = regexp.MustCompile(`^(gef-)?[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	base64URL = regexp.MustCompile(`^[A-Za-z0-9_-]*=*$`)
+)
+
+// bundleRequired are the ProofBundle fields the verifier reads.
+var bundleRequired = []string{
+	"gef_version", "public_key_hex", "signing_dict", "chain_dict",
+	"chain_bytes_hex", "causal_hash_of_this", "signature_b64url",
+}
+
+type linter struct {
+	findings []LintFinding
+
+	// Key and signature sizes of the bundle's sig_alg; 0 for P-256,
+	// whose encodings vary (sigalg.go).
+	keySize, sigSize int
+}
+
+func (l *linter) error(id, pointer, hint, format string, a ...interface{}) {
+	l.findings = append(l.findings, LintFinding{id, LintError, pointer, fmt.Sprintf(format, a...), hint})
+}
+
+func (l *linter) warn(id, pointer, hint, format string, a ...interface{}) {
+	l.findings = append(l.findings, LintFinding{id, LintWarning, pointer, fmt.Sprintf(format, a...), hint})
+}
+
+// Lint checks the structure of a proof bundle without verifying anything
+// cryptographic, returning every finding.
+func Lint(data []byte) []LintFinding {
+	l := &linter{}
+	switch {
+	case bytes.HasPrefix(data, []byte("\xef\xbb\xbf")):
+		l.error("L.json_syntax", "", "save the file as UTF-8 without a byte order mark", "document starts with a UTF-8 BOM")
+		data = data[3:]
+	case !utf8.Valid(data):
+		l.error("L.json_syntax", "", "emit UTF-8; canonical bytes of other encodings never match", "document is not valid UTF-8")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := l.walk(dec, ""); err != nil {
+		l.error("L.json_syntax", "", "fix the JSON syntax first; nothing else can be checked", "%v", err)
+		return l.findings
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		l.error("L.json_syntax", "", "write exactly one JSON value per bundle file", "data after the top-level value")
+	}
+
+	var bundle map[string]interface{}
+	dec = json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&bundle); err != nil || bundle == nil {
+		l.error("L.field_type", "", "a proof bundle is a JSON object", "top-level value is not an object")
+		return l.findings
+	}
+	l.bundleFields(bundle)
+	if sd, ok := bundle["signing_dict"].(map[string]interface{}); ok {
+		l.signingDict(sd, "/signing_dict")
+		if gv, ok := sd["gef_version"].(string); ok && bundle["gef_version"] != nil && bundle["gef_version"] != gv {
+			l.error("C7.version_binding", "/gef_version", "advertise the gef_version that was signed",
+				"bundle gef_version %v differs from signing_dict.gef_version %q", bundle["gef_version"], gv)
+		}
+		if cd, ok := bundle["chain_dict"].(map[string]interface{}); ok && !reflect.DeepEqual(sd, cd) {
+			l.error("C4.dict_identity", "/chain_dict", "chain_dict must be a copy of signing_dict",
+				"chain_dict differs from signing_dict")
+		}
+	}
+	return l.findings
+}
+
+// walk consumes one JSON value, reporting duplicate keys and numbers
+// outside the safe integer range.
+func (l *linter) walk(dec *json.Decoder, pointer string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			seen := make(map[string]bool)
+			for dec.More() {
+				kt, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key := kt.(string)
+				child := pointer + "/" + escapePointer(key)
+				if seen[key] {
+					l.error("L.duplicate_key", child, "emit each key once; readers disagree on which copy wins",
+						"key %q appears more than once", key)
+				}
+				seen[key] = true
+				if err := l.walk(dec, child); err != nil {
+					return err
+				}
+			}
+		case '[':
+			for i := 0; dec.More(); i++ {
+				if err := l.walk(dec, fmt.Sprintf("%s/%d", pointer, i)); err != nil {
+					return err
+				}
+			}
+		}
+		_, err = dec.Token() // closing delimiter
+		return err
+	case json.Number:
+		if f, err := v.Float64(); err == nil && math.Abs(f) > maxSafeInteger {
+			l.warn("L.number_range", pointer, "send large integers as strings",
+				"%s is beyond ±2^53 and loses precision outside Go and Python", v)
+		}
+	}
+	return nil
+}
+
+// bundleFields checks the top level of the bundle.
+func (l *linter) bundleFields(bundle map[string]interface{}) {
+	l.keySize, l.sigSize = 32, 64
+	if alg, ok := bundle["sig_alg"].(string); ok && alg != SigAlgEd25519 {
+		l.keySize, l.sigSize = 0, 0
+	}
+	for _, f := range bundleRequired {
+		if _, ok := bundle[f]; !ok {
+			l.error("L.field_type", "/"+f, "the verifier reads this field; emit it", "required bundle field %q is missing", f)
+		}
+	}
+	for _, key := range sortedKeys(bundle) {
+		if key == "chain_hash_alg" {
+			l.error("L.unknown_field", "/"+key, "rename it hash_alg", "unknown bundle field %q: the chain hash algorithm is hash_alg", key)
+		} else if !bundleFieldNames[key] {
+			l.warn("L.unknown_field", "/"+escapePointer(key), "remove it or check the spelling", "unknown bundle field %q", key)
+		}
+	}
+	for _, f := range []string{"signing_dict", "chain_dict"} {
+		if v, ok := bundle[f]; ok {
+			if _, isObj := v.(map[string]interface{}); !isObj {
+				l.error("L.field_type", "/"+f, "emit the record as a JSON object", "%s is %s, not an object", f, jsonType(v))
+			}
+		}
+	}
+	for _, f := range []string{"gef_version", "envelope_json", "_description"} {
+		if v, ok := bundle[f]; ok {
+			if _, isStr := v.(string); !isStr {
+				l.error("L.field_type", "/"+f, "emit a JSON string", "%s is %s, not a string", f, jsonType(v))
+			}
+		}
+	}
+	l.hexField(bundle, "public_key_hex", l.keySize)
+	hashSize := sha256.Size
+	if alg, ok := bundle["hash_alg"].(string); ok {
+		hashSize = 0 // unknown: CONTRACT 2 reports it
+		if h, known := DefaultHashAlgorithms[alg]; known {
+			hashSize = h().Size()
+		}
+	}
+	l.hexField(bundle, "causal_hash_of_this", hashSize)
+	l.hexField(bundle, "signature_hex", l.sigSize)
+	l.hexField(bundle, "canonical_bytes_hex", 0)
+	l.hexField(bundle, "chain_bytes_hex", 0)
+	if v, ok := bundle["signature_b64url"]; ok {
+		l.base64URL(v, "/signature_b64url", l.sigSize)
+	}
+	if v, ok := bundle["canonical_bytes_b64"]; ok {
+		s, isStr := v.(string)
+		if _, err := base64.StdEncoding.DecodeString(s); !isStr || err != nil {
+			l.error("L.base64_format", "/canonical_bytes_b64", "encode the canonical bytes with standard padded base64",
+				"canonical_bytes_b64 is not standard base64")
+		}
+	}
+}
+
+// signingDict checks the signed record at pointer.
+func (l *linter) signingDict(sd map[string]interface{}, pointer string) {
+	for _, f := range RequiredFields {
+		if _, ok := sd[f]; !ok {
+			l.error("C5.field_present."+f, pointer+"/"+f, "every GEF record carries all required fields",
+				"required field %q is missing", f)
+		}
+	}
+	if sd["gef_version"] == LegacyGEFVersion {
+		if ok, details := legacySignatureMember(sd); ok {
+			l.warn("C4.legacy_signature_member", pointer+"/signature", "verifies under the legacy signing-field profile only; new records follow GEF-SPEC-1.0",
+				"%s", details)
+		} else {
+			l.error("C4.legacy_signature_member", pointer+"/signature", "gef_version 0.9 records were signed with \"signature\": \"\"",
+				"%s", details)
+		}
+	} else if _, ok := sd["signature"]; ok {
+		l.error("C4.signature_excluded", pointer+"/signature", "the signature is not part of what is signed; move it out of signing_dict",
+			"signing_dict contains signature")
+	}
+	version, _ := sd["gef_version"].(string)
+	spec, _, supported := DefaultVersions.lookup(version)
+	if !supported {
+		l.error("C5.gef_version_supported", pointer+"/gef_version", "sign under a gef_version this verifier knows",
+			"unsupported gef_version %q", version)
+	}
+	optional := make(map[string]bool, len(spec.Optional))
+	for _, f := range spec.Optional {
+		optional[f] = true
+	}
+	for _, key := range sortedKeys(sd) {
+		if !isRequiredField(key) && key != "signature" && !optional[key] {
+			l.error("C5.field_count", pointer+"/"+escapePointer(key), "signing_dict holds exactly the required fields",
+				"unexpected field %q", key)
+		}
+	}
+
+	str := func(f string) (string, bool) {
+		v, ok := sd[f]
+		if !ok {
+			return "", false
+		}
+		s, isStr := v.(string)
+		if !isStr {
+			l.error("L.field_type", pointer+"/"+f, "emit a JSON string", "%s is %s, not a string", f, jsonType(v))
+		}
+		return s, isStr
+	}
+
+	if s, ok := str("agent_id"); ok && s == "" {
+		l.error("L.field_type", pointer+"/agent_id", "name the logical agent", "agent_id is empty")
+	}
+	if s, ok := str("gef_version"); ok && s == "" {
+		l.error("L.field_type", pointer+"/gef_version", "emit the spec version, e.g. \"1.0\"", "gef_version is empty")
+	}
+	if s, ok := str("record_type"); ok {
+		known := false
+		for _, rt := range RecordTypes {
+			known = known || s == rt
+		}
+		if !known {
+			l.error("L.record_type", pointer+"/record_type", "use one of "+strings.Join(RecordTypes, ", "),
+				"record_type %q is not a GEF record type", s)
+		}
+	}
+	if s, ok := str("record_id"); ok && !uuidv4.MatchString(s) {
+		l.warn("L.record_id_format", pointer+"/record_id", "GEF-SPEC §3.1 strongly recommends a UUIDv4; the builder emits \"gef-\" + UUIDv4",
+			"record_id %q is not a UUIDv4", s)
+	}
+	if s, ok := str("nonce"); ok {
+		switch {
+		case !anyHex.MatchString(s):
+			l.warn("L.nonce_format", pointer+"/nonce", "emit 16 or more random bytes as lowercase hex",
+				"nonce is not hex, its entropy cannot be judged")
+		case len(s) < 32:
+			l.error("L.nonce_format", pointer+"/nonce", "emit 16 or more random bytes (32 hex characters)",
+				"nonce has %d bits, at least 128 required", len(s)*4)
+		}
+	}
+	if s, ok := str("timestamp"); ok {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		switch {
+		case err != nil:
+			l.error("C9.timestamp_format", pointer+"/timestamp", "use "+TimestampLayout, "timestamp %q is not RFC 3339", s)
+		case !strings.HasSuffix(s, "Z") || t.Location() != time.UTC:
+			l.error("C9.timestamp_format", pointer+"/timestamp", "convert to UTC and write the zone as Z", "timestamp %q is not UTC with Z", s)
+		}
+	}
+	if s, ok := str("signer_public_key"); ok {
+		l.hexString(s, pointer+"/signer_public_key", l.keySize)
+	}
+	causal, hasCausal := str("causal_hash")
+	if hasCausal {
+		l.hexString(causal, pointer+"/causal_hash", 32)
+	}
+	if v, ok := sd["sequence"]; ok {
+		n, isNum := v.(json.Number)
+		seq, err := n.Int64()
+		switch {
+		case !isNum:
+			l.error("L.field_type", pointer+"/sequence", "emit an integer", "sequence is %s, not a number", jsonType(v))
+		case err != nil || seq < 0:
+			l.error("L.field_type", pointer+"/sequence", "sequence counts from 0 for genesis", "sequence %s is not a non-negative integer", n)
+		case seq == 0 && hasCausal && causal != GenesisHash:
+			l.error("L.genesis_link", pointer+"/causal_hash", "the first record of a chain links to 64 zeros",
+				"sequence 0 with causal_hash %s", causal)
+		}
+	}
+	if v, ok := sd["payload"]; ok && v == nil {
+		l.warn("L.field_type", pointer+"/payload", "send {} for an empty payload", "payload is null")
+	}
+}
+
+// hexField checks a hex string field of the bundle; size 0 means any
+// whole number of bytes.
+func (l *linter) hexField(bundle map[string]interface{}, field string, size int) {
+	v, ok := bundle[field]
+	if !ok {
+		return
+	}
+	s, isStr := v.(string)
+	if !isStr {
+		l.error("L.field_type", "/"+field, "emit a JSON string", "%s is %s, not a string", field, jsonType(v))
+		return
+	}
+	l.hexString(s, "/"+field, size)
+}
+
+func (l *linter) hexString(s, pointer string, size int) {
+	field := pointer[strings.LastIndexByte(pointer, '/')+1:]
+	switch {
+	case !anyHex.MatchString(s) || len(s)%2 != 0:
+		l.error("L.hex_format", pointer, "encode bytes as hex, two characters per byte", "%s is not hex", field)
+	case size > 0 && len(s) != 2*size:
+		l.error("L.hex_format", pointer, fmt.Sprintf("a %d-byte value is %d hex characters", size, 2*size),
+			"%s is %d hex characters, want %d", field, len(s), 2*size)
+	case !lowerHex.MatchString(s):
+		l.warn("L.hex_format", pointer, "emit lowercase hex; string comparisons are case-sensitive", "%s has uppercase hex", field)
+	}
+	if s != "" && len(s)%2 == 0 {
+		if _, err := hex.DecodeString(s); err != nil && anyHex.MatchString(s) {
+			l.error("L.hex_format", pointer, "encode bytes as hex", "%s: %v", field, err)
+		}
+	}
+}
+
+func (l *linter) base64URL(v interface{}, pointer string, size int) {
+	s, isStr := v.(string)
+	switch {
+	case !isStr:
+		l.error("L.field_type", pointer, "emit a JSON string", "value is %s, not a string", jsonType(v))
+	case !base64URL.MatchString(s):
+		l.error("L.base64_format", pointer, "use the URL-safe alphabet (- and _, not + and /)", "not base64url")
+	default:
+		raw, err := decodeBase64URL(s)
+		switch {
+		case err != nil:
+			l.error("L.base64_format", pointer, "encode the raw signature bytes with base64url", "%v", err)
+		case size > 0 && len(raw) != size:
+			l.error("L.base64_format", pointer, fmt.Sprintf("an Ed25519 signature is %d bytes", size), "decodes to %d bytes, want %d", len(raw), size)
+		case strings.HasSuffix(s, "="):
+			l.warn("L.base64_format", pointer, "omit base64 padding; GEF signatures are unpadded", "padded base64url")
+		}
+	}
+}
+
+// bundleFieldNames are the JSON names of ProofBundle, plus the fields
+// emit_proof.py writes that the verifier ignores.
+var bundleFieldNames = func() map[string]bool {
+	names := map[string]bool{"canonical_bytes_b64": true, "expected_results": true}
+	t := reflect.TypeOf(ProofBundle{})
+	for i := 0; i < t.NumField(); i++ {
+		if tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+			names[tag] = true
+		}
+	}
+	return names
+}()
+
+func isRequiredField(f string) bool {
+	for _, r := range RequiredFields {
+		if f == r {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// jsonType names the JSON type of a decoded value.
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case json.Number, float64:
+		return "a number"
+	case string:
+		return "a string"
+	case []interface{}:
+		return "an array"
+	}
+	return "an object"
+}
+
+// escapePointer escapes a key for an RFC 6901 JSON Pointer.
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
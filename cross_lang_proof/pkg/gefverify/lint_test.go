@@ -0,0 +1,122 @@
+// cross_lang_proof/pkg/gefverify/lint_test.go
+
+package gefverify
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func lintReference(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("../../proof_bundle.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestLintReferenceBundleHasNoErrors(t *testing.T) {
+	findings := Lint(lintReference(t))
+	if n := LintErrors(findings); n != 0 {
+		t.Fatalf("%d error(s) on the reference bundle: %+v", n, findings)
+	}
+}
+
+func TestLintReportsEveryFindingAtOnce(t *testing.T) {
+	data := string(lintReference(t))
+	for old, new := range map[string]string{
+		`"sequence": 0`:         `"sequence": -1`,
+		`"record_type": "`:      `"record_type": "x`,
+		`"public_key_hex": "`:   `"public_key_hex": "ab`,
+		`"signature_b64url": "`: `"signature_b64url": "+`,
+	} {
+		if !strings.Contains(data, old) {
+			t.Fatalf("reference bundle has no %s", old)
+		}
+		data = strings.Replace(data, old, new, 1)
+	}
+
+	got := make(map[string]bool)
+	for _, f := range Lint([]byte(data)) {
+		if f.Severity == LintError {
+			got[f.CheckID+" "+f.Pointer] = true
+		}
+	}
+	for _, want := range []string{
+		"L.field_type /signing_dict/sequence",
+		"L.record_type /signing_dict/record_type",
+		"L.hex_format /public_key_hex",
+		"L.base64_format /signature_b64url",
+	} {
+		if !got[want] {
+			t.Errorf("missing finding %s; got %v", want, got)
+		}
+	}
+}
+
+func TestLintDuplicateKeyPointer(t *testing.T) {
+	findings := Lint([]byte(`{"signing_dict": {"a/b": 1, "a/b": 2}}`))
+	for _, f := range findings {
+		if f.CheckID == "L.duplicate_key" {
+			if f.Pointer != "/signing_dict/a~1b" {
+				t.Errorf("pointer = %q", f.Pointer)
+			}
+			return
+		}
+	}
+	t.Fatalf("no L.duplicate_key in %+v", findings)
+}
+
+func TestLintSyntaxErrorStopsEarly(t *testing.T) {
+	findings := Lint([]byte(`{"signing_dict": `))
+	if len(findings) != 1 || findings[0].CheckID != "L.json_syntax" {
+		t.Fatalf("findings = %+v", findings)
+	}
+}
+
+func TestLintFindingsAreRegisteredInTheLintProfile(t *testing.T) {
+	inputs := []string{
+		string(lintReference(t)),
+		"\xef\xbb\xbf{} []",
+		`{"signing_dict": {"signature": "x", "sequence": 9007199254740993, "nonce": "ab"}, "chain_dict": {}, "extra": 1}`,
+	}
+	for _, in := range inputs {
+		for _, f := range Lint([]byte(in)) {
+			spec, ok := LookupCheck(f.CheckID)
+			if !ok || !spec.Lint {
+				t.Errorf("%s is not a registered lint check", f.CheckID)
+			}
+			if f.Hint == "" {
+				t.Errorf("%s at %s has no hint", f.CheckID, f.Pointer)
+			}
+		}
+	}
+}
+
+func TestLintVersionFields(t *testing.T) {
+	data := string(lintReference(t))
+	errorsFor := func(version string) map[string]bool {
+		edited := strings.NewReplacer(
+			`"gef_version": "1.0",`, `"gef_version": "`+version+`",`,
+			`"agent_id": "`, `"key_id": "k-1", "agent_id": "`,
+		).Replace(data)
+		got := make(map[string]bool)
+		for _, f := range Lint([]byte(edited)) {
+			if f.Severity == LintError {
+				got[f.CheckID] = true
+			}
+		}
+		return got
+	}
+	if got := errorsFor("1.1"); got["C5.field_count"] {
+		t.Errorf("key_id rejected under 1.1: %v", got)
+	}
+	if got := errorsFor("1.0"); !got["C5.field_count"] {
+		t.Errorf("key_id accepted under 1.0: %v", got)
+	}
+	if got := errorsFor("2.0"); !got["C5.gef_version_supported"] {
+		t.Errorf("gef_version 2.0 not reported: %v", got)
+	}
+}
@@ -0,0 +1,79 @@
+// cross_lang_proof/pkg/gefverify/merkle.go
+//
+// Merkle root over a record set
+// =============================
+//
+// MerkleRoot commits to a whole log with one hash, for publishing or
+// anchoring. The tree is binary SHA-256 over the records in the order
+// given, with RFC 6962 domain separation so no leaf can pass for an
+// interior node:
+//
+//   leaf      = SHA-256(0x00 || JCS(chain_dict))
+//   interior  = SHA-256(0x01 || left || right)
+//
+// JCS(chain_dict) is the input of the record's chain hash (CONTRACT 2),
+// not the chain hash itself. A level with an odd number of nodes pairs
+// its last node with itself; a single record's root is its leaf hash.
+// In Python:
+//
+//   level = [sha256(b"\x00" + jcs(r["chain_dict"])).digest() for r in records]
+//   while len(level) > 1:
+//       if len(level) % 2:
+//           level.append(level[-1])
+//       level = [sha256(b"\x01" + level[i] + level[i + 1]).digest()
+//                for i in range(0, len(level), 2)]
+//   root = level[0]
+//
+// Duplication makes n and n+1 records (the last one repeated) share a
+// root, so publish the record count beside it.
+
+package gefverify
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// Merkle node prefixes (RFC 6962 §2.1).
+const (
+	merkleLeafPrefix     = 0x00
+	merkleInteriorPrefix = 0x01
+)
+
+// MerkleRoot returns the SHA-256 Merkle root over the canonical chain
+// bytes of records. It fails if records is empty or a chain_dict cannot
+// be canonicalized.
+func MerkleRoot(records []ProofBundle) ([]byte, error) {
+	if len(records) == 0 {
+		return nil, errors.New("gefverify: merkle root of no records")
+	}
+	level := make([][]byte, len(records))
+	for i, b := range records {
+		canonical, err := Canonicalize(b.ChainDict)
+		if err != nil {
+			return nil, fmt.Errorf("gefverify: record %d: %w", i, &MalformedError{"canonicalize chain_dict", err})
+		}
+		level[i] = merkleHash(merkleLeafPrefix, canonical)
+	}
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+		next := make([][]byte, 0, len(level)/2)
+		for i := 0; i < len(level); i += 2 {
+			next = append(next, merkleHash(merkleInteriorPrefix, level[i], level[i+1]))
+		}
+		level = next
+	}
+	return level[0], nil
+}
+
+func merkleHash(prefix byte, parts ...[]byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{prefix})
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,66 @@
+// cross_lang_proof/pkg/gefverify/merkle_test.go
+
+package gefverify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestMerkleRootVectors(t *testing.T) {
+	// Computed in Python from proof_bundle.json's chain_bytes_hex, as in
+	// the merkle.go comment.
+	pb := loadProofBundle(t)
+	for _, tt := range []struct {
+		n    int
+		want string
+	}{
+		{1, "001d54a1b5d264946df3ed80c565362a43280d3f25318801e14d1782e70835d2"},
+		{2, "261941bc8920cee75a03141a69ac8c7d9f0d43030c06b9f20125f73ff65e3528"},
+		{3, "b5f84c01f5ed99e82377f9af3ef21482bde6e77494ff8feae7175a23940f4485"},
+	} {
+		records := make([]ProofBundle, tt.n)
+		for i := range records {
+			records[i] = pb
+		}
+		root, err := MerkleRoot(records)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(root); got != tt.want {
+			t.Errorf("%d record(s): root %s, want %s", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestMerkleRootOddLevel(t *testing.T) {
+	records := []ProofBundle{signedBundle(t, 1, nil), signedBundle(t, 2, nil), signedBundle(t, 3, nil)}
+	var leaves [][]byte
+	for _, r := range records {
+		chain, _ := hex.DecodeString(r.ChainBytesHex)
+		leaf := sha256.Sum256(append([]byte{0x00}, chain...))
+		leaves = append(leaves, leaf[:])
+	}
+	node := func(l, r []byte) []byte {
+		h := sha256.Sum256(append(append([]byte{0x01}, l...), r...))
+		return h[:]
+	}
+	want := node(node(leaves[0], leaves[1]), node(leaves[2], leaves[2]))
+
+	root, err := MerkleRoot(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(root, want) {
+		t.Errorf("root %x, want %x", root, want)
+	}
+	swapped, _ := MerkleRoot([]ProofBundle{records[1], records[0], records[2]})
+	if bytes.Equal(root, swapped) {
+		t.Error("root does not depend on record order")
+	}
+	if _, err := MerkleRoot(nil); err == nil {
+		t.Error("root of no records")
+	}
+}
@@ -0,0 +1,59 @@
+// cross_lang_proof/pkg/gefverify/metrics.go
+//
+// Metrics hooks
+// =============
+//
+// The core never imports a metrics library. Embedders pass a
+// MetricsRecorder via WithMetrics and adapt it to whatever stack they run
+// (Prometheus, OpenCensus, StatsD, ...). Per verification the Verifier
+// calls:
+//
+//   IncCheckResult  once per check in the report  (outcome "pass" | "fail"
+//                   | "excepted" for a failure accepted by an exception)
+//   ObserveDuration once per phase that ran      (see Phase* constants)
+//   IncVerdict      once with the final verdict
+//
+// The default recorder discards everything.
+
+package gefverify
+
+import "time"
+
+// MetricsRecorder receives counters and latencies from a Verifier.
+type MetricsRecorder interface {
+	IncCheckResult(checkID string, outcome string)
+	ObserveDuration(phase string, d time.Duration)
+	IncVerdict(v Verdict)
+}
+
+// Check outcomes passed to IncCheckResult.
+const (
+	OutcomePass     = "pass"
+	OutcomeFail     = "fail"
+	OutcomeExcepted = "excepted"
+)
+
+// Phases passed to ObserveDuration, in execution order.
+const (
+	PhaseCanonicalize   = "canonicalize"
+	PhaseChainHash      = "chain_hash"
+	PhaseSignature      = "signature"
+	PhaseDictIdentity   = "dict_identity"
+	PhaseFieldCount     = "field_count"
+	PhaseNegativeTest   = "negative_test"
+	PhaseVersionBinding = "version_binding"
+	PhaseEnvelope       = "envelope"
+	PhaseTimestamp      = "timestamp"
+	PhaseKeyTrust       = "key_trust"
+	PhaseSignerBinding  = "signer_binding"
+	PhasePolicy         = "policy" // freshness
+	PhaseSizeLimits     = "size_limits"
+	PhaseHygiene        = "hygiene"
+	PhaseTotal          = "total"
+)
+
+type nopMetrics struct{}
+
+func (nopMetrics) IncCheckResult(string, string)          {}
+func (nopMetrics) ObserveDuration(string, time.Duration) {}
+func (nopMetrics) IncVerdict(Verdict)                    {}
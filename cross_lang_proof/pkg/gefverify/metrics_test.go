@@ -0,0 +1,145 @@
+// cross_lang_proof/pkg/gefverify/metrics_test.go
+
+package gefverify
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMetrics is a MetricsRecorder fake that counts every call.
+type recordingMetrics struct {
+	mu       sync.Mutex
+	checks   map[string]int
+	outcomes map[string]string
+	phases   map[string]int
+	verdicts map[Verdict]int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{
+		checks:   make(map[string]int),
+		outcomes: make(map[string]string),
+		phases:   make(map[string]int),
+		verdicts: make(map[Verdict]int),
+	}
+}
+
+func (m *recordingMetrics) IncCheckResult(checkID, outcome string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checks[checkID]++
+	m.outcomes[checkID] = outcome
+}
+
+func (m *recordingMetrics) ObserveDuration(phase string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.phases[phase]++
+}
+
+func (m *recordingMetrics) IncVerdict(v Verdict) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.verdicts[v]++
+}
+
+func loadProofBundle(t *testing.T) ProofBundle {
+	t.Helper()
+	data, err := os.ReadFile("../../proof_bundle.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := ParseBundle(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bundle
+}
+
+func TestMetricsEmittedOncePerVerification(t *testing.T) {
+	m := newRecordingMetrics()
+	v := NewVerifier(
+		WithMetrics(m),
+		WithFreshness(time.Hour),
+		WithSizeLimits(SizeLimitsStrict),
+		WithReferenceTime(time.Date(2026, 2, 25, 0, 0, 0, 0, time.UTC)),
+	)
+
+	report, err := v.Verify(loadProofBundle(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(m.checks) != len(report.Checks) {
+		t.Errorf("got %d distinct check IDs, report has %d checks", len(m.checks), len(report.Checks))
+	}
+	for _, c := range report.Checks {
+		if n := m.checks[c.ID]; n != 1 {
+			t.Errorf("check %s emitted %d times, want 1", c.ID, n)
+		}
+		if m.outcomes[c.ID] != OutcomePass {
+			t.Errorf("check %s outcome = %q, want %q", c.ID, m.outcomes[c.ID], OutcomePass)
+		}
+	}
+
+	for _, phase := range []string{
+		PhaseCanonicalize, PhaseChainHash, PhaseSignature, PhaseDictIdentity,
+		PhaseFieldCount, PhaseNegativeTest, PhaseVersionBinding, PhaseEnvelope,
+		PhaseTimestamp, PhaseKeyTrust, PhaseSignerBinding, PhasePolicy, PhaseSizeLimits,
+		PhaseTotal,
+	} {
+		if n := m.phases[phase]; n != 1 {
+			t.Errorf("phase %s observed %d times, want 1", phase, n)
+		}
+	}
+	if len(m.verdicts) != 1 || m.verdicts[VerdictVerified] != 1 {
+		t.Errorf("verdicts = %v, want exactly one %s", m.verdicts, VerdictVerified)
+	}
+}
+
+func TestMetricsFailureOutcome(t *testing.T) {
+	bundle := loadProofBundle(t)
+	bundle.GEFVersion = "0.9"
+
+	m := newRecordingMetrics()
+	report, err := NewVerifier(WithMetrics(m)).Verify(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.outcomes["C7.version_binding"] != OutcomeFail {
+		t.Errorf("C7.version_binding outcome = %q, want %q", m.outcomes["C7.version_binding"], OutcomeFail)
+	}
+	if m.verdicts[report.Verdict] != 1 || report.Verdict != VerdictTampered {
+		t.Errorf("verdicts = %v, report verdict %s", m.verdicts, report.Verdict)
+	}
+	for _, phase := range []string{PhasePolicy, PhaseSizeLimits} {
+		if _, ok := m.phases[phase]; ok {
+			t.Errorf("%s phase observed although no policy was configured", phase)
+		}
+	}
+}
+
+func TestMetricsMalformed(t *testing.T) {
+	bundle := loadProofBundle(t)
+	bundle.PublicKeyHex = "zz"
+
+	m := newRecordingMetrics()
+	if _, err := NewVerifier(WithMetrics(m)).Verify(bundle); err == nil {
+		t.Fatal("expected error for malformed public key")
+	}
+	if len(m.checks) != 0 {
+		t.Errorf("checks emitted for malformed bundle: %v", m.checks)
+	}
+	if m.verdicts[VerdictMalformed] != 1 || m.phases[PhaseTotal] != 1 {
+		t.Errorf("verdicts = %v, phases = %v", m.verdicts, m.phases)
+	}
+}
+
+func TestNilMetricsIsNoop(t *testing.T) {
+	if _, err := NewVerifier(WithMetrics(nil)).Verify(loadProofBundle(t)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,82 @@
+// cross_lang_proof/pkg/gefverify/mutations_test.go
+
+package gefverify
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRandomMutationsAllRejected(t *testing.T) {
+	opts := VerifyOptions{Mutations: 500, MutationSeed: 42}
+	report, err := Verify(loadProofBundle(t), opts)
+	if err != nil || !report.OK() {
+		t.Fatalf("err=%v failed=%v", err, failedIDs(report))
+	}
+	m := report.Mutations
+	if m == nil || m.Trials != 500 || m.Rejected != 500 || len(m.Accepted) != 0 || m.Seed != 42 {
+		t.Fatalf("stats %+v", m)
+	}
+	c, ok := checkByID(report, "C6.random_mutations")
+	if !ok || !c.Passed || c.Section != SectionNegativeTest || !strings.Contains(c.Details, "not proof") {
+		t.Errorf("check %+v", c)
+	}
+
+	doc := NewReportDocument(report, "proof_bundle.json", "1.0")
+	if doc.Mutations == nil || doc.Mutations.Evidence != "sampling, not proof" || doc.Mutations.Rejected != 500 {
+		t.Errorf("document %+v", doc.Mutations)
+	}
+
+	plain, _ := Verify(loadProofBundle(t), VerifyOptions{})
+	if plain.Mutations != nil {
+		t.Error("mutations sampled without the option")
+	}
+	if _, ok := checkByID(plain, "C6.random_mutations"); ok {
+		t.Error("C6.random_mutations reported without the option")
+	}
+}
+
+// The identity point as key, with R the identity and S = 0, verifies
+// every message under crypto/ed25519: each flip is an accept, which
+// shows what a catastrophic finding records.
+var (
+	identityRaw, _ = hex.DecodeString("01" + strings.Repeat("00", 31))
+	identityKey    = PublicKey{Alg: SigAlgEd25519, Bytes: identityRaw}
+	identitySig, _ = hex.DecodeString("01" + strings.Repeat("00", 63))
+)
+
+func TestRandomMutationsRecordAccepts(t *testing.T) {
+	canonical := []byte(`{"agent_id":"a","payload":{}}`)
+	stats := sampleMutations(identityKey, canonical, identitySig, 20, 7)
+	if stats.Rejected != 0 || len(stats.Accepted) != 20 {
+		t.Fatalf("stats %+v", stats)
+	}
+	for _, a := range stats.Accepted {
+		mutated, _ := hex.DecodeString(a.MutatedHex)
+		want := append([]byte(nil), canonical...)
+		want[a.Position] ^= 1 << a.Bit
+		if !bytes.Equal(mutated, want) || a.Original != canonical[a.Position] {
+			t.Errorf("accept %+v does not describe its mutation", a)
+		}
+	}
+
+	if again := sampleMutations(identityKey, canonical, identitySig, 20, 7); !reflect.DeepEqual(again, stats) {
+		t.Error("same seed sampled different positions")
+	}
+	if other := sampleMutations(identityKey, canonical, identitySig, 20, 8); reflect.DeepEqual(other.Accepted, stats.Accepted) {
+		t.Error("different seeds sampled the same positions")
+	}
+
+	r := newRun()
+	r.mutationCheck(stats)
+	c := r.checks[0]
+	if c.Passed || c.Category != CategoryIntegrity || len(c.Diagnostics) != 40 {
+		t.Errorf("check %+v", c)
+	}
+	if !strings.Contains(strings.Join(c.Diagnostics, "\n"), stats.Accepted[0].MutatedHex) {
+		t.Error("mutated bytes not dumped in full")
+	}
+}
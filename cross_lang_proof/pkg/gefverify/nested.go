@@ -0,0 +1,125 @@
+// cross_lang_proof/pkg/gefverify/nested.go
+//
+// Nested bundles (optional, VerifyOptions.Recursive)
+// ==================================================
+//
+// A forwarded record carries another proof bundle as its payload, either
+// as a JSON object or as a JSON string holding one. With Recursive set,
+// once the outer bundle's contracts have run, a payload that looks like a
+// bundle is verified with the same options and its Report is attached to
+// the outer one as a NestedReport — recursively, which yields a tree.
+//
+// Every nested bundle adds one check to its parent; the check fails in
+// the category matching the nested verdict, so a tampered inner record
+// makes the outer verdict TAMPERED too. Nesting deeper than MaxDepth is
+// not followed and fails as UNVERIFIABLE.
+
+package gefverify
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SectionNested is the heading for nested-bundle checks.
+const SectionNested = "NESTED — Forwarded Bundles in payload"
+
+// DefaultMaxDepth is the nesting limit when VerifyOptions.MaxDepth is 0.
+const DefaultMaxDepth = 4
+
+// NestedReport is the result of verifying a bundle found inside another.
+type NestedReport struct {
+	// Path locates the nested bundle from the top-level bundle, e.g.
+	// "signing_dict.payload.signing_dict.payload" at depth 2.
+	Path       string
+	Depth      int
+	GEFVersion string // as advertised by the nested bundle
+	// Report is the nested verification; Err is set if it could not
+	// finish (its Report then holds whatever did run).
+	Report Report
+	Err    error
+}
+
+// bundleKeys must all be present for a payload to count as a bundle.
+var bundleKeys = []string{"signing_dict", "public_key_hex", "signature_b64url"}
+
+// nestedBundle returns the bundle carried by payload, if it looks like one.
+func nestedBundle(payload interface{}) (ProofBundle, bool, error) {
+	var raw []byte
+	switch p := payload.(type) {
+	case map[string]interface{}:
+		for _, k := range bundleKeys {
+			if _, ok := p[k]; !ok {
+				return ProofBundle{}, false, nil
+			}
+		}
+		var err error
+		if raw, err = json.Marshal(p); err != nil {
+			return ProofBundle{}, true, err
+		}
+	case string:
+		var probe map[string]interface{}
+		if json.Unmarshal([]byte(p), &probe) != nil {
+			return ProofBundle{}, false, nil
+		}
+		return nestedBundle(probe)
+	default:
+		return ProofBundle{}, false, nil
+	}
+	bundle, err := ParseBundle(raw)
+	return bundle, true, err
+}
+
+// verdictCategory is the failure category that reproduces v in a parent.
+func verdictCategory(v Verdict) Category {
+	for _, rule := range verdictRules {
+		if rule.verdict == v {
+			return rule.category
+		}
+	}
+	return CategoryIntegrity
+}
+
+func (v *Verifier) maxDepth() int {
+	if v.opts.MaxDepth > 0 {
+		return v.opts.MaxDepth
+	}
+	return DefaultMaxDepth
+}
+
+// nested verifies a bundle nested in bundle's payload, if there is one.
+func (v *Verifier) nested(r *run, bundle ProofBundle, path string, depth int) {
+	inner, found, err := nestedBundle(bundle.SigningDict["payload"])
+	if !found {
+		return
+	}
+	if path != "" {
+		path += "."
+	}
+	path += "signing_dict.payload"
+	r.section = SectionNested
+	id := fmt.Sprintf("N%d.nested_bundle", depth)
+
+	if depth > v.maxDepth() {
+		r.check(id, CategoryCompleteness, "nested bundle within depth limit", false,
+			fmt.Sprintf("depth %d exceeds limit %d at %s — not verified", depth, v.maxDepth(), path))
+		return
+	}
+
+	nr := NestedReport{Path: path, Depth: depth, GEFVersion: inner.GEFVersion, Err: err}
+	if err == nil {
+		nr.Report, nr.Err = v.verifyAt(newRun(), inner, path, depth)
+	}
+	if nr.Err != nil {
+		nr.Report.Verdict = VerdictMalformed
+	}
+	r.nested = append(r.nested, nr)
+
+	details := fmt.Sprintf("%s  verdict=%s  (%d/%d checks)",
+		path, nr.Report.Verdict, nr.Report.Passed(), nr.Report.Total())
+	if nr.Err != nil {
+		details = fmt.Sprintf("%s  %v", path, nr.Err)
+	}
+	r.check(id, verdictCategory(nr.Report.Verdict), "nested bundle verifies",
+		nr.Err == nil && nr.Report.OK(), details)
+}
@@ -0,0 +1,138 @@
+// cross_lang_proof/pkg/gefverify/nested_test.go
+
+package gefverify
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// signedBundle builds a valid proof bundle around payload, signed with a
+// key derived from seed.
+func signedBundle(t *testing.T, seed byte, payload interface{}) ProofBundle {
+	t.Helper()
+	priv := ed25519.NewKeyFromSeed([]byte(strings.Repeat(string(rune('a'+seed%26)), 32)))
+	pubHex := hex.EncodeToString(priv.Public().(ed25519.PublicKey))
+
+	sd := map[string]interface{}{
+		"agent_id":          "agent-nested",
+		"causal_hash":       strings.Repeat("0", 64),
+		"gef_version":       "1.0",
+		"nonce":             "00112233445566778899aabbccddeeff",
+		"payload":           payload,
+		"record_id":         "rec-1",
+		"record_type":       "forward",
+		"sequence":          0.0,
+		"signer_public_key": pubHex,
+		"timestamp":         "2026-02-24T12:00:00.000Z",
+	}
+	canonical, err := Canonicalize(sd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := ed25519.Sign(priv, canonical)
+	hash := sha256.Sum256(canonical)
+	return ProofBundle{
+		GEFVersion:        "1.0",
+		PublicKeyHex:      pubHex,
+		SigningDict:       sd,
+		CanonicalBytesHex: hex.EncodeToString(canonical),
+		ChainDict:         sd,
+		ChainBytesHex:     hex.EncodeToString(canonical),
+		CausalHashOfThis:  hex.EncodeToString(hash[:]),
+		SignatureB64URL:   base64.RawURLEncoding.EncodeToString(sig),
+		SignatureHex:      hex.EncodeToString(sig),
+	}
+}
+
+// asPayload turns a bundle into the JSON object a forwarding record embeds.
+func asPayload(t *testing.T, b ProofBundle) map[string]interface{} {
+	t.Helper()
+	raw, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestRecursiveVerification(t *testing.T) {
+	inner := signedBundle(t, 1, map[string]interface{}{"msg": "hello"})
+	middle := signedBundle(t, 2, asPayload(t, inner))
+	outer := signedBundle(t, 3, asPayload(t, middle))
+
+	report, err := Verify(outer, VerifyOptions{Recursive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() || report.Verdict != VerdictVerified {
+		t.Fatalf("verdict %s, failed %v", report.Verdict, report.Failed())
+	}
+	if len(report.Nested) != 1 || len(report.Nested[0].Report.Nested) != 1 {
+		t.Fatalf("want a 2-level tree, got %+v", report.Nested)
+	}
+	deepest := report.Nested[0].Report.Nested[0]
+	if deepest.Depth != 2 || deepest.Path != "signing_dict.payload.signing_dict.payload" {
+		t.Errorf("deepest = depth %d path %q", deepest.Depth, deepest.Path)
+	}
+
+	plain, err := Verify(outer, VerifyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plain.Nested) != 0 {
+		t.Error("nested bundles verified without Recursive")
+	}
+}
+
+func TestRecursiveTamperedInnerTaintsOuter(t *testing.T) {
+	inner := signedBundle(t, 1, "original")
+	payload := asPayload(t, inner)
+	payload["signing_dict"].(map[string]interface{})["payload"] = "forged"
+	outer := signedBundle(t, 3, payload)
+
+	report, err := Verify(outer, VerifyOptions{Recursive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Verdict != VerdictTampered {
+		t.Errorf("verdict = %s, want %s", report.Verdict, VerdictTampered)
+	}
+}
+
+func TestRecursiveDepthLimit(t *testing.T) {
+	b := signedBundle(t, 0, "leaf")
+	for i := 1; i <= 3; i++ {
+		b = signedBundle(t, byte(i), asPayload(t, b))
+	}
+
+	report, err := Verify(b, VerifyOptions{Recursive: true, MaxDepth: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Verdict != VerdictUnverifiable {
+		t.Errorf("verdict = %s, want %s", report.Verdict, VerdictUnverifiable)
+	}
+}
+
+func TestRecursiveStringPayload(t *testing.T) {
+	raw, err := json.Marshal(signedBundle(t, 1, "leaf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Verify(signedBundle(t, 2, string(raw)), VerifyOptions{Recursive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Nested) != 1 || !report.OK() {
+		t.Errorf("string-encoded nested bundle: nested=%d ok=%v", len(report.Nested), report.OK())
+	}
+}
@@ -0,0 +1,76 @@
+// cross_lang_proof/pkg/gefverify/normalize.go
+//
+// Normalized report documents
+// ===========================
+//
+// Two runs over the same bundle produce the same report except for a few
+// volatile parts. NormalizeReportDocument redacts exactly those, so the
+// rest is what counts as a meaningful change — for golden files
+// (gefverifytest) and for anything else that compares reports:
+//
+//   verifier_version        redacted: a release is not a report change
+//   delta=, reference=      redacted in details and notes: they depend on
+//                           the clock when -freshness runs without -now
+//
+// MarshalNormalizedReport writes the normalized document with sorted
+// keys and two-space indentation, one trailing newline.
+
+package gefverify
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+)
+
+// Redacted replaces every volatile value in a normalized document.
+const Redacted = "<redacted>"
+
+// volatileDetail matches the clock-dependent key=value pairs.
+var volatileDetail = regexp.MustCompile(`\b(delta|reference)=\S+`)
+
+// NormalizeReportDocument returns doc with its volatile parts redacted.
+// doc itself is not modified.
+func NormalizeReportDocument(doc ReportDocument) ReportDocument {
+	doc.VerifierVersion = Redacted
+	doc.Checks = append([]CheckDocument(nil), doc.Checks...)
+	for i := range doc.Checks {
+		doc.Checks[i].Details = redactVolatile(doc.Checks[i].Details)
+	}
+	doc.Notes = append([]string(nil), doc.Notes...)
+	for i := range doc.Notes {
+		doc.Notes[i] = redactVolatile(doc.Notes[i])
+	}
+	doc.Nested = append([]NestedDocument(nil), doc.Nested...)
+	for i := range doc.Nested {
+		doc.Nested[i].Report = NormalizeReportDocument(doc.Nested[i].Report)
+	}
+	return doc
+}
+
+func redactVolatile(s string) string {
+	return volatileDetail.ReplaceAllString(s, "$1="+Redacted)
+}
+
+// MarshalNormalizedReport returns the normalized JSON form of doc.
+func MarshalNormalizedReport(doc ReportDocument) ([]byte, error) {
+	raw, err := json.Marshal(NormalizeReportDocument(doc))
+	if err != nil {
+		return nil, err
+	}
+	// A round trip through interface{} sorts object keys.
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,44 @@
+// cross_lang_proof/pkg/gefverify/normalize_test.go
+
+package gefverify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeReportDocument(t *testing.T) {
+	doc := ReportDocument{
+		SchemaVersion:   ReportSchemaVersion,
+		VerifierVersion: Version,
+		Checks:          []CheckDocument{{ID: "P.freshness", Details: "delta=3m2s  reference=2026-10-14T09:00:00Z"}},
+		Notes:           []string{"chain hash of this record: abc"},
+		Nested: []NestedDocument{{Report: ReportDocument{
+			VerifierVersion: Version,
+			Checks:          []CheckDocument{{ID: "P.freshness", Details: "delta=-1s  reference=x"}},
+		}}},
+	}
+	n := NormalizeReportDocument(doc)
+	if n.VerifierVersion != Redacted || n.Nested[0].Report.VerifierVersion != Redacted {
+		t.Errorf("verifier_version not redacted: %+v", n)
+	}
+	if want := "delta=<redacted>  reference=<redacted>"; n.Checks[0].Details != want {
+		t.Errorf("details = %q, want %q", n.Checks[0].Details, want)
+	}
+	if n.Notes[0] != doc.Notes[0] {
+		t.Errorf("stable note changed: %q", n.Notes[0])
+	}
+	if doc.Checks[0].Details != "delta=3m2s  reference=2026-10-14T09:00:00Z" || doc.VerifierVersion != Version {
+		t.Error("NormalizeReportDocument modified its argument")
+	}
+
+	data, err := MarshalNormalizedReport(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	if !strings.HasSuffix(text, "}\n") || strings.Index(text, `"checks"`) > strings.Index(text, `"schema_version"`) ||
+		strings.Index(text, `"verdict"`) < strings.Index(text, `"total"`) {
+		t.Errorf("keys not sorted or trailing newline missing:\n%s", text)
+	}
+}
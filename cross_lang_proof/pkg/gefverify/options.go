@@ -0,0 +1,219 @@
+// cross_lang_proof/pkg/gefverify/options.go
+//
+// Verification options
+// ====================
+//
+// Everything a verification can be configured with lives in one struct.
+// The zero value is the original cross-language proof: eleven contracts,
+// no policies, no metrics. Embedders either fill in VerifyOptions
+// directly and call Verify / NewVerifierFromOptions, or use the With*
+// functional options with NewVerifier — both end up here.
+//
+// New configuration goes into VerifyOptions first; a With* helper is a
+// convenience on top, never the only way in.
+
+package gefverify
+
+import (
+	"hash"
+	"time"
+)
+
+// VerifyOptions configures a verification. Zero values are defaults.
+// The maps and slices it holds are read, never written, and may be
+// shared between Verifiers and goroutines.
+type VerifyOptions struct {
+	// RejectWeakKeys rejects the 8 small-order Ed25519 public keys
+	// before verifying (see keys.go).
+	RejectWeakKeys bool
+
+	// TrustedKeys are the public keys CONTRACT 10 accepts, optionally
+	// bound to agents (see trust.go). Nil checks no key trust.
+	TrustedKeys []TrustedKey
+
+	// Freshness requires |reference - timestamp| <= Freshness for records
+	// carrying a timestamp and nonce. Zero disables the policy.
+	Freshness time.Duration
+	// MaxClockSkew fails a record whose timestamp is more than
+	// MaxClockSkew after the reference time. Zero disables the policy.
+	MaxClockSkew time.Duration
+	// MaxAge fails a record whose timestamp is more than MaxAge before
+	// the reference time. Zero disables the policy.
+	MaxAge time.Duration
+	// ReferenceTime is the reference for Freshness, MaxClockSkew and
+	// MaxAge. Zero means the system clock at verification time.
+	ReferenceTime time.Time
+
+	// Hygiene scans every string in signing_dict for BOMs, control
+	// characters and encoding damage; findings are warnings only.
+	Hygiene bool
+
+	// SizeLimits enforces the payload and identifier size limits of the
+	// signed gef_version (see sizelimits.go); SizeLimitTable nil means
+	// DefaultSizeLimits.
+	SizeLimits     SizeLimitMode
+	SizeLimitTable SizeLimitTable
+
+	// ExpectedFields are the signing_dict fields Contract 5 requires,
+	// whatever the signed gef_version. Nil means those Versions gives.
+	ExpectedFields []string
+
+	// Versions are the fields of each signed gef_version (see
+	// versions.go). Nil means DefaultVersions.
+	Versions VersionTable
+
+	// ChainHash is the hash of Contract 2 for a bundle without hash_alg,
+	// for spec versions that do not use SHA-256; it must produce at least
+	// 8 bytes. Nil means
+	// sha256.New. VerifyDetached and VerifyCarried derive the expected
+	// chain hash with it; BundleFromEnvelope, which takes no options, always uses
+	// SHA-256.
+	ChainHash func() hash.Hash
+
+	// HashAlgorithms are the chain hashes a bundle may name in hash_alg
+	// (see hashalg.go). Nil means DefaultHashAlgorithms.
+	HashAlgorithms HashAlgorithms
+
+	// FieldAliases are per-version field renames accepted by the
+	// required-field contract. Nil means DefaultFieldAliases.
+	FieldAliases FieldAliases
+
+	// TraceFields always computes the per-field canonicalization trace
+	// (Report.FieldTrace), not only when canonical bytes mismatch.
+	TraceFields bool
+
+	// Recursive verifies a proof bundle carried in payload, and one in
+	// its payload, and so on, up to MaxDepth levels (0 means
+	// DefaultMaxDepth). See nested.go.
+	Recursive bool
+	MaxDepth  int
+
+	// Exceptions accept known failures for a scope of records until
+	// they expire (see exceptions.go). Expiry uses ReferenceTime.
+	Exceptions []PolicyException
+
+	// Mutations adds that many random single-bit flips to Contract 6,
+	// drawn from MutationSeed (see mutations.go). Zero samples none.
+	Mutations    int
+	MutationSeed int64
+
+	// LegacySigningField verifies pre-1.0 records under the legacy
+	// signing-field profile, which gef_version "0.9" selects without it;
+	// refused for 1.0 and later (see legacy.go).
+	LegacySigningField bool
+
+	// CanonicalBody makes VerifyDetached verify the body bytes exactly
+	// as received and require them to already be JCS canonical, instead
+	// of re-canonicalizing them (see detached.go).
+	CanonicalBody bool
+
+	// FullValues puts whole hex strings, hashes and signatures in check
+	// details instead of their first 8 or 16 characters.
+	FullValues bool
+
+	// Metrics receives counters and latencies. Nil discards them.
+	Metrics MetricsRecorder
+}
+
+// Option sets one field of VerifyOptions, for use with NewVerifier.
+type Option func(*VerifyOptions)
+
+// WithOptions replaces every option set so far with o.
+func WithOptions(o VerifyOptions) Option {
+	return func(dst *VerifyOptions) { *dst = o }
+}
+
+// WithRejectWeakKeys sets VerifyOptions.RejectWeakKeys.
+func WithRejectWeakKeys(reject bool) Option {
+	return func(o *VerifyOptions) { o.RejectWeakKeys = reject }
+}
+
+// WithTrustedKeys sets VerifyOptions.TrustedKeys.
+func WithTrustedKeys(keys []TrustedKey) Option {
+	return func(o *VerifyOptions) { o.TrustedKeys = keys }
+}
+
+// WithFreshness sets VerifyOptions.Freshness.
+func WithFreshness(window time.Duration) Option {
+	return func(o *VerifyOptions) { o.Freshness = window }
+}
+
+// WithMaxClockSkew sets VerifyOptions.MaxClockSkew.
+func WithMaxClockSkew(skew time.Duration) Option {
+	return func(o *VerifyOptions) { o.MaxClockSkew = skew }
+}
+
+// WithMaxAge sets VerifyOptions.MaxAge.
+func WithMaxAge(age time.Duration) Option {
+	return func(o *VerifyOptions) { o.MaxAge = age }
+}
+
+// WithReferenceTime sets VerifyOptions.ReferenceTime.
+func WithReferenceTime(t time.Time) Option {
+	return func(o *VerifyOptions) { o.ReferenceTime = t }
+}
+
+// WithHygiene sets VerifyOptions.Hygiene (see hygiene.go).
+func WithHygiene(scan bool) Option {
+	return func(o *VerifyOptions) { o.Hygiene = scan }
+}
+
+// WithSizeLimits enables the size policy in mode.
+func WithSizeLimits(mode SizeLimitMode) Option {
+	return func(o *VerifyOptions) { o.SizeLimits = mode }
+}
+
+// WithExpectedFields sets VerifyOptions.ExpectedFields.
+func WithExpectedFields(fields []string) Option {
+	return func(o *VerifyOptions) { o.ExpectedFields = fields }
+}
+
+// WithVersions sets VerifyOptions.Versions.
+func WithVersions(t VersionTable) Option {
+	return func(o *VerifyOptions) { o.Versions = t }
+}
+
+// WithChainHash sets VerifyOptions.ChainHash.
+func WithChainHash(h func() hash.Hash) Option {
+	return func(o *VerifyOptions) { o.ChainHash = h }
+}
+
+// WithHashAlgorithms sets VerifyOptions.HashAlgorithms.
+func WithHashAlgorithms(t HashAlgorithms) Option {
+	return func(o *VerifyOptions) { o.HashAlgorithms = t }
+}
+
+// WithFieldAliases sets VerifyOptions.FieldAliases (see aliases.go).
+func WithFieldAliases(a FieldAliases) Option {
+	return func(o *VerifyOptions) { o.FieldAliases = a }
+}
+
+// WithExceptions sets VerifyOptions.Exceptions.
+func WithExceptions(e []PolicyException) Option {
+	return func(o *VerifyOptions) { o.Exceptions = e }
+}
+
+// WithMutations sets VerifyOptions.Mutations and MutationSeed.
+func WithMutations(trials int, seed int64) Option {
+	return func(o *VerifyOptions) { o.Mutations, o.MutationSeed = trials, seed }
+}
+
+// WithLegacySigningField sets VerifyOptions.LegacySigningField.
+func WithLegacySigningField(accept bool) Option {
+	return func(o *VerifyOptions) { o.LegacySigningField = accept }
+}
+
+// WithCanonicalBody sets VerifyOptions.CanonicalBody.
+func WithCanonicalBody(assert bool) Option {
+	return func(o *VerifyOptions) { o.CanonicalBody = assert }
+}
+
+// WithFullValues sets VerifyOptions.FullValues.
+func WithFullValues(full bool) Option {
+	return func(o *VerifyOptions) { o.FullValues = full }
+}
+
+// WithMetrics sets VerifyOptions.Metrics.
+func WithMetrics(m MetricsRecorder) Option {
+	return func(o *VerifyOptions) { o.Metrics = m }
+}
@@ -0,0 +1,190 @@
+// cross_lang_proof/pkg/gefverify/record.go
+//
+// Typed GEF record
+// ================
+//
+// The signing_dict travels as a JSON object and ProofBundle keeps it as
+// map[string]interface{}: that map is what the signature covers, and it
+// is canonicalized exactly as decoded. GEFRecord is the same record with
+// the GEF-SPEC-1.0 §3.1 types, for code that reads its fields:
+//
+//   agent_id, causal_hash, gef_version, nonce,    JSON string
+//   record_id, record_type, signer_public_key,
+//   timestamp
+//   sequence                                      JSON integer
+//   payload                                       any JSON value, kept raw
+//
+// FromMap decodes a signing_dict and fails on a missing, unknown or
+// wrongly-typed field; ToSigningDict turns the record back into a map
+// whose canonical bytes are those of the map it came from. CONTRACT 5
+// decodes every signing_dict this way and fails C5.field_type.{field}
+// and C5.unknown_field.{field} for what does not fit.
+
+package gefverify
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// GEFRecord is a signing_dict with typed fields.
+type GEFRecord struct {
+	AgentID         string          `json:"agent_id"`
+	CausalHash      string          `json:"causal_hash"`
+	GEFVersion      string          `json:"gef_version"`
+	Nonce           string          `json:"nonce"`
+	Payload         json.RawMessage `json:"payload"`
+	RecordID        string          `json:"record_id"`
+	RecordType      string          `json:"record_type"`
+	Sequence        int64           `json:"sequence"`
+	SignerPublicKey string          `json:"signer_public_key"`
+	Timestamp       string          `json:"timestamp"`
+}
+
+// FieldError is one signing_dict field that does not fit GEFRecord.
+type FieldError struct {
+	Field   string
+	Problem string // e.g. "missing", "unknown field", "is a number, not a string"
+}
+
+// RecordError lists every field FromMap could not decode, sorted by
+// field name.
+type RecordError struct {
+	Fields []FieldError
+}
+
+func (e *RecordError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + ": " + f.Problem
+	}
+	return "signing_dict does not fit GEFRecord: " + strings.Join(parts, "; ")
+}
+
+// FromMap decodes m, a signing_dict as decoded by encoding/json, into r.
+// The error is a *RecordError naming each missing, unknown or
+// wrongly-typed field; r holds the fields that did decode.
+func (r *GEFRecord) FromMap(m map[string]interface{}) error {
+	var errs []FieldError
+	for _, key := range sortedKeys(m) {
+		if !isRequiredField(key) {
+			errs = append(errs, FieldError{key, "unknown field"})
+		}
+	}
+	for _, f := range RequiredFields {
+		v, ok := m[f]
+		if !ok {
+			errs = append(errs, FieldError{f, "missing"})
+			continue
+		}
+		if problem := r.set(f, v); problem != "" {
+			errs = append(errs, FieldError{f, problem})
+		}
+	}
+	if errs == nil {
+		return nil
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return &RecordError{errs}
+}
+
+// ToSigningDict returns r as a signing_dict. Its canonical bytes are
+// those of the map r was decoded from: payload is embedded as written
+// and JCS normalizes the numbers in it.
+func (r GEFRecord) ToSigningDict() map[string]interface{} {
+	payload := r.Payload
+	if payload == nil {
+		payload = json.RawMessage("null")
+	}
+	return map[string]interface{}{
+		"agent_id":          r.AgentID,
+		"causal_hash":       r.CausalHash,
+		"gef_version":       r.GEFVersion,
+		"nonce":             r.Nonce,
+		"payload":           payload,
+		"record_id":         r.RecordID,
+		"record_type":       r.RecordType,
+		"sequence":          r.Sequence,
+		"signer_public_key": r.SignerPublicKey,
+		"timestamp":         r.Timestamp,
+	}
+}
+
+// set stores v as field f of r, or returns why it cannot.
+func (r *GEFRecord) set(f string, v interface{}) string {
+	switch f {
+	case "payload":
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("cannot be encoded: %v", err)
+		}
+		r.Payload = raw
+		return ""
+	case "sequence":
+		seq, ok := recordInteger(v)
+		if !ok {
+			return fmt.Sprintf("is %s, not an integer", recordValueType(v))
+		}
+		r.Sequence = seq
+		return ""
+	}
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Sprintf("is %s, not a string", recordValueType(v))
+	}
+	*r.stringField(f) = s
+	return ""
+}
+
+func (r *GEFRecord) stringField(f string) *string {
+	switch f {
+	case "agent_id":
+		return &r.AgentID
+	case "causal_hash":
+		return &r.CausalHash
+	case "gef_version":
+		return &r.GEFVersion
+	case "nonce":
+		return &r.Nonce
+	case "record_id":
+		return &r.RecordID
+	case "record_type":
+		return &r.RecordType
+	case "signer_public_key":
+		return &r.SignerPublicKey
+	}
+	return &r.Timestamp
+}
+
+// recordInteger converts a decoded JSON number that is a whole number
+// in int64 range.
+func recordInteger(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	case float64:
+		if n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(n), true
+	}
+	return 0, false
+}
+
+// recordValueType describes v for a FieldError: "a string", "the number
+// 1.5", "null".
+func recordValueType(v interface{}) string {
+	switch n := v.(type) {
+	case float64, json.Number, int, int64:
+		return fmt.Sprintf("the number %v", n)
+	}
+	return jsonType(v)
+}
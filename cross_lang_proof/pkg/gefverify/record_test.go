@@ -0,0 +1,98 @@
+// cross_lang_proof/pkg/gefverify/record_test.go
+
+package gefverify
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGEFRecordCanonicalBytesMatchMap(t *testing.T) {
+	bundles := []ProofBundle{loadProofBundle(t)}
+	for i, payload := range []interface{}{
+		map[string]interface{}{"tool": "déploy", "n": 2.50, "big": 1e21, "nested": []interface{}{" ", nil, true}},
+		[]interface{}{1.0, -0.0, 0.1},
+		"plain",
+		nil,
+	} {
+		bundles = append(bundles, signedBundle(t, byte(i), payload))
+	}
+	for i, b := range bundles {
+		var record GEFRecord
+		if err := record.FromMap(b.SigningDict); err != nil {
+			t.Fatalf("bundle %d: %v", i, err)
+		}
+		fromMap, err := Canonicalize(b.SigningDict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromRecord, err := Canonicalize(record.ToSigningDict())
+		if err != nil {
+			t.Fatalf("bundle %d: %v", i, err)
+		}
+		if !bytes.Equal(fromRecord, fromMap) {
+			t.Errorf("bundle %d: canonical bytes differ\nrecord %s\nmap    %s", i, fromRecord, fromMap)
+		}
+		if got := hex.EncodeToString(fromRecord); got != b.CanonicalBytesHex {
+			t.Errorf("bundle %d: record does not canonicalize to canonical_bytes_hex", i)
+		}
+	}
+}
+
+func TestGEFRecordFromMapErrors(t *testing.T) {
+	sd := loadProofBundle(t).SigningDict
+	sd["sequence"] = 1.5
+	sd["nonce"] = 7.0
+	sd["agent_id"] = nil
+	sd["extra"] = "x"
+	delete(sd, "timestamp")
+
+	var record GEFRecord
+	err := record.FromMap(sd)
+	var recErr *RecordError
+	if !errors.As(err, &recErr) {
+		t.Fatalf("err = %v, want *RecordError", err)
+	}
+	want := []FieldError{
+		{"agent_id", "is null, not a string"},
+		{"extra", "unknown field"},
+		{"nonce", "is the number 7, not a string"},
+		{"sequence", "is the number 1.5, not an integer"},
+		{"timestamp", "missing"},
+	}
+	if !reflect.DeepEqual(recErr.Fields, want) {
+		t.Errorf("fields\n got %v\nwant %v", recErr.Fields, want)
+	}
+	if record.RecordType != "execution" {
+		t.Errorf("fields that decode are not kept: record_type %q", record.RecordType)
+	}
+}
+
+func TestFieldTypeChecks(t *testing.T) {
+	report, _ := Verify(loadProofBundle(t), VerifyOptions{})
+	if c, ok := checkByID(report, "C5.field_types"); !ok || !c.Passed {
+		t.Errorf("reference bundle: C5.field_types %+v", c)
+	}
+
+	b := editedBundle(t, map[string]interface{}{"sequence": "0", "tenant": "acme"})
+	report, _ = Verify(b, VerifyOptions{})
+	for _, id := range []string{"C5.field_type.sequence", "C5.unknown_field.tenant"} {
+		if c, ok := checkByID(report, id); !ok || c.Passed {
+			t.Errorf("%s not failed: %+v", id, c)
+		}
+	}
+	if _, ok := checkByID(report, "C5.field_types"); ok {
+		t.Error("C5.field_types reported beside a failed field type")
+	}
+
+	// An ExpectedFields extra is expected, and not typed.
+	b = editedBundle(t, map[string]interface{}{"tenant": 42.0})
+	fields := append(append([]string(nil), RequiredFields...), "tenant")
+	report, _ = NewVerifier(WithExpectedFields(fields)).Verify(b)
+	if !report.OK() {
+		t.Errorf("tenant field rejected: %v", failedIDs(report))
+	}
+}
@@ -0,0 +1,165 @@
+// cross_lang_proof/pkg/gefverify/report.go
+//
+// Verification report
+// ===================
+//
+// Verification never prints. Every check becomes a CheckResult in a Report
+// and callers decide how to present it (the CLI renders the same console
+// output the monolithic verifier used to print directly).
+
+package gefverify
+
+// CheckResult is the outcome of one check.
+type CheckResult struct {
+	// ID is stable across releases, e.g. "C3.signature_go".
+	ID string
+	// Section is the contract heading the check belongs to.
+	Section string
+	Name    string
+	Passed  bool
+	Details string
+	// Category decides what a failure means for the verdict.
+	Category Category
+	// Exception is the ID of the PolicyException that accepted this
+	// failure. The check stays failed but no longer affects the verdict.
+	Exception string
+	// Diagnostics are extra lines worth showing when the check fails,
+	// e.g. both full hex strings of a canonical-bytes mismatch.
+	Diagnostics []string
+}
+
+// Report is the result of verifying one bundle.
+type Report struct {
+	Checks []CheckResult
+	// Notes are informational lines that are not checks, e.g. a policy
+	// that did not apply to this record.
+	Notes []string
+	// Warnings are hygiene findings (WithHygiene), size-limit excesses
+	// in relaxed mode (WithSizeLimits) and an unknown minor gef_version
+	// (versions.go). They never affect checks or the verdict.
+	Warnings []string
+	// Contracts records, for each of RequiredContracts, whether it ran.
+	Contracts []ContractResult
+	// FieldTrace is the per-field canonical comparison, computed when
+	// canonical bytes mismatch or TraceFields is set.
+	FieldTrace []FieldTrace
+	// Nested holds reports for bundles found in payload (Recursive).
+	Nested []NestedReport
+	// Exceptions lists the failures accepted under a PolicyException.
+	Exceptions []AppliedException
+	// Profile names a compatibility profile the bundle was verified
+	// under, e.g. ProfileLegacySigningField; "" for GEF-SPEC-1.0 as is.
+	Profile string
+	// Mutations is the random mutation sampling of Contract 6, nil
+	// unless VerifyOptions.Mutations is set.
+	Mutations *MutationStats
+	// KeyFingerprint is KeyFingerprint of the bundle's public key, ""
+	// if the key did not decode.
+	KeyFingerprint string
+	// HashAlg and SigAlg name the chain hash and signature algorithm
+	// the bundle was verified under, defaults filled in.
+	HashAlg string
+	SigAlg  string
+	Verdict Verdict
+}
+
+// ContractStatus says whether a required contract ran to completion.
+type ContractStatus string
+
+const (
+	ContractExecuted      ContractStatus = "executed"
+	ContractAborted       ContractStatus = "aborted"        // started, stopped by an error
+	ContractSkipped       ContractStatus = "skipped"        // never started
+	ContractNotApplicable ContractStatus = "not_applicable" // ran; the bundle makes no claim to check
+)
+
+// ContractResult is the execution status of one required contract.
+type ContractResult struct {
+	Section string
+	Status  ContractStatus
+}
+
+// Passed returns the number of passing checks.
+func (r Report) Passed() int {
+	n := 0
+	for _, c := range r.Checks {
+		if c.Passed {
+			n++
+		}
+	}
+	return n
+}
+
+// Total returns the number of checks.
+func (r Report) Total() int { return len(r.Checks) }
+
+// Executed returns how many required contracts ran to completion,
+// counting those that did not apply.
+func (r Report) Executed() int {
+	n := 0
+	for _, c := range r.Contracts {
+		if c.Status.ran() {
+			n++
+		}
+	}
+	return n
+}
+
+func (s ContractStatus) ran() bool {
+	return s == ContractExecuted || s == ContractNotApplicable
+}
+
+// Status returns the status of the required contract section, "" if the
+// report has no record of it.
+func (r Report) Status(section string) ContractStatus {
+	for _, c := range r.Contracts {
+		if c.Section == section {
+			return c.Status
+		}
+	}
+	return ""
+}
+
+// Complete reports whether every required contract was executed. A report
+// with no contract record at all is not complete.
+func (r Report) Complete() bool {
+	return len(r.Contracts) > 0 && r.Executed() == len(r.Contracts)
+}
+
+// Incomplete returns the contracts that were aborted or skipped, in order.
+func (r Report) Incomplete() []ContractResult {
+	var missing []ContractResult
+	for _, c := range r.Contracts {
+		if !c.Status.ran() {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// OK reports whether every required contract executed and every check
+// passed or failed under an exception. Passing checks alone are not
+// enough: a contract that never ran has no failing checks either.
+func (r Report) OK() bool { return r.Complete() && len(r.Failed()) == 0 }
+
+// Failed returns the failing checks not covered by an exception, in order.
+func (r Report) Failed() []CheckResult {
+	var failed []CheckResult
+	for _, c := range r.Checks {
+		if !c.Passed && c.Exception == "" {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// Excepted returns the failing checks accepted under an exception.
+func (r Report) Excepted() []CheckResult {
+	var excepted []CheckResult
+	for _, c := range r.Checks {
+		if !c.Passed && c.Exception != "" {
+			excepted = append(excepted, c)
+		}
+	}
+	return excepted
+}
@@ -0,0 +1,12 @@
+// cross_lang_proof/pkg/gefverify/report_test.go
+
+package gefverify
+
+import "testing"
+
+func TestPassingChecksWithoutContractsIsNotOK(t *testing.T) {
+	report := Report{Checks: []CheckResult{{ID: "C1.canonical_bytes", Passed: true}}}
+	if report.OK() {
+		t.Error("report with no executed contracts is OK")
+	}
+}
@@ -0,0 +1,159 @@
+// cross_lang_proof/pkg/gefverify/sigalg.go
+//
+// Signature algorithms (CONTRACTS 3 and 6)
+// ========================================
+//
+// A bundle may name the algorithm of its signature in sig_alg:
+//
+//   ed25519      crypto/ed25519, also used when sig_alg is absent
+//                public_key_hex   the raw 32-byte key
+//                signature        64 bytes
+//   ecdsa-p256   crypto/ecdsa over P-256, of SHA-256(canonical bytes)
+//                public_key_hex   the uncompressed SEC1 point (65 bytes,
+//                                 04 || X || Y) or a PKIX DER key
+//                signature        64 bytes r || s, as JWS ES256 writes
+//                                 it, or ASN.1 DER, as cloud KMS does
+//
+// Both sign the same canonical bytes, so CONTRACT 3 verifies and
+// CONTRACT 6 corrupts them in the same way; their details name the
+// algorithm, so that a chain mixing the two can be audited record by
+// record. An unknown sig_alg fails validation: no contract can read the
+// key. The fingerprint of a P-256 key is that of its uncompressed point,
+// whichever encoding the bundle used.
+
+package gefverify
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Signature algorithms a bundle may name in sig_alg.
+const (
+	SigAlgEd25519   = "ed25519"
+	SigAlgECDSAP256 = "ecdsa-p256"
+)
+
+// PublicKey is a bundle's public key and the algorithm it verifies under.
+type PublicKey struct {
+	Alg   string // SigAlgEd25519 or SigAlgECDSAP256
+	Bytes []byte // the Ed25519 key, or the uncompressed SEC1 point
+
+	ecdsa  *ecdsa.PublicKey
+	encode func([]byte) []byte // what a transport signs for a message (carried.go)
+}
+
+// p256SPKIPrefix is the DER SubjectPublicKeyInfo of a P-256 key up to
+// the point: prefixed to an uncompressed point, it is a PKIX key.
+var p256SPKIPrefix, _ = hex.DecodeString("3059301306072a8648ce3d020106082a8648ce3d030107034200")
+
+// DecodeBundleKey decodes pubHex as a public key of alg; "" is Ed25519.
+func DecodeBundleKey(alg, pubHex string) (PublicKey, error) {
+	switch alg {
+	case "", SigAlgEd25519:
+		pub, err := DecodePublicKey(pubHex)
+		if err != nil {
+			return PublicKey{}, err
+		}
+		return PublicKey{Alg: SigAlgEd25519, Bytes: pub}, nil
+	case SigAlgECDSAP256:
+		pub, err := DecodeP256PublicKey(pubHex)
+		if err != nil {
+			return PublicKey{}, err
+		}
+		point, err := pub.ECDH()
+		if err != nil {
+			return PublicKey{}, err
+		}
+		return PublicKey{Alg: SigAlgECDSAP256, Bytes: point.Bytes(), ecdsa: pub}, nil
+	}
+	return PublicKey{}, errors.New(unsupportedSigAlg(alg))
+}
+
+func unsupportedSigAlg(alg string) string {
+	return fmt.Sprintf("unsupported signature algorithm %q: this verifier knows %s, %s",
+		alg, SigAlgECDSAP256, SigAlgEd25519)
+}
+
+// DecodeP256PublicKey decodes a P-256 public key from hex: an
+// uncompressed SEC1 point or a PKIX DER key.
+func DecodeP256PublicKey(pubHex string) (*ecdsa.PublicKey, error) {
+	raw, err := hex.DecodeString(pubHex)
+	if err != nil {
+		return nil, err
+	}
+	der := raw
+	if len(raw) == 65 && raw[0] == 4 {
+		der = append(append([]byte(nil), p256SPKIPrefix...), raw...)
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("not an uncompressed SEC1 point or a PKIX key (%d bytes)", len(raw))
+	}
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok || pub.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("PKIX key is %T, not ECDSA P-256", key)
+	}
+	return pub, nil
+}
+
+// Verify reports whether sig is k's signature of message.
+func (k PublicKey) Verify(message, sig []byte) bool {
+	if k.encode != nil {
+		message = k.encode(message)
+	}
+	if k.ecdsa == nil {
+		return len(k.Bytes) == ed25519.PublicKeySize && ed25519.Verify(k.Bytes, message, sig)
+	}
+	digest := sha256.Sum256(message)
+	if len(sig) == 64 {
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(k.ecdsa, digest[:], r, s)
+	}
+	return ecdsa.VerifyASN1(k.ecdsa, digest[:], sig)
+}
+
+// Fingerprint is KeyFingerprint of k.Bytes.
+func (k PublicKey) Fingerprint() string { return KeyFingerprint(k.Bytes) }
+
+// DecodeBundleSignature decodes a base64url signature of alg (see
+// DecodeSignature for the encoding); "" is Ed25519.
+func DecodeBundleSignature(alg, sigB64 string) ([]byte, error) {
+	if alg != SigAlgECDSAP256 {
+		return DecodeSignature(sigB64)
+	}
+	sig, err := decodeBase64URL(sigB64)
+	if err != nil {
+		return nil, err
+	}
+	if problem := p256SignatureProblem(sig); problem != "" {
+		return nil, errors.New(problem)
+	}
+	return sig, nil
+}
+
+// p256SignatureProblem describes what is wrong with sig as a P-256
+// signature, 64 bytes or ASN.1 DER, or returns "".
+func p256SignatureProblem(sig []byte) string {
+	if len(sig) == 64 {
+		return ""
+	}
+	var rs struct{ R, S *big.Int }
+	rest, err := asn1.Unmarshal(sig, &rs)
+	switch {
+	case err != nil || len(rest) > 0:
+		return fmt.Sprintf("%d bytes, neither r || s (64) nor an ASN.1 DER signature", len(sig))
+	case rs.R.Sign() <= 0 || rs.S.Sign() <= 0:
+		return "ASN.1 signature with r or s not positive"
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+// cross_lang_proof/pkg/gefverify/sigalg_test.go
+
+package gefverify
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// p256Bundle signs a record with a new P-256 key, as a KMS would. der
+// writes the key as PKIX and the signature as ASN.1, otherwise SEC1 and
+// r || s.
+func p256Bundle(t *testing.T, der bool) ProofBundle {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	point, err := priv.PublicKey.ECDH()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := point.Bytes()
+	if der {
+		if pub, err = x509.MarshalPKIXPublicKey(&priv.PublicKey); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b := signedBundle(t, 3, map[string]interface{}{"kms": "p256"})
+	b.SigAlg = SigAlgECDSAP256
+	b.PublicKeyHex = hex.EncodeToString(pub)
+	b.SigningDict["signer_public_key"] = b.PublicKeyHex
+	b.SigningDict["record_type"] = "execution"
+
+	canonical, err := Canonicalize(b.SigningDict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256(canonical)
+	var sig []byte
+	if der {
+		sig, err = ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	} else {
+		r, s, signErr := ecdsa.Sign(rand.Reader, priv, digest[:])
+		sig, err = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...), signErr
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.Sum256(canonical)
+	b.CanonicalBytesHex = hex.EncodeToString(canonical)
+	b.ChainBytesHex = b.CanonicalBytesHex
+	b.CausalHashOfThis = hex.EncodeToString(hash[:])
+	b.SignatureB64URL = base64.RawURLEncoding.EncodeToString(sig)
+	b.SignatureHex = hex.EncodeToString(sig)
+	return b
+}
+
+func TestECDSAP256Bundles(t *testing.T) {
+	for _, der := range []bool{false, true} {
+		b := p256Bundle(t, der)
+		report, err := NewVerifier(WithMutations(16, 1), WithRejectWeakKeys(true)).Verify(b)
+		if err != nil || !report.OK() {
+			t.Fatalf("der=%v: err=%v failed=%v", der, err, failedIDs(report))
+		}
+		if report.HashAlg != DefaultHashAlg || report.SigAlg != SigAlgECDSAP256 {
+			t.Errorf("der=%v: report names %s, %s", der, report.HashAlg, report.SigAlg)
+		}
+		for _, id := range []string{"C3.signature_go", "C6.flip_byte"} {
+			if c, _ := checkByID(report, id); !strings.HasPrefix(c.Details, "alg=ecdsa-p256 ") {
+				t.Errorf("der=%v: %s does not name the algorithm: %q", der, id, c.Details)
+			}
+		}
+		if report.Mutations == nil || report.Mutations.Rejected != 16 {
+			t.Errorf("der=%v: mutations %+v", der, report.Mutations)
+		}
+
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := LintErrors(Lint(data)); n != 0 {
+			t.Errorf("der=%v: lint errors %v", der, Lint(data))
+		}
+
+		b.SigningDict["record_type"] = "tampered"
+		b.ChainDict = b.SigningDict
+		report, _ = Verify(b, VerifyOptions{})
+		if c, _ := checkByID(report, "C3.signature_go"); c.Passed {
+			t.Errorf("der=%v: edited record verifies", der)
+		}
+	}
+}
+
+func TestP256KeyEncodingsShareFingerprint(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	point, _ := priv.PublicKey.ECDH()
+	der, _ := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	sec1Key, err := DecodeBundleKey(SigAlgECDSAP256, hex.EncodeToString(point.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkixKey, err := DecodeBundleKey(SigAlgECDSAP256, hex.EncodeToString(der))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sec1Key.Fingerprint() != pkixKey.Fingerprint() {
+		t.Errorf("fingerprints differ: %s, %s", sec1Key.Fingerprint(), pkixKey.Fingerprint())
+	}
+}
+
+func TestSigAlgValidation(t *testing.T) {
+	for _, tt := range []struct {
+		edit  func(*ProofBundle)
+		field string
+	}{
+		{func(b *ProofBundle) { b.SigAlg = "rsa" }, "sig_alg"},
+		{func(b *ProofBundle) { b.PublicKeyHex = loadProofBundle(t).PublicKeyHex }, "public_key_hex"},
+		{func(b *ProofBundle) { b.SignatureB64URL = base64.RawURLEncoding.EncodeToString(make([]byte, 70)) }, "signature_b64url"},
+	} {
+		b := p256Bundle(t, true)
+		tt.edit(&b)
+		report, err := Verify(b, VerifyOptions{})
+		if c, ok := checkByID(report, "V.bundle_field."+tt.field); err == nil || !ok || c.Passed {
+			t.Errorf("%s: err=%v checks=%v", tt.field, err, failedIDs(report))
+		}
+	}
+
+	// An Ed25519 bundle is unchanged by naming its algorithm.
+	b := loadProofBundle(t)
+	b.SigAlg = SigAlgEd25519
+	if report, err := Verify(b, VerifyOptions{}); err != nil || !report.OK() {
+		t.Errorf("sig_alg ed25519: err=%v failed=%v", err, failedIDs(report))
+	}
+}
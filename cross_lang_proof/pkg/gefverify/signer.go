@@ -0,0 +1,103 @@
+// cross_lang_proof/pkg/gefverify/signer.go
+//
+// Signer binding (CONTRACT 11)
+// ============================
+//
+// The signing dict names its signer in signer_public_key, and the bundle
+// carries the key it is verified under in public_key_hex. Nothing ties
+// the two together: a record can claim one signer and verify under
+// another's key, and every other contract passes. CONTRACT 11 requires
+// them to be the same key:
+//
+//   C11.signer_binding   signer_public_key decodes to the key of
+//                        public_key_hex
+//
+// Either may be written in hex or in base64 (standard or URL alphabet,
+// padded or not); hex is tried first. Both are then decoded under the
+// bundle's sig_alg and compared as key material, as CONTRACT 10 does: a
+// P-256 key written as PKIX is its SEC1 point. A failure shows the
+// SHA-256 fingerprint of both keys. A field renamed under -field-aliases is
+// found under its alias; a record without it leaves a note, and
+// CONTRACT 5 reports the missing field.
+
+package gefverify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// decodeKeyText decodes a key written in hex or base64 and names the
+// encoding it was written in.
+func decodeKeyText(s string) ([]byte, string, error) {
+	if b, err := hex.DecodeString(s); err == nil && s != "" {
+		return b, "hex", nil
+	}
+	trimmed := strings.TrimRight(s, "=")
+	for _, enc := range []*base64.Encoding{base64.RawStdEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(trimmed); err == nil && trimmed != "" {
+			return b, "base64", nil
+		}
+	}
+	return nil, "", errors.New("neither hex nor base64")
+}
+
+// decodeSignerKey decodes a key written in hex or base64 to its key
+// bytes under sig_alg alg, and names the encoding it was written in.
+func decodeSignerKey(alg, s string) ([]byte, string, error) {
+	raw, encoding, err := decodeKeyText(s)
+	if err != nil {
+		return nil, "", err
+	}
+	key, err := DecodeBundleKey(alg, hex.EncodeToString(raw))
+	if err != nil {
+		return nil, "", err
+	}
+	return key.Bytes, encoding, nil
+}
+
+// checkSignerBinding runs CONTRACT 11 on the signing dict of a bundle
+// verified under pubHex with sig_alg alg, signed under version.
+func (v *Verifier) checkSignerBinding(r *run, alg, pubHex string, signingDict map[string]interface{}, version string) {
+	field, _, ok := v.aliases.resolve(version, "signer_public_key", signingDict)
+	if !ok {
+		r.notes = append(r.notes, "signer_public_key absent: CONTRACT 11 has nothing to compare")
+		return
+	}
+	key, _, err := decodeSignerKey(alg, pubHex)
+	var signer []byte
+	var encoding string
+	raw, isString := signingDict[field].(string)
+	switch {
+	case err != nil:
+		err = fmt.Errorf("public_key_hex: %w", err)
+	case !isString:
+		err = fmt.Errorf("%s is %T, not a string", field, signingDict[field])
+	default:
+		if signer, encoding, err = decodeSignerKey(alg, raw); err != nil {
+			err = fmt.Errorf("%s: %w", field, err)
+		}
+	}
+
+	bound := err == nil && bytes.Equal(signer, key)
+	var details string
+	switch {
+	case err != nil:
+		details = err.Error()
+	case bound:
+		details = fmt.Sprintf("%s (%s) is %s", field, encoding, KeyFingerprint(key))
+	default:
+		details = fmt.Sprintf("%s %s  public_key_hex %s", field, KeyFingerprint(signer), KeyFingerprint(key))
+	}
+	r.check(
+		"C11.signer_binding",
+		CategoryIntegrity,
+		"signer_public_key is the verifying key",
+		bound,
+		details,
+	)
+}
@@ -0,0 +1,74 @@
+// cross_lang_proof/pkg/gefverify/signer_test.go
+
+package gefverify
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestSignerBinding(t *testing.T) {
+	key, err := hex.DecodeString(editedBundle(t, nil).PublicKeyHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := strings.Repeat("ab", 32)
+	otherKey, _ := hex.DecodeString(other)
+
+	tests := []struct {
+		name    string
+		signer  interface{}
+		passed  bool
+		verdict Verdict
+		details string
+	}{
+		{"hex", strings.ToUpper(hex.EncodeToString(key)), true, VerdictVerified, "signer_public_key (hex) is " + KeyFingerprint(key)},
+		{"base64", base64.StdEncoding.EncodeToString(key), true, VerdictVerified, "(base64)"},
+		{"base64url", base64.RawURLEncoding.EncodeToString(key), true, VerdictVerified, "(base64)"},
+		{"other key", other, false, VerdictTampered, "signer_public_key " + KeyFingerprint(otherKey) + "  public_key_hex " + KeyFingerprint(key)},
+		{"undecodable", "not a key!", false, VerdictTampered, "signer_public_key: neither hex nor base64"},
+		// CONTRACT 5 reports the type too.
+		{"not a string", 42.0, false, VerdictMalformed, "signer_public_key is float64, not a string"},
+	}
+	for _, tt := range tests {
+		report, err := Verify(editedBundle(t, map[string]interface{}{"signer_public_key": tt.signer}), VerifyOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		c, ok := checkByID(report, "C11.signer_binding")
+		if !ok || c.Passed != tt.passed || !strings.Contains(c.Details, tt.details) {
+			t.Errorf("%s: %+v, want passed=%v and details with %q", tt.name, c, tt.passed, tt.details)
+		}
+		if report.Verdict != tt.verdict {
+			t.Errorf("%s: verdict %s, want %s", tt.name, report.Verdict, tt.verdict)
+		}
+	}
+
+	// A renamed field is found under its alias; a missing one is left to
+	// CONTRACT 5.
+	renamed := editedBundle(t, map[string]interface{}{"signer_public_key": nil, "signer_key": hex.EncodeToString(key)})
+	report, _ := Verify(renamed, VerifyOptions{FieldAliases: FieldAliases{"1.0": {"signer_public_key": "signer_key"}}})
+	if c, _ := checkByID(report, "C11.signer_binding"); !c.Passed || !strings.HasPrefix(c.Details, "signer_key (hex)") {
+		t.Errorf("alias: %+v", c)
+	}
+	report, _ = Verify(editedBundle(t, map[string]interface{}{"signer_public_key": nil}), VerifyOptions{})
+	if _, ok := checkByID(report, "C11.signer_binding"); ok || !strings.Contains(strings.Join(report.Notes, "\n"), "CONTRACT 11 has nothing to compare") {
+		t.Errorf("absent: checks %v, notes %q", failedIDs(report), report.Notes)
+	}
+}
+
+func TestSignerBindingP256Encodings(t *testing.T) {
+	// Signed for the PKIX key, verified under its SEC1 point.
+	b := p256Bundle(t, true)
+	key, err := DecodeBundleKey(SigAlgECDSAP256, b.PublicKeyHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.PublicKeyHex = hex.EncodeToString(key.Bytes)
+	report, err := Verify(b, VerifyOptions{})
+	if c, _ := checkByID(report, "C11.signer_binding"); err != nil || !c.Passed || report.Verdict != VerdictVerified {
+		t.Errorf("err=%v verdict=%s C11=%+v", err, report.Verdict, c)
+	}
+}
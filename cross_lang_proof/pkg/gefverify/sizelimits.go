@@ -0,0 +1,156 @@
+// cross_lang_proof/pkg/gefverify/sizelimits.go
+//
+// Size limits (optional, WithSizeLimits)
+// ======================================
+//
+// GEF-SPEC-1.0 §3.2 caps the canonical payload at 1 MiB and each
+// identifier field at 256 bytes. A record over the limit still verifies
+// cryptographically, and then breaks the first consumer with a tighter
+// parser. The size policy measures the signed record and compares it
+// with the limits of its gef_version:
+//
+//   payload            length of the JCS serialization of the payload
+//   agent_id,          length of the JCS serialization of the value
+//   record_id,         without the enclosing quotes, i.e. the bytes
+//   record_type        between them on the wire
+//
+// Both come from the canonical form, never from the bundle as written, so
+// pretty-printing or escaping in the input does not change the measured
+// size: it is what every conforming implementation sees.
+//
+// Like FieldAliases the table is keyed by the signed gef_version; a
+// version absent from the table is not evaluated (noted in the report).
+// SizeLimitsStrict fails the record (P.payload_size, P.field_size.*,
+// MALFORMED); SizeLimitsRelaxed reports every excess as a warning only.
+
+package gefverify
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gowebpki/jcs"
+)
+
+// SizeLimits are the byte limits of one gef_version.
+type SizeLimits struct {
+	Payload    int `json:"payload"`
+	Identifier int `json:"identifier"`
+}
+
+// SizeLimitTable maps gef_version → limits.
+type SizeLimitTable map[string]SizeLimits
+
+// DefaultSizeLimits is the built-in table (GEF-SPEC-1.0 §3.2).
+var DefaultSizeLimits = SizeLimitTable{
+	"1.0": {Payload: 1 << 20, Identifier: 256},
+}
+
+// SizeLimitMode selects how the size policy reports an excess.
+type SizeLimitMode int
+
+const (
+	SizeLimitsOff SizeLimitMode = iota
+	SizeLimitsStrict
+	SizeLimitsRelaxed
+)
+
+// ParseSizeLimitMode parses "off", "strict" or "relaxed".
+func ParseSizeLimitMode(s string) (SizeLimitMode, error) {
+	switch s {
+	case "", "off":
+		return SizeLimitsOff, nil
+	case "strict":
+		return SizeLimitsStrict, nil
+	case "relaxed":
+		return SizeLimitsRelaxed, nil
+	}
+	return SizeLimitsOff, fmt.Errorf("size limit mode %q: want off, strict or relaxed", s)
+}
+
+// FieldSize is one measured part of a record.
+type FieldSize struct {
+	Field   string // "payload" or an identifier field
+	Size    int    // canonical bytes
+	Allowed int
+}
+
+// Exceeds reports whether the measured size is over the limit.
+func (f FieldSize) Exceeds() bool { return f.Size > f.Allowed }
+
+// MeasureSizes returns the canonical sizes of the payload and the
+// identifier fields present in dict, against limits, payload first.
+func MeasureSizes(dict map[string]interface{}, limits SizeLimits) ([]FieldSize, error) {
+	var sizes []FieldSize
+	if p, ok := dict["payload"]; ok {
+		n, err := canonicalSize(p)
+		if err != nil {
+			return nil, fmt.Errorf("payload: %w", err)
+		}
+		sizes = append(sizes, FieldSize{"payload", n, limits.Payload})
+	}
+	fields := make([]string, 0, len(identifierFields))
+	for f := range identifierFields {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	for _, f := range fields {
+		s, ok := dict[f].(string)
+		if !ok {
+			continue // absence and type are CHECK 5's business
+		}
+		n, err := canonicalSize(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		sizes = append(sizes, FieldSize{f, n - 2, limits.Identifier})
+	}
+	return sizes, nil
+}
+
+func canonicalSize(v interface{}) (int, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return 0, err
+	}
+	canonical, err := jcs.Transform(raw)
+	if err != nil {
+		return 0, err
+	}
+	return len(canonical), nil
+}
+
+// sizePolicy runs the size policy on dict for the signed version.
+func (v *Verifier) sizePolicy(r *run, dict map[string]interface{}) {
+	table := v.opts.SizeLimitTable
+	if table == nil {
+		table = DefaultSizeLimits
+	}
+	version, _ := dict["gef_version"].(string)
+	limits, ok := table[version]
+	if !ok {
+		r.notes = append(r.notes, fmt.Sprintf("size limits not evaluated: no profile for gef_version %q", version))
+		return
+	}
+	sizes, err := MeasureSizes(dict, limits)
+	if err != nil {
+		r.check("P.payload_size", CategoryStructure, "record canonicalizes for measurement", false, err.Error())
+		return
+	}
+	for _, s := range sizes {
+		field := s.Field
+		details := fmt.Sprintf("size=%d  allowed=%d", s.Size, s.Allowed)
+		if v.opts.SizeLimits == SizeLimitsRelaxed {
+			if s.Exceeds() {
+				r.warnings = append(r.warnings, fmt.Sprintf("signing_dict.%s: over the gef_version %s size limit, %s", field, version, details))
+			}
+			continue
+		}
+		if field == "payload" {
+			r.check("P.payload_size", CategoryStructure, "canonical payload within size limit", !s.Exceeds(), details)
+		} else {
+			r.check("P.field_size."+field, CategoryStructure, field+" within size limit", !s.Exceeds(), details)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// cross_lang_proof/pkg/gefverify/sizelimits_test.go
+
+package gefverify
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// sizedBundle is signedBundle with field set to value, signed again.
+func sizedBundle(t *testing.T, field string, value interface{}) ProofBundle {
+	t.Helper()
+	return editedBundle(t, map[string]interface{}{field: value})
+}
+
+// editedBundle is signedBundle with changes applied to signing_dict
+// (nil deletes a field), signed again.
+func editedBundle(t *testing.T, changes map[string]interface{}) ProofBundle {
+	t.Helper()
+	b := signedBundle(t, 7, map[string]interface{}{"k": "v"})
+	for field, value := range changes {
+		if value == nil {
+			delete(b.SigningDict, field)
+		} else {
+			b.SigningDict[field] = value
+		}
+	}
+	priv := ed25519.NewKeyFromSeed([]byte(strings.Repeat("h", 32)))
+	canonical, err := Canonicalize(b.SigningDict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := ed25519.Sign(priv, canonical)
+	hash := sha256.Sum256(canonical)
+	b.CanonicalBytesHex = hex.EncodeToString(canonical)
+	b.ChainBytesHex = b.CanonicalBytesHex
+	b.CausalHashOfThis = hex.EncodeToString(hash[:])
+	b.SignatureB64URL = base64.RawURLEncoding.EncodeToString(sig)
+	b.SignatureHex = hex.EncodeToString(sig)
+	return b
+}
+
+func sizeCheck(t *testing.T, rep Report, id string) CheckResult {
+	t.Helper()
+	for _, c := range rep.Checks {
+		if c.ID == id {
+			return c
+		}
+	}
+	t.Fatalf("no %s in report", id)
+	return CheckResult{}
+}
+
+func TestSizeLimitsAtUnderAndOver(t *testing.T) {
+	limits := DefaultSizeLimits["1.0"]
+	// {"d":"xxx…"} is 8 bytes plus the string.
+	payload := func(size int) interface{} {
+		return map[string]interface{}{"d": strings.Repeat("x", size-8)}
+	}
+	cases := []struct {
+		field, id string
+		value     func(size int) interface{}
+		limit     int
+	}{
+		{"payload", "P.payload_size", payload, limits.Payload},
+		{"agent_id", "P.field_size.agent_id", func(n int) interface{} { return strings.Repeat("a", n) }, limits.Identifier},
+		{"record_id", "P.field_size.record_id", func(n int) interface{} { return strings.Repeat("r", n) }, limits.Identifier},
+	}
+	for _, tc := range cases {
+		for _, delta := range []int{-1, 0, 1} {
+			b := sizedBundle(t, tc.field, tc.value(tc.limit+delta))
+			rep, err := NewVerifier(WithSizeLimits(SizeLimitsStrict)).Verify(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := sizeCheck(t, rep, tc.id)
+			if want := delta <= 0; c.Passed != want {
+				t.Errorf("%s at limit%+d: passed = %v (%s)", tc.field, delta, c.Passed, c.Details)
+			}
+			if delta > 0 && rep.Verdict != VerdictMalformed {
+				t.Errorf("%s over limit: verdict %s", tc.field, rep.Verdict)
+			}
+			if delta <= 0 && rep.Verdict != VerdictVerified {
+				t.Errorf("%s within limit: verdict %s", tc.field, rep.Verdict)
+			}
+		}
+	}
+}
+
+func TestSizeLimitsRelaxedWarns(t *testing.T) {
+	b := sizedBundle(t, "agent_id", strings.Repeat("a", 300))
+	rep, err := NewVerifier(WithSizeLimits(SizeLimitsRelaxed)).Verify(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Verdict != VerdictVerified {
+		t.Errorf("verdict %s", rep.Verdict)
+	}
+	if len(rep.Warnings) != 1 || !strings.Contains(rep.Warnings[0], "size=300  allowed=256") {
+		t.Errorf("warnings = %q", rep.Warnings)
+	}
+}
+
+func TestSizeLimitsMeasureCanonicalForm(t *testing.T) {
+	// é is two UTF-8 bytes either way; U+0001 is written \u0001 by JCS.
+	sizes, err := MeasureSizes(map[string]interface{}{
+		"agent_id": "é\u0001",
+		"payload":  map[string]interface{}{"b": 1.0, "a": []interface{}{}},
+	}, SizeLimits{Payload: 100, Identifier: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int{}
+	for _, s := range sizes {
+		got[s.Field] = s.Size
+	}
+	if got["agent_id"] != 8 || got["payload"] != len(`{"a":[],"b":1}`) {
+		t.Errorf("sizes = %v", got)
+	}
+}
+
+func TestSizeLimitsUnknownVersionNoted(t *testing.T) {
+	b := sizedBundle(t, "gef_version", "9.9")
+	b.GEFVersion = "9.9"
+	rep, err := NewVerifier(WithSizeLimits(SizeLimitsStrict)).Verify(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range rep.Checks {
+		if c.Section == SectionSizeLimits {
+			t.Errorf("unexpected check %s", c.ID)
+		}
+	}
+	if !strings.Contains(strings.Join(rep.Notes, "\n"), `no profile for gef_version "9.9"`) {
+		t.Errorf("notes = %q", rep.Notes)
+	}
+}
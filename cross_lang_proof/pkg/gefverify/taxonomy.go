@@ -0,0 +1,217 @@
+// cross_lang_proof/pkg/gefverify/taxonomy.go
+//
+// Taxonomy
+// ========
+//
+// The stable identifiers embedders match on: check IDs, verdicts,
+// categories and the errors this package returns. Checks and Errors are
+// the registries; verdicts, categories and exit codes are read from
+// verdictRules and ExitCode, so the reference output of the CLI
+// (verify_proof reference) is generated rather than kept in step by hand.
+//
+// Every check ID the package can emit must have a CheckSpec, and every
+// exported error an ErrorSpec; taxonomy_test.go scans the sources and
+// fails otherwise. IDs built at run time are registered as patterns with
+// the variable part in braces, e.g. "N{depth}.nested_bundle".
+
+package gefverify
+
+import (
+	"reflect"
+	"strings"
+)
+
+// CheckSpec is the reference metadata of one check ID.
+type CheckSpec struct {
+	ID       string
+	Section  string
+	Category Category // category of a failure; see Note if it varies
+	Spec     string   // GEF-SPEC reference, "" for checks local to this verifier
+	Note     string
+	Lint     bool // also run by Lint, the profile without cryptography
+}
+
+// ErrorSpec describes an error value or type callers can match with
+// errors.Is or errors.As.
+type ErrorSpec struct {
+	Name    string
+	Value   error       // sentinel, for errors.Is; nil for types
+	Type    interface{} // pointer to a zero value, for errors.As; nil for sentinels
+	Verdict Verdict     // verdict a caller should report, "" if not a verification outcome
+}
+
+var checkSpecs = []CheckSpec{
+	{ID: "V.bundle_field.{field}", Section: SectionValidation, Category: CategoryStructure,
+		Note: "one per invalid bundle field; no contract runs after one"},
+	{ID: "C1.canonical_bytes", Section: SectionCanonicalBytes, Category: CategoryIntegrity, Spec: "GEF-SPEC-1.0 §4"},
+	{ID: "C2.hash_alg_supported", Section: SectionChainHash, Category: CategoryStructure,
+		Note: "only for a hash_alg the verifier does not know; the other C2 checks then do not run"},
+	{ID: "C2.chain_hash", Section: SectionChainHash, Category: CategoryIntegrity, Spec: "GEF-SPEC-1.0 §6"},
+	{ID: "C2.chain_bytes", Section: SectionChainHash, Category: CategoryIntegrity, Spec: "GEF-SPEC-1.0 §6"},
+	{ID: "C3.weak_key", Section: SectionSignature, Category: CategoryPolicy, Note: "only with WithRejectWeakKeys"},
+	{ID: "C3.signature_go", Section: SectionSignature, Category: CategoryIntegrity, Spec: "GEF-SPEC-1.0 §5.1"},
+	{ID: "C3.signature_python", Section: SectionSignature, Category: CategoryIntegrity, Spec: "GEF-SPEC-1.0 §5.1"},
+	{ID: "C3.cose_sign1", Section: SectionSignature, Category: CategoryIntegrity, Spec: "RFC 9052 §4.4", Note: "only for bundles with cose_sign1"},
+	{ID: "C4.dict_identity", Section: SectionDictIdentity, Category: CategoryIntegrity, Spec: "GEF-SPEC-1.0 §5.2", Lint: true},
+	{ID: "C4.signature_excluded", Section: SectionDictIdentity, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §5.2", Lint: true},
+	{ID: "C4.legacy_signature_member", Section: SectionDictIdentity, Category: CategoryStructure,
+		Lint: true, Note: "legacy signing-field profile only, gef_version 0.x; replaces C4.signature_excluded"},
+	{ID: "C5.gef_version_supported", Section: SectionFieldCount, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §11",
+		Lint: true,
+		Note: "only for an unknown major gef_version; the other C5 checks then do not run"},
+	{ID: "C5.field_count", Section: SectionFieldCount, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §3.1", Lint: true},
+	{ID: "C5.field_present.{field}", Section: SectionFieldCount, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §3.1",
+		Lint: true,
+		Note: "one per missing required field"},
+	{ID: "C5.fields_present", Section: SectionFieldCount, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §3.1"},
+	{ID: "C5.field_type.{field}", Section: SectionFieldCount, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §3.1",
+		Note: "one per wrongly-typed field, see GEFRecord"},
+	{ID: "C5.field_types", Section: SectionFieldCount, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §3.1"},
+	{ID: "C5.unknown_field.{field}", Section: SectionFieldCount, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §3.1",
+		Note: "one per signing_dict field that is not expected"},
+	{ID: "C6.flip_byte", Section: SectionNegativeTest, Category: CategoryIntegrity},
+	{ID: "C6.flip_bit", Section: SectionNegativeTest, Category: CategoryIntegrity},
+	{ID: "C6.original_intact", Section: SectionNegativeTest, Category: CategoryIntegrity},
+	{ID: "C6.random_mutations", Section: SectionNegativeTest, Category: CategoryIntegrity, Note: "only with VerifyOptions.Mutations"},
+	{ID: "C7.version_binding", Section: SectionVersionBinding, Category: CategoryIntegrity, Spec: "GEF-SPEC-1.0 §11", Lint: true},
+	{ID: "C8.envelope_fields", Section: SectionEnvelope, Category: CategoryIntegrity,
+		Note: "only when the bundle carries envelope_json"},
+	{ID: "C8.envelope_signature", Section: SectionEnvelope, Category: CategoryIntegrity,
+		Note: "only when the bundle carries envelope_json"},
+	{ID: "C9.timestamp_format", Section: SectionTimestamp, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §3.1",
+		Lint: true, Note: "only when signing_dict carries a timestamp"},
+	{ID: "C10.key_trusted", Section: SectionKeyTrust, Category: CategoryTrust,
+		Note: "only with VerifyOptions.TrustedKeys"},
+	{ID: "C10.key_agent_binding", Section: SectionKeyTrust, Category: CategoryTrust,
+		Note: "only for trusted keys listed with an agent_id"},
+	{ID: "C11.signer_binding", Section: SectionSignerBinding, Category: CategoryIntegrity, Spec: "GEF-SPEC-1.0 §5.1",
+		Note: "only when signing_dict carries signer_public_key"},
+	{ID: "P.clock_skew", Section: SectionFreshness, Category: CategoryPolicy, Spec: "GEF-SPEC-1.0 §9",
+		Note: "only with WithMaxClockSkew"},
+	{ID: "P.max_age", Section: SectionFreshness, Category: CategoryPolicy, Spec: "GEF-SPEC-1.0 §9",
+		Note: "only with WithMaxAge"},
+	{ID: "P.freshness", Section: SectionFreshness, Category: CategoryPolicy, Spec: "GEF-SPEC-1.0 §9",
+		Note: "only with WithFreshness"},
+	{ID: "P.payload_size", Section: SectionSizeLimits, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §3.2",
+		Note: "only with WithSizeLimits strict"},
+	{ID: "P.field_size.{field}", Section: SectionSizeLimits, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §3.2",
+		Note: "only with WithSizeLimits strict; agent_id, record_id, record_type"},
+	{ID: "D.signer_binding", Section: SectionDetached, Category: CategoryIntegrity, Spec: "GEF-SPEC-1.0 §5.2",
+		Note: "VerifyDetached only"},
+	{ID: "D.body_canonical", Section: SectionDetached, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §4",
+		Note: "VerifyDetached with WithCanonicalBody"},
+	{ID: "N{depth}.nested_bundle", Section: SectionNested, Category: CategoryCompleteness,
+		Note: "completeness beyond max depth, otherwise the category of the nested verdict"},
+	{ID: "K.genesis_link", Section: SectionChainLinkage, Category: CategoryIntegrity, Spec: "GEF-SPEC-1.0 §6",
+		Note: "VerifyChain only"},
+	{ID: "K.causal_link", Section: SectionChainLinkage, Category: CategoryIntegrity, Spec: "GEF-SPEC-1.0 §6",
+		Note: "VerifyChain only"},
+	{ID: "K.sequence_contiguous", Section: SectionChainLinkage, Category: CategoryCompleteness, Spec: "GEF-SPEC-1.0 §6",
+		Note: "VerifyChain only: one check over the chain, no gaps, duplicates or steps back from genesis"},
+	{ID: "L.json_syntax", Section: SectionLint, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §4", Lint: true,
+		Note: "Lint only: UTF-8, BOM, syntax and trailing data"},
+	{ID: "L.duplicate_key", Section: SectionLint, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §4", Lint: true,
+		Note: "Lint only"},
+	{ID: "L.number_range", Section: SectionLint, Category: CategoryStructure, Lint: true,
+		Note: "Lint only, warning: beyond ±2^53"},
+	{ID: "L.field_type", Section: SectionLint, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §3.1", Lint: true,
+		Note: "Lint only: presence, type and nullability outside C5"},
+	{ID: "L.unknown_field", Section: SectionLint, Category: CategoryStructure, Lint: true,
+		Note: "Lint only, warning; an error for chain_hash_alg, which Validate refuses"},
+	{ID: "L.hex_format", Section: SectionLint, Category: CategoryStructure, Lint: true,
+		Note: "Lint only; uppercase hex is a warning"},
+	{ID: "L.base64_format", Section: SectionLint, Category: CategoryStructure, Lint: true,
+		Note: "Lint only; padding is a warning"},
+	{ID: "L.record_id_format", Section: SectionLint, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §3.1", Lint: true,
+		Note: "Lint only, warning"},
+	{ID: "L.nonce_format", Section: SectionLint, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §3.1", Lint: true,
+		Note: "Lint only"},
+	{ID: "L.record_type", Section: SectionLint, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §3.1", Lint: true,
+		Note: "Lint only"},
+	{ID: "L.genesis_link", Section: SectionLint, Category: CategoryStructure, Spec: "GEF-SPEC-1.0 §6", Lint: true,
+		Note: "Lint only"},
+}
+
+var errorSpecs = []ErrorSpec{
+	{Name: "ErrFreshnessNotApplicable", Value: ErrFreshnessNotApplicable},
+	{Name: "ErrEmptyChain", Value: ErrEmptyChain},
+	{Name: "MalformedError", Type: (*MalformedError)(nil), Verdict: VerdictMalformed},
+	{Name: "RecordError", Type: (*RecordError)(nil)},
+	{Name: "ValidationError", Type: (*ValidationError)(nil), Verdict: VerdictMalformed},
+	{Name: "ValidationErrors", Type: (*ValidationErrors)(nil), Verdict: VerdictMalformed},
+}
+
+// Checks returns the registered check IDs, in execution order.
+func Checks() []CheckSpec {
+	return append([]CheckSpec(nil), checkSpecs...)
+}
+
+// LintChecks returns the registered checks Lint can report.
+func LintChecks() []CheckSpec {
+	var out []CheckSpec
+	for _, c := range checkSpecs {
+		if c.Lint {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Errors returns the registered errors.
+func Errors() []ErrorSpec {
+	return append([]ErrorSpec(nil), errorSpecs...)
+}
+
+// LookupCheck returns the spec of a check ID, matching patterns against
+// IDs built at run time.
+func LookupCheck(id string) (CheckSpec, bool) {
+	for _, s := range checkSpecs {
+		if s.Matches(id) {
+			return s, true
+		}
+	}
+	return CheckSpec{}, false
+}
+
+// Matches reports whether id is s.ID, or fits it if s.ID is a pattern.
+func (s CheckSpec) Matches(id string) bool {
+	open := strings.IndexByte(s.ID, '{')
+	if open < 0 {
+		return id == s.ID
+	}
+	close := strings.IndexByte(s.ID, '}')
+	prefix, suffix := s.ID[:open], s.ID[close+1:]
+	return len(id) > len(prefix)+len(suffix) && strings.HasPrefix(id, prefix) && strings.HasSuffix(id, suffix)
+}
+
+// TypeName returns the Go type name of a type ErrorSpec, "" for sentinels.
+func (e ErrorSpec) TypeName() string {
+	if e.Type == nil {
+		return ""
+	}
+	return reflect.TypeOf(e.Type).String()
+}
+
+// Verdicts returns every verdict: VERIFIED, then in derivation order.
+func Verdicts() []Verdict {
+	vs := []Verdict{VerdictVerified}
+	for _, rule := range verdictRules {
+		vs = append(vs, rule.verdict)
+	}
+	return vs
+}
+
+// Categories returns every category, in declaration order.
+func Categories() []Category {
+	cs := make([]Category, len(categoryNames))
+	for i := range categoryNames {
+		cs[i] = Category(i)
+	}
+	return cs
+}
+
+// Verdict returns the verdict a failed check of category c derives on
+// its own.
+func (c Category) Verdict() Verdict {
+	return DeriveVerdict([]CheckResult{{Category: c}})
+}
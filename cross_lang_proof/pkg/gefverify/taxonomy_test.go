@@ -0,0 +1,161 @@
+// cross_lang_proof/pkg/gefverify/taxonomy_test.go
+
+package gefverify
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// checkLiteral matches a check ID and the category passed after it, as
+// every r.check call in this package is written.
+var checkLiteral = regexp.MustCompile(`(?:"([A-Z][A-Z0-9]*\.[a-z][a-z0-9_]*\.?)"(\+\w+)?|Sprintf\("(N)%d(\.[a-z_]+)"),\s*(Category\w+)?`)
+
+// packageSources returns the non-test Go files of this package.
+func packageSources(t *testing.T) map[string]string {
+	t.Helper()
+	paths, _ := filepath.Glob("*.go")
+	srcs := make(map[string]string)
+	for _, p := range paths {
+		if strings.HasSuffix(p, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		srcs[p] = string(data)
+	}
+	return srcs
+}
+
+func TestEveryCheckIDHasReferenceMetadata(t *testing.T) {
+	categories := make(map[string]Category)
+	for _, c := range Categories() {
+		name := c.String()
+		categories["Category"+strings.ToUpper(name[:1])+name[1:]] = c
+	}
+	used := make(map[string]bool)
+	for file, src := range packageSources(t) {
+		for _, m := range checkLiteral.FindAllStringSubmatch(src, -1) {
+			id := m[1]
+			switch {
+			case m[3] != "":
+				id = m[3] + "0" + m[4]
+			case m[2] != "":
+				id += "x"
+			}
+			spec, ok := LookupCheck(id)
+			if !ok {
+				t.Errorf("%s: check %s has no CheckSpec in taxonomy.go", file, id)
+				continue
+			}
+			used[spec.ID] = true
+			if c, ok := categories[m[5]]; ok && c != spec.Category {
+				t.Errorf("%s: check %s is %s, CheckSpec says %s", file, id, c, spec.Category)
+			}
+		}
+	}
+	for _, s := range Checks() {
+		if !used[s.ID] {
+			t.Errorf("CheckSpec %s is not emitted anywhere", s.ID)
+		}
+		if s.Section == "" {
+			t.Errorf("CheckSpec %s has no section", s.ID)
+		}
+	}
+}
+
+func TestEveryExportedErrorHasReferenceMetadata(t *testing.T) {
+	registered := make(map[string]bool)
+	for _, e := range Errors() {
+		registered[e.Name] = true
+		if (e.Value == nil) == (e.Type == nil) {
+			t.Errorf("ErrorSpec %s must set exactly one of Value and Type", e.Name)
+		}
+	}
+
+	fset := token.NewFileSet()
+	for file, src := range packageSources(t) {
+		f, err := parser.ParseFile(fset, file, src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if vs, ok := spec.(*ast.ValueSpec); ok && d.Tok == token.VAR {
+						for _, n := range vs.Names {
+							if strings.HasPrefix(n.Name, "Err") && !registered[n.Name] {
+								t.Errorf("%s: %s has no ErrorSpec in taxonomy.go", file, n.Name)
+							}
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Name.Name != "Error" || d.Recv == nil || len(d.Recv.List) != 1 {
+					continue
+				}
+				typ := d.Recv.List[0].Type
+				if star, ok := typ.(*ast.StarExpr); ok {
+					typ = star.X
+				}
+				if id, ok := typ.(*ast.Ident); ok && id.IsExported() && !registered[id.Name] {
+					t.Errorf("%s: error type %s has no ErrorSpec in taxonomy.go", file, id.Name)
+				}
+			}
+		}
+	}
+}
+
+func TestReportedChecksMatchTheirSpec(t *testing.T) {
+	weak := signedBundle(t, 4, map[string]interface{}{"n": 1})
+	delete(weak.SigningDict, "nonce")
+	bundles := []ProofBundle{loadProofBundle(t), signedBundle(t, 5, nil), weak}
+	opts := VerifyOptions{RejectWeakKeys: true, Freshness: time.Minute}
+	for _, b := range bundles {
+		report, _ := Verify(b, opts)
+		for _, c := range report.Checks {
+			spec, ok := LookupCheck(c.ID)
+			switch {
+			case !ok:
+				t.Errorf("reported check %s has no CheckSpec", c.ID)
+			case c.Section != spec.Section:
+				t.Errorf("%s: section %q, CheckSpec says %q", c.ID, c.Section, spec.Section)
+			case c.Category != spec.Category && spec.Note == "":
+				t.Errorf("%s: category %s, CheckSpec says %s", c.ID, c.Category, spec.Category)
+			}
+		}
+	}
+}
+
+func TestTaxonomyVerdicts(t *testing.T) {
+	seen := make(map[int]Verdict)
+	for _, v := range Verdicts() {
+		code := v.ExitCode()
+		if prev, dup := seen[code]; dup || code == 2 {
+			t.Errorf("%s exits %d, shared with %q or the usage code", v, code, prev)
+		}
+		seen[code] = v
+	}
+	if len(seen) != len(categoryNames)+1 {
+		t.Errorf("verdicts %v, want one per category plus VERIFIED", Verdicts())
+	}
+	for _, c := range Categories() {
+		if c.Verdict() == VerdictVerified {
+			t.Errorf("a failed %s check does not change the verdict", c)
+		}
+	}
+	if !(CheckSpec{ID: "N{depth}.nested_bundle"}).Matches("N12.nested_bundle") ||
+		(CheckSpec{ID: "N{depth}.nested_bundle"}).Matches("N.nested_bundle") {
+		t.Error("pattern matching of run-time IDs is wrong")
+	}
+}
@@ -0,0 +1,136 @@
+// cross_lang_proof/pkg/gefverify/trust.go
+//
+// Trusted keys (CONTRACT 10, VerifyOptions.TrustedKeys)
+// =====================================================
+//
+//   NewVerifier(WithTrustedKeys(keys))
+//   verify_proof -trusted-keys keys.json bundle.json
+//
+// The signature contracts verify a bundle under its own public_key_hex:
+// they prove the record is intact, not who signed it, since anyone can
+// re-sign an edited record with a key of their own. CONTRACT 10 checks
+// the key against a list the caller trusts:
+//
+//   [{"public_key_hex": "3b6a27bc…"},
+//    {"public_key_hex": "d75a9801…", "agent_id": "agent-a"},
+//    {"public_key_hex": "d75a9801…", "agent_id": "agent-b"}]
+//
+//   C10.key_trusted        public_key_hex is a listed key; a failure
+//                          shows the fingerprint of the unlisted key
+//   C10.key_agent_binding  a key listed with agent_id signs only for
+//                          those agents (a listed key only)
+//
+// Keys compare as key material: a listed key matches if it decodes
+// under the bundle's sig_alg to the key that verified, so a P-256 key
+// listed as an SEC1 point matches the same key written as PKIX. Both are
+// trust failures: the record is intact, the verdict
+// VERIFIED_UNTRUSTED_KEY. With no TrustedKeys the contract runs with no
+// checks and leaves a note; the CLI warns that key trust was not checked.
+
+package gefverify
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// TrustedKey is one entry of a trusted keys list. AgentID, if set,
+// binds the key to that agent.
+type TrustedKey struct {
+	PublicKeyHex string `json:"public_key_hex"`
+	AgentID      string `json:"agent_id,omitempty"`
+}
+
+// ParseTrustedKeys decodes a JSON array of trusted keys. Every entry
+// needs a public_key_hex that decodes as hex. Anything but an array is
+// an error: null would decode to no list and turn CONTRACT 10 off.
+func ParseTrustedKeys(data []byte) ([]TrustedKey, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '[' {
+		return nil, fmt.Errorf("trusted keys: want a JSON array of keys")
+	}
+	var list []TrustedKey
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("trusted keys: %w", err)
+	}
+	for i, k := range list {
+		if k.PublicKeyHex == "" {
+			return nil, fmt.Errorf("trusted keys: entry %d: missing public_key_hex", i)
+		}
+		if _, err := hex.DecodeString(k.PublicKeyHex); err != nil {
+			return nil, fmt.Errorf("trusted keys: entry %d: public_key_hex: %w", i, err)
+		}
+	}
+	return list, nil
+}
+
+// LoadTrustedKeys reads and parses a trusted keys file.
+func LoadTrustedKeys(path string) ([]TrustedKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTrustedKeys(data)
+}
+
+// checkKeyTrust runs CONTRACT 10 for a bundle whose public key, key,
+// signed for agentID.
+func (v *Verifier) checkKeyTrust(r *run, key PublicKey, agentID string) {
+	if v.opts.TrustedKeys == nil {
+		r.notes = append(r.notes, "key trust not checked: no trusted keys given, the bundle's own public key was used")
+		return
+	}
+	listed, anyAgent := false, false
+	var agents []string
+	for _, k := range v.opts.TrustedKeys {
+		if !key.matches(k.PublicKeyHex) {
+			continue
+		}
+		listed = true
+		if k.AgentID == "" {
+			anyAgent = true
+		} else {
+			agents = append(agents, k.AgentID)
+		}
+	}
+	details := "key " + key.Fingerprint() + " is trusted"
+	if !listed {
+		details = fmt.Sprintf("untrusted key %s: not among the %d trusted key(s)", key.Fingerprint(), len(v.opts.TrustedKeys))
+	}
+	r.check(
+		"C10.key_trusted",
+		CategoryTrust,
+		"public key is a trusted key",
+		listed,
+		details,
+	)
+	if !listed || anyAgent {
+		return
+	}
+	sort.Strings(agents)
+	bound := false
+	for _, a := range agents {
+		bound = bound || a == agentID
+	}
+	details = fmt.Sprintf("agent_id=%s", agentID)
+	if !bound {
+		details = fmt.Sprintf("key %s signs for %s, not agent_id=%s", key.Fingerprint(), strings.Join(agents, ", "), agentID)
+	}
+	r.check(
+		"C10.key_agent_binding",
+		CategoryTrust,
+		"key is trusted for this agent_id",
+		bound,
+		details,
+	)
+}
+
+// matches reports whether pubHex decodes under k.Alg to k.
+func (k PublicKey) matches(pubHex string) bool {
+	other, err := DecodeBundleKey(k.Alg, pubHex)
+	return err == nil && bytes.Equal(other.Bytes, k.Bytes)
+}
@@ -0,0 +1,83 @@
+// cross_lang_proof/pkg/gefverify/trust_test.go
+
+package gefverify
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestKeyTrust(t *testing.T) {
+	b := loadProofBundle(t)
+	agentID := b.SigningDict["agent_id"].(string)
+	key := strings.ToUpper(b.PublicKeyHex)
+	other := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name    string
+		keys    []TrustedKey
+		verdict Verdict
+		failed  string // failing check ID, "" if none
+		details string
+	}{
+		{"listed", []TrustedKey{{PublicKeyHex: other}, {PublicKeyHex: key}}, VerdictVerified, "", "is trusted"},
+		{"unlisted", []TrustedKey{{PublicKeyHex: other}}, VerdictVerifiedUntrustedKey, "C10.key_trusted", "untrusted key sha256:"},
+		{"empty list", []TrustedKey{}, VerdictVerifiedUntrustedKey, "C10.key_trusted", "not among the 0 trusted key(s)"},
+		{"bound", []TrustedKey{{PublicKeyHex: key, AgentID: "agent-x"}, {PublicKeyHex: key, AgentID: agentID}}, VerdictVerified, "", ""},
+		{"bound elsewhere", []TrustedKey{{PublicKeyHex: key, AgentID: "agent-x"}}, VerdictVerifiedUntrustedKey, "C10.key_agent_binding", "signs for agent-x, not agent_id=" + agentID},
+		{"also unbound", []TrustedKey{{PublicKeyHex: key, AgentID: "agent-x"}, {PublicKeyHex: key}}, VerdictVerified, "", ""},
+	}
+	for _, tt := range tests {
+		report, err := NewVerifier(WithTrustedKeys(tt.keys)).Verify(b)
+		if err != nil || report.Verdict != tt.verdict {
+			t.Errorf("%s: verdict %s, err %v, failed %v", tt.name, report.Verdict, err, failedIDs(report))
+			continue
+		}
+		if tt.failed == "" {
+			if c, _ := checkByID(report, "C10.key_trusted"); !c.Passed || !strings.Contains(c.Details, tt.details) {
+				t.Errorf("%s: C10.key_trusted %+v", tt.name, c)
+			}
+			continue
+		}
+		if c, ok := checkByID(report, tt.failed); !ok || c.Passed || !strings.Contains(c.Details, tt.details) {
+			t.Errorf("%s: %s %+v, want details with %q", tt.name, tt.failed, c, tt.details)
+		}
+	}
+
+	report, _ := Verify(b, VerifyOptions{})
+	if _, ok := checkByID(report, "C10.key_trusted"); ok || !strings.Contains(strings.Join(report.Notes, "\n"), "key trust not checked") {
+		t.Errorf("no trusted keys: checks %v, notes %q", report.Checks, report.Notes)
+	}
+}
+
+func TestParseTrustedKeys(t *testing.T) {
+	keys, err := ParseTrustedKeys([]byte(`[{"public_key_hex": "abcd"}, {"public_key_hex": "ef01", "agent_id": "a"}]`))
+	if err != nil || len(keys) != 2 || keys[1].AgentID != "a" {
+		t.Errorf("keys %v, err %v", keys, err)
+	}
+	for _, bad := range []string{`null`, ` null `, ``, `{}`, `"abcd"`, `[{"agent_id": "a"}]`, `[{"public_key_hex": "xyz"}]`} {
+		if _, err := ParseTrustedKeys([]byte(bad)); err == nil || !strings.HasPrefix(err.Error(), "trusted keys: ") {
+			t.Errorf("%s: err %v", bad, err)
+		}
+	}
+}
+
+func TestKeyTrustP256Encodings(t *testing.T) {
+	// Signed under one encoding of the key, trusted under the other.
+	for _, der := range []bool{false, true} {
+		b := p256Bundle(t, der)
+		key, err := DecodeBundleKey(b.SigAlg, b.PublicKeyHex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		other := hex.EncodeToString(key.Bytes)
+		if !der {
+			other = hex.EncodeToString(append(append([]byte(nil), p256SPKIPrefix...), key.Bytes...))
+		}
+		report, err := NewVerifier(WithTrustedKeys([]TrustedKey{{PublicKeyHex: other}})).Verify(b)
+		if err != nil || report.Verdict != VerdictVerified {
+			t.Errorf("der=%v: verdict %s, err %v, failed %v", der, report.Verdict, err, failedIDs(report))
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// cross_lang_proof/pkg/gefverify/validate.go
+//
+// Bundle validation (before any contract)
+// =======================================
+//
+// The contracts decode, slice and compare the bundle's fields; Validate
+// checks first that each is present and sized for that:
+//
+//   sig_alg               absent, ed25519 or ecdsa-p256 (sigalg.go)
+//   public_key_hex        64 hex characters (32 bytes); for ecdsa-p256
+//                         an uncompressed SEC1 point or a PKIX key
+//   signing_dict          a non-empty JSON object
+//   canonical_bytes_hex   hex, if present (absent: CONTRACT 1 does not
+//                         apply)
+//   chain_dict            a non-empty JSON object
+//   chain_bytes_hex       non-empty hex
+//   causal_hash_of_this   hex of the digest size of hash_alg (64
+//                         characters for SHA-256); any hex under
+//                         WithChainHash or for an unknown hash_alg,
+//                         which CONTRACT 2 judges
+//   chain_hash_alg        absent: the algorithm is named in hash_alg,
+//                         and a bundle naming it here would otherwise
+//                         be hashed with SHA-256
+//   signature_b64url      base64url of 64 bytes; for ecdsa-p256, or of
+//                         an ASN.1 DER signature
+//   signature_hex         the same bytes in hex, if present: other
+//                         bytes than signature_b64url's are a problem
+//
+// Every field is checked, not just up to the first problem. Verify fails
+// one V.bundle_field.{field} check per problem, in the VALIDATION
+// section, then stops with a *MalformedError: no contract runs on a
+// bundle it could not read. Whether the values are right is for the
+// contracts; gef_version is compared with the signed one by CONTRACT 7.
+
+package gefverify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SectionValidation is the heading of the bundle field checks.
+const SectionValidation = "VALIDATION — Bundle Fields (before any contract)"
+
+// ValidationError is one bundle field that fails validation.
+type ValidationError struct {
+	Path    string // JSON path of the field, e.g. "signature_b64url"
+	Problem string // e.g. "illegal character at offset 12"
+}
+
+func (e ValidationError) Error() string { return e.Path + ": " + e.Problem }
+
+// ValidationErrors are the problems Validate found, in bundle order.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	parts := make([]string, len(e))
+	for i, v := range e {
+		parts[i] = v.Error()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Validate checks every field the contracts read; see the table at the
+// top of validate.go. It returns nil, or a *MalformedError wrapping the
+// ValidationErrors:
+//
+//	var problems gefverify.ValidationErrors
+//	if errors.As(bundle.Validate(), &problems) { ... }
+//
+// causal_hash_of_this is checked against the digest size of hash_alg
+// in DefaultHashAlgorithms; Verify checks it against the chain hashes it
+// was configured with.
+func (b ProofBundle) Validate() error {
+	if errs := b.validate(NewVerifier().hashSize(b.HashAlg)); len(errs) > 0 {
+		return &MalformedError{"invalid proof bundle", errs}
+	}
+	return nil
+}
+
+// validate returns the problems of b, with a chain hash of hashSize
+// bytes (any size for 0). A field ParseBundle could not decode is
+// reported as such.
+func (b ProofBundle) validate(hashSize int) ValidationErrors {
+	errs := append(ValidationErrors(nil), b.decodeErrs...)
+	failed := make(map[string]bool, len(errs))
+	for _, e := range errs {
+		failed[e.Path] = true
+	}
+	add := func(path, problem string) {
+		if problem != "" && !failed[path] {
+			errs = append(errs, ValidationError{path, problem})
+			failed[path] = true
+		}
+	}
+	ed := b.SigAlg == "" || b.SigAlg == SigAlgEd25519
+	switch {
+	case ed:
+		add("public_key_hex", hexProblem(b.PublicKeyHex, 32))
+	case b.SigAlg == SigAlgECDSAP256:
+		add("public_key_hex", p256KeyProblem(b.PublicKeyHex))
+	default:
+		add("sig_alg", unsupportedSigAlg(b.SigAlg))
+	}
+	add("signing_dict", objectProblem(b.SigningDict))
+	if b.CanonicalBytesHex != "" {
+		add("canonical_bytes_hex", hexProblem(b.CanonicalBytesHex, 0))
+	}
+	add("chain_dict", objectProblem(b.ChainDict))
+	add("chain_bytes_hex", hexProblem(b.ChainBytesHex, 0))
+	add("causal_hash_of_this", hexProblem(b.CausalHashOfThis, hashSize))
+	if b.chainHashAlg {
+		add("chain_hash_alg", "not a bundle field: name the chain hash algorithm in hash_alg")
+	}
+	switch {
+	case ed:
+		add("signature_b64url", base64URLProblem(b.SignatureB64URL, 64))
+		if b.SignatureHex != "" {
+			add("signature_hex", hexProblem(b.SignatureHex, 64))
+		}
+	case b.SigAlg == SigAlgECDSAP256:
+		problem := base64URLProblem(b.SignatureB64URL, 0)
+		if problem == "" {
+			sig, _ := decodeBase64URL(b.SignatureB64URL)
+			problem = p256SignatureProblem(sig)
+		}
+		add("signature_b64url", problem)
+		if b.SignatureHex != "" {
+			problem = hexProblem(b.SignatureHex, 0)
+			if problem == "" {
+				sig, _ := hex.DecodeString(b.SignatureHex)
+				problem = p256SignatureProblem(sig)
+			}
+			add("signature_hex", problem)
+		}
+	}
+	if b.SignatureHex != "" && !failed["sig_alg"] && !failed["signature_b64url"] && !failed["signature_hex"] {
+		sig, _ := decodeBase64URL(b.SignatureB64URL)
+		sigHex, _ := hex.DecodeString(b.SignatureHex)
+		if !bytes.Equal(sig, sigHex) {
+			add("signature_hex", "decodes to other bytes than signature_b64url")
+		}
+	}
+	return errs
+}
+
+// hexProblem describes what is wrong with s as hex of size bytes (any
+// non-zero number for size 0), or returns "".
+func hexProblem(s string, size int) string {
+	if s == "" {
+		return "missing or empty"
+	}
+	for i, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return fmt.Sprintf("illegal character %q at offset %d", c, i)
+		}
+	}
+	switch {
+	case len(s)%2 != 0:
+		return fmt.Sprintf("odd length %d", len(s))
+	case size > 0 && len(s) != 2*size:
+		return fmt.Sprintf("%d hex characters, want %d", len(s), 2*size)
+	}
+	return ""
+}
+
+// base64URLProblem describes what is wrong with s as base64url of size
+// bytes (any number for size 0; see decodeBase64URL), or returns "".
+func base64URLProblem(s string, size int) string {
+	if s == "" {
+		return "missing or empty"
+	}
+	raw, err := decodeBase64URL(s)
+	var corrupt base64.CorruptInputError
+	switch {
+	case errors.As(err, &corrupt):
+		return fmt.Sprintf("illegal character at offset %d", corrupt)
+	case err != nil:
+		return err.Error()
+	case size > 0 && len(raw) != size:
+		return fmt.Sprintf("decodes to %d bytes, want %d", len(raw), size)
+	}
+	return ""
+}
+
+// p256KeyProblem describes what is wrong with s as a P-256 public key,
+// or returns "".
+func p256KeyProblem(s string) string {
+	if problem := hexProblem(s, 0); problem != "" {
+		return problem
+	}
+	if _, err := DecodeP256PublicKey(s); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func objectProblem(m map[string]interface{}) string {
+	switch {
+	case m == nil:
+		return "missing or not a JSON object"
+	case len(m) == 0:
+		return "empty"
+	}
+	return ""
+}
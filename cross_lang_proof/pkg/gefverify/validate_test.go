@@ -0,0 +1,102 @@
+// cross_lang_proof/pkg/gefverify/validate_test.go
+
+package gefverify
+
+import (
+	"crypto/sha512"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	if err := loadProofBundle(t).Validate(); err != nil {
+		t.Fatalf("reference bundle: %v", err)
+	}
+	b := loadProofBundle(t)
+	b.PublicKeyHex = "ab"
+	b.ChainDict = map[string]interface{}{}
+	b.ChainBytesHex = "7b7"
+	b.CausalHashOfThis = strings.Repeat("0", 63) + "z"
+	b.SignatureB64URL = b.SignatureB64URL[:12] + "+" + b.SignatureB64URL[13:]
+	b.SignatureHex = ""
+
+	var problems ValidationErrors
+	var malformed *MalformedError
+	err := b.Validate()
+	if !errors.As(err, &malformed) || !errors.As(err, &problems) {
+		t.Fatalf("err = %v, want *MalformedError wrapping ValidationErrors", err)
+	}
+	want := ValidationErrors{
+		{"public_key_hex", "2 hex characters, want 64"},
+		{"chain_dict", "empty"},
+		{"chain_bytes_hex", "odd length 3"},
+		{"causal_hash_of_this", `illegal character 'z' at offset 63`},
+		{"signature_b64url", "illegal character at offset 12"},
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems\n got %v\nwant %v", problems, want)
+	}
+
+	// Verify fails one check per problem, and runs no contract.
+	report, err := Verify(b, VerifyOptions{})
+	if !errors.As(err, &problems) || report.Verdict != VerdictMalformed || len(report.Checks) != len(want) {
+		t.Fatalf("err=%v verdict=%s checks=%v", err, report.Verdict, failedIDs(report))
+	}
+	for i, c := range report.Checks {
+		if c.ID != "V.bundle_field."+want[i].Path || c.Section != SectionValidation || c.Details != want[i].Error() {
+			t.Errorf("check %d: %+v, want %v", i, c, want[i])
+		}
+	}
+}
+
+func TestValidateSignatureHexMatches(t *testing.T) {
+	b := loadProofBundle(t)
+	b.SignatureHex = strings.Repeat("00", 64)
+	report, err := Verify(b, VerifyOptions{})
+	if _, found := checkByID(report, "V.bundle_field.signature_hex"); err == nil || report.Verdict != VerdictMalformed || !found {
+		t.Errorf("err=%v verdict=%s failed=%v", err, report.Verdict, failedIDs(report))
+	}
+}
+
+func TestValidateChainHashSize(t *testing.T) {
+	// Under WithChainHash CONTRACT 2 judges the hash, whatever its size.
+	report, err := NewVerifier(WithChainHash(sha512.New)).Verify(loadProofBundle(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := checkByID(report, "C2.chain_hash"); !ok || c.Passed {
+		t.Errorf("C2.chain_hash: %+v", c)
+	}
+}
+
+func TestParseBundleTypeErrors(t *testing.T) {
+	data, err := os.ReadFile("../../proof_bundle.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.NewReplacer(
+		`"public_key_hex": "191d5a13a26d64f8d43b0406cda76bbcbf429e7507b88eadfdaa43ba3749dd2b"`, `"public_key_hex": 5`,
+		`"chain_dict": {`, `"chain_dict": [], "unused": {`,
+	).Replace(string(data)))
+
+	want := ValidationErrors{
+		{"public_key_hex", "is a JSON number, want a string"},
+		{"chain_dict", "is a JSON array, want an object"},
+	}
+	bundle, err := ParseBundle(data)
+	var problems ValidationErrors
+	if !errors.As(err, &problems) || !reflect.DeepEqual(problems, want) {
+		t.Fatalf("err = %v, want %v", err, want)
+	}
+	if bundle.SignatureB64URL == "" {
+		t.Error("fields of the right type were not decoded")
+	}
+
+	report, _ := VerifyBytes(data, VerifyOptions{})
+	if got := failedIDs(report); !reflect.DeepEqual(got, []string{"V.bundle_field.public_key_hex", "V.bundle_field.chain_dict"}) {
+		t.Errorf("failed %v", got)
+	}
+}
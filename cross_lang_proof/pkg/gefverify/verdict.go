@@ -0,0 +1,117 @@
+// cross_lang_proof/pkg/gefverify/verdict.go
+//
+// Verdicts
+// ========
+//
+// Every check belongs to a category. The top-level verdict is derived
+// from the categories of the FAILED checks, first matching rule wins:
+//
+//   1. any structure    check failed → MALFORMED
+//   2. any integrity    check failed → TAMPERED
+//   3. any policy       check failed → POLICY_REJECTED
+//   4. any completeness check failed → UNVERIFIABLE
+//   5. any trust        check failed → VERIFIED_UNTRUSTED_KEY
+//   6. otherwise                      → VERIFIED
+//
+// Rule order is deliberate: a bundle that cannot even be parsed says
+// nothing about tampering, and a tampered bundle must never be reported
+// as merely "policy rejected" or "unverifiable".
+//
+// Exit codes (2 is reserved for usage and I/O errors, which have no
+// verdict):
+//
+//   VERIFIED 0 · TAMPERED 1 · MALFORMED 3 · POLICY_REJECTED 4
+//   UNVERIFIABLE 5 · VERIFIED_UNTRUSTED_KEY 6
+
+package gefverify
+
+// Verdict is the top-level outcome of a verification run.
+type Verdict string
+
+const (
+	VerdictVerified             Verdict = "VERIFIED"
+	VerdictVerifiedUntrustedKey Verdict = "VERIFIED_UNTRUSTED_KEY"
+	VerdictPolicyRejected       Verdict = "POLICY_REJECTED"
+	VerdictUnverifiable         Verdict = "UNVERIFIABLE"
+	VerdictTampered             Verdict = "TAMPERED"
+	VerdictMalformed            Verdict = "MALFORMED"
+)
+
+// Category says what a failed check means for the verdict.
+type Category int
+
+const (
+	// CategoryIntegrity — bytes, hashes or signatures do not match.
+	CategoryIntegrity Category = iota
+	// CategoryStructure — the bundle or record is not well-formed.
+	CategoryStructure
+	// CategoryPolicy — cryptographically fine, but a local policy says no.
+	CategoryPolicy
+	// CategoryCompleteness — something needed to verify is missing.
+	CategoryCompleteness
+	// CategoryTrust — the signing key is not known to be trusted.
+	CategoryTrust
+)
+
+var categoryNames = [...]string{
+	CategoryIntegrity:    "integrity",
+	CategoryStructure:    "structure",
+	CategoryPolicy:       "policy",
+	CategoryCompleteness: "completeness",
+	CategoryTrust:        "trust",
+}
+
+// String returns the lower-case category name used in JSON reports.
+func (c Category) String() string {
+	if c < 0 || int(c) >= len(categoryNames) {
+		return "unknown"
+	}
+	return categoryNames[c]
+}
+
+// verdictRules is the documented derivation order.
+var verdictRules = []struct {
+	category Category
+	verdict  Verdict
+}{
+	{CategoryStructure, VerdictMalformed},
+	{CategoryIntegrity, VerdictTampered},
+	{CategoryPolicy, VerdictPolicyRejected},
+	{CategoryCompleteness, VerdictUnverifiable},
+	{CategoryTrust, VerdictVerifiedUntrustedKey},
+}
+
+// DeriveVerdict applies verdictRules to a set of check results.
+func DeriveVerdict(checks []CheckResult) Verdict {
+	failed := make(map[Category]bool)
+	for _, c := range checks {
+		if !c.Passed && c.Exception == "" {
+			failed[c.Category] = true
+		}
+	}
+	for _, rule := range verdictRules {
+		if failed[rule.category] {
+			return rule.verdict
+		}
+	}
+	return VerdictVerified
+}
+
+// ExitCode maps a verdict to the process exit code.
+func (v Verdict) ExitCode() int {
+	switch v {
+	case VerdictVerified:
+		return 0
+	case VerdictTampered:
+		return 1
+	case VerdictMalformed:
+		return 3
+	case VerdictPolicyRejected:
+		return 4
+	case VerdictUnverifiable:
+		return 5
+	case VerdictVerifiedUntrustedKey:
+		return 6
+	}
+	return 1
+}
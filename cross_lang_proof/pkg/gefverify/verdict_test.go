@@ -0,0 +1,60 @@
+// cross_lang_proof/pkg/gefverify/verdict_test.go
+
+package gefverify
+
+import "testing"
+
+func TestDeriveVerdict(t *testing.T) {
+	pass := func(c Category) CheckResult { return CheckResult{Name: "ok", Passed: true, Category: c} }
+	fail := func(c Category) CheckResult { return CheckResult{Name: "bad", Passed: false, Category: c} }
+
+	tests := []struct {
+		name   string
+		checks []CheckResult
+		want   Verdict
+	}{
+		{"no checks", nil, VerdictVerified},
+		{"all pass", []CheckResult{pass(CategoryIntegrity), pass(CategoryStructure), pass(CategoryPolicy)}, VerdictVerified},
+		{"structure only", []CheckResult{fail(CategoryStructure)}, VerdictMalformed},
+		{"integrity only", []CheckResult{fail(CategoryIntegrity)}, VerdictTampered},
+		{"policy only", []CheckResult{pass(CategoryIntegrity), fail(CategoryPolicy)}, VerdictPolicyRejected},
+		{"completeness only", []CheckResult{pass(CategoryIntegrity), fail(CategoryCompleteness)}, VerdictUnverifiable},
+		{"trust only", []CheckResult{pass(CategoryIntegrity), fail(CategoryTrust)}, VerdictVerifiedUntrustedKey},
+		{"structure beats integrity", []CheckResult{fail(CategoryIntegrity), fail(CategoryStructure)}, VerdictMalformed},
+		{"integrity beats policy", []CheckResult{fail(CategoryPolicy), fail(CategoryIntegrity)}, VerdictTampered},
+		{"policy beats completeness", []CheckResult{fail(CategoryCompleteness), fail(CategoryPolicy)}, VerdictPolicyRejected},
+		{"completeness beats trust", []CheckResult{fail(CategoryTrust), fail(CategoryCompleteness)}, VerdictUnverifiable},
+		{"integrity beats trust", []CheckResult{fail(CategoryTrust), fail(CategoryIntegrity)}, VerdictTampered},
+		{"everything failed", []CheckResult{
+			fail(CategoryTrust), fail(CategoryCompleteness), fail(CategoryPolicy),
+			fail(CategoryIntegrity), fail(CategoryStructure),
+		}, VerdictMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DeriveVerdict(tt.checks); got != tt.want {
+				t.Errorf("DeriveVerdict() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerdictExitCode(t *testing.T) {
+	tests := []struct {
+		verdict Verdict
+		want    int
+	}{
+		{VerdictVerified, 0},
+		{VerdictTampered, 1},
+		{VerdictMalformed, 3},
+		{VerdictPolicyRejected, 4},
+		{VerdictUnverifiable, 5},
+		{VerdictVerifiedUntrustedKey, 6},
+		{Verdict("SOMETHING_NEW"), 1},
+	}
+	for _, tt := range tests {
+		if got := tt.verdict.ExitCode(); got != tt.want {
+			t.Errorf("%s.ExitCode() = %d, want %d", tt.verdict, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,874 @@
+// cross_lang_proof/pkg/gefverify/verifier.go
+//
+// GEF Verifier
+// ============
+//
+// Independently recomputes, using ONLY Go standard library + JCS, one
+// contract per CONTRACT n section:
+//
+//    1. canonical bytes  = JCS(signing_dict), if canonical_bytes_hex is given
+//    2. chain hash       = H(JCS(chain_dict)), H the bundle's hash_alg
+//                          (SHA-256 when absent)
+//    3. signature valid  = Ed25519.Verify(public_key, canonical_bytes, signature),
+//                          or ECDSA P-256 under the bundle's sig_alg
+//    4. dict identity    = signing_dict == chain_dict, without signature
+//    5. field count      = signing_dict holds every required field
+//    6. NEGATIVE TEST    = flip one byte → signature must FAIL
+//    7. version binding  = signing_dict.gef_version == bundle gef_version
+//    8. envelope         = envelope_json == signing_dict + signature
+//    9. timestamp        = signing_dict.timestamp parses as RFC 3339
+//   10. key trust        = public_key_hex is a trusted key (if given)
+//   11. signer binding   = signing_dict.signer_public_key is public_key_hex
+//
+// A Verifier holds configuration only. Verify keeps all state on the
+// stack, so one Verifier is safe for concurrent use by many goroutines.
+//
+// Verification never modifies its inputs: not the bundle's maps and
+// slices, not the bytes given to VerifyBytes, VerifyDetached,
+// VerifyCarried, BundleFromEnvelope, ParseBundle or Lint, and not the
+// Exceptions, FieldAliases or SizeLimitTable in VerifyOptions; CONTRACT
+// 6 corrupts copies. A caller may reuse the inputs afterwards and read
+// them while a verification is running; immutability_test.go holds
+// every entry point to this, including under -race.
+
+package gefverify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+	"time"
+)
+
+// Contract section headings, in execution order.
+const (
+	SectionCanonicalBytes = "CONTRACT 1 — Canonical Bytes (RFC 8785 JCS)"
+	SectionChainHash      = "CONTRACT 2 — Chain Hash (hash_alg of JCS chain dict)"
+	SectionSignature      = "CONTRACT 3 — Signature Verification (positive, sig_alg)"
+	SectionDictIdentity   = "CONTRACT 4 — Signing Dict == Chain Dict"
+	SectionFieldCount     = "CONTRACT 5 — Field Count (signing dict completeness)"
+	SectionNegativeTest   = "CONTRACT 6 — NEGATIVE TEST: Single Byte Flip Must Fail"
+	SectionVersionBinding = "CONTRACT 7 — Version Binding (signed vs advertised gef_version)"
+	SectionEnvelope       = "CONTRACT 8 — Envelope Consistency (envelope_json vs signed values)"
+	SectionTimestamp      = "CONTRACT 9 — Timestamp (signing_dict.timestamp is RFC 3339)"
+	SectionKeyTrust       = "CONTRACT 10 — Key Trust (public_key_hex vs trusted keys)"
+	SectionSignerBinding  = "CONTRACT 11 — Signer Binding (signer_public_key vs public_key_hex)"
+	SectionFreshness      = "POLICY — Timestamp Freshness"
+	SectionSizeLimits     = "POLICY — Size Limits"
+)
+
+// RequiredContracts are the sections every verification must execute
+// before a report can be OK. Policies are optional and not listed.
+var RequiredContracts = []string{
+	SectionCanonicalBytes,
+	SectionChainHash,
+	SectionSignature,
+	SectionDictIdentity,
+	SectionFieldCount,
+	SectionNegativeTest,
+	SectionVersionBinding,
+	SectionEnvelope,
+	SectionTimestamp,
+	SectionKeyTrust,
+	SectionSignerBinding,
+}
+
+// RequiredFields are the signing_dict fields of GEF-SPEC-v1.0, sorted.
+var RequiredFields = []string{
+	"agent_id", "causal_hash", "gef_version", "nonce",
+	"payload", "record_id", "record_type", "sequence",
+	"signer_public_key", "timestamp",
+}
+
+// ── Verifier ──────────────────────────────────────────────────────────────────
+
+// Verifier verifies proof bundles with a fixed VerifyOptions.
+type Verifier struct {
+	opts VerifyOptions
+
+	// Resolved from opts, defaults applied.
+	aliases   FieldAliases
+	fields    []string // nil: versions decides
+	versions  VersionTable
+	chainHash func() hash.Hash
+	chainAlg  string // name of chainHash in hashAlgs, "custom" if absent
+	hashAlgs  HashAlgorithms
+	now       func() time.Time
+	metrics   MetricsRecorder
+}
+
+// NewVerifier returns a Verifier. With no options it runs all eleven
+// contracts, C1 to C11, under the defaults documented on VerifyOptions.
+func NewVerifier(opts ...Option) *Verifier {
+	var o VerifyOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return NewVerifierFromOptions(o)
+}
+
+// NewVerifierFromOptions returns a Verifier configured by o, applying
+// the zero-value defaults documented on VerifyOptions.
+func NewVerifierFromOptions(o VerifyOptions) *Verifier {
+	v := &Verifier{
+		opts:      o,
+		aliases:   o.FieldAliases,
+		fields:    o.ExpectedFields,
+		versions:  o.Versions,
+		chainHash: o.ChainHash,
+		hashAlgs:  o.HashAlgorithms,
+		now:       time.Now,
+		metrics:   o.Metrics,
+	}
+	if !o.ReferenceTime.IsZero() {
+		v.now = func() time.Time { return o.ReferenceTime }
+	}
+	if v.aliases == nil {
+		v.aliases = DefaultFieldAliases
+	}
+	if v.versions == nil {
+		v.versions = DefaultVersions
+	}
+	if v.chainHash == nil {
+		v.chainHash = sha256.New
+	}
+	if v.hashAlgs == nil {
+		v.hashAlgs = DefaultHashAlgorithms
+	}
+	v.chainAlg = v.hashAlgs.nameOf(v.chainHash)
+	if v.metrics == nil {
+		v.metrics = nopMetrics{}
+	}
+	return v
+}
+
+// Options returns the options v was built from, as given.
+func (v *Verifier) Options() VerifyOptions { return v.opts }
+
+// Verify verifies bundle with opts. It is shorthand for
+// NewVerifierFromOptions(opts).Verify(bundle).
+func Verify(bundle ProofBundle, opts VerifyOptions) (Report, error) {
+	return NewVerifierFromOptions(opts).Verify(bundle)
+}
+
+// VerifyBytes parses data as a proof bundle and verifies it with opts.
+// It is shorthand for NewVerifierFromOptions(opts).VerifyBytes(data).
+func VerifyBytes(data []byte, opts VerifyOptions) (Report, error) {
+	return NewVerifierFromOptions(opts).VerifyBytes(data)
+}
+
+// run collects checks for one verification.
+type run struct {
+	section  string
+	checks   []CheckResult
+	notes    []string
+	warnings []string
+	status   map[string]ContractStatus
+	nested   []NestedReport
+
+	fieldTraces []FieldTrace
+	exceptions  []AppliedException
+	profile     string
+	mutations   *MutationStats
+	fingerprint string
+	hashAlg     string
+	sigAlg      string
+
+	// encode is the transport encoding signatures cover (carried.go),
+	// nil for the bytes themselves.
+	encode func([]byte) []byte
+}
+
+func newRun() *run {
+	return &run{status: make(map[string]ContractStatus)}
+}
+
+// report assembles the Report, recording every required contract's status.
+func (r *run) report() Report {
+	contracts := make([]ContractResult, 0, len(RequiredContracts))
+	for _, s := range RequiredContracts {
+		status, ok := r.status[s]
+		if !ok {
+			status = ContractSkipped
+		}
+		contracts = append(contracts, ContractResult{Section: s, Status: status})
+	}
+	return Report{
+		Checks:         r.checks,
+		Notes:          r.notes,
+		Warnings:       r.warnings,
+		Contracts:      contracts,
+		Nested:         r.nested,
+		FieldTrace:     r.fieldTraces,
+		Exceptions:     r.exceptions,
+		Profile:        r.profile,
+		Mutations:      r.mutations,
+		KeyFingerprint: r.fingerprint,
+		HashAlg:        r.hashAlg,
+		SigAlg:         r.sigAlg,
+		Verdict:        DeriveVerdict(r.checks),
+	}
+}
+
+func (r *run) check(id string, category Category, name string, passed bool, details string, diagnostics ...string) {
+	r.checks = append(r.checks, CheckResult{
+		ID:          id,
+		Section:     r.section,
+		Name:        name,
+		Passed:      passed,
+		Details:     details,
+		Category:    category,
+		Diagnostics: diagnostics,
+	})
+}
+
+// contract runs one required contract as a timed phase. It counts as
+// executed only if fn returns without error.
+func (v *Verifier) contract(r *run, section, phase string, fn func() error) error {
+	r.section = section
+	r.status[section] = ContractAborted
+	err := v.phase(phase, fn)
+	if err == nil {
+		r.status[section] = ContractExecuted
+	}
+	return err
+}
+
+// phase times fn and reports it under name.
+func (v *Verifier) phase(name string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	v.metrics.ObserveDuration(name, time.Since(start))
+	return err
+}
+
+// Verify runs every contract against bundle, reading but never
+// modifying it. A non-nil error (always a *MalformedError) means
+// verification could not finish; the returned report then holds the
+// checks that did run and which contracts were aborted or skipped, with
+// verdict MALFORMED.
+func (v *Verifier) Verify(bundle ProofBundle) (Report, error) {
+	return v.verifyTop(newRun(), bundle)
+}
+
+// VerifyBytes parses data with ParseBundle and verifies the bundle. A
+// bundle that does not parse gives a report with no checks, verdict
+// MALFORMED, and the *MalformedError from ParseBundle; one with fields of
+// the wrong type is verified, and fails validation.
+func (v *Verifier) VerifyBytes(data []byte) (Report, error) {
+	bundle, err := ParseBundle(data)
+	var problems ValidationErrors
+	if err != nil && !errors.As(err, &problems) {
+		v.metrics.IncVerdict(VerdictMalformed)
+		return Report{Verdict: VerdictMalformed}, err
+	}
+	return v.Verify(bundle)
+}
+
+// abbrev is s for a check's details: its first n characters and "...",
+// or all of it with FullValues.
+func (v *Verifier) abbrev(s string, n int) string {
+	if v.opts.FullValues {
+		return s
+	}
+	return ShortHex(s, n) + "..."
+}
+
+// hashHex is the chain hash of canonical under v, hex-encoded.
+func (v *Verifier) hashHex(canonical []byte) string {
+	return sumHex(v.chainHash, canonical)
+}
+
+// sumHex is the hash newHash of data, hex-encoded.
+func sumHex(newHash func() hash.Hash, data []byte) string {
+	h := newHash()
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// verifyTop verifies a top-level bundle into r, which may already hold
+// checks of its own (see detached.go), and records metrics.
+func (v *Verifier) verifyTop(r *run, bundle ProofBundle) (Report, error) {
+	start := time.Now()
+	report, err := v.verifyAt(r, bundle, "", 0)
+	v.metrics.ObserveDuration(PhaseTotal, time.Since(start))
+	if err != nil {
+		v.metrics.IncVerdict(VerdictMalformed)
+		report.Verdict = VerdictMalformed
+		return report, err
+	}
+	for _, c := range report.Checks {
+		outcome := OutcomePass
+		switch {
+		case !c.Passed && c.Exception != "":
+			outcome = OutcomeExcepted
+		case !c.Passed:
+			outcome = OutcomeFail
+		}
+		v.metrics.IncCheckResult(c.ID, outcome)
+	}
+	v.metrics.IncVerdict(report.Verdict)
+	return report, nil
+}
+
+// verifyAt verifies bundle found at path, depth levels of nesting down
+// (the top-level bundle is path "", depth 0), adding checks to r.
+func (v *Verifier) verifyAt(r *run, bundle ProofBundle, path string, depth int) (Report, error) {
+	// ── Decode shared inputs ──────────────────────────────────
+	pubKey, err := DecodeBundleKey(bundle.SigAlg, bundle.PublicKeyHex)
+	if err == nil {
+		r.fingerprint = pubKey.Fingerprint()
+		pubKey.encode = r.encode
+	}
+	r.hashAlg, r.sigAlg = v.hashAlgName(bundle.HashAlg), bundle.SigAlg
+	if r.sigAlg == "" {
+		r.sigAlg = SigAlgEd25519
+	}
+	if problems := bundle.validate(v.hashSize(bundle.HashAlg)); len(problems) > 0 {
+		r.section = SectionValidation
+		for _, p := range problems {
+			field := p.Path
+			r.check(
+				"V.bundle_field."+field,
+				CategoryStructure,
+				fmt.Sprintf("%s well-formed", field),
+				false,
+				p.Error(),
+			)
+		}
+		return r.report(), &MalformedError{"invalid proof bundle", problems}
+	}
+	sigBytes, _ := DecodeBundleSignature(bundle.SigAlg, bundle.SignatureB64URL)
+
+	// Pre-1.0 records signed an empty signature member (see legacy.go).
+	signedVersion, _ := bundle.SigningDict["gef_version"].(string)
+	legacy := v.legacyProfile(r, signedVersion)
+	if legacy {
+		r.profile = ProfileLegacySigningField
+	}
+
+	// ════════════════════════════════════════════════════════
+	// CHECK 1 — Canonical bytes (JCS)
+	// Proves: RFC 8785 JCS is byte-identical across Python and Go.
+	// A bundle without canonical_bytes_hex makes no claim to
+	// cross-check: the contract is not applicable, and CONTRACT 3 verifies
+	// the signature over Go's bytes only.
+	// ════════════════════════════════════════════════════════
+	var goCanonicalBytes []byte
+	pythonCanonicalHex := bundle.CanonicalBytesHex
+	err = v.contract(r, SectionCanonicalBytes, PhaseCanonicalize, func() error {
+		var err error
+		goCanonicalBytes, err = Canonicalize(bundle.SigningDict)
+		if err != nil {
+			return &MalformedError{"canonicalize signing_dict", err}
+		}
+		if pythonCanonicalHex == "" {
+			r.notes = append(r.notes, "canonical_bytes_hex absent: CONTRACT 1 not applicable, the signature is verified over Go's canonical bytes only")
+			return nil
+		}
+		goCanonicalHex := hex.EncodeToString(goCanonicalBytes)
+		canonicalMatch := goCanonicalHex == pythonCanonicalHex
+
+		diagnostics := []string{
+			"Go     canonical: " + goCanonicalHex,
+			"Python canonical: " + pythonCanonicalHex,
+		}
+		if !canonicalMatch || v.opts.TraceFields {
+			traces, err := TraceFields(bundle.SigningDict, pythonCanonicalHex)
+			if err != nil {
+				diagnostics = append(diagnostics, "per-field trace unavailable: "+err.Error())
+			} else {
+				r.fieldTraces = traces
+				diagnostics = append(diagnostics, "Per-field canonical form (differences only):")
+				diagnostics = append(diagnostics, FieldTraceTable(traces, true)...)
+			}
+		}
+
+		r.check(
+			"C1.canonical_bytes",
+			CategoryIntegrity,
+			"canonical_bytes match",
+			canonicalMatch,
+			fmt.Sprintf("go=%s  python=%s",
+				v.abbrev(goCanonicalHex, 16), v.abbrev(pythonCanonicalHex, 16)),
+			diagnostics...,
+		)
+		return nil
+	})
+	if err != nil {
+		return r.report(), err
+	}
+	if pythonCanonicalHex == "" {
+		r.status[SectionCanonicalBytes] = ContractNotApplicable
+	}
+
+	// ════════════════════════════════════════════════════════
+	// CHECK 2 — Chain hash (hash_alg of JCS chain dict)
+	// Proves: causal_hash is byte-identical in Python and Go.
+	// ════════════════════════════════════════════════════════
+	err = v.contract(r, SectionChainHash, PhaseChainHash, func() error {
+		goChainCanonicalBytes, err := Canonicalize(bundle.ChainDict)
+		if err != nil {
+			return &MalformedError{"canonicalize chain_dict", err}
+		}
+
+		newHash, ok := v.bundleHash(bundle.HashAlg)
+		if !ok {
+			r.check(
+				"C2.hash_alg_supported",
+				CategoryStructure,
+				"hash_alg supported",
+				false,
+				v.unsupportedHashAlg(bundle.HashAlg),
+			)
+			return nil
+		}
+		goChainHashHex := sumHex(newHash, goChainCanonicalBytes)
+		chainHashMatch := goChainHashHex == bundle.CausalHashOfThis
+
+		r.check(
+			"C2.chain_hash",
+			CategoryIntegrity,
+			"chain_hash match",
+			chainHashMatch,
+			fmt.Sprintf("alg=%s  go=%s  python=%s", v.hashAlgName(bundle.HashAlg),
+				v.abbrev(goChainHashHex, 16), v.abbrev(bundle.CausalHashOfThis, 16)),
+			"Go     chain hash: "+goChainHashHex,
+			"Python chain hash: "+bundle.CausalHashOfThis,
+		)
+
+		goChainBytesHex := hex.EncodeToString(goChainCanonicalBytes)
+		chainBytesMatch := goChainBytesHex == bundle.ChainBytesHex
+
+		r.check(
+			"C2.chain_bytes",
+			CategoryIntegrity,
+			"chain_canonical_bytes match",
+			chainBytesMatch,
+			fmt.Sprintf("go=%s  python=%s",
+				v.abbrev(goChainBytesHex, 16), v.abbrev(bundle.ChainBytesHex, 16)),
+		)
+		return nil
+	})
+	if err != nil {
+		return r.report(), err
+	}
+
+	// ════════════════════════════════════════════════════════
+	// CHECK 3 — Signature verification (positive, sig_alg)
+	// Proves: Python Ed25519 signatures verify in Go crypto/ed25519,
+	// and KMS ECDSA P-256 ones in crypto/ecdsa (sigalg.go); a
+	// COSE_Sign1 in cose_sign1 is verified too (cose.go).
+	// ════════════════════════════════════════════════════════
+	v.contract(r, SectionSignature, PhaseSignature, func() error {
+		if v.opts.RejectWeakKeys {
+			weakKey := pubKey.Alg == SigAlgEd25519 && IsWeakPublicKey(pubKey.Bytes)
+			details := "not one of the 8 small-order points"
+			switch {
+			case weakKey:
+				details = "weak/low-order public key rejected"
+			case pubKey.Alg != SigAlgEd25519:
+				details = "P-256 point decoded on the curve"
+			}
+			r.check("C3.weak_key", CategoryPolicy, "public key is not small-order", !weakKey, details)
+		}
+
+		sigValid := pubKey.Verify(goCanonicalBytes, sigBytes)
+		r.check(
+			"C3.signature_go",
+			CategoryIntegrity,
+			"signature valid (Go canonical bytes)",
+			sigValid,
+			fmt.Sprintf("alg=%s  pubkey=%s  sig=%s",
+				pubKey.Alg,
+				v.abbrev(bundle.PublicKeyHex, 8),
+				v.abbrev(bundle.SignatureB64URL, 16)),
+		)
+
+		if pythonCanonicalHex != "" {
+			pythonCanonicalDecoded, _ := hex.DecodeString(pythonCanonicalHex)
+			sigValidPythonBytes := pubKey.Verify(pythonCanonicalDecoded, sigBytes)
+			r.check(
+				"C3.signature_python",
+				CategoryIntegrity,
+				"signature valid (Python canonical bytes)",
+				sigValidPythonBytes,
+				"cross-check: Go verifies Python's raw bytes directly",
+			)
+		}
+
+		if bundle.CoseSign1 != "" {
+			coseValid, details := verifyCOSESign1(pubKey, bundle.SigAlg, bundle.CoseSign1, goCanonicalBytes)
+			r.check("C3.cose_sign1", CategoryIntegrity, "COSE_Sign1 signature valid", coseValid, details)
+		}
+		return nil
+	})
+
+	// ════════════════════════════════════════════════════════
+	// CHECK 4 — Signing dict == Chain dict (field identity)
+	// Proves: to_signing_dict() == to_chain_dict() by GEF-SPEC-v1.0.
+	// The dicts are compared as JCS canonical bytes, what the signature
+	// and the chain hash are computed over: 2.50 and 2.5 are one value.
+	// ════════════════════════════════════════════════════════
+	v.contract(r, SectionDictIdentity, PhaseDictIdentity, func() error {
+		signingCanonical, err := Canonicalize(bundle.SigningDict)
+		if err != nil {
+			return &MalformedError{"canonicalize signing_dict", err}
+		}
+		chainCanonical, err := Canonicalize(bundle.ChainDict)
+		if err != nil {
+			return &MalformedError{"canonicalize chain_dict", err}
+		}
+		dictsEqual := bytes.Equal(signingCanonical, chainCanonical)
+		details    := "GEF-SPEC-v1.0: both dicts are identical by design"
+		if !dictsEqual {
+			details = fmt.Sprintf("canonical bytes differ: signing_dict %d bytes, chain_dict %d bytes",
+				len(signingCanonical), len(chainCanonical))
+		}
+
+		r.check(
+			"C4.dict_identity",
+			CategoryIntegrity,
+			"signing_dict == chain_dict",
+			dictsEqual,
+			details,
+		)
+
+		if legacy {
+			emptyMember, details := legacySignatureMember(bundle.SigningDict)
+			r.check(
+				"C4.legacy_signature_member",
+				CategoryStructure,
+				"signature member is empty (legacy profile)",
+				emptyMember,
+				details,
+			)
+			return nil
+		}
+		_, sigInDict := bundle.SigningDict["signature"]
+		r.check(
+			"C4.signature_excluded",
+			CategoryStructure,
+			"signature NOT in signing_dict",
+			!sigInDict,
+			"signature field must be excluded from signed payload",
+		)
+		return nil
+	})
+
+	// ════════════════════════════════════════════════════════
+	// CHECK 5 — Field count (no extra or missing fields)
+	// Proves: no silent field injection or omission across the boundary.
+	// ════════════════════════════════════════════════════════
+	v.contract(r, SectionFieldCount, PhaseFieldCount, func() error {
+		spec, supported := v.versionSpec(r, signedVersion)
+		if !supported {
+			return nil
+		}
+		expectedFields := spec.Fields
+		var optional []string
+		for _, f := range spec.Optional {
+			if _, ok := bundle.SigningDict[f]; ok {
+				optional = append(optional, f)
+			}
+		}
+		expectedCount := len(expectedFields) + len(optional)
+		if legacy {
+			expectedCount++ // the empty signature member
+		}
+		fieldCountOK := len(bundle.SigningDict) == expectedCount
+		r.check(
+			"C5.field_count",
+			CategoryStructure,
+			fmt.Sprintf("signing_dict has exactly %d fields", expectedCount),
+			fieldCountOK,
+			fmt.Sprintf("got %d, expected %d",
+				len(bundle.SigningDict), expectedCount),
+		)
+
+		allPresent := true
+		for _, f := range expectedFields {
+			key, aliased, ok := v.aliases.resolve(signedVersion, f, bundle.SigningDict)
+			if aliased {
+				r.notes = append(r.notes, fmt.Sprintf(
+					"field '%s' satisfied by alias '%s' (gef_version %s)", f, key, signedVersion))
+			}
+			if !ok {
+				allPresent = false
+				r.check(
+					"C5.field_present."+f,
+					CategoryStructure,
+					fmt.Sprintf("field '%s' present", f),
+					false,
+					"MISSING — signing_dict is incomplete",
+				)
+			}
+		}
+		if allPresent {
+			r.check(
+				"C5.fields_present",
+				CategoryStructure,
+				fmt.Sprintf("all %d required fields present", len(expectedFields)),
+				true,
+				strings.Join(expectedFields, " "),
+			)
+		}
+
+		// Decode into GEFRecord (record.go): every field is expected, and
+		// the present spec fields have their types. Missing fields were
+		// reported above; the signature member is CONTRACT 4's.
+		expected := map[string]bool{"signature": true}
+		for _, f := range optional {
+			expected[f] = true
+		}
+		typed := make(map[string]interface{}, len(expectedFields))
+		for _, f := range expectedFields {
+			if key, _, ok := v.aliases.resolve(signedVersion, f, bundle.SigningDict); ok {
+				expected[key] = true
+				typed[f]      = bundle.SigningDict[key]
+			}
+		}
+		for _, key := range sortedKeys(bundle.SigningDict) {
+			if !expected[key] {
+				r.check(
+					"C5.unknown_field."+key,
+					CategoryStructure,
+					fmt.Sprintf("field '%s' expected", key),
+					false,
+					"UNKNOWN — not a field of this record",
+				)
+			}
+		}
+		var record GEFRecord
+		typesOK := true
+		if err := record.FromMap(typed); err != nil {
+			for _, fe := range err.(*RecordError).Fields {
+				f := fe.Field
+				if _, present := typed[f]; !present || !isRequiredField(f) {
+					continue // missing, or an ExpectedFields extra GEFRecord does not type
+				}
+				typesOK = false
+				r.check(
+					"C5.field_type."+f,
+					CategoryStructure,
+					fmt.Sprintf("field '%s' has its GEF type", f),
+					false,
+					fe.Problem,
+				)
+			}
+		}
+		if typesOK {
+			r.check(
+				"C5.field_types",
+				CategoryStructure,
+				"fields have their GEF types",
+				true,
+				"strings, integer sequence, JSON payload",
+			)
+		}
+		return nil
+	})
+
+	// ════════════════════════════════════════════════════════
+	// CHECK 6 — NEGATIVE TEST: flipped byte must NOT verify
+	//
+	// The most important single check in this package.
+	//
+	// Procedure:
+	//   1. Copy Go's canonical bytes
+	//   2. Flip ONE byte at midpoint (XOR 0xFF — all 8 bits)
+	//   3. Verify (Ed25519 or P-256) on corrupted bytes → must return FALSE
+	//   4. Flip ONE bit at position 1 → must also return FALSE
+	//   5. Verify original bytes still pass (copy correctness check)
+	//   6. With Mutations, N random 1-bit flips → all must return FALSE
+	//
+	// Why this matters:
+	//   Passing CHECK 3 but failing CHECK 6 would mean something is
+	//   silently normalizing data before verification — making ALL
+	//   positive results untrustworthy.
+	//   Both passing together means:
+	//   "The signature is bound to exactly these bytes.
+	//    Any single-bit mutation breaks it."
+	//   That is the definition of tamper-evident.
+	// ════════════════════════════════════════════════════════
+	v.contract(r, SectionNegativeTest, PhaseNegativeTest, func() error {
+		// Sub-test A: flip all 8 bits at midpoint
+		corruptedA    := make([]byte, len(goCanonicalBytes))
+		copy(corruptedA, goCanonicalBytes)
+		flipIdx       := len(corruptedA) / 2
+		origByte      := corruptedA[flipIdx]
+		corruptedA[flipIdx] ^= 0xFF
+
+		sigOnCorruptedA   := pubKey.Verify(corruptedA, sigBytes)
+		negativePassedA   := !sigOnCorruptedA
+
+		r.check(
+			"C6.flip_byte",
+			CategoryIntegrity,
+			"corrupted bytes rejected (8-bit flip at mid)",
+			negativePassedA,
+			fmt.Sprintf("alg=%s pos=%d orig=0x%02X flipped=0x%02X verify=%v (must be false)",
+				pubKey.Alg, flipIdx, origByte, corruptedA[flipIdx], sigOnCorruptedA),
+		)
+
+		// Sub-test B: flip 1 bit at position 1 (weakest possible corruption)
+		corruptedB   := make([]byte, len(goCanonicalBytes))
+		copy(corruptedB, goCanonicalBytes)
+		corruptedB[1] ^= 0x01
+
+		sigOnCorruptedB := pubKey.Verify(corruptedB, sigBytes)
+		negativePassedB := !sigOnCorruptedB
+
+		r.check(
+			"C6.flip_bit",
+			CategoryIntegrity,
+			"corrupted bytes rejected (1-bit flip at pos 1)",
+			negativePassedB,
+			fmt.Sprintf("pos=1 orig=0x%02X flipped=0x%02X verify=%v (must be false)",
+				goCanonicalBytes[1], corruptedB[1], sigOnCorruptedB),
+		)
+
+		// Sub-test C: original still verifies — confirms A and B used copies
+		restoredVerifies := pubKey.Verify(goCanonicalBytes, sigBytes)
+		r.check(
+			"C6.original_intact",
+			CategoryIntegrity,
+			"original bytes still verify after corruption test",
+			restoredVerifies,
+			"confirms copies were used — original was never mutated",
+		)
+
+		// Sub-test D: random single-bit flips (optional, see mutations.go)
+		if v.opts.Mutations > 0 {
+			stats := sampleMutations(pubKey, goCanonicalBytes, sigBytes, v.opts.Mutations, v.opts.MutationSeed)
+			r.mutations = &stats
+			r.mutationCheck(stats)
+		}
+		return nil
+	})
+
+	// ════════════════════════════════════════════════════════
+	// CHECK 7 — Version binding (signed vs advertised gef_version)
+	// Proves: the version the record was signed under is the version
+	// the bundle advertises. The top-level gef_version is NOT covered
+	// by the signature, so a mismatch is a potential downgrade signal.
+	// ════════════════════════════════════════════════════════
+	v.contract(r, SectionVersionBinding, PhaseVersionBinding, func() error {
+		versionMatch := signedVersion == bundle.GEFVersion
+
+		r.check(
+			"C7.version_binding",
+			CategoryIntegrity,
+			"signed gef_version == bundle gef_version",
+			versionMatch,
+			fmt.Sprintf("signed=%q  advertised=%q", signedVersion, bundle.GEFVersion),
+		)
+		return nil
+	})
+
+	// ════════════════════════════════════════════════════════
+	// CHECK 8 — Envelope consistency (envelope_json vs signed values)
+	// Proves: the envelope shipped with the bundle is the record that
+	// was signed. Nothing else reads envelope_json, so an envelope
+	// edited after signing would pass CHECKS 1–7 unnoticed.
+	// ════════════════════════════════════════════════════════
+	v.contract(r, SectionEnvelope, PhaseEnvelope, func() error {
+		v.checkEnvelopeJSON(r, bundle, sigBytes)
+		return nil
+	})
+
+	// ════════════════════════════════════════════════════════
+	// CHECK 9 — Timestamp (signing_dict.timestamp is RFC 3339)
+	// Proves: the signed time is a time. Whether it is an acceptable
+	// one is policy, below; a record claiming 2099 passes here.
+	// ════════════════════════════════════════════════════════
+	var signedAt time.Time
+	var timestampOK bool
+	v.contract(r, SectionTimestamp, PhaseTimestamp, func() error {
+		signedAt, timestampOK = checkTimestamp(r, bundle.SigningDict)
+		return nil
+	})
+
+	// ════════════════════════════════════════════════════════
+	// CHECK 10 — Key trust (public_key_hex vs WithTrustedKeys)
+	// Proves: the key that verified is one the caller vouches for,
+	// for this agent; without it a re-signed record is "intact" too.
+	// ════════════════════════════════════════════════════════
+	v.contract(r, SectionKeyTrust, PhaseKeyTrust, func() error {
+		agentID, _ := bundle.SigningDict["agent_id"].(string)
+		v.checkKeyTrust(r, pubKey, agentID)
+		return nil
+	})
+
+	// ════════════════════════════════════════════════════════
+	// CHECK 11 — Signer binding (signer_public_key vs public_key_hex)
+	// Proves: the signer the record names is the key it verified
+	// under, not merely some key that signed it.
+	// ════════════════════════════════════════════════════════
+	v.contract(r, SectionSignerBinding, PhaseSignerBinding, func() error {
+		v.checkSignerBinding(r, bundle.SigAlg, bundle.PublicKeyHex, bundle.SigningDict, signedVersion)
+		return nil
+	})
+
+	// ════════════════════════════════════════════════════════
+	// POLICY — Timestamp window (optional: WithMaxClockSkew,
+	// WithMaxAge, WithFreshness)
+	// Signature + nonce say "this exact record, once"; the window
+	// says "and recently". Together they bound replay.
+	// ════════════════════════════════════════════════════════
+	if v.opts.MaxClockSkew > 0 || v.opts.MaxAge > 0 || v.opts.Freshness > 0 {
+		v.phase(PhasePolicy, func() error {
+			r.section = SectionFreshness
+			if !timestampOK {
+				r.notes = append(r.notes, "timestamp policy not evaluated: no valid timestamp (CONTRACT 9)")
+				return nil
+			}
+			v.timestampPolicy(r, bundle.SigningDict, signedAt)
+			return nil
+		})
+	}
+
+	// ════════════════════════════════════════════════════════
+	// POLICY — Size limits (optional, WithSizeLimits)
+	// Measured on the canonical form, the one every implementation
+	// sees; relaxed mode turns failures into warnings.
+	// ════════════════════════════════════════════════════════
+	if v.opts.SizeLimits != SizeLimitsOff {
+		v.phase(PhaseSizeLimits, func() error {
+			r.section = SectionSizeLimits
+			v.sizePolicy(r, bundle.SigningDict)
+			return nil
+		})
+	}
+
+	// ════════════════════════════════════════════════════════
+	// HYGIENE — String field encoding (optional, WithHygiene)
+	// Warnings only: the bytes verified, but they may still be wrong.
+	// ════════════════════════════════════════════════════════
+	if v.opts.Hygiene {
+		v.phase(PhaseHygiene, func() error {
+			findings := ScanStrings(bundle.SigningDict, "signing_dict")
+			for _, f := range findings {
+				r.warnings = append(r.warnings, f.String())
+			}
+			if len(findings) == 0 {
+				r.notes = append(r.notes, "hygiene: no encoding findings in signing_dict")
+			}
+			return nil
+		})
+	}
+
+	// NESTED — Forwarded bundles (optional, Recursive; see nested.go)
+	if v.opts.Recursive {
+		v.nested(r, bundle, path, depth+1)
+	}
+
+	// Exceptions last, so they can cover any check above.
+	v.exceptions(r, bundle)
+
+	return r.report(), nil
+}
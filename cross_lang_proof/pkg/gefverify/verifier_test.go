@@ -0,0 +1,225 @@
+// cross_lang_proof/pkg/gefverify/verifier_test.go
+
+package gefverify
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestVerifyBytes(t *testing.T) {
+	data, err := os.ReadFile("../../proof_bundle.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := VerifyBytes(data, VerifyOptions{})
+	if err != nil || !report.OK() || report.Verdict != VerdictVerified {
+		t.Fatalf("err=%v verdict=%s failed=%v", err, report.Verdict, failedIDs(report))
+	}
+	want, _ := Verify(loadProofBundle(t), VerifyOptions{})
+	if !reflect.DeepEqual(report.Checks, want.Checks) {
+		t.Error("VerifyBytes and Verify disagree on the checks")
+	}
+
+	for _, input := range []string{"", "{not json", "[]"} {
+		report, err := VerifyBytes([]byte(input), VerifyOptions{})
+		var malformed *MalformedError
+		if !errors.As(err, &malformed) || report.Verdict != VerdictMalformed || report.OK() {
+			t.Errorf("%q: err=%v verdict=%s", input, err, report.Verdict)
+		}
+	}
+}
+
+func TestVerifierSharedAcrossGoroutines(t *testing.T) {
+	data, err := os.ReadFile("../../proof_bundle.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewVerifier()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if report, err := v.VerifyBytes(data); err != nil || !report.OK() {
+				t.Errorf("err=%v failed=%v", err, failedIDs(report))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestDefaultsReproduceSpecV1(t *testing.T) {
+	b := loadProofBundle(t)
+	plain, _ := Verify(b, VerifyOptions{})
+	for name, opts := range map[string]VerifyOptions{
+		"explicit fields": {ExpectedFields: RequiredFields},
+		"explicit hash":   {ChainHash: sha256.New},
+	} {
+		report, err := Verify(b, opts)
+		if err != nil || !reflect.DeepEqual(report.Checks, plain.Checks) {
+			t.Errorf("%s: checks differ from the defaults (err=%v)", name, err)
+		}
+	}
+	if c, _ := checkByID(plain, "C5.fields_present"); c.Name != "all 10 required fields present" {
+		t.Errorf("C5.fields_present named %q", c.Name)
+	}
+}
+
+func TestWithChainHash(t *testing.T) {
+	b := loadProofBundle(t)
+	report, _ := NewVerifier(WithChainHash(sha512.New)).Verify(b)
+	if c, _ := checkByID(report, "C2.chain_hash"); c.Passed || report.Verdict != VerdictTampered {
+		t.Errorf("SHA-256 bundle passed C2 under SHA-512: %+v", c)
+	}
+
+	canonical, err := Canonicalize(b.ChainDict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha512.Sum512(canonical)
+	b.CausalHashOfThis = hex.EncodeToString(sum[:])
+	report, _ = NewVerifier(WithChainHash(sha512.New)).Verify(b)
+	if !report.OK() {
+		t.Errorf("SHA-512 bundle failed under SHA-512: %v", failedIDs(report))
+	}
+	if c, _ := checkByID(report, "C2.chain_hash"); !strings.HasPrefix(c.Details, "alg=sha512  ") {
+		t.Errorf("C2.chain_hash details %q, want alg=sha512", c.Details)
+	}
+	report, _ = NewVerifier(WithChainHash(sha512.New384)).Verify(b)
+	if c, _ := checkByID(report, "C2.chain_hash"); !strings.HasPrefix(c.Details, "alg=custom  ") {
+		t.Errorf("C2.chain_hash details %q, want alg=custom", c.Details)
+	}
+}
+
+func TestWithExpectedFields(t *testing.T) {
+	b := editedBundle(t, map[string]interface{}{"tenant": "acme"})
+	fields := append(append([]string(nil), RequiredFields...), "tenant")
+
+	report, _ := NewVerifier(WithExpectedFields(fields)).Verify(b)
+	if !report.OK() {
+		t.Fatalf("failed %v", failedIDs(report))
+	}
+	if c, _ := checkByID(report, "C5.field_count"); c.Name != "signing_dict has exactly 11 fields" {
+		t.Errorf("C5.field_count named %q", c.Name)
+	}
+	if report, _ := Verify(b, VerifyOptions{}); report.OK() {
+		t.Error("11-field record verified against the 10 spec fields")
+	}
+
+	missing := editedBundle(t, nil)
+	report, _ = NewVerifier(WithExpectedFields(fields)).Verify(missing)
+	if c, ok := checkByID(report, "C5.field_present.tenant"); !ok || c.Passed {
+		t.Errorf("missing tenant not reported: %+v", c)
+	}
+}
+
+func TestShortValuesDoNotPanic(t *testing.T) {
+	// Details print a prefix of each hex value; a value shorter than the
+	// prefix must fail its check, not panic.
+	for _, edit := range []func(*ProofBundle){
+		func(b *ProofBundle) { b.PublicKeyHex = "ab" },
+		func(b *ProofBundle) { b.CanonicalBytesHex = "7b7d" },
+		func(b *ProofBundle) { b.ChainBytesHex = "7b7d" },
+		func(b *ProofBundle) { b.CausalHashOfThis = "ab" },
+		func(b *ProofBundle) { b.SignatureB64URL = "AA" },
+	} {
+		b := loadProofBundle(t)
+		edit(&b)
+		report, err := Verify(b, VerifyOptions{})
+		if report.OK() {
+			t.Errorf("short value verified: err=%v", err)
+		}
+	}
+}
+
+func TestWithFullValues(t *testing.T) {
+	b := loadProofBundle(t)
+	report, _ := NewVerifier(WithFullValues(true)).Verify(b)
+	for id, want := range map[string]string{
+		"C1.canonical_bytes": "go=" + b.CanonicalBytesHex + "  python=" + b.CanonicalBytesHex,
+		"C2.chain_hash":      "python=" + b.CausalHashOfThis,
+		"C3.signature_go":    "pubkey=" + b.PublicKeyHex + "  sig=" + b.SignatureB64URL,
+	} {
+		if c, _ := checkByID(report, id); !strings.Contains(c.Details, want) {
+			t.Errorf("%s details %q, want the whole value", id, c.Details)
+		}
+	}
+	report, _ = Verify(b, VerifyOptions{})
+	if c, _ := checkByID(report, "C1.canonical_bytes"); c.Details != "go=7b226167656e745f...  python=7b226167656e745f..." {
+		t.Errorf("default C1.canonical_bytes details %q", c.Details)
+	}
+}
+
+func TestDictIdentityComparesCanonicalBytes(t *testing.T) {
+	for _, tt := range []struct {
+		n    interface{}
+		pass bool
+	}{
+		// json.Marshal writes the literal "2.50"; JCS, like the
+		// signature and chain hash, sees the number 2.5.
+		{json.Number("2.50"), true},
+		{json.Number("25e-1"), true},
+		{2.51, false},
+	} {
+		b := signedBundle(t, 9, map[string]interface{}{"n": 2.5})
+		chain := make(map[string]interface{}, len(b.ChainDict))
+		for k, v := range b.ChainDict {
+			chain[k] = v
+		}
+		chain["payload"] = map[string]interface{}{"n": tt.n}
+		b.ChainDict = chain
+
+		report, _ := Verify(b, VerifyOptions{})
+		if c, ok := checkByID(report, "C4.dict_identity"); !ok || c.Passed != tt.pass {
+			t.Errorf("chain_dict n=%v: %+v, want passed=%v", tt.n, c, tt.pass)
+		}
+	}
+}
+
+func TestWithoutCanonicalBytesHex(t *testing.T) {
+	b := loadProofBundle(t)
+	b.CanonicalBytesHex = ""
+	report, err := Verify(b, VerifyOptions{})
+	if err != nil || !report.OK() || report.Verdict != VerdictVerified {
+		t.Fatalf("verdict %s, err %v, failed %v", report.Verdict, err, failedIDs(report))
+	}
+	if s := report.Status(SectionCanonicalBytes); s != ContractNotApplicable {
+		t.Errorf("CONTRACT 1 status %q, want %q", s, ContractNotApplicable)
+	}
+	if report.Executed() != len(RequiredContracts) || len(report.Incomplete()) != 0 {
+		t.Errorf("executed %d, incomplete %v", report.Executed(), report.Incomplete())
+	}
+	for _, id := range []string{"C1.canonical_bytes", "C3.signature_python"} {
+		if _, ok := checkByID(report, id); ok {
+			t.Errorf("%s ran without canonical_bytes_hex", id)
+		}
+	}
+	if c, _ := checkByID(report, "C3.signature_go"); !c.Passed {
+		t.Errorf("C3.signature_go %+v", c)
+	}
+	if !strings.Contains(strings.Join(report.Notes, "\n"), "CONTRACT 1 not applicable") {
+		t.Errorf("notes %q", report.Notes)
+	}
+
+	// The signature still covers JCS(signing_dict).
+	b.SigningDict["nonce"] = "forged"
+	report, _ = Verify(b, VerifyOptions{})
+	if c, _ := checkByID(report, "C3.signature_go"); c.Passed || report.Verdict != VerdictTampered {
+		t.Errorf("edited signing_dict: verdict %s, failed %v", report.Verdict, failedIDs(report))
+	}
+	// Present, it must still be hex.
+	b = loadProofBundle(t)
+	b.CanonicalBytesHex = "7b7"
+	if _, err := Verify(b, VerifyOptions{}); err == nil {
+		t.Error("odd-length canonical_bytes_hex verified")
+	}
+}
@@ -0,0 +1,131 @@
+// cross_lang_proof/pkg/gefverify/versions.go
+//
+// Spec versions (CONTRACT 5 dispatch)
+// ===================================
+//
+// The fields a signing_dict carries depend on the spec version it was
+// signed under. VersionTable maps the *signed* gef_version to them, like
+// FieldAliases and SizeLimitTable:
+//
+//   0.9   the ten GEF-SPEC-1.0 fields (plus "signature": "", legacy.go)
+//   1.0   the ten fields, exactly
+//   1.1   the ten fields, and key_id if the signer names its key
+//
+// A version is looked up exactly first. One absent from the table whose
+// major version is in it (1.2) is verified against the nearest known
+// minor of that major, the newest below it (1.1) if there is one, with a
+// warning: minor versions only add optional fields. A major version
+// absent from the table (2.0) fails C5.gef_version_supported, and the
+// field checks of CONTRACT 5 do not run: their counts would mean
+// nothing. VerifyOptions.ExpectedFields replaces the table altogether.
+
+package gefverify
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VersionSpec is what one gef_version requires of the signing_dict.
+type VersionSpec struct {
+	Fields   []string // required, sorted
+	Optional []string // allowed, not required
+}
+
+// VersionTable maps gef_version → VersionSpec.
+type VersionTable map[string]VersionSpec
+
+// DefaultVersions is the built-in table.
+var DefaultVersions = VersionTable{
+	LegacyGEFVersion: {Fields: RequiredFields},
+	"1.0":            {Fields: RequiredFields},
+	"1.1":            {Fields: RequiredFields, Optional: []string{"key_id"}},
+}
+
+// lookup returns the spec records signed under version are verified
+// against, and the version of t it came from. ok is false if the major
+// version of version is not in t. An empty version, which
+// C5.field_present reports, is looked up as 1.0.
+func (t VersionTable) lookup(version string) (spec VersionSpec, as string, ok bool) {
+	if version == "" {
+		version = "1.0"
+	}
+	if spec, ok := t[version]; ok {
+		return spec, version, true
+	}
+	major, minor, valid := splitVersion(version)
+	if !valid {
+		return VersionSpec{}, "", false
+	}
+	// The newest known minor below it, else the oldest above it.
+	below, above := -1, -1
+	var belowAs, aboveAs string
+	for known := range t {
+		kMajor, kMinor, valid := splitVersion(known)
+		switch {
+		case !valid || kMajor != major:
+		case kMinor < minor && kMinor > below:
+			below, belowAs = kMinor, known
+		case kMinor > minor && (above < 0 || kMinor < above):
+			above, aboveAs = kMinor, known
+		}
+	}
+	switch {
+	case below >= 0:
+		as = belowAs
+	case above >= 0:
+		as = aboveAs
+	default:
+		return VersionSpec{}, "", false
+	}
+	return t[as], as, true
+}
+
+// known returns the versions of t, sorted.
+func (t VersionTable) known() []string {
+	versions := make([]string, 0, len(t))
+	for v := range t {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// splitVersion parses "major.minor", ignoring any further components.
+func splitVersion(version string) (major, minor int, ok bool) {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err1 := strconv.Atoi(parts[0])
+	minor, err2 := strconv.Atoi(parts[1])
+	return major, minor, err1 == nil && err2 == nil && major >= 0 && minor >= 0
+}
+
+// versionSpec returns the spec CONTRACT 5 checks a record signed under
+// version against, warning in r for an unknown minor version. ok is
+// false, with C5.gef_version_supported failed, for an unknown major.
+func (v *Verifier) versionSpec(r *run, version string) (spec VersionSpec, ok bool) {
+	if v.fields != nil {
+		return VersionSpec{Fields: v.fields}, true
+	}
+	spec, as, ok := v.versions.lookup(version)
+	if !ok {
+		r.check(
+			"C5.gef_version_supported",
+			CategoryStructure,
+			"gef_version supported",
+			false,
+			fmt.Sprintf("unsupported gef_version %q: this verifier knows %s",
+				version, strings.Join(v.versions.known(), ", ")),
+		)
+		return VersionSpec{}, false
+	}
+	if version != "" && as != version {
+		r.warnings = append(r.warnings, fmt.Sprintf(
+			"gef_version %s is not a known minor version; fields checked as gef_version %s", version, as))
+	}
+	return spec, true
+}
@@ -0,0 +1,71 @@
+// cross_lang_proof/pkg/gefverify/versions_test.go
+
+package gefverify
+
+import (
+	"strings"
+	"testing"
+)
+
+// versionBundle is a signed record under gef_version version, with any
+// extra fields.
+func versionBundle(t *testing.T, version string, extra map[string]interface{}) ProofBundle {
+	t.Helper()
+	changes := map[string]interface{}{"gef_version": version}
+	for k, v := range extra {
+		changes[k] = v
+	}
+	b := editedBundle(t, changes)
+	b.GEFVersion = version
+	return b
+}
+
+func TestVersionDispatch(t *testing.T) {
+	keyID := map[string]interface{}{"key_id": "signer-2026"}
+	for _, tt := range []struct {
+		name    string
+		bundle  ProofBundle
+		failed  []string
+		warning string
+	}{
+		{"1.0", versionBundle(t, "1.0", nil), nil, ""},
+		{"1.1 with key_id", versionBundle(t, "1.1", keyID), nil, ""},
+		{"1.1 without key_id", versionBundle(t, "1.1", nil), nil, ""},
+		{"1.0 with key_id", versionBundle(t, "1.0", keyID), []string{"C5.field_count", "C5.unknown_field.key_id"}, ""},
+		{"unknown minor", versionBundle(t, "1.4", keyID), nil, "gef_version 1.4 is not a known minor version; fields checked as gef_version 1.1"},
+		{"unknown major", versionBundle(t, "2.0", keyID), []string{"C5.gef_version_supported"}, ""},
+	} {
+		report, err := Verify(tt.bundle, VerifyOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := failedIDs(report); strings.Join(got, " ") != strings.Join(tt.failed, " ") {
+			t.Errorf("%s: failed %v, want %v", tt.name, got, tt.failed)
+		}
+		if got := strings.Join(report.Warnings, "\n"); got != tt.warning {
+			t.Errorf("%s: warnings %q, want %q", tt.name, got, tt.warning)
+		}
+	}
+
+	// An unknown major runs none of the field checks.
+	report, _ := Verify(versionBundle(t, "2.0", nil), VerifyOptions{})
+	if c, _ := checkByID(report, "C5.gef_version_supported"); !strings.Contains(c.Details, `unsupported gef_version "2.0": this verifier knows 0.9, 1.0, 1.1`) {
+		t.Errorf("details %q", c.Details)
+	}
+	if _, ok := checkByID(report, "C5.field_count"); ok {
+		t.Error("C5.field_count ran for an unsupported gef_version")
+	}
+}
+
+func TestVersionTableLookup(t *testing.T) {
+	table := VersionTable{"0.9": {}, "1.0": {}, "1.1": {}, "1.3": {}}
+	for version, want := range map[string]string{
+		"1.0": "1.0", "1.2": "1.1", "1.7": "1.3", "0.5": "0.9", "": "1.0",
+		"2.0": "", "1": "", "x.y": "",
+	} {
+		_, as, ok := table.lookup(version)
+		if as != want || ok != (want != "") {
+			t.Errorf("%q: looked up as %q (ok=%v), want %q", version, as, ok, want)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+// cross_lang_proof/reference.go
+//
+// Taxonomy reference (reference subcommand)
+// =========================================
+//
+//   verify_proof reference [-format text|json]
+//
+// Everything an integration matches on, for the exact binary that runs:
+// verdicts and their exit codes, failure categories, rule severities,
+// every check ID with its section, category, default severity and spec
+// reference, the errors pkg/gefverify returns, and the report schema
+// version. The output is generated from the registries: gefverify.Checks
+// and gefverify.Errors, the verdict rules, builtinChainRule, and
+// cliChecks below for the checks subcommands add. reference_test.go
+// fails when a subcommand emits a check ID that cliChecks does not list.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// referenceVersion is bumped on incompatible changes to the JSON form.
+const referenceVersion = 1
+
+// cliCheck is a check emitted by a subcommand rather than the library.
+type cliCheck struct {
+	Command string
+	gefverify.CheckSpec
+	rule func(chainRule) severity // severity under -chain-rules, nil if fixed
+}
+
+var cliChecks = []cliCheck{
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.unreadable_bundle", Section: "PASS 1", Category: gefverify.CategoryStructure}},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.manifest_entry_absent_on_disk", Section: "ORDER", Category: gefverify.CategoryCompleteness,
+		Note: "only with -manifest"}},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.record_absent_from_manifest", Section: "ORDER", Category: gefverify.CategoryCompleteness,
+		Note: "only with -manifest"}},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.record_unverified", Section: "PASS 2", Category: gefverify.CategoryIntegrity, Spec: "GEF-SPEC-1.0 §5.1",
+		Note: "the record's own failed checks follow it under their IDs"}},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.broken_link", Section: "PASS 2", Category: gefverify.CategoryIntegrity, Spec: "GEF-SPEC-1.0 §6.2"},
+		rule: func(r chainRule) severity { return r.Linkage }},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.bad_genesis_link", Section: "PASS 2", Category: gefverify.CategoryIntegrity, Spec: "GEF-SPEC-1.0 §6.1"},
+		rule: func(r chainRule) severity { return r.Linkage }},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.nonce_replay", Section: "PASS 2", Category: gefverify.CategoryIntegrity,
+		Note: "records with an agent_id and nonce; across runs with -nonce-db"}},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.trusted_head_mismatch", Section: "PASS 2", Category: gefverify.CategoryIntegrity,
+		Note: "only with -trusted-head or -from-snapshot; stops pass 2"}},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.sequence_gap", Section: "PASS 2", Category: gefverify.CategoryCompleteness, Spec: "GEF-SPEC-1.0 §6.2",
+		Note: "warn with -allow-gaps"},
+		rule: func(r chainRule) severity { return r.SequenceGaps }},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.duplicate_sequence", Section: "PASS 2", Category: gefverify.CategoryIntegrity, Spec: "GEF-SPEC-1.0 §6.2"}},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.sequence_regression", Section: "PASS 2", Category: gefverify.CategoryIntegrity,
+		Note: "only with -manifest"}},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.timestamp_regression", Section: "PASS 2", Category: gefverify.CategoryPolicy},
+		rule: func(r chainRule) severity { return r.TimestampMonotonic }},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.interval_exceeded", Section: "PASS 2", Category: gefverify.CategoryPolicy,
+		Note: "only for rules with max_interval"},
+		rule: func(r chainRule) severity { return r.MaxIntervalSeverity }},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.reference_to_unverified_record", Section: "PASS 3", Category: gefverify.CategoryIntegrity,
+		Note: "only with -ref-pointer"}},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.dangling_reference", Section: "PASS 3", Category: gefverify.CategoryCompleteness,
+		Note: "only with -ref-pointer -require-closed-world"}},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.malformed_amendment", Section: "AMENDMENTS", Category: gefverify.CategoryStructure,
+		Note: "amendment records, with the amendments rule on"},
+		rule: func(r chainRule) severity { return r.Amendments }},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.amendment_unresolvable", Section: "AMENDMENTS", Category: gefverify.CategoryCompleteness,
+		Note: "amendment records, with the amendments rule on"},
+		rule: func(r chainRule) severity { return r.Amendments }},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.amendment_of_unverified_record", Section: "AMENDMENTS", Category: gefverify.CategoryIntegrity,
+		Note: "amendment records, with the amendments rule on"},
+		rule: func(r chainRule) severity { return r.Amendments }},
+	{Command: "chain", CheckSpec: gefverify.CheckSpec{ID: "chain.amendment_rule_violated", Section: "AMENDMENTS", Category: gefverify.CategoryPolicy,
+		Note: "amendment records, with the amendments rule on: same agent, later timestamp, no amendment of an amendment"},
+		rule: func(r chainRule) severity { return r.Amendments }},
+	{Command: "verify-image", CheckSpec: gefverify.CheckSpec{ID: "I.proof_present", Section: sectionImage, Category: gefverify.CategoryCompleteness}},
+	{Command: "verify-image", CheckSpec: gefverify.CheckSpec{ID: "I.image_digest", Section: sectionImage, Category: gefverify.CategoryIntegrity}},
+	{Command: "verify-paseto", CheckSpec: gefverify.CheckSpec{ID: "P.key", Section: sectionPaseto, Category: gefverify.CategoryCompleteness}},
+	{Command: "verify-paseto", CheckSpec: gefverify.CheckSpec{ID: "P.signature", Section: sectionPaseto, Category: gefverify.CategoryIntegrity}},
+	{Command: "verify-paseto", CheckSpec: gefverify.CheckSpec{ID: "R.record_json", Section: sectionRecord, Category: gefverify.CategoryStructure, Note: "also verify-dsse"}},
+	{Command: "verify-paseto", CheckSpec: gefverify.CheckSpec{ID: "R.causal_link", Section: sectionRecord, Category: gefverify.CategoryIntegrity, Spec: "GEF-SPEC-1.0 §6.2", Note: "also verify-dsse"}},
+	{Command: "verify-dsse", CheckSpec: gefverify.CheckSpec{ID: "D.payload_type", Section: sectionDSSE, Category: gefverify.CategoryStructure}},
+	{Command: "verify-dsse", CheckSpec: gefverify.CheckSpec{ID: "D.key", Section: sectionDSSE, Category: gefverify.CategoryCompleteness}},
+	{Command: "verify-dsse", CheckSpec: gefverify.CheckSpec{ID: "D.signature", Section: sectionDSSE, Category: gefverify.CategoryIntegrity, Spec: "DSSE v1 protocol"}},
+	{Command: "verify-jws", CheckSpec: gefverify.CheckSpec{ID: "J.detached", Section: sectionJWS, Category: gefverify.CategoryStructure, Spec: "RFC 7515 Appendix F"}},
+	{Command: "verify-jws", CheckSpec: gefverify.CheckSpec{ID: "J.header", Section: sectionJWS, Category: gefverify.CategoryStructure, Spec: "RFC 7797 §3"}},
+	{Command: "verify-jws", CheckSpec: gefverify.CheckSpec{ID: "J.signature", Section: sectionJWS, Category: gefverify.CategoryIntegrity, Spec: "RFC 7797 §3"}},
+	{Command: "verify", CheckSpec: gefverify.CheckSpec{ID: "B.nonce_replay", Section: sectionNonces, Category: gefverify.CategoryIntegrity,
+		Note: "batch mode, bundles with an agent_id and nonce; across runs with -nonce-db"}},
+	{Command: "verify", CheckSpec: gefverify.CheckSpec{ID: "B.sequence_gap", Section: sectionSequences, Category: gefverify.CategoryCompleteness, Spec: "GEF-SPEC-1.0 §6.2",
+		Note: "batch mode, per agent_id; a warning with -allow-gaps"}},
+	{Command: "verify", CheckSpec: gefverify.CheckSpec{ID: "B.duplicate_sequence", Section: sectionSequences, Category: gefverify.CategoryIntegrity, Spec: "GEF-SPEC-1.0 §6.2",
+		Note: "batch mode, per agent_id"}},
+	{Command: "verify", CheckSpec: gefverify.CheckSpec{ID: "X.cross_verify", Section: sectionCrossVerify, Category: gefverify.CategoryIntegrity,
+		Note: "only with -cross-verify; completeness if the external verifier cannot run"}},
+	{Command: "audit", CheckSpec: gefverify.CheckSpec{ID: "A.readable", Section: sectionArtifacts, Category: gefverify.CategoryStructure,
+		Note: "a bundle, envelope or token that cannot be read as one"}},
+	{Command: "audit", CheckSpec: gefverify.CheckSpec{ID: "S.checksum", Section: sectionArtifacts, Category: gefverify.CategoryIntegrity}},
+	{Command: "audit", CheckSpec: gefverify.CheckSpec{ID: "S.structure", Section: sectionArtifacts, Category: gefverify.CategoryStructure}},
+	{Command: "audit", CheckSpec: gefverify.CheckSpec{ID: "S.head", Section: sectionArtifacts, Category: gefverify.CategoryIntegrity,
+		Note: "snapshot heads whose sequence is in the folder"}},
+	{Command: "report merge", CheckSpec: gefverify.CheckSpec{ID: "M.shard_set", Section: SectionMerge, Category: gefverify.CategoryCompleteness}},
+	{Command: "report merge", CheckSpec: gefverify.CheckSpec{ID: "M.corpus_mismatch", Section: SectionMerge, Category: gefverify.CategoryCompleteness}},
+	{Command: "report merge", CheckSpec: gefverify.CheckSpec{ID: "M.partition", Section: SectionMerge, Category: gefverify.CategoryIntegrity}},
+	{Command: "report merge", CheckSpec: gefverify.CheckSpec{ID: "M.claimed_twice", Section: SectionMerge, Category: gefverify.CategoryIntegrity}},
+	{Command: "report merge", CheckSpec: gefverify.CheckSpec{ID: "M.unclaimed", Section: SectionMerge, Category: gefverify.CategoryCompleteness}},
+	{Command: "report merge", CheckSpec: gefverify.CheckSpec{ID: "M.manifest_unclaimed", Section: SectionMerge, Category: gefverify.CategoryCompleteness,
+		Note: "only with -manifest"}},
+}
+
+// severityMeanings documents the chain rule severities (chainrules.go).
+var severityMeanings = []struct {
+	Severity severity
+	Meaning  string
+}{
+	{severityOff, "check skipped"},
+	{severityWarn, "failure printed as a finding, verdict unaffected"},
+	{severityFail, "failure counts toward the verdict"},
+}
+
+type referenceDocument struct {
+	ReferenceVersion    int           `json:"reference_version"`
+	VerifierVersion     string        `json:"verifier_version"`
+	ReportSchemaVersion int           `json:"report_schema_version"`
+	Verdicts            []verdictRef  `json:"verdicts"`
+	Categories          []categoryRef `json:"categories"`
+	Severities          []severityRef `json:"severities"`
+	ExitCodes           []exitCodeRef `json:"exit_codes"`
+	Checks              []checkRef    `json:"checks"`
+	Errors              []errorRef    `json:"errors"`
+}
+
+type verdictRef struct {
+	Verdict  gefverify.Verdict `json:"verdict"`
+	ExitCode int               `json:"exit_code"`
+}
+
+type categoryRef struct {
+	Category string            `json:"category"`
+	Verdict  gefverify.Verdict `json:"verdict"` // on a failed check
+}
+
+type severityRef struct {
+	Severity severity `json:"severity"`
+	Meaning  string   `json:"meaning"`
+}
+
+type exitCodeRef struct {
+	Code    int    `json:"code"`
+	Meaning string `json:"meaning"`
+}
+
+type checkRef struct {
+	ID       string            `json:"id"`
+	Command  string            `json:"command"` // "" for every verification
+	Section  string            `json:"section"`
+	Category string            `json:"category"`
+	Verdict  gefverify.Verdict `json:"verdict"`
+	Severity severity          `json:"default_severity"`
+	Spec     string            `json:"spec,omitempty"`
+	Note     string            `json:"note,omitempty"`
+	Lint     bool              `json:"lint,omitempty"` // also reported by the lint subcommand
+}
+
+type errorRef struct {
+	Name    string            `json:"name"`
+	Kind    string            `json:"kind"` // "sentinel" (errors.Is) or "type" (errors.As)
+	Message string            `json:"message,omitempty"`
+	GoType  string            `json:"go_type,omitempty"`
+	Verdict gefverify.Verdict `json:"verdict,omitempty"`
+}
+
+// newReferenceDocument assembles the reference from the registries.
+func newReferenceDocument() referenceDocument {
+	doc := referenceDocument{
+		ReferenceVersion:    referenceVersion,
+		VerifierVersion:     gefverify.Version,
+		ReportSchemaVersion: gefverify.ReportSchemaVersion,
+	}
+
+	meanings := map[int]string{
+		1: "refused or internal error",
+		2: "usage or I/O error",
+		3: "unparseable input",
+	}
+	for _, v := range gefverify.Verdicts() {
+		doc.Verdicts = append(doc.Verdicts, verdictRef{v, v.ExitCode()})
+		if m, ok := meanings[v.ExitCode()]; ok {
+			meanings[v.ExitCode()] = string(v) + ", " + m
+		} else {
+			meanings[v.ExitCode()] = string(v)
+		}
+	}
+	for code, m := range meanings {
+		doc.ExitCodes = append(doc.ExitCodes, exitCodeRef{code, m})
+	}
+	sort.Slice(doc.ExitCodes, func(i, j int) bool { return doc.ExitCodes[i].Code < doc.ExitCodes[j].Code })
+
+	for _, c := range gefverify.Categories() {
+		doc.Categories = append(doc.Categories, categoryRef{c.String(), c.Verdict()})
+	}
+	for _, s := range severityMeanings {
+		doc.Severities = append(doc.Severities, severityRef{s.Severity, s.Meaning})
+	}
+
+	add := func(command string, s gefverify.CheckSpec, sev severity) {
+		doc.Checks = append(doc.Checks, checkRef{
+			ID: s.ID, Command: command, Section: s.Section, Category: s.Category.String(),
+			Verdict: s.Category.Verdict(), Severity: sev, Spec: s.Spec, Note: s.Note, Lint: s.Lint,
+		})
+	}
+	for _, s := range gefverify.Checks() {
+		command := ""
+		if s.Section == gefverify.SectionLint {
+			command = "lint" // gefverify.Lint only, never in a report
+		}
+		add(command, s, severityFail)
+	}
+	for _, c := range cliChecks {
+		sev := severityFail
+		if c.rule != nil {
+			sev = c.rule(builtinChainRule)
+		}
+		add(c.Command, c.CheckSpec, sev)
+	}
+
+	for _, e := range gefverify.Errors() {
+		ref := errorRef{Name: e.Name, Kind: "sentinel", GoType: e.TypeName(), Verdict: e.Verdict}
+		if e.Value != nil {
+			ref.Message = e.Value.Error()
+		} else {
+			ref.Kind = "type"
+		}
+		doc.Errors = append(doc.Errors, ref)
+	}
+	return doc
+}
+
+func runReference(args []string) int {
+	fs := newFlagSet("reference")
+	format := fs.String("format", "text", "output `format`: text or json")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof reference [-format text|json]")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+	if fs.NArg() != 0 || (*format != "text" && *format != "json") {
+		fs.Usage()
+		return 2
+	}
+
+	doc := newReferenceDocument()
+	if *format == "json" {
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, string(data))
+		return 0
+	}
+
+	fmt.Fprintf(stdout, "GEF verifier %s — report schema %d\n\n", doc.VerifierVersion, doc.ReportSchemaVersion)
+	fmt.Fprintln(stdout, "VERDICTS")
+	for _, v := range doc.Verdicts {
+		fmt.Fprintf(stdout, "  %-24s exit %d\n", v.Verdict, v.ExitCode)
+	}
+	fmt.Fprintln(stdout, "\nEXIT CODES")
+	for _, e := range doc.ExitCodes {
+		fmt.Fprintf(stdout, "  %d  %s\n", e.Code, e.Meaning)
+	}
+	fmt.Fprintln(stdout, "\nCATEGORIES (verdict of a failed check)")
+	for _, c := range doc.Categories {
+		fmt.Fprintf(stdout, "  %-14s %s\n", c.Category, c.Verdict)
+	}
+	fmt.Fprintln(stdout, "\nSEVERITIES (chain -chain-rules)")
+	for _, s := range doc.Severities {
+		fmt.Fprintf(stdout, "  %-5s %s\n", s.Severity, s.Meaning)
+	}
+	fmt.Fprintln(stdout, "\nCHECKS")
+	for _, c := range doc.Checks {
+		command := c.Command
+		if command == "" {
+			command = "*"
+		}
+		fmt.Fprintf(stdout, "  %-40s %-14s %-13s %-5s %-18s %s\n", c.ID, command, c.Category, c.Severity, c.Spec, c.Note)
+	}
+	fmt.Fprintln(stdout, "\nERRORS (pkg/gefverify)")
+	for _, e := range doc.Errors {
+		detail := e.Message
+		if e.Kind == "type" {
+			detail = e.GoType
+		}
+		fmt.Fprintf(stdout, "  %-28s %-8s %s\n", e.Name, e.Kind, detail)
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+// cross_lang_proof/reference_test.go
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+var (
+	// cliCheckID matches check IDs written as literals: check("P.key", ...)
+	// and CheckResult{ID: "I.proof_present", ...}.
+	cliCheckID = regexp.MustCompile(`(?:check\(|ID:\s*)"([A-Z][A-Z0-9]*\.[a-z_]+)"`)
+	// chainCode matches the code argument of chainRun.check and ruleCheck,
+	// which becomes "chain." + code.
+	chainCode = regexp.MustCompile(`(?:check\(|ruleCheck\(rule, [\w.]+, )gefverify\.Category(\w+), "([a-z ]+)"`)
+)
+
+func TestEveryCLICheckIDHasReferenceMetadata(t *testing.T) {
+	specs := make(map[string]cliCheck)
+	for _, c := range cliChecks {
+		specs[c.ID] = c
+	}
+	paths, _ := filepath.Glob("*.go")
+	used := make(map[string]bool)
+	for _, p := range paths {
+		if strings.HasSuffix(p, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range cliCheckID.FindAllStringSubmatch(string(data), -1) {
+			used[m[1]] = true
+			if _, ok := specs[m[1]]; !ok {
+				t.Errorf("%s: check %s has no entry in cliChecks", p, m[1])
+			}
+		}
+		for _, m := range chainCode.FindAllStringSubmatch(string(data), -1) {
+			id := "chain." + strings.ReplaceAll(m[2], " ", "_")
+			used[id] = true
+			spec, ok := specs[id]
+			if !ok {
+				t.Errorf("%s: check %s has no entry in cliChecks", p, id)
+				continue
+			}
+			if got := spec.Category.String(); !strings.EqualFold(got, m[1]) {
+				t.Errorf("%s: check %s is %s, cliChecks says %s", p, id, m[1], got)
+			}
+		}
+	}
+	for _, c := range cliChecks {
+		if !used[c.ID] {
+			t.Errorf("cliChecks entry %s is not emitted anywhere", c.ID)
+		}
+		if _, clash := gefverify.LookupCheck(c.ID); clash {
+			t.Errorf("cliChecks entry %s collides with a library check", c.ID)
+		}
+	}
+}
+
+func TestReferenceJSON(t *testing.T) {
+	code, out, errOut := runCaptured(t, "reference", "-format", "json")
+	if code != 0 || errOut != "" {
+		t.Fatalf("exit %d\n%s", code, errOut)
+	}
+	var doc referenceDocument
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.ReportSchemaVersion != gefverify.ReportSchemaVersion || len(doc.Verdicts) != 6 {
+		t.Errorf("schema %d, verdicts %v", doc.ReportSchemaVersion, doc.Verdicts)
+	}
+	if len(doc.Checks) != len(gefverify.Checks())+len(cliChecks) || len(doc.Errors) != len(gefverify.Errors()) {
+		t.Errorf("%d checks, %d errors: registries not fully emitted", len(doc.Checks), len(doc.Errors))
+	}
+	byID := make(map[string]checkRef)
+	for _, c := range doc.Checks {
+		byID[c.ID] = c
+	}
+	for id, want := range map[string]checkRef{
+		"C3.signature_go":    {Category: "integrity", Verdict: gefverify.VerdictTampered, Severity: severityFail},
+		"chain.sequence_gap": {Category: "completeness", Verdict: gefverify.VerdictUnverifiable, Severity: severityFail},
+		"P.freshness":        {Category: "policy", Verdict: gefverify.VerdictPolicyRejected, Severity: severityFail},
+	} {
+		got := byID[id]
+		if got.Category != want.Category || got.Verdict != want.Verdict || got.Severity != want.Severity {
+			t.Errorf("%s = %+v, want %+v", id, got, want)
+		}
+	}
+	codes := make(map[int]string)
+	for _, e := range doc.ExitCodes {
+		codes[e.Code] = e.Meaning
+	}
+	if codes[2] != "usage or I/O error" || codes[3] != "MALFORMED, unparseable input" || codes[6] != "VERIFIED_UNTRUSTED_KEY" || !strings.HasPrefix(codes[1], "TAMPERED") {
+		t.Errorf("exit codes = %v", codes)
+	}
+
+	if code, _, errOut := runCaptured(t, "reference", "-format", "yaml"); code != 2 || !strings.Contains(errOut, "usage:") {
+		t.Errorf("-format yaml: exit %d, stderr %q", code, errOut)
+	}
+}
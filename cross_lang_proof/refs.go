@@ -0,0 +1,149 @@
+// cross_lang_proof/refs.go
+//
+// Referential integrity across a record corpus
+// ============================================
+//
+//   verify_proof chain -ref-pointer /parent_record -ref-pointer /related \
+//                      [-require-closed-world] <dir>...
+//
+// Payloads cross-reference other records by causal hash, e.g.
+// {"parent_record": "<hash>", "related": ["<hash>", ...]}. With one or
+// more -ref-pointer (RFC 6901 JSON Pointers into signing_dict.payload),
+// every string — or array of strings — found at a pointer must be the
+// chain hash of a record in the corpus that verified (valid signature,
+// intact link). Resolution per reference:
+//
+//   resolved     target in corpus and verified
+//   unverified   target in corpus but failed verification   → TAMPERED
+//   unknown      target not in corpus                       → note only,
+//                or with -require-closed-world a failure    → UNVERIFIABLE
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (s *stringList) String() string     { return strings.Join(*s, ",") }
+func (s *stringList) Set(v string) error { *s = append(*s, v); return nil }
+
+// recordRef is one cross-reference found in a payload.
+type recordRef struct {
+	Pointer string
+	Hash    string
+}
+
+// resolvePointer evaluates an RFC 6901 JSON Pointer against doc.
+func resolvePointer(doc interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return doc, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	cur := doc
+	for _, tok := range strings.Split(pointer[1:], "/") {
+		tok = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			next, ok := node[tok]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []interface{}:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			cur = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// normalizeHash lower-cases a causal hash and drops a "sha256:" prefix.
+func normalizeHash(h string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(h)), "sha256:")
+}
+
+// extractRefs collects the references at pointers in payload. Pointers
+// that do not resolve contribute nothing; non-string values are
+// reported as refs with an empty hash so they fail loudly.
+func extractRefs(payload interface{}, pointers []string) []recordRef {
+	var refs []recordRef
+	for _, p := range pointers {
+		v, ok := resolvePointer(payload, p)
+		if !ok {
+			continue
+		}
+		switch val := v.(type) {
+		case string:
+			refs = append(refs, recordRef{p, normalizeHash(val)})
+		case []interface{}:
+			for i, e := range val {
+				s, _ := e.(string)
+				refs = append(refs, recordRef{fmt.Sprintf("%s/%d", p, i), normalizeHash(s)})
+			}
+		default:
+			refs = append(refs, recordRef{p, ""})
+		}
+	}
+	return refs
+}
+
+// checkRefs runs the referential-integrity pass over ordered records.
+// verified and inCorpus are keyed by chain hash.
+func checkRefs(run *chainRun, ordered []chainTuple, verified, inCorpus map[string]bool, closedWorld bool) {
+	defer func() { run.agent = "" }()
+	for _, t := range ordered {
+		if len(t.Refs) == 0 {
+			continue
+		}
+		run.agent = t.AgentID
+		var resolved, unknown int
+		var unverified, dangling []recordRef
+		for _, ref := range t.Refs {
+			switch {
+			case verified[ref.Hash]:
+				resolved++
+			case inCorpus[ref.Hash]:
+				unverified = append(unverified, ref)
+			case closedWorld:
+				dangling = append(dangling, ref)
+			default:
+				unknown++
+			}
+		}
+
+		name := filepath.Base(t.File)
+		if len(unverified) > 0 {
+			run.check(gefverify.CategoryIntegrity, "reference to unverified record", fmt.Sprintf("%s references verified records", name), false,
+				fmt.Sprintf("%d reference(s) to records that failed verification", len(unverified)))
+			for _, ref := range unverified {
+				fmt.Fprintf(stdout, "       unverified: %s → %s\n", ref.Pointer, ref.Hash)
+			}
+		}
+		if len(dangling) > 0 {
+			run.check(gefverify.CategoryCompleteness, "dangling reference", fmt.Sprintf("%s references resolve in corpus", name), false,
+				fmt.Sprintf("%d reference(s) outside corpus (closed world)", len(dangling)))
+			for _, ref := range dangling {
+				fmt.Fprintf(stdout, "       dangling  : %s → %q\n", ref.Pointer, ref.Hash)
+			}
+		}
+		if len(unverified) == 0 && len(dangling) == 0 {
+			run.check(gefverify.CategoryIntegrity, "reference to unverified record", fmt.Sprintf("%s references resolve", name), true,
+				fmt.Sprintf("%d resolved, %d unknown (outside corpus)", resolved, unknown))
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// cross_lang_proof/refs_test.go
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolvePointer(t *testing.T) {
+	doc := map[string]interface{}{
+		"parent_record": "abc",
+		"related":       []interface{}{"x", "y"},
+		"a/b":           map[string]interface{}{"m~n": "esc"},
+	}
+	tests := []struct {
+		pointer string
+		want    interface{}
+		ok      bool
+	}{
+		{"/parent_record", "abc", true},
+		{"/related/1", "y", true},
+		{"/a~1b/m~0n", "esc", true},
+		{"/related/2", nil, false},
+		{"/missing", nil, false},
+		{"parent_record", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := resolvePointer(doc, tt.pointer)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("%s: got (%v, %v), want (%v, %v)", tt.pointer, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestExtractRefs(t *testing.T) {
+	payload := map[string]interface{}{
+		"parent_record": "SHA256:ABCD",
+		"related":       []interface{}{"ef01", 7},
+	}
+	refs := extractRefs(payload, []string{"/parent_record", "/related", "/absent"})
+	want := []recordRef{{"/parent_record", "abcd"}, {"/related/0", "ef01"}, {"/related/1", ""}}
+	if len(refs) != len(want) {
+		t.Fatalf("refs = %v, want %v", refs, want)
+	}
+	for i := range want {
+		if refs[i] != want[i] {
+			t.Errorf("ref %d = %v, want %v", i, refs[i], want[i])
+		}
+	}
+}
+
+func TestCheckRefs(t *testing.T) {
+	good, bad, outside := strings.Repeat("a", 64), strings.Repeat("b", 64), strings.Repeat("c", 64)
+	verified := map[string]bool{good: true}
+	inCorpus := map[string]bool{good: true, bad: true}
+	ordered := []chainTuple{
+		{File: "ok.json", Refs: []recordRef{{"/parent", good}, {"/related/0", outside}}},
+		{File: "tampered.json", Refs: []recordRef{{"/parent", bad}}},
+	}
+
+	run := newChainRun()
+	checkRefs(run, ordered, verified, inCorpus, false)
+	if len(run.results) != 2 || !run.results[0].Passed || run.results[1].Passed {
+		t.Fatalf("open world: results = %+v", run.results)
+	}
+	if run.failures["reference to unverified record"] != 1 {
+		t.Errorf("failures = %v", run.failures)
+	}
+
+	run = newChainRun()
+	checkRefs(run, ordered[:1], verified, inCorpus, true)
+	if len(run.results) != 1 || run.results[0].Passed || run.failures["dangling reference"] != 1 {
+		t.Errorf("closed world: results = %+v failures = %v", run.results, run.failures)
+	}
+}
@@ -0,0 +1,247 @@
+// cross_lang_proof/reportmerge.go
+//
+// Shard report merge (report merge subcommand)
+// ============================================
+//
+//   verify_proof report merge [-manifest order.json] [-o corpus.json] <shard.json...>
+//
+// Combines the shard reports of one sharded chain run (see shard.go) into
+// one corpus report. The shard checks are carried over unchanged; merge
+// adds its own consistency checks (section MERGE) and derives the verdict
+// from all of them, exactly as an unsharded run would:
+//
+//   M.shard_set          every shard 1..N present once, same N and scheme
+//   M.corpus_mismatch    every shard saw the same input listing
+//   M.partition          every claimed record belongs to its shard
+//   M.claimed_twice      no bundle claimed by two shards
+//   M.unclaimed          every file of the input listing claimed
+//   M.manifest_unclaimed with -manifest: every entry (file path or
+//                        record_id) claimed, the completeness a chain
+//                        -manifest run would check; a path matches as
+//                        it does there, and one matching two records
+//                        claims neither
+//
+// -o writes the corpus report document; the exit code is its verdict.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// SectionMerge heads the consistency checks of report merge.
+const SectionMerge = "MERGE"
+
+// runReport dispatches the report subcommands.
+func runReport(args []string) int {
+	if len(args) > 0 && args[0] == "merge" {
+		return runReportMerge(args[1:])
+	}
+	fmt.Fprintln(stderr, "usage: verify_proof report merge [-manifest order.json] [-o corpus.json] <shard.json...>")
+	return 2
+}
+
+func runReportMerge(args []string) int {
+	fs := newFlagSet("report merge")
+	manifestPath := fs.String("manifest", "",
+		"JSON array of filenames or record_ids every one of which must be claimed by a shard")
+	outPath := fs.String("o", "", "write the corpus report document to this `file`")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof report merge [-manifest order.json] [-o corpus.json] <shard.json...>")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	var shards []shardReport
+	for _, path := range fs.Args() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", path, err)
+			return 2
+		}
+		r, err := parseShardReport(data)
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: %s: %v\n", path, err)
+			return gefverify.VerdictMalformed.ExitCode()
+		}
+		shards = append(shards, r)
+	}
+	var manifest []string
+	if *manifestPath != "" {
+		var err error
+		if manifest, err = readManifest(*manifestPath); err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot read manifest %s: %v\n", *manifestPath, err)
+			return 2
+		}
+	}
+	sort.SliceStable(shards, func(i, j int) bool { return shards[i].Shard < shards[j].Shard })
+
+	bar := console.bar
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout, "  GEF Shard Report Merge — Go Verifier")
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+
+	var checks []gefverify.CheckResult
+	for _, s := range shards {
+		for _, c := range s.Report.Checks {
+			checks = append(checks, gefverify.CheckResult{
+				ID: c.ID, Section: c.Section, Name: c.Name, Passed: c.Passed,
+				Details: c.Details, Category: categoryNamed(c.Category), Exception: c.Exception,
+			})
+		}
+		fmt.Fprintf(stdout, "  shard %d/%d   %6d record(s)   %d/%d checks   verdict=%s\n",
+			s.Shard, s.Shards, len(s.Records), s.Report.Passed, s.Report.Total, s.Report.Verdict)
+	}
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "  "+SectionMerge+" — Shard consistency")
+	fmt.Fprintln(stdout, "  "+console.rule)
+	check := func(id string, category gefverify.Category, name string, passed bool, details string) {
+		r := gefverify.CheckResult{ID: id, Section: SectionMerge, Name: name, Passed: passed, Details: details, Category: category}
+		checks = append(checks, r)
+		printCheck(r)
+	}
+
+	// Shard set: one N, one scheme, every index once.
+	n, seen, problems := shards[0].Shards, make(map[int]bool), []string(nil)
+	for _, s := range shards {
+		switch {
+		case s.Shards != n || s.Partition != shards[0].Partition:
+			problems = append(problems, fmt.Sprintf("shard %d/%d (%s) is from another run", s.Shard, s.Shards, s.Partition))
+		case seen[s.Shard]:
+			problems = append(problems, fmt.Sprintf("shard %d given twice", s.Shard))
+		}
+		seen[s.Shard] = true
+	}
+	for i := 1; i <= n; i++ {
+		if !seen[i] {
+			problems = append(problems, fmt.Sprintf("shard %d/%d missing", i, n))
+		}
+	}
+	check("M.shard_set", gefverify.CategoryCompleteness, fmt.Sprintf("shards 1..%d present once each", n), len(problems) == 0,
+		summarize(problems, fmt.Sprintf("%d shard(s), partition %s", len(shards), shards[0].Partition)))
+
+	// Corpus: every shard listed the same input.
+	corpus, mismatched := shards[0].Corpus, []string(nil)
+	for _, s := range shards[1:] {
+		if s.Corpus != corpus {
+			mismatched = append(mismatched, fmt.Sprintf("shard %d saw %d file(s), digest %.12s", s.Shard, s.Corpus.Files, s.Corpus.Digest))
+		}
+	}
+	check("M.corpus_mismatch", gefverify.CategoryCompleteness, "every shard saw the same corpus", len(mismatched) == 0,
+		summarize(mismatched, fmt.Sprintf("%d file(s), digest %.12s", corpus.Files, corpus.Digest)))
+
+	// Claims: partition respected, nothing twice, nothing missing.
+	owner := make(map[string]int)
+	byRecordID := make(map[string]bool)
+	var misplaced, twice []string
+	for _, s := range shards {
+		for _, rec := range s.Records {
+			if s.Partition == partitionScheme && shardOf(partitionKey(rec.AgentID, rec.File), s.Shards) != s.Shard {
+				misplaced = append(misplaced, fmt.Sprintf("%s claimed by shard %d", rec.File, s.Shard))
+			}
+			if prev, dup := owner[rec.File]; dup {
+				twice = append(twice, fmt.Sprintf("%s claimed by shards %d and %d", rec.File, prev, s.Shard))
+			}
+			owner[rec.File] = s.Shard
+			if rec.RecordID != "" {
+				byRecordID[rec.RecordID] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(owner))
+	for f := range owner {
+		names = append(names, f)
+	}
+	check("M.partition", gefverify.CategoryIntegrity, "every record verified by its own shard", len(misplaced) == 0,
+		summarize(misplaced, "partition "+partitionScheme))
+	check("M.claimed_twice", gefverify.CategoryIntegrity, "no bundle claimed by two shards", len(twice) == 0,
+		summarize(twice, fmt.Sprintf("%d bundle(s) claimed", len(owner))))
+	complete := len(names) == corpus.Files && corpusDigest(names) == corpus.Digest
+	check("M.unclaimed", gefverify.CategoryCompleteness, "every bundle of the corpus claimed", complete,
+		fmt.Sprintf("%d of %d claimed", len(names), corpus.Files))
+
+	if manifest != nil {
+		var unclaimed []string
+		for _, entry := range manifest {
+			want, matches := filepath.Clean(filepath.FromSlash(entry)), 0
+			for _, f := range names {
+				if namesFile(want, f) {
+					matches++
+				}
+			}
+			switch {
+			case matches > 1:
+				unclaimed = append(unclaimed, fmt.Sprintf("%s (matches %d records; name one by a longer path)", entry, matches))
+			case matches == 0 && !byRecordID[entry]:
+				unclaimed = append(unclaimed, entry)
+			}
+		}
+		check("M.manifest_unclaimed", gefverify.CategoryCompleteness, "every manifest entry claimed by a shard", len(unclaimed) == 0,
+			fmt.Sprintf("%d unclaimed", len(unclaimed)))
+		for _, e := range unclaimed {
+			fmt.Fprintf(stdout, "       unclaimed: %s\n", e)
+		}
+	}
+
+	report := gefverify.Report{Checks: checks, Verdict: gefverify.DeriveVerdict(checks)}
+	if *outPath != "" {
+		doc := gefverify.NewReportDocument(report, fmt.Sprintf("corpus (%d shards)", n), "")
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err == nil {
+			err = writeFileAtomic(*outPath, append(data, '\n'), 0o644)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", *outPath, err)
+			return 2
+		}
+	}
+
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, bar)
+	passed, total, verdict := report.Passed(), report.Total(), report.Verdict
+	if passed == total {
+		fmt.Fprintf(stdout, "  %s  CORPUS VERIFIED  (%d records, %d/%d checks)  verdict=%s\n", console.pass, len(owner), passed, total, verdict)
+	} else {
+		fmt.Fprintf(stdout, "  %s  CORPUS VERIFICATION FAILED  (%d/%d checks passed)  verdict=%s\n\n", console.fail, passed, total, verdict)
+		printFailures(report.Failed())
+	}
+	fmt.Fprintln(stdout, bar)
+	fmt.Fprintln(stdout)
+	return verdict.ExitCode()
+}
+
+// categoryNamed maps a report document category back; unknown names
+// count as structure so they can never pass silently.
+func categoryNamed(name string) gefverify.Category {
+	for _, c := range gefverify.Categories() {
+		if c.String() == name {
+			return c
+		}
+	}
+	return gefverify.CategoryStructure
+}
+
+// summarize lists at most a few problems, or ok if there are none.
+func summarize(problems []string, ok string) string {
+	const shown = 3
+	switch {
+	case len(problems) == 0:
+		return ok
+	case len(problems) > shown:
+		return strings.Join(problems[:shown], "; ") + fmt.Sprintf("; and %d more", len(problems)-shown)
+	}
+	return strings.Join(problems, "; ")
+}
@@ -48,7 +48,7 @@ Write-Host ""
 Write-Host "  [3/3] Running Go verifier..."
 Write-Host ""
 
-go run verify_proof.go
+go run .
 $goExitCode = $LASTEXITCODE
 
 # ── Final verdict ─────────────────────────────────────────────
@@ -0,0 +1,157 @@
+// cross_lang_proof/sarif.go
+//
+// SARIF output (-format sarif)
+// ============================
+//
+//   verify_proof -format sarif bundle.json > gef.sarif
+//
+// A SARIF 2.1.0 log for code scanning UIs (GitHub code scanning accepts
+// nothing else). It is built from the report document, the same data as
+// -format json and -report-json, not from a second pass over the Report.
+//
+//   rules     one per contract and policy, GEF-C3-signature and so on,
+//             declared whether or not it failed so baselines stay stable
+//   results   one per failed check: ruleId of its contract, the bundle
+//             file as location, the check details as message; a failure
+//             accepted under a policy exception carries a suppression
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifRules are the rules of the log, one per section.
+var sarifRules = []struct {
+	ID      string
+	Section string
+}{
+	{"GEF-C1-canonical-bytes", gefverify.SectionCanonicalBytes},
+	{"GEF-C2-chain-hash", gefverify.SectionChainHash},
+	{"GEF-C3-signature", gefverify.SectionSignature},
+	{"GEF-C4-dict-identity", gefverify.SectionDictIdentity},
+	{"GEF-C5-field-count", gefverify.SectionFieldCount},
+	{"GEF-C6-negative-test", gefverify.SectionNegativeTest},
+	{"GEF-C7-version-binding", gefverify.SectionVersionBinding},
+	{"GEF-C8-envelope", gefverify.SectionEnvelope},
+	{"GEF-C9-timestamp", gefverify.SectionTimestamp},
+	{"GEF-C10-key-trust", gefverify.SectionKeyTrust},
+	{"GEF-C11-signer-binding", gefverify.SectionSignerBinding},
+	{"GEF-P-freshness", gefverify.SectionFreshness},
+	{"GEF-P-size-limits", gefverify.SectionSizeLimits},
+	{"GEF-N-nested", gefverify.SectionNested},
+	{"GEF-X-cross-verify", sectionCrossVerify},
+	{"GEF-other", "checks outside the sections above"},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	RuleIndex    int                `json:"ruleIndex"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+	Properties   sarifProperties    `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+	} `json:"physicalLocation"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification"`
+}
+
+type sarifProperties struct {
+	CheckID  string `json:"check_id"`
+	Category string `json:"category"`
+	Verdict  string `json:"verdict"`
+}
+
+// newSARIFLog converts the report document doc.
+func newSARIFLog(doc gefverify.ReportDocument) sarifLog {
+	driver := sarifDriver{Name: "gef-verify_proof", Version: doc.VerifierVersion}
+	index := make(map[string]int, len(sarifRules))
+	for i, r := range sarifRules {
+		index[r.Section] = i
+		driver.Rules = append(driver.Rules, sarifRule{ID: r.ID, ShortDescription: sarifMessage{r.Section}})
+	}
+
+	var loc sarifLocation
+	loc.PhysicalLocation.ArtifactLocation.URI = filepath.ToSlash(doc.Bundle)
+	run := sarifRun{Tool: sarifTool{driver}, Results: []sarifResult{}}
+	for _, c := range doc.Checks {
+		if c.Passed {
+			continue
+		}
+		i, ok := index[c.Section]
+		if !ok {
+			i = len(sarifRules) - 1 // GEF-other, never dropped
+		}
+		res := sarifResult{
+			RuleID:     sarifRules[i].ID,
+			RuleIndex:  i,
+			Level:      "error",
+			Message:    sarifMessage{fmt.Sprintf("%s: %s failed: %s", c.ID, c.Name, c.Details)},
+			Locations:  []sarifLocation{loc},
+			Properties: sarifProperties{CheckID: c.ID, Category: c.Category, Verdict: string(doc.Verdict)},
+		}
+		if c.Exception != "" {
+			res.Suppressions = []sarifSuppression{{Kind: "external", Justification: "policy exception " + c.Exception}}
+		}
+		run.Results = append(run.Results, res)
+	}
+	return sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}}
+}
+
+// writeSARIF writes the SARIF log for doc.
+func writeSARIF(w io.Writer, doc gefverify.ReportDocument) error {
+	data, err := json.MarshalIndent(newSARIFLog(doc), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
@@ -0,0 +1,79 @@
+// cross_lang_proof/sarif_test.go
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+func sarifDocument(t *testing.T, edit func(string) string) gefverify.ReportDocument {
+	t.Helper()
+	bundle, err := gefverify.ParseBundle([]byte(edit(string(mustRead(t, "proof_bundle.json")))))
+	must(t, err)
+	report, _ := gefverify.Verify(bundle, gefverify.VerifyOptions{})
+	return gefverify.NewReportDocument(report, "proof_bundle.json", bundle.GEFVersion)
+}
+
+func TestSARIFGolden(t *testing.T) {
+	var buf bytes.Buffer
+	must(t, writeSARIF(&buf, sarifDocument(t, tamperSignature)))
+	got  := buf.String()
+	path := filepath.Join("testdata", "sarif_fail.golden.json")
+	if *update {
+		must(t, os.WriteFile(path, []byte(got), 0o644))
+	}
+	if want := string(mustRead(t, path)); got != want {
+		t.Errorf("SARIF differs from %s (run go test -update):\n%s", path, got)
+	}
+}
+
+func TestSARIFDeclaresRulesWhenPassing(t *testing.T) {
+	log := newSARIFLog(sarifDocument(t, func(s string) string { return s }))
+	run := log.Runs[0]
+	if len(run.Results) != 0 {
+		t.Errorf("passing bundle has results: %+v", run.Results)
+	}
+	declared := map[string]bool{}
+	for _, r := range run.Tool.Driver.Rules {
+		declared[r.ShortDescription.Text] = true
+	}
+	for _, section := range gefverify.RequiredContracts {
+		if !declared[section] {
+			t.Errorf("no rule for %s", section)
+		}
+	}
+}
+
+func TestSARIFException(t *testing.T) {
+	doc := gefverify.ReportDocument{Bundle: "b.json", Checks: []gefverify.CheckDocument{
+		{ID: "P.freshness", Section: gefverify.SectionFreshness, Name: "fresh", Exception: "EX-1"},
+		{ID: "Z.unknown", Section: "UNKNOWN"},
+	}}
+	results := newSARIFLog(doc).Runs[0].Results
+	if len(results) != 2 || results[0].RuleID != "GEF-P-freshness" || len(results[0].Suppressions) != 1 {
+		t.Fatalf("results %+v", results)
+	}
+	if results[1].RuleID != "GEF-other" || len(results[1].Suppressions) != 0 {
+		t.Errorf("unknown section: %+v", results[1])
+	}
+}
+
+func TestFormatSARIF(t *testing.T) {
+	code, out, _ := runCaptured(t, "-format", "sarif", writeBundle(t, tamperSignature))
+	var log sarifLog
+	if err := json.Unmarshal([]byte(out), &log); err != nil {
+		t.Fatalf("stdout is not SARIF: %v\n%s", err, out)
+	}
+	if code != gefverify.VerdictTampered.ExitCode() || log.Version != "2.1.0" || len(log.Runs[0].Results) != 4 {
+		t.Errorf("exit %d, log %+v", code, log)
+	}
+	if got := log.Runs[0].Results[0].RuleID; got != "GEF-C3-signature" {
+		t.Errorf("ruleId %s, want GEF-C3-signature", got)
+	}
+}
@@ -0,0 +1,133 @@
+// cross_lang_proof/sequences.go
+//
+// Per-agent sequence order (batch and chain modes, -allow-gaps)
+// =============================================================
+//
+//   verify_proof -dir ./bundles [-allow-gaps]
+//   verify_proof chain [-allow-gaps] <dir>
+//
+// Every record carries agent_id and sequence, so several records of one
+// agent say whether any are missing or doubled. Each agent's records
+// are taken in sequence order (a chain's causal order, which may be a
+// manifest's), and every record after the first is one step:
+//
+//   seq = previous + 1   passes
+//   seq > previous + 1   a gap: "agent X jumps from 41 to 44, 2 record(s)
+//                        missing between record_id A and B"
+//   seq = previous       a duplicate: two records claim one place, a fork
+//   seq < previous       a regression: out of order (manifest order only)
+//
+// In a batch these are B.sequence_gap and B.duplicate_sequence on the
+// later record of the step, among the bundles that verified; the same
+// bundle named twice is one record, and a copy of it in another file is
+// a nonce replay (nonces.go). In a chain they are chain.sequence_gap,
+// chain.duplicate_sequence and chain.sequence_regression in PASS 2, and
+// the causal link is not checked across a duplicate or a step back.
+// Across a gap a link to the record before it is checked as usual; one
+// to a missing record cannot be, and is a note: the gap is the finding.
+// -chain-rules sets the severity of gaps per record_type; duplicates and
+// regressions always fail.
+//
+// -allow-gaps is for archives that are partial on purpose: gaps are
+// warnings, and do not affect the verdict.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// sectionSequences is the section of the batch sequence checks.
+const sectionSequences = "BATCH — Sequence order"
+
+// sequenceOrder is how a record follows the one before it.
+type sequenceOrder int
+
+const (
+	sequenceNext       sequenceOrder = iota // seq = previous + 1
+	sequenceGap                             // records missing in between
+	sequenceDuplicate                       // the same seq twice
+	sequenceRegression                      // seq below the previous
+)
+
+// sequenceStep classifies the step from the record prevID at prevSeq to
+// recordID at seq, both agentID's, and returns the details for the
+// check line.
+func sequenceStep(agentID string, prevSeq, seq int64, prevID, recordID string) (sequenceOrder, string) {
+	switch {
+	case seq == prevSeq+1:
+		return sequenceNext, fmt.Sprintf("seq=%d previous=%d", seq, prevSeq)
+	case seq > prevSeq:
+		return sequenceGap, fmt.Sprintf("agent %s jumps from %d to %d, %d record(s) missing between record_id %s and %s",
+			agentID, prevSeq, seq, seq-prevSeq-1, prevID, recordID)
+	case seq == prevSeq:
+		return sequenceDuplicate, fmt.Sprintf("agent %s: record_id %s and %s both at seq %d", agentID, prevID, recordID, seq)
+	}
+	return sequenceRegression, fmt.Sprintf("agent %s goes back from %d to %d, record_id %s after %s",
+		agentID, prevSeq, seq, recordID, prevID)
+}
+
+// batchRecord is a verified batch entry as sequence order sees it.
+type batchRecord struct {
+	entry     *batchEntry
+	sequence  int64
+	recordID  string
+	chainHash string
+}
+
+// checkBatchSequences adds the sequence step checks to the verified
+// bundles of entries, per agent_id in sequence order; the batch order
+// breaks ties. allowGaps reports gaps as warnings.
+func checkBatchSequences(entries []batchEntry, allowGaps bool) {
+	agents := make(map[string][]batchRecord)
+	var order []string
+	for i := range entries {
+		e := &entries[i]
+		if e.Bundle == nil || e.Verdict != gefverify.VerdictVerified {
+			continue
+		}
+		agentID, _ := e.Bundle.SigningDict["agent_id"].(string)
+		seq, ok := e.Bundle.SigningDict["sequence"].(float64)
+		if agentID == "" || !ok || seq != float64(int64(seq)) {
+			continue
+		}
+		recordID, _ := e.Bundle.SigningDict["record_id"].(string)
+		if _, seen := agents[agentID]; !seen {
+			order = append(order, agentID)
+		}
+		agents[agentID] = append(agents[agentID], batchRecord{e, int64(seq), recordID, e.Bundle.CausalHashOfThis})
+	}
+	for _, agentID := range order {
+		records := agents[agentID]
+		sort.SliceStable(records, func(i, j int) bool { return records[i].sequence < records[j].sequence })
+		for i := 1; i < len(records); i++ {
+			prev, cur := records[i-1], records[i]
+			if cur.chainHash == prev.chainHash {
+				continue // the same record twice
+			}
+			order, details := sequenceStep(agentID, prev.sequence, cur.sequence, prev.recordID, cur.recordID)
+			e := cur.entry
+			if order == sequenceGap && allowGaps {
+				e.Report.Warnings = append(e.Report.Warnings, details+"  (-allow-gaps)")
+				continue
+			}
+			c := gefverify.CheckResult{
+				ID:       "B.sequence_gap",
+				Section:  sectionSequences,
+				Name:     fmt.Sprintf("seq %d follows seq %d of agent %s", cur.sequence, prev.sequence, agentID),
+				Passed:   order == sequenceNext,
+				Details:  details,
+				Category: gefverify.CategoryCompleteness,
+			}
+			if order == sequenceDuplicate {
+				c.ID, c.Category = "B.duplicate_sequence", gefverify.CategoryIntegrity
+			}
+			e.Report.Checks = append(e.Report.Checks, c)
+			e.Report.Verdict = gefverify.DeriveVerdict(e.Report.Checks)
+			e.summarize()
+		}
+	}
+}
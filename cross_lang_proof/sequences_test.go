@@ -0,0 +1,119 @@
+// cross_lang_proof/sequences_test.go
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// writeFork writes a second record of agent at seq, linked to the record
+// in prevFile and signed with writeChainDir's key.
+func writeFork(t *testing.T, dir, agent string, seq int64, prevFile string) string {
+	t.Helper()
+	var prev gefverify.ProofBundle
+	must(t, json.Unmarshal(mustRead(t, prevFile), &prev))
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte(agent[:1]), 32))
+	_, bundle, err := gefverify.NewRecord(agent, "execution").Sequence(seq).PreviousHash(prev.CausalHashOfThis).Finalize(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(bundle)
+	path := filepath.Join(dir, agent+"-fork.json")
+	must(t, os.WriteFile(path, data, 0o644))
+	return path
+}
+
+func TestSequenceStep(t *testing.T) {
+	tests := []struct {
+		prev, seq int64
+		want      sequenceOrder
+		details   string
+	}{
+		{41, 42, sequenceNext, "seq=42 previous=41"},
+		{41, 44, sequenceGap, "agent a jumps from 41 to 44, 2 record(s) missing between record_id r41 and r44"},
+		{41, 41, sequenceDuplicate, "agent a: record_id r41 and r44 both at seq 41"},
+		{41, 40, sequenceRegression, "agent a goes back from 41 to 40, record_id r44 after r41"},
+	}
+	for _, tt := range tests {
+		got, details := sequenceStep("a", tt.prev, tt.seq, "r41", "r44")
+		if got != tt.want || details != tt.details {
+			t.Errorf("%d -> %d: %v %q, want %v %q", tt.prev, tt.seq, got, details, tt.want, tt.details)
+		}
+	}
+}
+
+func TestBatchSequenceOrder(t *testing.T) {
+	dir := t.TempDir()
+	files := writeChainDir(t, dir, "alpha", 4)
+	writeChainDir(t, dir, "beta", 2)
+	must(t, os.Remove(files[2]))
+
+	code, out, _ := runCaptured(t, "-dir", dir)
+	if code != gefverify.VerdictUnverifiable.ExitCode() || !strings.Contains(out, "failed: B.sequence_gap") {
+		t.Errorf("gap: exit %d\n%s", code, out)
+	}
+	_, _, errOut := runCaptured(t, "-quiet", "-dir", dir)
+	if !strings.Contains(errOut, "agent alpha jumps from 1 to 3, 1 record(s) missing between record_id gef-") {
+		t.Errorf("gap details: stderr %q", errOut)
+	}
+	code, out, _ = runCaptured(t, "-allow-gaps", "-dir", dir)
+	if code != 0 || !strings.Contains(out, "jumps from 1 to 3") || !strings.Contains(out, "(-allow-gaps)") {
+		t.Errorf("-allow-gaps: exit %d\n%s", code, out)
+	}
+
+	// Two records at seq 1 fail with or without -allow-gaps.
+	fork := writeFork(t, dir, "alpha", 1, files[0])
+	for _, args := range [][]string{{"-dir", dir}, {"-allow-gaps", "-dir", dir}} {
+		code, out, _ := runCaptured(t, args...)
+		if code != 1 || !strings.Contains(out, "TAMPERED               "+fork) || !strings.Contains(out, "failed: B.duplicate_sequence") {
+			t.Errorf("%v: exit %d\n%s", args, code, out)
+		}
+	}
+
+	if code, _, _ := runCaptured(t, "-allow-gaps", files[0]); code != 2 {
+		t.Errorf("-allow-gaps with one bundle: exit %d, want 2", code)
+	}
+}
+
+func TestChainSequenceOrder(t *testing.T) {
+	dir := t.TempDir()
+	files := writeChainDir(t, dir, "alpha", 4)
+	must(t, os.Remove(files[2]))
+
+	code, out, _ := runCaptured(t, "chain", dir)
+	if code != gefverify.VerdictUnverifiable.ExitCode() || !strings.Contains(out, "agent alpha jumps from 1 to 3, 1 record(s) missing") {
+		t.Errorf("gap: exit %d\n%s", code, out)
+	}
+	code, out, _ = runCaptured(t, "chain", "-allow-gaps", dir)
+	if code != 0 || !strings.Contains(out, "⚠   alpha-3.json follows seq 1") || !strings.Contains(out, "not in the corpus; link not checked") {
+		t.Errorf("-allow-gaps: exit %d\n%s", code, out)
+	}
+
+	forked := t.TempDir()
+	files = writeChainDir(t, forked, "alpha", 3)
+	writeFork(t, forked, "alpha", 1, files[0])
+	for _, args := range [][]string{{"chain", forked}, {"chain", "-allow-gaps", forked}} {
+		code, out, _ := runCaptured(t, args...)
+		if code != 1 || !strings.Contains(out, "duplicate sequence") || !strings.Contains(out, "both at seq 1") {
+			t.Errorf("%v: exit %d\n%s", args, code, out)
+		}
+	}
+
+	// A manifest can put a record after a later one.
+	manifest := filepath.Join(t.TempDir(), "order.json")
+	must(t, os.WriteFile(manifest, []byte(`["alpha-0.json", "alpha-2.json", "alpha-1.json"]`), 0o644))
+	ordered := t.TempDir()
+	writeChainDir(t, ordered, "alpha", 3)
+	code, out, _ = runCaptured(t, "chain", "-manifest", manifest, ordered)
+	if code != 1 || !strings.Contains(out, "agent alpha goes back from 2 to 1") {
+		t.Errorf("regression: exit %d\n%s", code, out)
+	}
+}
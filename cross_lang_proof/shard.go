@@ -0,0 +1,212 @@
+// cross_lang_proof/shard.go
+//
+// Sharded chain verification (chain -shard, -shard-report)
+// ========================================================
+//
+//   verify_proof chain -shard 2/8 -shard-report s2.json <dir>
+//   verify_proof report merge -o corpus.json s1.json ... s8.json
+//
+// Splits one corpus across N machines. Every shard lists the whole corpus
+// but verifies only the chains it owns, so each chain is verified whole,
+// by exactly one shard:
+//
+//   owner(key) = 1 + (first 8 bytes of SHA-256(key), big-endian) mod N
+//
+// The key is the record's agent_id, or the file base name for a bundle
+// whose agent_id cannot be read (it is reported as unreadable by its
+// owner and only there). The function is part of the shard report format
+// ("partition": "sha256-agent-v1"): changing it makes a new scheme name,
+// never a different assignment under the old one; shard_test.go pins it.
+//
+// A shard pays pass 1 parsing for every file but signatures, hashing and
+// everything after only for its own records. -manifest and -ref-pointer
+// need the whole corpus and are refused together with -shard; the merge
+// takes the manifest instead (see reportmerge.go).
+//
+// -shard-report writes the shard's result: the corpus it saw (file count
+// and digest of the sorted file names), every record it claimed, and its
+// report document. Without -shard it describes an unsharded run, 1/1.
+// Files and records are named by their path below the deepest directory
+// holding every input (corpusName), so a/x.json and b/x.json stay apart
+// while the mount point of the corpus on each machine drops out.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+const (
+	shardReportFormat  = "gef-shard-report"
+	shardReportVersion = 1
+	partitionScheme    = "sha256-agent-v1"
+)
+
+// errOtherShard marks a record another shard verifies.
+var errOtherShard = errors.New("record belongs to another shard")
+
+// shardSpec is one shard of N, 1-based; the zero value is unsharded.
+type shardSpec struct {
+	Index, Count int
+}
+
+func parseShard(s string) (shardSpec, error) {
+	if s == "" {
+		return shardSpec{}, nil
+	}
+	i, n, ok := strings.Cut(s, "/")
+	index, err1 := strconv.Atoi(i)
+	count, err2 := strconv.Atoi(n)
+	if !ok || err1 != nil || err2 != nil || count < 1 || index < 1 || index > count {
+		return shardSpec{}, fmt.Errorf("-shard %q: want i/N with 1 <= i <= N", s)
+	}
+	return shardSpec{index, count}, nil
+}
+
+func (s shardSpec) sharded() bool { return s.Count > 1 }
+
+func (s shardSpec) String() string {
+	if s.Count == 0 {
+		return "1/1"
+	}
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
+
+// owns reports whether key is verified by this shard.
+func (s shardSpec) owns(key string) bool {
+	return !s.sharded() || shardOf(key, s.Count) == s.Index
+}
+
+// shardOf is the partition function of partitionScheme. Never change it.
+func shardOf(key string, n int) int {
+	sum := sha256.Sum256([]byte(key))
+	return 1 + int(binary.BigEndian.Uint64(sum[:8])%uint64(n))
+}
+
+// partitionKey is the key a record is assigned by.
+func partitionKey(agentID, file string) string {
+	if agentID != "" {
+		return agentID
+	}
+	return filepath.Base(file)
+}
+
+// corpusRoot is the deepest directory holding every input in args: a
+// directory argument itself, or the directory of a file argument.
+func corpusRoot(args []string) string {
+	var root []string
+	for i, arg := range args {
+		dir, err := filepath.Abs(arg)
+		if err != nil {
+			dir = filepath.Clean(arg)
+		}
+		if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+			dir = filepath.Dir(dir)
+		}
+		parts := strings.Split(dir, string(filepath.Separator))
+		if i == 0 {
+			root = parts
+			continue
+		}
+		n := 0
+		for n < len(root) && n < len(parts) && root[n] == parts[n] {
+			n++
+		}
+		root = root[:n]
+	}
+	return strings.Join(root, string(filepath.Separator)) + string(filepath.Separator)
+}
+
+// corpusName is path, a file or array record (file#index) of an input,
+// named relative to root from corpusRoot, with forward slashes.
+func corpusName(root, path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(strings.TrimPrefix(abs, root))
+}
+
+// corpusDigest identifies the input listing independently of the paths
+// it was mounted under on each machine.
+func corpusDigest(names []string) string {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// ── Shard report ──────────────────────────────────────────────────────────────
+
+type shardReport struct {
+	Format    string                   `json:"format"`
+	Version   int                      `json:"version"`
+	Shard     int                      `json:"shard"`
+	Shards    int                      `json:"shards"`
+	Partition string                   `json:"partition"`
+	Corpus    shardCorpus              `json:"corpus"`
+	Records   []shardRecord            `json:"records"`
+	Report    gefverify.ReportDocument `json:"report"`
+}
+
+type shardCorpus struct {
+	Files  int    `json:"files"`
+	Digest string `json:"digest"`
+}
+
+// shardRecord is one bundle a shard claimed.
+type shardRecord struct {
+	File     string `json:"file"` // corpusName
+	AgentID  string `json:"agent_id,omitempty"`
+	RecordID string `json:"record_id,omitempty"`
+	Readable bool   `json:"readable"`
+}
+
+// newShardReport describes the run of shard over the files of the
+// corpus under root, by corpusName.
+func newShardReport(shard shardSpec, root string, files []string, claimed []shardRecord, report gefverify.Report) shardReport {
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = corpusName(root, f)
+	}
+	if shard.Count == 0 {
+		shard = shardSpec{1, 1}
+	}
+	sort.Slice(claimed, func(i, j int) bool { return claimed[i].File < claimed[j].File })
+	return shardReport{
+		Format:    shardReportFormat,
+		Version:   shardReportVersion,
+		Shard:     shard.Index,
+		Shards:    shard.Count,
+		Partition: partitionScheme,
+		Corpus:    shardCorpus{Files: len(files), Digest: corpusDigest(names)},
+		Records:   claimed,
+		Report:    gefverify.NewReportDocument(report, "shard "+shard.String(), ""),
+	}
+}
+
+func parseShardReport(data []byte) (shardReport, error) {
+	var r shardReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return r, fmt.Errorf("cannot parse shard report: %w", err)
+	}
+	if r.Format != shardReportFormat || r.Version != shardReportVersion {
+		return r, fmt.Errorf("not a %s version %d document", shardReportFormat, shardReportVersion)
+	}
+	if r.Report.SchemaVersion != gefverify.ReportSchemaVersion {
+		return r, fmt.Errorf("unsupported report schema_version %d", r.Report.SchemaVersion)
+	}
+	return r, nil
+}
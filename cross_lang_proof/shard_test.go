@@ -0,0 +1,215 @@
+// cross_lang_proof/shard_test.go
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// The partition is part of the shard report format: these must never
+// change under partitionScheme.
+func TestShardOfIsStable(t *testing.T) {
+	for _, tc := range []struct {
+		key     string
+		n, want int
+	}{
+		{"alpha", 3, 3}, {"beta", 3, 1}, {"gamma", 3, 1}, {"delta", 3, 2},
+		{"alpha", 8, 7}, {"delta", 8, 6}, {"agent-m", 1000, 318},
+		{"cross-lang-proof-agent", 1000, 204}, {"anything", 1, 1},
+	} {
+		if got := shardOf(tc.key, tc.n); got != tc.want {
+			t.Errorf("shardOf(%q, %d) = %d, want %d", tc.key, tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestParseShard(t *testing.T) {
+	if s, err := parseShard("2/8"); err != nil || s != (shardSpec{2, 8}) {
+		t.Errorf("2/8: %v %v", s, err)
+	}
+	for _, bad := range []string{"0/3", "4/3", "1/0", "3", "a/b", "1/3/5"} {
+		if _, err := parseShard(bad); err == nil {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+}
+
+// writeShardCorpus writes four agents, one record with a broken
+// signature and one unreadable file.
+func writeShardCorpus(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeChainDir(t, dir, "alpha", 3)
+	writeChainDir(t, dir, "beta", 2)
+	gamma := writeChainDir(t, dir, "gamma", 4)
+	writeChainDir(t, dir, "delta", 2)
+	data, err := os.ReadFile(gamma[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.Replace(string(data), `"record_type":"execution"`, `"record_type":"result"`, 1))
+	must(t, os.WriteFile(gamma[2], data, 0o644))
+	must(t, os.WriteFile(filepath.Join(dir, "zz-garbage.json"), []byte("{not json"), 0o644))
+	return dir
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	must(t, err)
+	must(t, json.Unmarshal(data, v))
+}
+
+// checkSet renders checks for comparison regardless of order.
+func checkSet(checks []gefverify.CheckDocument) []string {
+	var out []string
+	for _, c := range checks {
+		if !strings.HasPrefix(c.ID, "M.") {
+			out = append(out, fmt.Sprintf("%s|%s|%v|%s|%s", c.ID, c.Name, c.Passed, c.Details, c.Category))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestMergedShardsMatchUnshardedRun(t *testing.T) {
+	dir := writeShardCorpus(t)
+	out := t.TempDir()
+	full := filepath.Join(out, "full.json")
+	fullCode, _, _ := runCaptured(t, "chain", "-shard-report", full, dir)
+
+	args := []string{"report", "merge", "-o", filepath.Join(out, "merged.json")}
+	for i := 1; i <= 3; i++ {
+		path := filepath.Join(out, fmt.Sprintf("s%d.json", i))
+		runCaptured(t, "chain", "-shard", fmt.Sprintf("%d/3", i), "-shard-report", path, dir)
+		args = append(args, path)
+	}
+	code, stdoutText, _ := runCaptured(t, args...)
+
+	var unsharded shardReport
+	var merged gefverify.ReportDocument
+	readJSON(t, full, &unsharded)
+	readJSON(t, filepath.Join(out, "merged.json"), &merged)
+	if code != fullCode || merged.Verdict != unsharded.Report.Verdict {
+		t.Errorf("merged exit %d verdict %s, unsharded exit %d verdict %s", code, merged.Verdict, fullCode, unsharded.Report.Verdict)
+	}
+	if merged.Verdict != gefverify.VerdictMalformed {
+		t.Errorf("verdict %s, want MALFORMED for the unreadable bundle", merged.Verdict)
+	}
+	want, got := checkSet(unsharded.Report.Checks), checkSet(merged.Checks)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("merged checks differ from unsharded run:\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for _, c := range merged.Checks {
+		if strings.HasPrefix(c.ID, "M.") && !c.Passed {
+			t.Errorf("%s failed: %s\n%s", c.ID, c.Details, stdoutText)
+		}
+	}
+}
+
+func TestMergeConsistencyChecks(t *testing.T) {
+	dir := writeShardCorpus(t)
+	out := t.TempDir()
+	var shards []string
+	for i := 1; i <= 3; i++ {
+		path := filepath.Join(out, fmt.Sprintf("s%d.json", i))
+		runCaptured(t, "chain", "-shard", fmt.Sprintf("%d/3", i), "-shard-report", path, dir)
+		shards = append(shards, path)
+	}
+	manifest := filepath.Join(out, "manifest.json")
+	must(t, os.WriteFile(manifest, []byte(`["alpha-0.json", "never-written"]`), 0o644))
+
+	for _, tc := range []struct {
+		name   string
+		args   []string
+		failed []string
+	}{
+		{"missing shard", shards[:2], []string{"M.shard_set", "M.unclaimed"}},
+		{"shard twice", append(append([]string(nil), shards...), shards[0]), []string{"M.shard_set", "M.claimed_twice"}},
+		{"manifest entry unclaimed", append([]string{"-manifest", manifest}, shards...), []string{"M.manifest_unclaimed"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			merged := filepath.Join(t.TempDir(), "merged.json")
+			runCaptured(t, append([]string{"report", "merge", "-o", merged}, tc.args...)...)
+			var doc gefverify.ReportDocument
+			readJSON(t, merged, &doc)
+			var failed []string
+			for _, c := range doc.Checks {
+				if strings.HasPrefix(c.ID, "M.") && !c.Passed {
+					failed = append(failed, c.ID)
+				}
+			}
+			if strings.Join(failed, ",") != strings.Join(tc.failed, ",") {
+				t.Errorf("failed merge checks %v, want %v", failed, tc.failed)
+			}
+		})
+	}
+}
+
+func TestMergeSameBaseName(t *testing.T) {
+	root := t.TempDir()
+	a, b := filepath.Join(root, "a"), filepath.Join(root, "b")
+	must(t, os.Mkdir(a, 0o755))
+	must(t, os.Mkdir(b, 0o755))
+	other := "beta"
+	for _, agent := range []string{"beta", "gamma", "delta", "omega"} {
+		if shardOf(agent, 2) != shardOf("alpha", 2) {
+			other = agent
+			break
+		}
+	}
+	must(t, os.Rename(writeChainDir(t, a, "alpha", 1)[0], filepath.Join(a, "x.json")))
+	must(t, os.Rename(writeChainDir(t, b, other, 1)[0], filepath.Join(b, "x.json")))
+
+	out := t.TempDir()
+	var shards []string
+	for i := 1; i <= 2; i++ {
+		path := filepath.Join(out, fmt.Sprintf("s%d.json", i))
+		runCaptured(t, "chain", "-shard", fmt.Sprintf("%d/2", i), "-shard-report", path, a, b)
+		shards = append(shards, path)
+	}
+	manifest := filepath.Join(out, "manifest.json")
+	for _, tt := range []struct {
+		entries string
+		code    int
+	}{
+		{`["a/x.json", "b/x.json"]`, 0},
+		{`["x.json"]`, gefverify.VerdictUnverifiable.ExitCode()},
+	} {
+		must(t, os.WriteFile(manifest, []byte(tt.entries), 0o644))
+		code, stdoutText, _ := runCaptured(t, append([]string{"report", "merge", "-manifest", manifest}, shards...)...)
+		if code != tt.code {
+			t.Errorf("%s: exit %d, want %d\n%s", tt.entries, code, tt.code, stdoutText)
+		}
+	}
+}
+
+func TestShardRefusesWholeCorpusFlags(t *testing.T) {
+	dir := t.TempDir()
+	writeChainDir(t, dir, "alpha", 1)
+	for _, args := range [][]string{
+		{"chain", "-shard", "1/2", "-manifest", "m.json", dir},
+		{"chain", "-shard", "1/2", "-ref-pointer", "/ref", dir},
+		{"chain", "-shard", "3/2", dir},
+		{"report"},
+	} {
+		if code, _, _ := runCaptured(t, args...); code != 2 {
+			t.Errorf("%v: exit %d, want 2", args, code)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// cross_lang_proof/since.go
+//
+// Time-sliced chain verification (chain -since, -trusted-head, -from-snapshot)
+// ============================================================================
+//
+//   verify_proof chain -since 2026-02-24T00:00:00Z -trusted-head <hash> <dir>
+//   verify_proof chain -since 1200 -trusted-head <hash> <dir>
+//   verify_proof chain -from-snapshot heads.gefsnap <dir>
+//
+// Verifies only the tail of a long chain and assumes the history before
+// it. -since is an RFC 3339 time or a sequence number: records signed
+// before the time, or below the sequence, are left out of pass 2.
+// Records whose timestamp does not parse are never left out.
+//
+// The first verified record of each agent must link to a trusted head:
+//
+//   -from-snapshot   the agent's head in a snapshot written by a previous
+//                    chain -snapshot run; records at or below the head's
+//                    sequence are left out as already verified
+//   -trusted-head    one chain hash, for the agent of a single-agent corpus
+//
+// A first record that does not link to its trusted head fails at once
+// (chain.trusted_head_mismatch, TAMPERED) and pass 2 stops there: every
+// later verdict would rest on the wrong assumption. An agent without a
+// trusted head starts unanchored, as a corpus without its genesis does.
+// The verdict names the assumption so a report never reads as full-chain.
+//
+// Bundle directories have no index, so pass 1 still reads every file;
+// the slice saves pass 2 and pass 3 work and, above all, makes an audit
+// of the recent window independent of years of history.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// sinceSpec is the start of the verified window.
+type sinceSpec struct {
+	Set      bool
+	BySeq    bool
+	Sequence int64
+	Time     time.Time
+}
+
+func parseSince(s string) (sinceSpec, error) {
+	if s == "" {
+		return sinceSpec{}, nil
+	}
+	if seq, err := strconv.ParseInt(s, 10, 64); err == nil && seq >= 0 {
+		return sinceSpec{Set: true, BySeq: true, Sequence: seq}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return sinceSpec{}, fmt.Errorf("-since %q is neither a sequence number nor an RFC 3339 time", s)
+	}
+	return sinceSpec{Set: true, Time: t}, nil
+}
+
+// excludes reports whether t was recorded before the window.
+func (s sinceSpec) excludes(t chainTuple) bool {
+	switch {
+	case !s.Set:
+		return false
+	case s.BySeq:
+		return t.Sequence < s.Sequence
+	default:
+		return !t.Timestamp.IsZero() && t.Timestamp.Before(s.Time)
+	}
+}
+
+func (s sinceSpec) String() string {
+	if s.BySeq {
+		return fmt.Sprintf("seq %d", s.Sequence)
+	}
+	return s.Time.Format(time.RFC3339Nano)
+}
+
+// trustedHeads are the assumed chain states the window starts from.
+type trustedHeads struct {
+	Source  string
+	All     string               // -trusted-head, for every agent
+	ByAgent map[string]agentHead // -from-snapshot
+	Digest  [sha256.Size]byte    // the snapshot's config digest
+}
+
+// forAgent returns the trusted head hash for agent, if any.
+func (h *trustedHeads) forAgent(agent string) (string, bool) {
+	if h == nil {
+		return "", false
+	}
+	if head, ok := h.ByAgent[agent]; ok {
+		return hex.EncodeToString(head.HeadHash[:]), true
+	}
+	return h.All, h.All != ""
+}
+
+// loadTrustedHeads reads -from-snapshot or takes -trusted-head; nil if
+// neither is set.
+func loadTrustedHeads(headHex, snapshotPath string) (*trustedHeads, error) {
+	switch {
+	case headHex != "" && snapshotPath != "":
+		return nil, fmt.Errorf("-trusted-head and -from-snapshot are mutually exclusive")
+	case headHex != "":
+		h := normalizeHash(headHex)
+		var raw [sha256.Size]byte
+		if err := decodeHash(raw[:], h); err != nil {
+			return nil, fmt.Errorf("-trusted-head: %w", err)
+		}
+		return &trustedHeads{Source: "-trusted-head", All: h}, nil
+	case snapshotPath != "":
+		data, err := os.ReadFile(snapshotPath)
+		if err != nil {
+			return nil, err
+		}
+		snap, err := decodeSnapshot(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", snapshotPath, err)
+		}
+		h := &trustedHeads{Source: "snapshot " + snapshotPath, ByAgent: make(map[string]agentHead, len(snap.Heads)), Digest: snap.ConfigDigest}
+		for _, head := range snap.Heads {
+			h.ByAgent[head.AgentID] = head
+		}
+		return h, nil
+	}
+	return nil, nil
+}
+
+// sliceWindow drops the records before the window from ordered, keeping
+// the order, and returns how many were dropped.
+func sliceWindow(ordered []chainTuple, since sinceSpec, heads *trustedHeads) ([]chainTuple, int) {
+	window := ordered[:0:0]
+	for _, t := range ordered {
+		if since.excludes(t) {
+			continue
+		}
+		if heads != nil {
+			if head, ok := heads.ByAgent[t.AgentID]; ok && t.Sequence <= head.Sequence {
+				continue
+			}
+		}
+		window = append(window, t)
+	}
+	return window, len(ordered) - len(window)
+}
+
+// carryOver adds the snapshot heads of agents with no record in the
+// window to last, so a snapshot written after the run still covers them.
+func (h *trustedHeads) carryOver(last map[string]chainTuple) {
+	if h == nil {
+		return
+	}
+	for id, head := range h.ByAgent {
+		if _, ok := last[id]; !ok {
+			last[id] = chainTuple{AgentID: id, Sequence: head.Sequence, Timestamp: head.LastTimestamp,
+				ChainHash: hex.EncodeToString(head.HeadHash[:])}
+		}
+	}
+}
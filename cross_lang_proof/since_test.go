@@ -0,0 +1,114 @@
+// cross_lang_proof/since_test.go
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func chainHashOf(t *testing.T, path string) string {
+	t.Helper()
+	tuple, err := readChainTuple(path, nil, shardSpec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tuple.ChainHash
+}
+
+func TestChainSinceSequence(t *testing.T) {
+	dir := t.TempDir()
+	files := writeChainDir(t, dir, "alpha", 5)
+	head := chainHashOf(t, files[2])
+
+	code, out, errOut := runCaptured(t, "chain", "-since", "3", "-trusted-head", head, dir)
+	if code != 0 {
+		t.Fatalf("exit %d\n%s%s", code, out, errOut)
+	}
+	for _, want := range []string{
+		"3 record(s) before the window left out, 2 to verify",
+		"✅  alpha-3.json links to trusted head",
+		"history before the window assumed, not verified",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "alpha-2.json signature valid") {
+		t.Errorf("record before the window was verified:\n%s", out)
+	}
+}
+
+func TestChainSinceWrongHeadFailsAtOnce(t *testing.T) {
+	dir := t.TempDir()
+	files := writeChainDir(t, dir, "alpha", 5)
+
+	code, out, _ := runCaptured(t, "chain", "-since", "3", "-trusted-head", chainHashOf(t, files[1]), dir)
+	if code != 1 {
+		t.Fatalf("exit %d, want 1 (TAMPERED)\n%s", code, out)
+	}
+	if !strings.Contains(out, "❌  alpha-3.json links to trusted head") || !strings.Contains(out, "stopping:") {
+		t.Errorf("mismatch not reported:\n%s", out)
+	}
+	if strings.Contains(out, "alpha-4.json") {
+		t.Errorf("pass 2 went on after the mismatch:\n%s", out)
+	}
+}
+
+func TestChainSinceTime(t *testing.T) {
+	dir := writeAnomalyChain(t, "tool_call", "none")
+	head := chainHashOf(t, filepath.Join(dir, "r1.json"))
+	code, out, errOut := runCaptured(t, "chain", "-since", "2026-02-24T12:00:02Z", "-trusted-head", head, dir)
+	if code != 0 || !strings.Contains(out, "2 record(s) before the window left out, 2 to verify") {
+		t.Errorf("exit %d\n%s%s", code, out, errOut)
+	}
+}
+
+func TestChainFromSnapshot(t *testing.T) {
+	full := t.TempDir()
+	files := writeChainDir(t, full, "alpha", 5)
+	writeChainDir(t, full, "beta", 2)
+	early := t.TempDir()
+	for _, f := range append(files[:3:3], filepath.Join(full, "beta-0.json"), filepath.Join(full, "beta-1.json")) {
+		data, _ := os.ReadFile(f)
+		os.WriteFile(filepath.Join(early, filepath.Base(f)), data, 0o644)
+	}
+	snap := filepath.Join(t.TempDir(), "heads.gefsnap")
+	if code, out, _ := runCaptured(t, "chain", "-snapshot", snap, early); code != 0 {
+		t.Fatalf("snapshot run: exit %d\n%s", code, out)
+	}
+
+	code, out, errOut := runCaptured(t, "chain", "-from-snapshot", snap, "-snapshot", snap, full)
+	if code != 0 || !strings.Contains(out, "5 record(s) before the window left out, 2 to verify") ||
+		!strings.Contains(out, "✅  alpha-3.json links to trusted head") {
+		t.Fatalf("exit %d\n%s%s", code, out, errOut)
+	}
+	data, _ := os.ReadFile(snap)
+	s, err := decodeSnapshot(data)
+	if err != nil || len(s.Heads) != 2 || s.Heads[0].Sequence != 4 || s.Heads[1].AgentID != "beta" {
+		t.Errorf("updated snapshot = %+v, %v; want alpha at 4 and beta carried over", s, err)
+	}
+
+	// A head alpha never had is a wrong assumption.
+	wrong := &chainSnapshot{Heads: []agentHead{{AgentID: "alpha", Sequence: 2}}}
+	wrong.Heads[0].HeadHash[0] = 1
+	os.WriteFile(snap, encodeSnapshot(wrong), 0o644)
+	if code, out, _ := runCaptured(t, "chain", "-from-snapshot", snap, full); code != 1 || !strings.Contains(out, "stopping:") {
+		t.Errorf("wrong snapshot head: exit %d\n%s", code, out)
+	}
+}
+
+func TestChainSinceUsage(t *testing.T) {
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"-since", "yesterday"},
+		{"-trusted-head", "abc"},
+		{"-trusted-head", strings.Repeat("a", 64), "-from-snapshot", "x"},
+	} {
+		if code, _, errOut := runCaptured(t, append(append([]string{"chain"}, args...), dir)...); code != 2 || !strings.HasPrefix(errOut, "FATAL:") {
+			t.Errorf("%v: exit %d, stderr %q", args, code, errOut)
+		}
+	}
+}
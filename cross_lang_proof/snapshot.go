@@ -0,0 +1,471 @@
+// cross_lang_proof/snapshot.go
+//
+// Chain-state snapshots (chain -snapshot, snapshot subcommand)
+// ============================================================
+//
+//   verify_proof chain -snapshot heads.gefsnap <dir>
+//   verify_proof snapshot -to-json heads.gefsnap [heads.json]
+//   verify_proof snapshot -from-json heads.json heads.gefsnap
+//
+// A snapshot is the per-agent head map at the end of a verified chain
+// run: for every agent_id, the chain hash, sequence and timestamp of its
+// last record. At a million agents the JSON form is slow to load, so the
+// file format is binary:
+//
+//   magic      8 bytes   "GEFSNAP\x00"
+//   version    uint16    big-endian, snapshotVersion
+//   digest     32 bytes  config digest: SHA-256 of the -chain-rules file,
+//                        all zero without one
+//   sections   kind uint8, length uvarint, payload; repeated
+//   checksum   32 bytes  SHA-256 of everything before it
+//
+// Section kindHeads (exactly one) holds a uvarint count, then count
+// length-prefixed records of
+//
+//   agent_id        uvarint length + UTF-8 bytes, strictly ascending
+//   head hash       32 bytes
+//   sequence        varint
+//   last timestamp  varint Unix nanoseconds, 0 if unknown
+//
+// Forward compatibility: readers skip section kinds they do not know and
+// bytes at the end of a record after the fields they know, so new data is
+// added as a new section or a trailing record field without a version
+// bump. version changes only for layouts an older reader must refuse.
+//
+// A snapshot either loads completely or not at all: the checksum is
+// verified before anything is decoded, and any structural error after
+// that names its byte offset. -to-json and -from-json convert to and
+// from the JSON form for debugging.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+const (
+	snapshotMagic   = "GEFSNAP\x00"
+	snapshotVersion = 1
+	snapshotFormat  = "gef-chain-snapshot" // "format" of the JSON form
+
+	kindHeads = 1
+
+	snapshotHeaderLen = len(snapshotMagic) + 2 + sha256.Size
+)
+
+// agentHead is the chain state of one agent.
+type agentHead struct {
+	AgentID       string
+	HeadHash      [sha256.Size]byte
+	Sequence      int64
+	LastTimestamp time.Time // zero if unknown
+}
+
+// chainSnapshot is the decoded content of a snapshot file.
+type chainSnapshot struct {
+	ConfigDigest [sha256.Size]byte
+	Heads        []agentHead // sorted by AgentID, no duplicates
+}
+
+// snapshotError is a structural error at a byte offset of the file.
+type snapshotError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *snapshotError) Error() string {
+	return fmt.Sprintf("snapshot: offset %d: %s", e.Offset, e.Msg)
+}
+
+// errSnapshotChecksum is wrapped by the error for a checksum mismatch.
+var errSnapshotChecksum = errors.New("snapshot: checksum mismatch")
+
+// snapshotFromHeads builds a snapshot from pass 2's last fully verified
+// record per agent.
+func snapshotFromHeads(last map[string]chainTuple, digest [sha256.Size]byte) (*chainSnapshot, error) {
+	s := &chainSnapshot{ConfigDigest: digest, Heads: make([]agentHead, 0, len(last))}
+	for id, t := range last {
+		h := agentHead{AgentID: id, Sequence: t.Sequence, LastTimestamp: t.Timestamp}
+		if n, err := hex.Decode(h.HeadHash[:], []byte(t.ChainHash)); err != nil || n != sha256.Size {
+			return nil, fmt.Errorf("agent %s: chain hash %q is not 32 bytes of hex", id, t.ChainHash)
+		}
+		s.Heads = append(s.Heads, h)
+	}
+	sort.Slice(s.Heads, func(i, j int) bool { return s.Heads[i].AgentID < s.Heads[j].AgentID })
+	return s, nil
+}
+
+// ── Binary form ───────────────────────────────────────────────────────────────
+
+// encodeSnapshot returns the binary form of s, whose Heads must be sorted.
+func encodeSnapshot(s *chainSnapshot) []byte {
+	var payload, rec []byte
+	payload = binary.AppendUvarint(payload, uint64(len(s.Heads)))
+	for _, h := range s.Heads {
+		rec = binary.AppendUvarint(rec[:0], uint64(len(h.AgentID)))
+		rec = append(rec, h.AgentID...)
+		rec = append(rec, h.HeadHash[:]...)
+		rec = binary.AppendVarint(rec, h.Sequence)
+		var ts int64
+		if !h.LastTimestamp.IsZero() {
+			ts = h.LastTimestamp.UnixNano()
+		}
+		rec = binary.AppendVarint(rec, ts)
+		payload = binary.AppendUvarint(payload, uint64(len(rec)))
+		payload = append(payload, rec...)
+	}
+
+	out := make([]byte, 0, snapshotHeaderLen+binary.MaxVarintLen64+len(payload)+sha256.Size)
+	out = append(out, snapshotMagic...)
+	out = binary.BigEndian.AppendUint16(out, snapshotVersion)
+	out = append(out, s.ConfigDigest[:]...)
+	out = append(out, kindHeads)
+	out = binary.AppendUvarint(out, uint64(len(payload)))
+	out = append(out, payload...)
+	sum := sha256.Sum256(out)
+	return append(out, sum[:]...)
+}
+
+// decodeSnapshot verifies the trailing checksum of data, then decodes it.
+func decodeSnapshot(data []byte) (*chainSnapshot, error) {
+	if len(data) < snapshotHeaderLen+sha256.Size {
+		return nil, &snapshotError{len(data), fmt.Sprintf("file is %d bytes, shorter than header and checksum", len(data))}
+	}
+	content, trailer := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	if sum := sha256.Sum256(content); !bytes.Equal(sum[:], trailer) {
+		return nil, fmt.Errorf("%w: content hashes to %x, trailer at offset %d says %x (truncated or corrupted)",
+			errSnapshotChecksum, sum, len(content), trailer)
+	}
+	return parseSnapshot(content)
+}
+
+// snapshotReader decodes content, tracking the offset for errors.
+type snapshotReader struct {
+	buf []byte
+	off int
+}
+
+func (r *snapshotReader) fail(format string, a ...interface{}) error {
+	return &snapshotError{r.off, fmt.Sprintf(format, a...)}
+}
+
+func (r *snapshotReader) bytes(n int, what string) ([]byte, error) {
+	if n < 0 || n > len(r.buf)-r.off {
+		return nil, r.fail("%s: need %d bytes, %d left", what, n, len(r.buf)-r.off)
+	}
+	b := r.buf[r.off : r.off+n]
+	r.off += n
+	return b, nil
+}
+
+func (r *snapshotReader) uvarint(what string) (uint64, error) {
+	v, n := binary.Uvarint(r.buf[r.off:])
+	if n <= 0 {
+		return 0, r.fail("%s: bad uvarint", what)
+	}
+	r.off += n
+	return v, nil
+}
+
+func (r *snapshotReader) varint(what string) (int64, error) {
+	v, n := binary.Varint(r.buf[r.off:])
+	if n <= 0 {
+		return 0, r.fail("%s: bad varint", what)
+	}
+	r.off += n
+	return v, nil
+}
+
+// length reads a uvarint length that must fit in the bytes left.
+func (r *snapshotReader) length(what string) (int, error) {
+	v, err := r.uvarint(what)
+	if err != nil {
+		return 0, err
+	}
+	if v > uint64(len(r.buf)-r.off) {
+		return 0, r.fail("%s %d exceeds the %d bytes left", what, v, len(r.buf)-r.off)
+	}
+	return int(v), nil
+}
+
+// parseSnapshot decodes checksummed content (everything but the trailer).
+// It returns a complete snapshot or an error, never a partial one.
+func parseSnapshot(content []byte) (*chainSnapshot, error) {
+	r := &snapshotReader{buf: content}
+	magic, err := r.bytes(len(snapshotMagic), "magic")
+	if err != nil {
+		return nil, err
+	}
+	if string(magic) != snapshotMagic {
+		r.off = 0
+		return nil, r.fail("not a chain snapshot (magic %q)", magic)
+	}
+	v, err := r.bytes(2, "version")
+	if err != nil {
+		return nil, err
+	}
+	if version := binary.BigEndian.Uint16(v); version != snapshotVersion {
+		r.off -= 2
+		return nil, r.fail("format version %d, this reader supports %d", version, snapshotVersion)
+	}
+	s := &chainSnapshot{}
+	digest, err := r.bytes(sha256.Size, "config digest")
+	if err != nil {
+		return nil, err
+	}
+	copy(s.ConfigDigest[:], digest)
+
+	seenHeads := false
+	for r.off < len(r.buf) {
+		start := r.off
+		kind, _ := r.bytes(1, "section kind")
+		n, err := r.length("section length")
+		if err != nil {
+			return nil, err
+		}
+		payload, _ := r.bytes(n, "section")
+		if kind[0] != kindHeads {
+			continue // unknown section: skipped
+		}
+		if seenHeads {
+			r.off = start
+			return nil, r.fail("second heads section")
+		}
+		seenHeads = true
+		if s.Heads, err = parseHeads(payload, r.off-n); err != nil {
+			return nil, err
+		}
+	}
+	if !seenHeads {
+		return nil, r.fail("no heads section")
+	}
+	return s, nil
+}
+
+// parseHeads decodes the heads section payload found at offset base.
+func parseHeads(payload []byte, base int) ([]agentHead, error) {
+	r := &snapshotReader{buf: payload}
+	wrap := func(err error) error {
+		var se *snapshotError
+		if errors.As(err, &se) {
+			se.Offset += base
+		}
+		return err
+	}
+	count, err := r.uvarint("head count")
+	if err != nil {
+		return nil, wrap(err)
+	}
+	// Every record takes at least two bytes, which bounds the allocation.
+	if count > uint64(len(payload)/2) {
+		return nil, wrap(r.fail("head count %d cannot fit in %d bytes", count, len(payload)))
+	}
+	heads := make([]agentHead, 0, count)
+	for i := uint64(0); i < count; i++ {
+		n, err := r.length("head length")
+		if err != nil {
+			return nil, wrap(err)
+		}
+		recBase := r.off
+		data, _ := r.bytes(n, "head")
+		rr := &snapshotReader{buf: data}
+		h, err := parseHead(rr, i)
+		if err != nil {
+			var se *snapshotError
+			if errors.As(err, &se) {
+				se.Offset += recBase
+			}
+			return nil, wrap(err)
+		}
+		if i > 0 && h.AgentID <= heads[i-1].AgentID {
+			r.off = recBase
+			return nil, wrap(r.fail("head %d: agent_id %q not after %q", i, h.AgentID, heads[i-1].AgentID))
+		}
+		heads = append(heads, h)
+	}
+	if r.off != len(payload) {
+		return nil, wrap(r.fail("%d bytes after head %d of %d", len(payload)-r.off, count, count))
+	}
+	return heads, nil
+}
+
+// parseHead decodes record i; bytes after the known fields are skipped.
+// Field names go into errors only, so the happy path does not format.
+func parseHead(r *snapshotReader, i uint64) (agentHead, error) {
+	var h agentHead
+	fail := func(err error) (agentHead, error) {
+		se := err.(*snapshotError)
+		se.Msg = fmt.Sprintf("head %d %s", i, se.Msg)
+		return h, se
+	}
+	n, err := r.length("agent_id length")
+	if err != nil {
+		return fail(err)
+	}
+	if n == 0 {
+		return fail(r.fail("agent_id: empty"))
+	}
+	id, _ := r.bytes(n, "agent_id")
+	h.AgentID = string(id)
+	hash, err := r.bytes(sha256.Size, "hash")
+	if err != nil {
+		return fail(err)
+	}
+	copy(h.HeadHash[:], hash)
+	if h.Sequence, err = r.varint("sequence"); err != nil {
+		return fail(err)
+	}
+	ts, err := r.varint("timestamp")
+	if err != nil {
+		return fail(err)
+	}
+	if ts != 0 {
+		h.LastTimestamp = time.Unix(0, ts).UTC()
+	}
+	return h, nil
+}
+
+// ── JSON form ─────────────────────────────────────────────────────────────────
+
+type snapshotJSON struct {
+	Format       string          `json:"format"`
+	Version      int             `json:"version"`
+	ConfigDigest string          `json:"config_digest"`
+	Heads        []agentHeadJSON `json:"heads"`
+}
+
+type agentHeadJSON struct {
+	AgentID       string `json:"agent_id"`
+	HeadHash      string `json:"head_hash"`
+	Sequence      int64  `json:"sequence"`
+	LastTimestamp string `json:"last_timestamp,omitempty"`
+}
+
+// marshalSnapshotJSON returns the JSON form of s.
+func marshalSnapshotJSON(s *chainSnapshot) ([]byte, error) {
+	doc := snapshotJSON{
+		Format:       snapshotFormat,
+		Version:      snapshotVersion,
+		ConfigDigest: hex.EncodeToString(s.ConfigDigest[:]),
+		Heads:        make([]agentHeadJSON, len(s.Heads)),
+	}
+	for i, h := range s.Heads {
+		doc.Heads[i] = agentHeadJSON{AgentID: h.AgentID, HeadHash: hex.EncodeToString(h.HeadHash[:]), Sequence: h.Sequence}
+		if !h.LastTimestamp.IsZero() {
+			doc.Heads[i].LastTimestamp = h.LastTimestamp.Format(time.RFC3339Nano)
+		}
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	return append(data, '\n'), err
+}
+
+// unmarshalSnapshotJSON decodes and validates the JSON form.
+func unmarshalSnapshotJSON(data []byte) (*chainSnapshot, error) {
+	var doc snapshotJSON
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("snapshot json: %w", err)
+	}
+	if doc.Format != snapshotFormat || doc.Version != snapshotVersion {
+		return nil, fmt.Errorf("snapshot json: format %q version %d, want %q version %d",
+			doc.Format, doc.Version, snapshotFormat, snapshotVersion)
+	}
+	s := &chainSnapshot{Heads: make([]agentHead, len(doc.Heads))}
+	if err := decodeHash(s.ConfigDigest[:], doc.ConfigDigest); err != nil {
+		return nil, fmt.Errorf("snapshot json: config_digest: %w", err)
+	}
+	for i, j := range doc.Heads {
+		h := &s.Heads[i]
+		h.AgentID, h.Sequence = j.AgentID, j.Sequence
+		if j.AgentID == "" {
+			return nil, fmt.Errorf("snapshot json: heads[%d]: empty agent_id", i)
+		}
+		if i > 0 && j.AgentID <= doc.Heads[i-1].AgentID {
+			return nil, fmt.Errorf("snapshot json: heads[%d]: agent_id %q not after %q", i, j.AgentID, doc.Heads[i-1].AgentID)
+		}
+		if err := decodeHash(h.HeadHash[:], j.HeadHash); err != nil {
+			return nil, fmt.Errorf("snapshot json: heads[%d]: head_hash: %w", i, err)
+		}
+		if j.LastTimestamp != "" {
+			ts, err := time.Parse(time.RFC3339Nano, j.LastTimestamp)
+			if err != nil {
+				return nil, fmt.Errorf("snapshot json: heads[%d]: %w", i, err)
+			}
+			h.LastTimestamp = ts.UTC()
+		}
+	}
+	return s, nil
+}
+
+func decodeHash(dst []byte, s string) error {
+	if n, err := hex.Decode(dst, []byte(s)); err != nil || n != len(dst) || len(s) != 2*len(dst) {
+		return fmt.Errorf("%q is not %d bytes of hex", s, len(dst))
+	}
+	return nil
+}
+
+// ── Subcommand ────────────────────────────────────────────────────────────────
+
+// runSnapshot converts a snapshot between the binary and JSON forms.
+func runSnapshot(args []string) int {
+	fs := newFlagSet("snapshot")
+	toJSON := fs.Bool("to-json", false, "decode a binary snapshot to JSON (stdout without an output file)")
+	fromJSON := fs.Bool("from-json", false, "encode a JSON snapshot to the binary form")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: verify_proof snapshot -to-json <in.gefsnap> [out.json]")
+		fmt.Fprintln(stderr, "       verify_proof snapshot -from-json <in.json> <out.gefsnap>")
+		fs.PrintDefaults()
+	}
+	parseFlags(fs, args)
+	if *toJSON == *fromJSON || fs.NArg() < 1 || fs.NArg() > 2 || (*fromJSON && fs.NArg() != 2) {
+		fs.Usage()
+		return 2
+	}
+
+	in := fs.Arg(0)
+	data, err := os.ReadFile(in)
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot read %s: %v\n", in, err)
+		return 2
+	}
+	var out []byte
+	if *toJSON {
+		var s *chainSnapshot
+		if s, err = decodeSnapshot(data); err == nil {
+			out, err = marshalSnapshotJSON(s)
+		}
+	} else {
+		var s *chainSnapshot
+		if s, err = unmarshalSnapshotJSON(data); err == nil {
+			out = encodeSnapshot(s)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: %s: %v\n", in, err)
+		return gefverify.VerdictMalformed.ExitCode()
+	}
+
+	if fs.NArg() == 1 {
+		_, err = io.Copy(stdout, bytes.NewReader(out))
+	} else {
+		err = writeFileAtomic(fs.Arg(1), out, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "FATAL: cannot write %s: %v\n", fs.Arg(fs.NArg()-1), err)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,252 @@
+// cross_lang_proof/snapshot_test.go
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testSnapshot(n int) *chainSnapshot {
+	s := &chainSnapshot{ConfigDigest: sha256.Sum256([]byte("rules")), Heads: make([]agentHead, n)}
+	base := time.Date(2026, 2, 24, 12, 0, 0, 0, time.UTC)
+	for i := range s.Heads {
+		s.Heads[i] = agentHead{
+			AgentID:       fmt.Sprintf("agent-%08d", i),
+			HeadHash:      sha256.Sum256([]byte{byte(i), byte(i >> 8), byte(i >> 16)}),
+			Sequence:      int64(i % 5000),
+			LastTimestamp: base.Add(time.Duration(i) * time.Millisecond),
+		}
+	}
+	s.Heads[0].LastTimestamp = time.Time{}
+	return s
+}
+
+// reseal returns content with a fresh checksum trailer.
+func reseal(content []byte) []byte {
+	sum := sha256.Sum256(content)
+	return append(append([]byte{}, content...), sum[:]...)
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	want := testSnapshot(3)
+	got, err := decodeSnapshot(encodeSnapshot(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("binary round trip:\ngot  %+v\nwant %+v", got, want)
+	}
+
+	data, err := marshalSnapshotJSON(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = unmarshalSnapshotJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON round trip:\ngot  %+v\nwant %+v\n%s", got, want, data)
+	}
+}
+
+func TestSnapshotRejectsEveryCorruption(t *testing.T) {
+	data := encodeSnapshot(testSnapshot(4))
+	for i := range data {
+		bad := append([]byte{}, data...)
+		bad[i] ^= 0x01
+		if s, err := decodeSnapshot(bad); err == nil || s != nil {
+			t.Fatalf("bit flip at %d accepted", i)
+		}
+	}
+	for n := 0; n < len(data); n++ {
+		if s, err := decodeSnapshot(data[:n]); err == nil || s != nil {
+			t.Fatalf("truncation to %d bytes accepted", n)
+		}
+	}
+	_, err := decodeSnapshot(append(data[:len(data)-1:len(data)-1], 0))
+	if !errors.Is(err, errSnapshotChecksum) || !strings.Contains(err.Error(), fmt.Sprintf("offset %d", len(data)-sha256.Size)) {
+		t.Errorf("checksum error = %v, want errSnapshotChecksum naming the trailer offset", err)
+	}
+}
+
+func TestSnapshotForwardCompatible(t *testing.T) {
+	want := testSnapshot(2)
+	content := encodeSnapshot(want)
+	content = content[:len(content)-sha256.Size]
+
+	// An unknown section after the heads is skipped.
+	withSection := append(append([]byte{}, content...), 0x7f, 3, 'n', 'e', 'w')
+	if got, err := decodeSnapshot(reseal(withSection)); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("unknown section: %v\n%+v", err, got)
+	}
+
+	// A field appended to a record is skipped.
+	h := want.Heads[0]
+	var rec []byte
+	rec = binary.AppendUvarint(rec, uint64(len(h.AgentID)))
+	rec = append(rec, h.AgentID...)
+	rec = append(rec, h.HeadHash[:]...)
+	rec = binary.AppendVarint(rec, h.Sequence)
+	rec = binary.AppendVarint(rec, 0)
+	rec = append(rec, "future"...)
+	var payload []byte
+	payload = binary.AppendUvarint(payload, 1)
+	payload = binary.AppendUvarint(payload, uint64(len(rec)))
+	payload = append(payload, rec...)
+	extended := append([]byte{}, content[:snapshotHeaderLen]...)
+	extended = append(extended, kindHeads)
+	extended = binary.AppendUvarint(extended, uint64(len(payload)))
+	extended = append(extended, payload...)
+	got, err := decodeSnapshot(reseal(extended))
+	if err != nil || len(got.Heads) != 1 || got.Heads[0].AgentID != h.AgentID {
+		t.Errorf("extended record: %v\n%+v", err, got)
+	}
+}
+
+func TestSnapshotStructuralErrors(t *testing.T) {
+	content := encodeSnapshot(testSnapshot(2))
+	content = content[:len(content)-sha256.Size]
+	tweak := func(f func(b []byte) []byte) []byte { return reseal(f(append([]byte{}, content...))) }
+
+	for name, tt := range map[string]struct {
+		data []byte
+		want string
+	}{
+		"magic":        {tweak(func(b []byte) []byte { b[0] = 'X'; return b }), "offset 0: not a chain snapshot"},
+		"version":      {tweak(func(b []byte) []byte { b[9] = 2; return b }), "offset 8: format version 2"},
+		"no heads":     {tweak(func(b []byte) []byte { return b[:snapshotHeaderLen] }), "no heads section"},
+		"two heads":    {tweak(func(b []byte) []byte { return append(b, b[snapshotHeaderLen:]...) }), "second heads section"},
+		"section size": {tweak(func(b []byte) []byte { return b[:len(b)-1] }), "section length"},
+	} {
+		if _, err := decodeSnapshot(tt.data); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", name, err, tt.want)
+		}
+	}
+
+	unsorted := testSnapshot(2)
+	unsorted.Heads[0], unsorted.Heads[1] = unsorted.Heads[1], unsorted.Heads[0]
+	if _, err := decodeSnapshot(encodeSnapshot(unsorted)); err == nil || !strings.Contains(err.Error(), "not after") {
+		t.Errorf("unsorted heads: err = %v", err)
+	}
+}
+
+func TestChainSnapshotCLI(t *testing.T) {
+	dir := t.TempDir()
+	writeChainDir(t, dir, "alpha", 3)
+	writeChainDir(t, dir, "beta", 2)
+	out := t.TempDir()
+	snap, js, back := filepath.Join(out, "heads.gefsnap"), filepath.Join(out, "heads.json"), filepath.Join(out, "back.gefsnap")
+
+	if code, _, errOut := runCaptured(t, "chain", "-snapshot", snap, dir); code != 0 {
+		t.Fatalf("chain: exit %d\n%s", code, errOut)
+	}
+	if code, _, errOut := runCaptured(t, "snapshot", "-to-json", snap, js); code != 0 {
+		t.Fatalf("-to-json: exit %d\n%s", code, errOut)
+	}
+	text, _ := os.ReadFile(js)
+	for _, want := range []string{`"agent_id": "alpha"`, `"sequence": 2`, `"agent_id": "beta"`, `"config_digest": "` + strings.Repeat("0", 64)} {
+		if !strings.Contains(string(text), want) {
+			t.Errorf("JSON form missing %q:\n%s", want, text)
+		}
+	}
+	if code, _, errOut := runCaptured(t, "snapshot", "-from-json", js, back); code != 0 {
+		t.Fatalf("-from-json: exit %d\n%s", code, errOut)
+	}
+	a, _ := os.ReadFile(snap)
+	b, _ := os.ReadFile(back)
+	if !bytes.Equal(a, b) {
+		t.Error("binary → JSON → binary changed the snapshot")
+	}
+
+	a[len(a)/2] ^= 0xff
+	os.WriteFile(snap, a, 0o644)
+	if code, out, errOut := runCaptured(t, "snapshot", "-to-json", snap); code != 3 || out != "" || !strings.Contains(errOut, "checksum mismatch") {
+		t.Errorf("corrupted: exit %d\nstdout=%q\nstderr=%q", code, out, errOut)
+	}
+	if code, _, errOut := runCaptured(t, "snapshot", "-to-json", "-from-json", snap); code != 2 || !strings.Contains(errOut, "usage:") {
+		t.Errorf("both directions: exit %d, stderr %q", code, errOut)
+	}
+}
+
+func TestChainSnapshotNotWrittenWhenChainFails(t *testing.T) {
+	dir := writeAnomalyChain(t, "tool_call", "link")
+	snap := filepath.Join(t.TempDir(), "heads.gefsnap")
+	if code, _, _ := runCaptured(t, "chain", "-snapshot", snap, dir); code != 1 {
+		t.Fatalf("exit %d, want 1", code)
+	}
+	if _, err := os.Stat(snap); !os.IsNotExist(err) {
+		t.Errorf("snapshot written for a broken chain (stat err %v)", err)
+	}
+}
+
+// ── Benchmarks and fuzzing ────────────────────────────────────────────────────
+
+var (
+	benchOnce        sync.Once
+	benchBin, benchJ []byte
+)
+
+// BenchmarkSnapshotLoad compares loading 1M agent heads from each form:
+//
+//	go test -run '^$' -bench SnapshotLoad -benchmem
+func BenchmarkSnapshotLoad(b *testing.B) {
+	benchOnce.Do(func() {
+		s := testSnapshot(1_000_000)
+		benchBin = encodeSnapshot(s)
+		benchJ, _ = marshalSnapshotJSON(s)
+	})
+	b.Run("binary", func(b *testing.B) {
+		b.SetBytes(int64(len(benchBin)))
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeSnapshot(benchBin); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("json", func(b *testing.B) {
+		b.SetBytes(int64(len(benchJ)))
+		for i := 0; i < b.N; i++ {
+			if _, err := unmarshalSnapshotJSON(benchJ); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// FuzzParseSnapshot feeds the decoder content behind a valid checksum,
+// which random corruption would almost never reach. A decode either
+// fails with nothing returned or yields a snapshot that re-encodes to a
+// file decoding to the same snapshot.
+func FuzzParseSnapshot(f *testing.F) {
+	valid := encodeSnapshot(testSnapshot(3))
+	content := valid[:len(valid)-sha256.Size]
+	f.Add(content)
+	f.Add(content[:len(content)/2])
+	f.Add(append(append([]byte{}, content...), 0x7f, 1, 0))
+	f.Add(content[:snapshotHeaderLen])
+	f.Fuzz(func(t *testing.T, content []byte) {
+		s, err := decodeSnapshot(reseal(content))
+		if err != nil {
+			if s != nil {
+				t.Fatalf("error %v with a partial snapshot", err)
+			}
+			return
+		}
+		again, err := decodeSnapshot(encodeSnapshot(s))
+		if err != nil || !reflect.DeepEqual(again, s) {
+			t.Fatalf("re-encoded snapshot does not decode the same: %v", err)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+// cross_lang_proof/threshold.go
+//
+// Alerting threshold (-fail-threshold, -baseline)
+// ===============================================
+//
+//   verify_proof -fail-threshold 5 -dir ./archive
+//   verify_proof -fail-threshold 2% -baseline last-night.json \
+//                -report-json tonight.json -dir ./archive
+//
+// A nightly run over a whole archive should page someone only when
+// things are actually bad. -fail-threshold takes a count of failing
+// bundles, or a percentage of the bundles verified, that a run may have
+// and still exit 0. Above it the exit code is that of the worst counted
+// verdict. The report still lists every failure either way.
+//
+// -baseline names the batch document of an earlier run (-report-json,
+// batchreport.go). A bundle that failed there is known and does not
+// count, so only new failures do: bundles that passed last time, and
+// bundles the baseline does not list. Bundles are matched by path as
+// given. -baseline without -fail-threshold allows no new failure.
+//
+// The evaluation is the "threshold" member of the batch document and a
+// line of the summary.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+// failThreshold is a parsed -fail-threshold: a count, or a percentage
+// of the bundles verified.
+type failThreshold struct {
+	Count   int
+	Percent float64
+	InPct   bool
+}
+
+// parseFailThreshold parses "5" or "2.5%".
+func parseFailThreshold(s string) (failThreshold, error) {
+	if pct, ok := strings.CutSuffix(s, "%"); ok {
+		p, err := strconv.ParseFloat(pct, 64)
+		if err != nil || p < 0 || p > 100 {
+			return failThreshold{}, fmt.Errorf("%q: want a percentage from 0%% to 100%%", s)
+		}
+		return failThreshold{Percent: p, InPct: true}, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return failThreshold{}, fmt.Errorf("%q: want a count of bundles or a percentage", s)
+	}
+	return failThreshold{Count: n}, nil
+}
+
+func (t failThreshold) String() string {
+	if t.InPct {
+		return strconv.FormatFloat(t.Percent, 'f', -1, 64) + "%"
+	}
+	return strconv.Itoa(t.Count)
+}
+
+// exceeded reports whether counted failures of total bundles are above t.
+func (t failThreshold) exceeded(counted, total int) bool {
+	if t.InPct {
+		return float64(counted)*100 > t.Percent*float64(total)
+	}
+	return counted > t.Count
+}
+
+// loadBaseline reads the batch document at path and returns the paths
+// of the bundles that failed in it.
+func loadBaseline(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc batchDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("baseline: %w", err)
+	}
+	if doc.SchemaVersion != gefverify.ReportSchemaVersion || doc.Bundles == nil {
+		return nil, errors.New("baseline: not a batch document (write one with -report-json in batch mode)")
+	}
+	failed := make(map[string]bool)
+	for _, b := range doc.Bundles {
+		if b.Verdict != gefverify.VerdictVerified {
+			failed[b.Path] = true
+		}
+	}
+	return failed, nil
+}
+
+// thresholdResult is the evaluation of -fail-threshold for one batch.
+type thresholdResult struct {
+	Threshold string `json:"threshold"`
+	Baseline  string `json:"baseline,omitempty"`
+	Bundles   int    `json:"bundles"` // verified, failing or not
+	Failed    int    `json:"failed"`  // every failing bundle
+	Known     int    `json:"known"`   // failing in the baseline too
+	Counted   int    `json:"counted"` // Failed - Known
+	Exceeded  bool   `json:"exceeded"`
+
+	verdict gefverify.Verdict // of the counted failures
+}
+
+// evaluateThreshold counts the new failures of entries against t.
+// known holds the paths that failed in the baseline, if any.
+func evaluateThreshold(entries []batchEntry, t failThreshold, baselinePath string, known map[string]bool) *thresholdResult {
+	r := &thresholdResult{Threshold: t.String(), Baseline: baselinePath}
+	var counted []gefverify.Verdict
+	for _, e := range entries {
+		switch {
+		case e.Skipped != "":
+			continue
+		case e.Verdict == gefverify.VerdictVerified:
+		case known[e.Path]:
+			r.Failed++
+			r.Known++
+		default:
+			r.Failed++
+			counted = append(counted, e.Verdict)
+		}
+		r.Bundles++
+	}
+	r.Counted = len(counted)
+	r.Exceeded = t.exceeded(r.Counted, r.Bundles)
+	r.verdict = gefverify.VerdictVerified
+	if r.Exceeded {
+		r.verdict = folderVerdict(counted)
+	}
+	return r
+}
+
+// summary is the threshold line of the batch summary.
+func (r *thresholdResult) summary() string {
+	state := "not exceeded"
+	if r.Exceeded {
+		state = "EXCEEDED"
+	}
+	line := fmt.Sprintf("threshold  %d counted failure(s) of %d bundle(s), limit %s: %s", r.Counted, r.Bundles, r.Threshold, state)
+	if r.Baseline != "" {
+		line += fmt.Sprintf("  (%d known from %s)", r.Known, r.Baseline)
+	}
+	return line
+}
@@ -0,0 +1,83 @@
+// cross_lang_proof/threshold_test.go
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gef_cross_lang_proof/pkg/gefverify"
+)
+
+func TestParseFailThreshold(t *testing.T) {
+	for s, want := range map[string]failThreshold{
+		"0":    {},
+		"5":    {Count: 5},
+		"2.5%": {Percent: 2.5, InPct: true},
+		"100%": {Percent: 100, InPct: true},
+	} {
+		got, err := parseFailThreshold(s)
+		if err != nil || got != want || got.String() != s {
+			t.Errorf("%q: %+v (%s), %v", s, got, got, err)
+		}
+	}
+	for _, s := range []string{"", "-1", "x", "1.5", "101%", "-2%"} {
+		if _, err := parseFailThreshold(s); err == nil {
+			t.Errorf("%q parsed", s)
+		}
+	}
+}
+
+func TestFailThreshold(t *testing.T) {
+	// 2 of 4 bundles fail: tampered.json TAMPERED, broken.json MALFORMED.
+	dir := writeBatchDir(t)
+	for _, tt := range []struct {
+		threshold string
+		code      int
+		summary   string
+	}{
+		{"2", 0, "2 counted failure(s) of 4 bundle(s), limit 2: not exceeded"},
+		{"1", gefverify.VerdictMalformed.ExitCode(), "limit 1: EXCEEDED"},
+		{"50%", 0, "limit 50%: not exceeded"},
+		{"25%", gefverify.VerdictMalformed.ExitCode(), "limit 25%: EXCEEDED"},
+	} {
+		code, out, _ := runCaptured(t, "-fail-threshold", tt.threshold, "-dir", dir)
+		if code != tt.code || !strings.Contains(out, tt.summary) || !strings.Contains(out, "2 passed, 2 failed of 4 bundle(s)") {
+			t.Errorf("-fail-threshold %s: exit %d, want %d\n%s", tt.threshold, code, tt.code, out)
+		}
+	}
+
+	// Last night's failures are known; a new one counts alone.
+	baseline := filepath.Join(t.TempDir(), "baseline.json")
+	runCaptured(t, "-report-json", baseline, "-dir", dir)
+	must(t, os.WriteFile(filepath.Join(dir, "tampered-2.json"), mustRead(t, filepath.Join(dir, "tampered.json")), 0o644))
+
+	code, out, _ := runCaptured(t, "-baseline", baseline, "-format", "json", "-dir", dir)
+	var doc batchDocument
+	must(t, json.Unmarshal([]byte(out), &doc))
+	want := thresholdResult{Threshold: "0", Baseline: baseline, Bundles: 5, Failed: 3, Known: 2, Counted: 1, Exceeded: true}
+	if code != gefverify.VerdictTampered.ExitCode() || doc.Threshold == nil || *doc.Threshold != want {
+		t.Errorf("-baseline: exit %d, threshold %+v, want %+v", code, doc.Threshold, want)
+	}
+	code, out, _ = runCaptured(t, "-quiet", "-fail-threshold", "1", "-baseline", baseline, "-dir", dir)
+	if code != 0 || !strings.HasSuffix(out, "limit 1: not exceeded  (2 known from "+baseline+")\n") {
+		t.Errorf("-baseline -fail-threshold 1: exit %d\n%s", code, out)
+	}
+
+	report := filepath.Join(t.TempDir(), "report.json")
+	runCaptured(t, "-report-json", report, "proof_bundle.json")
+	for _, args := range [][]string{
+		{"-fail-threshold", "x", "-dir", dir},
+		{"-baseline", report, "-dir", dir},
+		{"-baseline", filepath.Join(dir, "absent.json"), "-dir", dir},
+		{"-fail-threshold", "1", "proof_bundle.json"},
+		{"-baseline", baseline, "proof_bundle.json"},
+	} {
+		if code, _, _ := runCaptured(t, args...); code != 2 {
+			t.Errorf("%v: exit %d, want 2", args, code)
+		}
+	}
+}